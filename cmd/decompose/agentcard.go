@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/agent"
+)
+
+// builtinAgents constructs the built-in specialist agents by short name.
+var builtinAgents = map[string]func() agent.Agent{
+	"research":    func() agent.Agent { return agent.NewResearchAgent() },
+	"schema":      func() agent.Agent { return agent.NewSchemaAgent() },
+	"planning":    func() agent.Agent { return agent.NewPlanningAgent() },
+	"task-writer": func() agent.Agent { return agent.NewTaskWriterAgent() },
+}
+
+// runAgentCard prints an agent card as indented JSON: a remote agent's card
+// fetched with DiscoverAgent, or with --local the card of a built-in agent,
+// named with or without its "-agent" suffix.
+func runAgentCard(ctx context.Context, client a2a.Client, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("agent-card", flag.ContinueOnError)
+	local := fs.String("local", "", "built-in agent name: "+strings.Join(builtinAgentNames(), ", "))
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
+	var card a2a.AgentCard
+	switch {
+	case *local != "":
+		newAgent, ok := builtinAgents[strings.TrimSuffix(*local, "-agent")]
+		if !ok {
+			return usageErrorf("unknown built-in agent %q (want one of: %s)", *local, strings.Join(builtinAgentNames(), ", "))
+		}
+		card = newAgent().Card()
+	case fs.NArg() == 1:
+		discovered, err := client.DiscoverAgent(ctx, fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("discover agent %s: %w", fs.Arg(0), err)
+		}
+		card = *discovered
+	default:
+		return usageErrorf("usage: decompose agent-card <url> | --local <name>")
+	}
+
+	data, err := json.MarshalIndent(card, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode agent card: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// builtinAgentNames returns the short names of the built-in agents, sorted.
+func builtinAgentNames() []string {
+	names := make([]string, 0, len(builtinAgents))
+	for name := range builtinAgents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAgentCard_Remote(t *testing.T) {
+	ts := cardServer(t, a2a.AgentCard{
+		Name:   "remote-agent",
+		Skills: []a2a.AgentSkill{{ID: "explore", Name: "Explore"}},
+	})
+
+	var out bytes.Buffer
+	require.NoError(t, runAgentCard(context.Background(), a2a.NewHTTPClient(), []string{ts.URL}, &out))
+
+	var card a2a.AgentCard
+	require.NoError(t, json.Unmarshal(out.Bytes(), &card))
+	assert.Equal(t, "remote-agent", card.Name)
+	require.Len(t, card.Skills, 1)
+	assert.Equal(t, "explore", card.Skills[0].ID)
+	assert.Contains(t, out.String(), "\n  \"name\": \"remote-agent\"", "card should be indented")
+}
+
+func TestRunAgentCard_Local(t *testing.T) {
+	for _, name := range []string{"schema", "schema-agent"} {
+		var out bytes.Buffer
+		require.NoError(t, runAgentCard(context.Background(), nil, []string{"--local", name}, &out))
+
+		var card a2a.AgentCard
+		require.NoError(t, json.Unmarshal(out.Bytes(), &card))
+		assert.Equal(t, "schema-agent", card.Name)
+
+		var skills []string
+		for _, s := range card.Skills {
+			skills = append(skills, s.ID)
+		}
+		assert.Equal(t, []string{"translate-schema", "validate-types", "write-contracts"}, skills)
+	}
+}
+
+func TestRunAgentCard_Errors(t *testing.T) {
+	err := runAgentCard(context.Background(), nil, []string{"--local", "nope"}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "planning, research, schema, task-writer")
+
+	err = runAgentCard(context.Background(), nil, nil, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "usage: decompose agent-card")
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/onedusk/pd/internal/orchestrator"
+)
+
+// explicitAgents merges the endpoints named by --agents and --agents-file.
+// It returns CapA2AMCP when any endpoint is configured, along with the
+// per-endpoint headers for agents-file entries that carry credentials.
+func explicitAgents(flags cliFlags) (orchestrator.CapabilityLevel, []string, map[string]map[string]string, error) {
+	var urls []string
+	if flags.Agents != "" {
+		for _, u := range strings.Split(flags.Agents, ",") {
+			urls = append(urls, strings.TrimSpace(u))
+		}
+	}
+
+	var fromFile []orchestrator.AgentEndpoint
+	if flags.AgentsFile != "" {
+		var err error
+		if fromFile, err = orchestrator.LoadAgentsFile(flags.AgentsFile); err != nil {
+			return orchestrator.CapBasic, nil, nil, err
+		}
+	}
+
+	endpoints := orchestrator.MergeAgentEndpoints(urls, fromFile)
+	if len(endpoints) == 0 {
+		return orchestrator.CapBasic, nil, nil, nil
+	}
+	return orchestrator.CapA2AMCP, endpoints, orchestrator.EndpointHeaders(fromFile), nil
+}
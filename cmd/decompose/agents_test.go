@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onedusk/pd/internal/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplicitAgents_AgentsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.txt")
+	content := "# local agents\nhttp://localhost:9100\nhttp://localhost:9101\n\nhttp://localhost:9102\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	cap, endpoints, headers, err := explicitAgents(cliFlags{AgentsFile: path})
+	require.NoError(t, err)
+	assert.Equal(t, orchestrator.CapA2AMCP, cap)
+	assert.Equal(t, []string{"http://localhost:9100", "http://localhost:9101", "http://localhost:9102"}, endpoints)
+	assert.Empty(t, headers)
+}
+
+func TestExplicitAgents_MergesFlagAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"url": "http://localhost:9101", "token": "t"}]`), 0o644))
+
+	cap, endpoints, headers, err := explicitAgents(cliFlags{
+		Agents:     "http://localhost:9100, http://localhost:9101",
+		AgentsFile: path,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, orchestrator.CapA2AMCP, cap)
+	assert.Equal(t, []string{"http://localhost:9100", "http://localhost:9101"}, endpoints)
+	assert.Equal(t, "Bearer t", headers["http://localhost:9101"]["Authorization"])
+}
@@ -0,0 +1,87 @@
+//go:build cgo
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/onedusk/pd/internal/graph"
+)
+
+func runCompact(ctx context.Context, projectRoot string, args []string, w io.Writer) error {
+	fset := flag.NewFlagSet("compact", flag.ContinueOnError)
+	graphDB := fset.String("graph-db", "", "path to the graph database (default <project-root>/.decompose/graph)")
+	if err := fset.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
+	graphPath := *graphDB
+	if graphPath == "" {
+		graphPath = filepath.Join(projectRoot, ".decompose", "graph")
+	}
+	if _, err := os.Stat(graphPath); err != nil {
+		return fmt.Errorf("no graph found at %s\nRun 'build_graph' via MCP first to index the codebase", graphPath)
+	}
+
+	before := graphSize(graphPath)
+	store, err := graph.NewKuzuFileStore(graphPath)
+	if err != nil {
+		return fmt.Errorf("open graph: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Compact(ctx); err != nil {
+		return fmt.Errorf("compact graph: %w", err)
+	}
+	after := graphSize(graphPath)
+
+	fmt.Fprintf(w, "Compacted %s\n", graphPath)
+	fmt.Fprintf(w, "  before: %s\n", formatBytes(before))
+	fmt.Fprintf(w, "  after:  %s\n", formatBytes(after))
+	if before > after {
+		fmt.Fprintf(w, "  reclaimed: %s\n", formatBytes(before-after))
+	}
+	return nil
+}
+
+// graphSize returns the on-disk size of the KuzuDB at path, including its
+// write-ahead log. Directory-based databases are summed recursively.
+func graphSize(path string) int64 {
+	var total int64
+	for _, p := range []string{path, path + ".wal"} {
+		filepath.WalkDir(p, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+			return nil
+		})
+	}
+	return total
+}
+
+// formatBytes renders a byte count with a binary unit suffix.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
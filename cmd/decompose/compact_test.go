@@ -0,0 +1,55 @@
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/onedusk/pd/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCompact_ReportsSizesAndKeepsData(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "graph")
+	store, err := graph.NewKuzuFileStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, store.InitSchema(ctx))
+	for i := 0; i < 50; i++ {
+		require.NoError(t, store.AddFile(ctx, graph.FileNode{Path: fmt.Sprintf("f%d.go", i), Language: graph.LangGo}))
+	}
+	for i := 1; i < 50; i++ {
+		require.NoError(t, store.RemoveFile(ctx, fmt.Sprintf("f%d.go", i)))
+	}
+	require.NoError(t, store.Close())
+
+	var out bytes.Buffer
+	require.NoError(t, runCompact(ctx, "/unused", []string{"--graph-db", dbPath}, &out))
+	assert.Contains(t, out.String(), "Compacted "+dbPath+"\n")
+	assert.Contains(t, out.String(), "  before: ")
+	assert.Contains(t, out.String(), "  after:  ")
+
+	store, err = graph.NewKuzuFileStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+	stats, err := store.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.FileCount)
+}
+
+func TestRunCompact_MissingGraph(t *testing.T) {
+	err := runCompact(context.Background(), t.TempDir(), nil, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no graph found")
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "4.0 MiB", formatBytes(4<<20))
+}
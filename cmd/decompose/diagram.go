@@ -4,6 +4,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,7 +14,19 @@ import (
 	"github.com/onedusk/pd/internal/graph"
 )
 
-func runDiagram(projectRoot string) error {
+func runDiagram(projectRoot string, args []string) error {
+	fs := flag.NewFlagSet("diagram", flag.ContinueOnError)
+	byCluster := fs.Bool("by-cluster", false, "color file nodes by cluster and add a legend")
+	root := fs.String("root", "", "only draw this file and the files it imports, relative to the project root")
+	depth := fs.Int("depth", 1, "with --root, how many import hops to follow")
+	maxNodes := fs.Int("max-nodes", 0, "draw at most this many files, noting how many were left out (0 = no cap)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
 	graphPath := filepath.Join(projectRoot, ".decompose", "graph")
 	if _, err := os.Stat(graphPath); err != nil {
 		return fmt.Errorf("no graph found at %s\nRun 'build_graph' via MCP first to index the codebase", graphPath)
@@ -25,7 +39,17 @@ func runDiagram(projectRoot string) error {
 	defer store.Close()
 
 	ctx := context.Background()
-	mermaid, err := export.GenerateMermaid(ctx, store)
+	var opts []export.MermaidOption
+	if *byCluster {
+		opts = append(opts, export.WithClusterStyles())
+	}
+	if *root != "" {
+		opts = append(opts, export.WithRoot(*root, *depth))
+	}
+	if *maxNodes > 0 {
+		opts = append(opts, export.WithMaxNodes(*maxNodes))
+	}
+	mermaid, err := export.GenerateMermaid(ctx, store, opts...)
 	if err != nil {
 		return err
 	}
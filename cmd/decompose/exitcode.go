@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/orchestrator"
+)
+
+// Exit codes, so scripts can tell failure classes apart without parsing
+// stderr.
+const (
+	exitFailure    = 1   // any failure not classified below
+	exitUsage      = 2   // bad flags, arguments, or commands
+	exitAgent      = 3   // an agent could not be reached or rejected a request
+	exitValidation = 4   // stage output failed validation or verification
+	exitTimeout    = 124 // a deadline expired, matching timeout(1)
+)
+
+// usageError marks err as a command line the CLI cannot act on, such as a
+// flag parse failure or a missing argument.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+
+func (e *usageError) Unwrap() error { return e.err }
+
+// usageErrorf formats a usageError, which main exits with exitUsage.
+func usageErrorf(format string, args ...any) error {
+	return &usageError{fmt.Errorf(format, args...)}
+}
+
+// exitCode selects the process exit code for err returned by run. Timeouts
+// take precedence, since a timed-out agent call also reads as a network
+// error.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return exitTimeout
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+
+	var compileErr *orchestrator.GoCompileError
+	if errors.As(err, &compileErr) || errors.Is(err, orchestrator.ErrVerificationFailed) {
+		return exitValidation
+	}
+
+	var (
+		rpcErr       *a2a.RPCError
+		statusErr    *a2a.StatusError
+		urlErr       *url.Error
+		nonRetryable *orchestrator.NonRetryableError
+	)
+	if errors.As(err, &rpcErr) || errors.As(err, &statusErr) || errors.As(err, &urlErr) ||
+		errors.As(err, &netErr) || errors.As(err, &nonRetryable) {
+		return exitAgent
+	}
+	return exitFailure
+}
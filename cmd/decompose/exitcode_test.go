@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/orchestrator"
+)
+
+// timeoutError is a net.Error that reports a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestExitCode(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "http://localhost:1", Err: errors.New("connection refused")}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"plain", errors.New("boom"), exitFailure},
+		{"usage", usageErrorf("usage: decompose review <name>"), exitUsage},
+		{"wrapped usage", fmt.Errorf("graph: %w", usageErrorf("unknown graph command %q", "x")), exitUsage},
+		{"deadline", fmt.Errorf("router: stage 1 failed: %w", context.DeadlineExceeded), exitTimeout},
+		{"net timeout", &url.Error{Op: "Post", URL: "http://agent", Err: timeoutError{}}, exitTimeout},
+		{"unreachable agent", fmt.Errorf("a2a: message/send: %w", dialErr), exitAgent},
+		{"rpc error", &a2a.RPCError{Method: "message/send", Code: a2a.ErrCodeInvalidParams}, exitAgent},
+		{"http status", &a2a.StatusError{Op: "discover agent", StatusCode: 503}, exitAgent},
+		{"non-retryable", &orchestrator.NonRetryableError{Err: errors.New("rejected")}, exitAgent},
+		{"compile", fmt.Errorf("pipeline: validate stage 2: %w", &orchestrator.GoCompileError{Output: "x.go:1: bad"}), exitValidation},
+		{"verification", fmt.Errorf("router: stage 1 (design-pack) %w", orchestrator.ErrVerificationFailed), exitValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+func TestExitCode_UnknownFlagIsUsage(t *testing.T) {
+	err := run([]string{"--no-such-flag"})
+	assert.Equal(t, exitUsage, exitCode(err))
+}
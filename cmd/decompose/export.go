@@ -1,29 +1,61 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/onedusk/pd/internal/export"
 )
 
 func runExport(projectRoot string, args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("usage: decompose export <name>")
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "json", "output format: json, yaml, or toml")
+
+	// Accept the name before or after the flags.
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	if name == "" {
+		return usageErrorf("usage: decompose export <name> [--format json|yaml|toml]")
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	// JSON is streamed task file by task file so large decompositions are
+	// never held in memory in full.
+	if format == export.FormatJSON {
+		if err := export.StreamDecompositionJSON(os.Stdout, projectRoot, name); err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+		return nil
 	}
-	name := args[0]
 
 	data, err := export.ExportDecomposition(projectRoot, name)
 	if err != nil {
 		return fmt.Errorf("export failed: %w", err)
 	}
 
-	out, err := json.MarshalIndent(data, "", "  ")
+	out, err := export.Encode(data, format)
 	if err != nil {
-		return fmt.Errorf("marshal JSON: %w", err)
+		return err
 	}
 
-	_, err = os.Stdout.Write(append(out, '\n'))
+	_, err = os.Stdout.Write(out)
 	return err
 }
@@ -0,0 +1,160 @@
+//go:build cgo
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onedusk/pd/internal/graph"
+	"github.com/onedusk/pd/internal/mcptools"
+)
+
+// runGraph dispatches the graph subcommands: build indexes the project
+// without an MCP client, and stats summarizes a persisted graph.
+func runGraph(ctx context.Context, projectRoot string, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: decompose graph build|stats [flags]")
+	}
+	switch args[0] {
+	case "build":
+		return runGraphBuild(ctx, projectRoot, args[1:], w)
+	case "stats":
+		return runGraphStats(ctx, projectRoot, args[1:], w)
+	default:
+		return usageErrorf("unknown graph command %q (want build or stats)", args[0])
+	}
+}
+
+// runGraphBuild indexes projectRoot and prints the resulting graph's stats.
+// The graph is kept in memory unless --db names a database to write it to.
+func runGraphBuild(ctx context.Context, projectRoot string, args []string, w io.Writer) error {
+	fset := flag.NewFlagSet("graph build", flag.ContinueOnError)
+	languages := fset.String("languages", "", "comma-separated languages to index (default: all supported)")
+	exclude := fset.String("exclude", "", "comma-separated directories to skip")
+	include := fset.String("include", "", "comma-separated subtrees (or globs) to index; imports leaving them are reported")
+	dbPath := fset.String("db", "", "persist the graph to this database path (default: in memory only)")
+	if err := fset.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
+	var langs []string
+	for _, name := range splitList(*languages) {
+		lang, err := graph.ParseLanguage(name)
+		if err != nil {
+			return err
+		}
+		langs = append(langs, string(lang))
+	}
+
+	var store graph.Store = graph.NewMemStore()
+	if *dbPath != "" {
+		if err := os.MkdirAll(filepath.Dir(*dbPath), 0o755); err != nil {
+			return fmt.Errorf("create graph directory: %w", err)
+		}
+		kuzu, err := graph.NewKuzuFileStore(*dbPath)
+		if err != nil {
+			return fmt.Errorf("open graph: %w", err)
+		}
+		store = kuzu
+	}
+	defer store.Close()
+
+	svc := mcptools.NewCodeIntelService(store, graph.NewTreeSitterParser())
+	_, out, err := svc.BuildGraph(ctx, nil, mcptools.BuildGraphInput{
+		RepoPath:    projectRoot,
+		Languages:   langs,
+		ExcludeDirs: splitList(*exclude),
+		Include:     splitList(*include),
+	})
+	if err != nil {
+		return fmt.Errorf("build graph: %w", err)
+	}
+
+	fmt.Fprintf(w, "Indexed %s\n", projectRoot)
+	printGraphStats(w, out.Stats)
+	if n := len(out.ExternalImports); n > 0 {
+		fmt.Fprintf(w, "  external imports: %d\n", n)
+	}
+	if n := len(out.RemovedFiles); n > 0 {
+		fmt.Fprintf(w, "  removed files: %d\n", n)
+	}
+	if *dbPath != "" {
+		fmt.Fprintf(w, "Saved to %s\n", *dbPath)
+	}
+	return nil
+}
+
+// runGraphStats prints the stats of a persisted graph.
+func runGraphStats(ctx context.Context, projectRoot string, args []string, w io.Writer) error {
+	fset := flag.NewFlagSet("graph stats", flag.ContinueOnError)
+	dbPath := fset.String("db", "", "path to the graph database (default <project-root>/.decompose/graph)")
+	if err := fset.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
+	graphPath := *dbPath
+	if graphPath == "" {
+		graphPath = filepath.Join(projectRoot, ".decompose", "graph")
+	}
+	if _, err := os.Stat(graphPath); err != nil {
+		return fmt.Errorf("no graph found at %s\nRun 'decompose graph build --db %s' to index the codebase", graphPath, graphPath)
+	}
+
+	store, err := graph.NewKuzuFileStore(graphPath)
+	if err != nil {
+		return fmt.Errorf("open graph: %w", err)
+	}
+	defer store.Close()
+
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		return fmt.Errorf("graph stats: %w", err)
+	}
+	fmt.Fprintf(w, "Graph %s\n", graphPath)
+	printGraphStats(w, *stats)
+	return nil
+}
+
+// printGraphStats writes stats as aligned lines, with edges broken down by
+// kind in name order.
+func printGraphStats(w io.Writer, stats graph.GraphStats) {
+	fmt.Fprintf(w, "  files:    %d\n", stats.FileCount)
+	fmt.Fprintf(w, "  symbols:  %d\n", stats.SymbolCount)
+	fmt.Fprintf(w, "  edges:    %d\n", stats.EdgeCount)
+	fmt.Fprintf(w, "  clusters: %d\n", stats.ClusterCount)
+	fmt.Fprintf(w, "  sloc:     %d\n", stats.SLOC)
+
+	kinds := make([]string, 0, len(stats.EdgesByKind))
+	for kind := range stats.EdgesByKind {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "    %s: %d\n", kind, stats.EdgesByKind[graph.EdgeKind(kind)])
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
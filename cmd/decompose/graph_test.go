@@ -0,0 +1,63 @@
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goFixture = "../../testdata/fixtures/go_project"
+
+// statCount returns the count printed on the "  label:" line of out.
+func statCount(t *testing.T, out, label string) int {
+	t.Helper()
+	m := regexp.MustCompile(`(?m)^  ` + label + `:\s+(\d+)$`).FindStringSubmatch(out)
+	require.NotNil(t, m, "no %s line in:\n%s", label, out)
+	n, err := strconv.Atoi(m[1])
+	require.NoError(t, err)
+	return n
+}
+
+func TestRunGraphBuild_Fixture(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, runGraph(context.Background(), goFixture, []string{"build", "--languages", "golang"}, &out))
+
+	assert.Positive(t, statCount(t, out.String(), "files"))
+	assert.Positive(t, statCount(t, out.String(), "symbols"))
+	assert.Positive(t, statCount(t, out.String(), "edges"))
+}
+
+func TestRunGraphBuild_UnknownLanguage(t *testing.T) {
+	err := runGraph(context.Background(), goFixture, []string{"build", "--languages", "go,cobol"}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown language "cobol"`)
+	assert.Contains(t, err.Error(), "supported: go, typescript, python, rust")
+}
+
+func TestRunGraphStats_ReadsPersistedGraph(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "db", "graph")
+
+	var built bytes.Buffer
+	require.NoError(t, runGraph(ctx, goFixture, []string{"build", "--db", dbPath}, &built))
+	assert.Contains(t, built.String(), "Saved to "+dbPath)
+
+	var out bytes.Buffer
+	require.NoError(t, runGraph(ctx, "/unused", []string{"stats", "--db", dbPath}, &out))
+	assert.Equal(t, statCount(t, built.String(), "files"), statCount(t, out.String(), "files"))
+	assert.Equal(t, statCount(t, built.String(), "symbols"), statCount(t, out.String(), "symbols"))
+}
+
+func TestRunGraphStats_MissingGraph(t *testing.T) {
+	err := runGraph(context.Background(), t.TempDir(), []string{"stats"}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no graph found")
+}
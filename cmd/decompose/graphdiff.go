@@ -0,0 +1,170 @@
+//go:build cgo
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onedusk/pd/internal/graph"
+)
+
+func runGraphDiff(ctx context.Context, args []string, w io.Writer) error {
+	if len(args) != 2 {
+		return usageErrorf("usage: decompose graph-diff <snapshot-a> <snapshot-b>")
+	}
+
+	var stores [2]*graph.KuzuStore
+	for i, path := range args {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("no graph found at %s", path)
+		}
+		store, closeStore, err := openSnapshot(ctx, path)
+		if err != nil {
+			return fmt.Errorf("open graph %s: %w", path, err)
+		}
+		defer closeStore()
+		stores[i] = store
+	}
+
+	return reportGraphDiff(ctx, stores[0], stores[1], w)
+}
+
+// openSnapshot opens the graph at path for reading without modifying it. A
+// snapshot older than the current schema lacks columns the diff reads, so it
+// is copied to a temporary directory and the copy is migrated instead. The
+// returned func closes the store and removes any copy.
+func openSnapshot(ctx context.Context, path string) (*graph.KuzuStore, func(), error) {
+	store, err := graph.NewKuzuReadOnlyFileStore(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A store that predates versioning has no SchemaVersion table, so a
+	// failed read also means the snapshot needs migrating.
+	if version, err := store.SchemaVersion(ctx); err == nil && version == graph.CurrentSchemaVersion {
+		return store, func() { store.Close() }, nil
+	}
+	store.Close()
+
+	tmpDir, err := os.MkdirTemp("", "decompose-graph-diff-")
+	if err != nil {
+		return nil, nil, err
+	}
+	copyPath := filepath.Join(tmpDir, filepath.Base(path))
+	if err := copySnapshot(path, copyPath); err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, nil, fmt.Errorf("copy snapshot: %w", err)
+	}
+	store, err = graph.NewKuzuFileStore(copyPath)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, nil, err
+	}
+	closeStore := func() {
+		store.Close()
+		os.RemoveAll(tmpDir)
+	}
+	if err := store.InitSchema(ctx); err != nil {
+		closeStore()
+		return nil, nil, err
+	}
+	return store, closeStore, nil
+}
+
+// copySnapshot copies the database at src, a file or a directory depending
+// on the KuzuDB version that wrote it, to dst, along with its write-ahead log
+// if there is one.
+func copySnapshot(src, dst string) error {
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(p, target)
+	})
+	if err != nil {
+		return err
+	}
+	if err := copyFile(src+".wal", dst+".wal"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// copyFile copies the regular file src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// reportGraphDiff prints the files and symbols that changed between the
+// before and after snapshots to w.
+func reportGraphDiff(ctx context.Context, before, after graph.Store, w io.Writer) error {
+	diff, err := graph.DiffGraphs(ctx, before, after)
+	if err != nil {
+		return err
+	}
+	if diff.Empty() {
+		fmt.Fprintln(w, "No differences.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Files: %d added, %d removed, %d modified\n",
+		len(diff.AddedFiles), len(diff.RemovedFiles), len(diff.ModifiedFiles))
+	for _, p := range diff.AddedFiles {
+		fmt.Fprintf(w, "  + %s\n", p)
+	}
+	for _, p := range diff.RemovedFiles {
+		fmt.Fprintf(w, "  - %s\n", p)
+	}
+	for _, p := range diff.ModifiedFiles {
+		fmt.Fprintf(w, "  ~ %s\n", p)
+	}
+
+	fmt.Fprintf(w, "\nSymbols: %d added, %d removed, %d modified\n",
+		len(diff.AddedSymbols), len(diff.RemovedSymbols), len(diff.ModifiedSymbols))
+	for _, s := range diff.AddedSymbols {
+		fmt.Fprintf(w, "  + %s %s\n", s.Kind, symbolLocation(s))
+	}
+	for _, s := range diff.RemovedSymbols {
+		fmt.Fprintf(w, "  - %s %s\n", s.Kind, symbolLocation(s))
+	}
+	for _, c := range diff.ModifiedSymbols {
+		loc := symbolLocation(c.New)
+		if c.Old.FilePath != c.New.FilePath || c.Old.Name != c.New.Name {
+			loc = symbolLocation(c.Old) + " -> " + loc
+		}
+		fmt.Fprintf(w, "  ~ %s %s (%s)\n", c.New.Kind, loc, strings.Join(c.Changes, ", "))
+	}
+	return nil
+}
+
+// symbolLocation formats a symbol as "path:name@start-end".
+func symbolLocation(s graph.SymbolNode) string {
+	return fmt.Sprintf("%s:%s@%d-%d", s.FilePath, s.Name, s.StartLine, s.EndLine)
+}
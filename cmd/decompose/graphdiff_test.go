@@ -0,0 +1,92 @@
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	kuzu "github.com/kuzudb/go-kuzu"
+	"github.com/onedusk/pd/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportGraphDiff(t *testing.T) {
+	ctx := context.Background()
+	before, after := graph.NewMemStore(), graph.NewMemStore()
+	require.NoError(t, before.AddFile(ctx, graph.FileNode{Path: "a.go", Language: graph.LangGo, ContentHash: "1"}))
+	require.NoError(t, after.AddFile(ctx, graph.FileNode{Path: "a.go", Language: graph.LangGo, ContentHash: "1"}))
+	require.NoError(t, after.AddFile(ctx, graph.FileNode{Path: "b.go", Language: graph.LangGo, ContentHash: "2"}))
+	require.NoError(t, before.AddSymbol(ctx, graph.SymbolNode{
+		Name: "Run", Kind: graph.SymbolKindFunction, FilePath: "a.go", StartLine: 1, EndLine: 4, StableID: "v1"}))
+	require.NoError(t, after.AddSymbol(ctx, graph.SymbolNode{
+		Name: "Run", Kind: graph.SymbolKindFunction, FilePath: "b.go", StartLine: 2, EndLine: 6, StableID: "v2"}))
+
+	var out bytes.Buffer
+	require.NoError(t, reportGraphDiff(ctx, before, after, &out))
+	assert.Equal(t, "Files: 1 added, 0 removed, 0 modified\n"+
+		"  + b.go\n"+
+		"\nSymbols: 0 added, 0 removed, 1 modified\n"+
+		"  ~ function a.go:Run@1-4 -> b.go:Run@2-6 (moved, edited, lines)\n", out.String())
+
+	out.Reset()
+	require.NoError(t, reportGraphDiff(ctx, before, before, &out))
+	assert.Equal(t, "No differences.\n", out.String())
+}
+
+func TestOpenSnapshot_DoesNotModifySnapshot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// A current snapshot is opened read-only.
+	current := filepath.Join(dir, "current")
+	store, err := graph.NewKuzuFileStore(current)
+	require.NoError(t, err)
+	require.NoError(t, store.InitSchema(ctx))
+	require.NoError(t, store.AddFile(ctx, graph.FileNode{Path: "a.go", Language: graph.LangGo}))
+	require.NoError(t, store.Close())
+
+	snap, closeSnap, err := openSnapshot(ctx, current)
+	require.NoError(t, err)
+	files, err := snap.GetAllFiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Error(t, snap.AddFile(ctx, graph.FileNode{Path: "b.go", Language: graph.LangGo}))
+	closeSnap()
+
+	// A snapshot from before schema versioning is diffed through a migrated
+	// copy, leaving the original unversioned.
+	old := filepath.Join(dir, "old")
+	db, err := kuzu.OpenDatabase(old, kuzu.DefaultSystemConfig())
+	require.NoError(t, err)
+	conn, err := kuzu.OpenConnection(db)
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		"CREATE NODE TABLE File(path STRING, language STRING, loc INT64, sloc INT64, PRIMARY KEY(path))",
+		"CREATE NODE TABLE Symbol(id STRING, name STRING, kind STRING, exported BOOLEAN, file_path STRING, start_line INT64, end_line INT64, PRIMARY KEY(id))",
+		"CREATE NODE TABLE Cluster(name STRING, cohesion_score DOUBLE, PRIMARY KEY(name))",
+		"CREATE (f:File {path: 'a.go', language: 'go', loc: 1, sloc: 1})",
+	} {
+		res, err := conn.Query(stmt)
+		require.NoError(t, err, stmt)
+		res.Close()
+	}
+	conn.Close()
+	db.Close()
+
+	snap, closeSnap, err = openSnapshot(ctx, old)
+	require.NoError(t, err)
+	files, err = snap.GetAllFiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	closeSnap()
+
+	orig, err := graph.NewKuzuReadOnlyFileStore(old)
+	require.NoError(t, err)
+	defer orig.Close()
+	_, err = orig.SchemaVersion(ctx)
+	assert.Error(t, err, "the original snapshot must not be migrated")
+}
@@ -0,0 +1,133 @@
+//go:build cgo
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onedusk/pd/internal/graph"
+)
+
+// gitDiffFunc lists the files changed between rev and HEAD under dir, as
+// paths relative to dir.
+type gitDiffFunc func(ctx context.Context, dir, rev string) ([]string, error)
+
+// gitChangedFiles runs `git diff --relative --name-only <rev>...HEAD` in dir.
+// --relative keeps the paths relative to dir, matching the graph's paths
+// when dir is a subdirectory of the repository, and omits changes outside
+// it.
+func gitChangedFiles(ctx context.Context, dir, rev string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--relative", "--name-only", rev+"...HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s...HEAD: %s", rev, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git diff %s...HEAD: %w", rev, err)
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+func runImpact(ctx context.Context, projectRoot string, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("impact", flag.ContinueOnError)
+	since := fs.String("since", "", "git revision to diff against HEAD")
+	excludeGenerated := fs.Bool("exclude-generated", false, "leave generated files out of the affected lists")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+	if *since == "" {
+		return usageErrorf("usage: decompose impact --since <rev>")
+	}
+
+	graphPath := filepath.Join(projectRoot, ".decompose", "graph")
+	if _, err := os.Stat(graphPath); err != nil {
+		return fmt.Errorf("no graph found at %s\nRun 'build_graph' via MCP first to index the codebase", graphPath)
+	}
+
+	store, err := graph.NewKuzuFileStore(graphPath)
+	if err != nil {
+		return fmt.Errorf("open graph: %w", err)
+	}
+	defer store.Close()
+
+	var opts []graph.QueryOption
+	if *excludeGenerated {
+		opts = append(opts, graph.ExcludeGenerated())
+	}
+	return reportImpact(ctx, store, projectRoot, *since, gitChangedFiles, w, opts...)
+}
+
+// reportImpact maps the files changed since rev onto the graph, assesses
+// their impact, and prints the affected files and risk score to w. Changed
+// files the graph does not know are listed but left out of the assessment.
+// opts are passed to AssessImpact.
+func reportImpact(ctx context.Context, store graph.Store, projectRoot, rev string, diff gitDiffFunc, w io.Writer, opts ...graph.QueryOption) error {
+	changed, err := diff(ctx, projectRoot, rev)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Changed since %s: %d file(s)\n", rev, len(changed))
+	var indexed []string
+	for _, path := range changed {
+		node, err := store.GetFile(ctx, path)
+		if err != nil {
+			return fmt.Errorf("look up %s: %w", path, err)
+		}
+		if node == nil {
+			fmt.Fprintf(w, "  %s — not in graph (reindex?)\n", path)
+			continue
+		}
+		indexed = append(indexed, path)
+		fmt.Fprintf(w, "  %s\n", path)
+	}
+
+	if len(indexed) == 0 {
+		fmt.Fprintln(w, "\nNo changed files are in the graph.")
+		return nil
+	}
+
+	impact, err := store.AssessImpact(ctx, indexed, opts...)
+	if err != nil {
+		return fmt.Errorf("assess impact: %w", err)
+	}
+
+	printFileList(w, "Directly affected", impact.DirectlyAffected)
+	printFileList(w, "Transitively affected", impact.TransitivelyAffected)
+	fmt.Fprintf(w, "\nRisk score: %.2f\n", impact.RiskScore)
+	return nil
+}
+
+// printFileList prints a titled, sorted list of files, or "none".
+func printFileList(w io.Writer, title string, files []string) {
+	fmt.Fprintf(w, "\n%s (%d):\n", title, len(files))
+	if len(files) == 0 {
+		fmt.Fprintln(w, "  none")
+		return
+	}
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+	for _, f := range sorted {
+		fmt.Fprintf(w, "  %s\n", f)
+	}
+}
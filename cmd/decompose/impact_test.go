@@ -0,0 +1,100 @@
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/onedusk/pd/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportImpact_ListsDependentsOfChangedFile(t *testing.T) {
+	ctx := context.Background()
+	store := graph.NewMemStore()
+	require.NoError(t, store.InitSchema(ctx))
+	for _, path := range []string{"core.go", "service.go", "handler.go"} {
+		require.NoError(t, store.AddFile(ctx, graph.FileNode{Path: path, Language: graph.LangGo}))
+	}
+	// handler.go imports service.go, which imports core.go.
+	require.NoError(t, store.AddEdge(ctx, graph.Edge{SourceID: "service.go", TargetID: "core.go", Kind: graph.EdgeKindImports}))
+	require.NoError(t, store.AddEdge(ctx, graph.Edge{SourceID: "handler.go", TargetID: "service.go", Kind: graph.EdgeKindImports}))
+
+	var gotDir, gotRev string
+	fakeDiff := func(_ context.Context, dir, rev string) ([]string, error) {
+		gotDir, gotRev = dir, rev
+		return []string{"core.go", "README.md"}, nil
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, reportImpact(ctx, store, "/repo", "main", fakeDiff, &out))
+	assert.Equal(t, "/repo", gotDir)
+	assert.Equal(t, "main", gotRev)
+
+	text := out.String()
+	assert.Contains(t, text, "Changed since main: 2 file(s)\n")
+	assert.Contains(t, text, "  README.md — not in graph (reindex?)\n")
+	assert.Contains(t, text, "Directly affected (1):\n  service.go\n")
+	assert.Contains(t, text, "  handler.go\n")
+	assert.Contains(t, text, "Risk score: ")
+}
+
+func TestReportImpact_NothingIndexed(t *testing.T) {
+	ctx := context.Background()
+	store := graph.NewMemStore()
+	require.NoError(t, store.InitSchema(ctx))
+
+	fakeDiff := func(context.Context, string, string) ([]string, error) {
+		return []string{"new.go"}, nil
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, reportImpact(ctx, store, "/repo", "HEAD~1", fakeDiff, &out))
+	assert.Contains(t, out.String(), "new.go — not in graph (reindex?)")
+	assert.Contains(t, out.String(), "No changed files are in the graph.")
+	assert.NotContains(t, out.String(), "Risk score")
+}
+
+// TestGitChangedFiles_Subdirectory verifies that run from a subdirectory of
+// the repository, gitChangedFiles lists paths relative to it and leaves out
+// changes elsewhere.
+func TestGitChangedFiles_Subdirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(repo, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	write("service/core.go", "package service\n")
+	write("README.md", "readme\n")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	git("tag", "base")
+
+	write("service/core.go", "package service\n\nfunc Run() {}\n")
+	write("README.md", "changed\n")
+	git("commit", "-q", "-am", "change")
+
+	files, err := gitChangedFiles(context.Background(), filepath.Join(repo, "service"), "base")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core.go"}, files)
+}
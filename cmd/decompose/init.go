@@ -2,7 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
@@ -36,22 +39,135 @@ func buildMCPEntry(projectRoot string) json.RawMessage {
 	return json.RawMessage(data)
 }
 
+// initActionKind is what runInit does to one file.
+type initActionKind string
+
+const (
+	initCreate    initActionKind = "create"
+	initOverwrite initActionKind = "overwrite"
+	initUpdate    initActionKind = "update"
+	initSkip      initActionKind = "skip"
+)
+
+// done returns the past tense of k for reporting applied actions.
+func (k initActionKind) done() string {
+	switch k {
+	case initCreate:
+		return "created"
+	case initOverwrite:
+		return "overwrote"
+	case initUpdate:
+		return "updated"
+	default:
+		return "skipped"
+	}
+}
+
+// initAction is one file change planned by planInit and carried out by
+// applyInit.
+type initAction struct {
+	Kind initActionKind
+	Path string // absolute destination path
+	Note string // detail shown after the path; the reason, for skips
+
+	Old  []byte // current content of a merged file, nil if it does not exist
+	New  []byte // content to write; nil for skips
+	Mode fs.FileMode
+
+	// Merge marks files whose existing content is merged rather than
+	// replaced; dry runs show a diff for them.
+	Merge bool
+	// Warn makes a failure to write the file a warning instead of an error.
+	Warn bool
+}
+
 // runInit installs the decompose skill files and MCP configuration into the
-// target project directory.
-func runInit(projectRoot string, force bool) error {
+// target project directory. With --dry-run it prints the planned actions,
+// with diffs for merged files, and leaves the disk untouched.
+func runInit(projectRoot string, force bool, args []string, w io.Writer) error {
+	fset := flag.NewFlagSet("init", flag.ContinueOnError)
+	fset.BoolVar(&force, "force", force, "overwrite existing files")
+	dryRun := fset.Bool("dry-run", false, "print the planned file changes without writing them")
+	if err := fset.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
 	abs, err := filepath.Abs(projectRoot)
 	if err != nil {
 		return fmt.Errorf("resolving project root: %w", err)
 	}
 
+	actions, err := planInit(abs, force)
+	if err != nil {
+		return err
+	}
+
+	// Check for hook dependencies.
+	if _, err := exec.LookPath("jq"); err != nil {
+		fmt.Fprintln(os.Stderr, "  note: the augmentation hook requires 'jq' (not found in PATH)")
+		fmt.Fprintln(os.Stderr, "        install with: brew install jq (macOS) or apt install jq (Linux)")
+	}
+
+	if *dryRun {
+		printInitPlan(w, abs, actions)
+		return nil
+	}
+	if err := applyInit(w, abs, actions); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nSetup complete. The /decompose skill and MCP server are ready.")
+	return nil
+}
+
+// planInit works out the file changes init makes in the project at abs. It
+// only reads from disk.
+func planInit(abs string, force bool) ([]initAction, error) {
+	var actions []initAction
+
+	// --- Embedded skill files and hook scripts ---
+
 	skillDir := filepath.Join(abs, ".claude", "skills", "decompose")
-	mcpPath := filepath.Join(abs, ".mcp.json")
+	skills, err := planEmbeddedFiles(skilldata.SkillFS, "skill/decompose", skillDir, 0o644, force)
+	if err != nil {
+		return nil, fmt.Errorf("copying skill files: %w", err)
+	}
+	actions = append(actions, skills...)
+
+	hooksDir := filepath.Join(abs, ".claude", "hooks")
+	hooks, err := planEmbeddedFiles(skilldata.HooksFS, "hooks", hooksDir, 0o755, force)
+	if err != nil {
+		return nil, fmt.Errorf("copying hook files: %w", err)
+	}
+	actions = append(actions, hooks...)
 
-	// --- Copy embedded skill files ---
+	// --- Merged configuration files ---
 
-	root := "skill/decompose"
-	err = fs.WalkDir(skilldata.SkillFS, root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
+	mcp, err := planMCPConfig(filepath.Join(abs, ".mcp.json"), abs, force)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := planSettings(filepath.Join(abs, ".claude", "settings.json"), force)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions,
+		mcp,
+		settings,
+		planClaudeMD(filepath.Join(abs, "CLAUDE.md")),
+		planGitignore(filepath.Join(abs, ".gitignore"), ".decompose/"),
+	)
+	return actions, nil
+}
+
+// planEmbeddedFiles plans copying every file under root in fsys to destDir,
+// skipping files that already exist unless force is set.
+func planEmbeddedFiles(fsys fs.FS, root, destDir string, mode fs.FileMode, force bool) ([]initAction, error) {
+	var actions []initAction
+	err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
 			return err
 		}
 
@@ -60,122 +176,153 @@ func runInit(projectRoot string, force bool) error {
 		if err != nil {
 			return err
 		}
+		dest := filepath.Join(destDir, rel)
 
-		dest := filepath.Join(skillDir, rel)
-
-		if d.IsDir() {
-			return os.MkdirAll(dest, 0o755)
-		}
-
-		// Check if file already exists.
-		if !force {
-			if _, err := os.Stat(dest); err == nil {
-				fmt.Printf("  skipped %s (exists, use --force to overwrite)\n", dotRelative(abs, dest))
+		kind := initCreate
+		if _, err := os.Stat(dest); err == nil {
+			if !force {
+				actions = append(actions, initAction{Kind: initSkip, Path: dest, Note: "exists, use --force to overwrite"})
 				return nil
 			}
+			kind = initOverwrite
 		}
 
-		data, err := skilldata.SkillFS.ReadFile(path)
+		data, err := fs.ReadFile(fsys, path)
 		if err != nil {
 			return fmt.Errorf("reading embedded %s: %w", path, err)
 		}
-
-		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
-			return err
-		}
-		if err := os.WriteFile(dest, data, 0o644); err != nil {
-			return fmt.Errorf("writing %s: %w", dest, err)
-		}
-
-		fmt.Printf("  created %s\n", dotRelative(abs, dest))
+		actions = append(actions, initAction{Kind: kind, Path: dest, New: data, Mode: mode})
 		return nil
 	})
-	if err != nil {
-		return fmt.Errorf("copying skill files: %w", err)
+	return actions, err
+}
+
+// mergedAction returns the action that writes content to path, which
+// currently holds old (nil if it does not exist).
+func mergedAction(path string, old, content []byte, note string) initAction {
+	kind := initCreate
+	if old != nil {
+		kind = initUpdate
 	}
+	return initAction{Kind: kind, Path: path, Note: note, Old: old, New: content, Mode: 0o644, Merge: true}
+}
 
-	// --- Install hook scripts ---
+// readExisting returns the content of path, or nil if it cannot be read.
+func readExisting(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return data
+}
 
-	hooksDir := filepath.Join(abs, ".claude", "hooks")
-	err = fs.WalkDir(skilldata.HooksFS, "hooks", func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
+// applyInit carries out actions, reporting each one to w. Failures of
+// actions marked Warn are reported on stderr and do not stop the others.
+func applyInit(w io.Writer, abs string, actions []initAction) error {
+	for _, a := range actions {
+		display := dotRelative(abs, a.Path)
+		if a.Kind == initSkip {
+			fmt.Fprintf(w, "  skipped %s (%s)\n", display, a.Note)
+			continue
 		}
-		if d.IsDir() {
-			return os.MkdirAll(hooksDir, 0o755)
-		}
-
-		dest := filepath.Join(hooksDir, d.Name())
 
-		if !force {
-			if _, statErr := os.Stat(dest); statErr == nil {
-				fmt.Printf("  skipped %s (exists, use --force to overwrite)\n", dotRelative(abs, dest))
-				return nil
+		err := os.MkdirAll(filepath.Dir(a.Path), 0o755)
+		if err == nil {
+			err = os.WriteFile(a.Path, a.New, a.Mode)
+		}
+		if err != nil {
+			if a.Warn {
+				fmt.Fprintf(os.Stderr, "warning: could not update %s: %v\n", display, err)
+				continue
 			}
+			return fmt.Errorf("writing %s: %w", a.Path, err)
 		}
 
-		data, readErr := skilldata.HooksFS.ReadFile(path)
-		if readErr != nil {
-			return fmt.Errorf("reading embedded %s: %w", path, readErr)
+		if a.Note != "" {
+			fmt.Fprintf(w, "  %s %s %s\n", a.Kind.done(), display, a.Note)
+		} else {
+			fmt.Fprintf(w, "  %s %s\n", a.Kind.done(), display)
 		}
+	}
+	return nil
+}
 
-		if mkErr := os.MkdirAll(filepath.Dir(dest), 0o755); mkErr != nil {
-			return mkErr
+// printInitPlan writes the actions a dry run would take, followed for each
+// merged file by a diff of its content before and after.
+func printInitPlan(w io.Writer, abs string, actions []initAction) {
+	fmt.Fprintln(w, "Dry run: no files will be changed.")
+	for _, a := range actions {
+		display := dotRelative(abs, a.Path)
+		switch {
+		case a.Kind == initSkip:
+			fmt.Fprintf(w, "  %-9s %s (%s)\n", a.Kind, display, a.Note)
+		case a.Note != "":
+			fmt.Fprintf(w, "  %-9s %s %s\n", a.Kind, display, a.Note)
+		default:
+			fmt.Fprintf(w, "  %-9s %s\n", a.Kind, display)
 		}
-		if writeErr := os.WriteFile(dest, data, 0o755); writeErr != nil {
-			return fmt.Errorf("writing %s: %w", dest, writeErr)
+		if a.Merge && a.Kind != initSkip {
+			for _, line := range diffLines(string(a.Old), string(a.New)) {
+				fmt.Fprintf(w, "      %s\n", line)
+			}
 		}
-
-		fmt.Printf("  created %s\n", dotRelative(abs, dest))
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("copying hook files: %w", err)
 	}
+}
 
-	// Check for hook dependencies.
-	if _, err := exec.LookPath("jq"); err != nil {
-		fmt.Fprintln(os.Stderr, "  note: the augmentation hook requires 'jq' (not found in PATH)")
-		fmt.Fprintln(os.Stderr, "        install with: brew install jq (macOS) or apt install jq (Linux)")
+// diffLines returns a line diff turning before into after: unchanged lines
+// are prefixed with "  ", removed ones with "- ", and added ones with "+ ".
+func diffLines(before, after string) []string {
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
 	}
+	a, b := split(before), split(after)
 
-	// --- Create/merge .mcp.json ---
-
-	if err := mergeMCPConfig(mcpPath, abs, force); err != nil {
-		return err
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
 	}
-
-	// --- Create/merge .claude/settings.json with hook config ---
-
-	settingsPath := filepath.Join(abs, ".claude", "settings.json")
-	if err := mergeSettings(settingsPath, force); err != nil {
-		return err
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
 	}
 
-	// --- Append decompose block to CLAUDE.md ---
-
-	claudeMDPath := filepath.Join(abs, "CLAUDE.md")
-	if err := mergeClaudeMD(claudeMDPath, abs); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: could not update CLAUDE.md: %v\n", err)
+	var out []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out = append(out, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "- "+a[i])
+			i++
+		default:
+			out = append(out, "+ "+b[j])
+			j++
+		}
 	}
-
-	// --- Add .decompose/ to .gitignore ---
-
-	gitignorePath := filepath.Join(abs, ".gitignore")
-	addToGitignore(gitignorePath, ".decompose/")
-
-	fmt.Println("\nSetup complete. The /decompose skill and MCP server are ready.")
-	return nil
+	return out
 }
 
-// mergeMCPConfig creates or merges the decompose entry into .mcp.json.
-func mergeMCPConfig(mcpPath, projectRoot string, force bool) error {
+// planMCPConfig plans creating or merging the decompose entry into .mcp.json.
+func planMCPConfig(mcpPath, projectRoot string, force bool) (initAction, error) {
 	var cfg mcpConfig
 
-	data, err := os.ReadFile(mcpPath)
-	if err == nil {
+	data := readExisting(mcpPath)
+	if data != nil {
 		if err := json.Unmarshal(data, &cfg); err != nil {
-			return fmt.Errorf("parsing %s: %w", mcpPath, err)
+			return initAction{}, fmt.Errorf("parsing %s: %w", mcpPath, err)
 		}
 	}
 
@@ -184,27 +331,16 @@ func mergeMCPConfig(mcpPath, projectRoot string, force bool) error {
 	}
 
 	if _, exists := cfg.MCPServers["decompose"]; exists && !force {
-		fmt.Printf("  skipped .mcp.json decompose entry (exists, use --force to overwrite)\n")
-		return nil
+		return initAction{Kind: initSkip, Path: mcpPath, Note: "decompose entry exists, use --force to overwrite"}, nil
 	}
 
 	cfg.MCPServers["decompose"] = buildMCPEntry(projectRoot)
 
 	out, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling .mcp.json: %w", err)
+		return initAction{}, fmt.Errorf("marshaling .mcp.json: %w", err)
 	}
-
-	if err := os.WriteFile(mcpPath, append(out, '\n'), 0o644); err != nil {
-		return fmt.Errorf("writing %s: %w", mcpPath, err)
-	}
-
-	action := "created"
-	if data != nil {
-		action = "updated"
-	}
-	fmt.Printf("  %s .mcp.json with decompose MCP server\n", action)
-	return nil
+	return mergedAction(mcpPath, data, append(out, '\n'), "with decompose MCP server"), nil
 }
 
 // settingsConfig represents the structure of .claude/settings.json.
@@ -213,8 +349,9 @@ type settingsConfig struct {
 	Rest  map[string]json.RawMessage `json:"-"` // preserve unknown keys
 }
 
-// mergeSettings creates or merges the hook configuration into .claude/settings.json.
-func mergeSettings(settingsPath string, force bool) error {
+// planSettings plans creating or merging the hook configuration into
+// .claude/settings.json.
+func planSettings(settingsPath string, force bool) (initAction, error) {
 	hookConfig := json.RawMessage(`[
     {
       "matcher": "Read|Write|Edit|Glob|Grep|Bash",
@@ -230,10 +367,10 @@ func mergeSettings(settingsPath string, force bool) error {
 
 	// Read existing file.
 	var raw map[string]json.RawMessage
-	data, err := os.ReadFile(settingsPath)
-	if err == nil {
+	data := readExisting(settingsPath)
+	if data != nil {
 		if jsonErr := json.Unmarshal(data, &raw); jsonErr != nil {
-			return fmt.Errorf("parsing %s: %w", settingsPath, jsonErr)
+			return initAction{}, fmt.Errorf("parsing %s: %w", settingsPath, jsonErr)
 		}
 	}
 	if raw == nil {
@@ -242,8 +379,7 @@ func mergeSettings(settingsPath string, force bool) error {
 
 	// Check if hooks already exist.
 	if _, exists := raw["hooks"]; exists && !force {
-		fmt.Printf("  skipped %s hooks (exists, use --force to overwrite)\n", dotRelative(filepath.Dir(filepath.Dir(settingsPath)), settingsPath))
-		return nil
+		return initAction{Kind: initSkip, Path: settingsPath, Note: "hooks exist, use --force to overwrite"}, nil
 	}
 
 	// Merge hooks key.
@@ -261,18 +397,9 @@ func mergeSettings(settingsPath string, force bool) error {
 
 	out, err := json.MarshalIndent(raw, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling settings: %w", err)
-	}
-
-	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o755); err != nil {
-		return err
+		return initAction{}, fmt.Errorf("marshaling settings: %w", err)
 	}
-	if err := os.WriteFile(settingsPath, append(out, '\n'), 0o644); err != nil {
-		return fmt.Errorf("writing %s: %w", settingsPath, err)
-	}
-
-	fmt.Printf("  created %s with hook config\n", dotRelative(filepath.Dir(filepath.Dir(settingsPath)), settingsPath))
-	return nil
+	return mergedAction(settingsPath, data, append(out, '\n'), "with hook config"), nil
 }
 
 const claudeMDMarkerStart = "<!-- decompose:start -->"
@@ -295,13 +422,11 @@ For the /decompose skill specifically:
 - ` + "`mcp__decompose__get_status`" + ` — check decomposition progress
 <!-- decompose:end -->`
 
-// mergeClaudeMD appends or replaces the decompose block in CLAUDE.md.
-func mergeClaudeMD(claudeMDPath, projectRoot string) error {
-	data, err := os.ReadFile(claudeMDPath)
-	content := ""
-	if err == nil {
-		content = string(data)
-	}
+// planClaudeMD plans appending or replacing the decompose block in
+// CLAUDE.md.
+func planClaudeMD(claudeMDPath string) initAction {
+	data := readExisting(claudeMDPath)
+	content := string(data)
 
 	// Check if block already exists — replace it.
 	if strings.Contains(content, claudeMDMarkerStart) {
@@ -321,24 +446,18 @@ func mergeClaudeMD(claudeMDPath, projectRoot string) error {
 		content += claudeMDBlock + "\n"
 	}
 
-	if err := os.WriteFile(claudeMDPath, []byte(content), 0o644); err != nil {
-		return err
-	}
-
-	fmt.Printf("  updated %s with decompose block\n", dotRelative(projectRoot, claudeMDPath))
-	return nil
+	action := mergedAction(claudeMDPath, data, []byte(content), "with decompose block")
+	action.Warn = true
+	return action
 }
 
-// addToGitignore adds a pattern to .gitignore if not already present.
-func addToGitignore(gitignorePath, pattern string) {
-	data, err := os.ReadFile(gitignorePath)
-	content := ""
-	if err == nil {
-		content = string(data)
-	}
+// planGitignore plans adding a pattern to .gitignore if not already present.
+func planGitignore(gitignorePath, pattern string) initAction {
+	data := readExisting(gitignorePath)
+	content := string(data)
 
 	if strings.Contains(content, pattern) {
-		return
+		return initAction{Kind: initSkip, Path: gitignorePath, Note: "already ignores " + pattern}
 	}
 
 	if content != "" && !strings.HasSuffix(content, "\n") {
@@ -346,9 +465,9 @@ func addToGitignore(gitignorePath, pattern string) {
 	}
 	content += pattern + "\n"
 
-	if err := os.WriteFile(gitignorePath, []byte(content), 0o644); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: could not update .gitignore: %v\n", err)
-	}
+	action := mergedAction(gitignorePath, data, []byte(content), "to ignore "+pattern)
+	action.Warn = true
+	return action
 }
 
 // dotRelative returns a display path relative to the project root, prefixed
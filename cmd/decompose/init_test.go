@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotDir returns the content of every file under dir, keyed by path
+// relative to dir.
+func snapshotDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[rel] = string(data)
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func TestRunInit_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	writeFile(".mcp.json", `{"mcpServers": {"other": {"type": "stdio", "command": "other"}}}`)
+	writeFile("CLAUDE.md", "# Project\n")
+	writeFile(".claude/skills/decompose/SKILL.md", "local edits\n")
+	writeFile(".gitignore", "bin/\n.decompose/\n")
+
+	before := snapshotDir(t, dir)
+
+	var out bytes.Buffer
+	require.NoError(t, runInit(dir, false, []string{"--dry-run"}, &out))
+	assert.Equal(t, before, snapshotDir(t, dir), "dry run must not change the project")
+
+	got := out.String()
+	assert.Contains(t, got, "Dry run: no files will be changed.")
+	assert.Contains(t, got, "  skip      ./.claude/skills/decompose/SKILL.md (exists, use --force to overwrite)")
+	assert.Contains(t, got, "  create    ./.claude/hooks/decompose-tool-guard.sh")
+	assert.Contains(t, got, "  create    ./.claude/settings.json with hook config")
+	assert.Contains(t, got, "  skip      ./.gitignore (already ignores .decompose/)")
+
+	// Merged files show a diff against their current content.
+	assert.Contains(t, got, "  update    ./.mcp.json with decompose MCP server")
+	assert.Contains(t, got, `      - {"mcpServers": {"other": {"type": "stdio", "command": "other"}}}`)
+	assert.Contains(t, got, `      +     "decompose": {`)
+	assert.Contains(t, got, "  update    ./CLAUDE.md with decompose block")
+	assert.Contains(t, got, "        # Project\n")
+	assert.Contains(t, got, "      + <!-- decompose:start -->\n")
+
+	// With --force, existing skill files would be overwritten.
+	out.Reset()
+	require.NoError(t, runInit(dir, false, []string{"--dry-run", "--force"}, &out))
+	assert.Contains(t, out.String(), "  overwrite ./.claude/skills/decompose/SKILL.md")
+	assert.Equal(t, before, snapshotDir(t, dir))
+}
+
+func TestRunInit_AppliesPlan(t *testing.T) {
+	dir := t.TempDir()
+
+	var out bytes.Buffer
+	require.NoError(t, runInit(dir, false, nil, &out))
+	assert.Contains(t, out.String(), "  created ./.claude/skills/decompose/SKILL.md\n")
+	assert.Contains(t, out.String(), "  created ./.mcp.json with decompose MCP server\n")
+
+	files := snapshotDir(t, dir)
+	assert.Contains(t, files, filepath.Join(".claude", "hooks", "decompose-tool-guard.sh"))
+	assert.Contains(t, files[".mcp.json"], `"decompose"`)
+	assert.Contains(t, files["CLAUDE.md"], claudeMDMarkerStart)
+	assert.Equal(t, ".decompose/\n", files[".gitignore"])
+
+	// A second run plans no changes beyond the always-refreshed CLAUDE.md.
+	out.Reset()
+	require.NoError(t, runInit(dir, false, []string{"--dry-run"}, &out))
+	assert.NotContains(t, out.String(), "  create ")
+	assert.Contains(t, out.String(), "  skip      ./.mcp.json (decompose entry exists, use --force to overwrite)")
+}
+
+func TestDiffLines(t *testing.T) {
+	assert.Equal(t, []string{"  a", "- b", "+ c", "  d"}, diffLines("a\nb\nd\n", "a\nc\nd\n"))
+	assert.Equal(t, []string{"+ x"}, diffLines("", "x\n"))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// runListSkills discovers each configured agent and prints its skills.
+// Skills offered by several agents are listed once, under the first agent
+// that offers them, with the other agents noted alongside. Endpoints that
+// cannot be discovered are reported with their error.
+func runListSkills(ctx context.Context, client a2a.Client, flags cliFlags, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("list-skills", flag.ContinueOnError)
+	fs.StringVar(&flags.Agents, "agents", flags.Agents, "comma-separated agent endpoint URLs")
+	fs.StringVar(&flags.AgentsFile, "agents-file", flags.AgentsFile, "file listing agent endpoints")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
+	_, endpoints, headers, err := explicitAgents(flags)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return usageErrorf("usage: decompose list-skills --agents <url1,url2,...>")
+	}
+	if len(headers) > 0 {
+		client = a2a.NewHTTPClient(a2a.WithEndpointHeaders(headers))
+	}
+
+	type discovered struct {
+		endpoint string
+		card     *a2a.AgentCard
+		err      error
+	}
+	agents := make([]discovered, len(endpoints))
+	offeredBy := make(map[string][]string)
+	for i, ep := range endpoints {
+		card, err := client.DiscoverAgent(ctx, ep)
+		agents[i] = discovered{endpoint: ep, card: card, err: err}
+		if err != nil {
+			continue
+		}
+		for _, s := range card.Skills {
+			offeredBy[s.ID] = append(offeredBy[s.ID], card.Name)
+		}
+	}
+
+	listed := make(map[string]bool)
+	for _, a := range agents {
+		if a.err != nil {
+			fmt.Fprintf(w, "%s — unreachable: %v\n", a.endpoint, a.err)
+			continue
+		}
+		fmt.Fprintf(w, "%s (%s)\n", a.card.Name, a.endpoint)
+		printed := 0
+		for _, s := range a.card.Skills {
+			if listed[s.ID] {
+				continue
+			}
+			listed[s.ID] = true
+			printed++
+			line := fmt.Sprintf("  %-20s %-24s [%s]", s.ID, s.Name, strings.Join(s.Tags, ", "))
+			if others := offeredBy[s.ID][1:]; len(others) > 0 {
+				line += fmt.Sprintf("  (also: %s)", strings.Join(others, ", "))
+			}
+			fmt.Fprintln(w, line)
+		}
+		if printed == 0 {
+			fmt.Fprintln(w, "  (no additional skills)")
+		}
+	}
+	return nil
+}
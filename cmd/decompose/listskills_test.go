@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cardServer returns an endpoint that serves card at the well-known URI.
+func cardServer(t *testing.T, card a2a.AgentCard) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(card))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestRunListSkills_DedupesSharedSkills(t *testing.T) {
+	research := cardServer(t, a2a.AgentCard{
+		Name: "research-agent",
+		Skills: []a2a.AgentSkill{
+			{ID: "explore", Name: "Explore", Tags: []string{"codebase"}},
+			{ID: "summarize", Name: "Summarize", Tags: []string{"text"}},
+		},
+	})
+	planning := cardServer(t, a2a.AgentCard{
+		Name: "planning-agent",
+		Skills: []a2a.AgentSkill{
+			{ID: "summarize", Name: "Summarize", Tags: []string{"text"}},
+			{ID: "plan", Name: "Plan", Tags: []string{"milestones", "phases"}},
+		},
+	})
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	var out bytes.Buffer
+	err := runListSkills(context.Background(), a2a.NewHTTPClient(), cliFlags{},
+		[]string{"--agents", research.URL + "," + planning.URL + "," + down.URL}, &out)
+	require.NoError(t, err)
+
+	want := "research-agent (" + research.URL + ")\n" +
+		"  explore              Explore                  [codebase]\n" +
+		"  summarize            Summarize                [text]  (also: planning-agent)\n" +
+		"planning-agent (" + planning.URL + ")\n" +
+		"  plan                 Plan                     [milestones, phases]\n"
+	assert.Equal(t, want, out.String()[:len(want)])
+	assert.Contains(t, out.String(), down.URL+" — unreachable: ")
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("summarize")))
+}
+
+func TestRunListSkills_RequiresAgents(t *testing.T) {
+	err := runListSkills(context.Background(), a2a.NewHTTPClient(), cliFlags{}, nil, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "usage: decompose list-skills")
+}
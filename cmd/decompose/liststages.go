@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/onedusk/pd/internal/config"
+	"github.com/onedusk/pd/internal/orchestrator"
+)
+
+// runListStages prints the effective pipeline: the built-in stages, or the
+// custom ones from decompose.yml, resolved the way the Router runs them.
+// With --mermaid it also prints the prerequisite DAG as a Mermaid graph,
+// drawing optional prerequisites as dashed edges.
+func runListStages(projectRoot string, projCfg *config.ProjectConfig, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("list-stages", flag.ContinueOnError)
+	mermaid := fs.Bool("mermaid", false, "also print the stage DAG as a Mermaid graph")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+
+	stages, err := stageDefinitions(projCfg)
+	if err != nil {
+		return err
+	}
+	cfg := orchestrator.Config{ProjectRoot: projectRoot, Stages: stages}
+	infos, err := cfg.ResolveStages()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%-6s %-26s %-9s %-24s %s\n", "STAGE", "NAME", "SECTIONS", "TEMPLATE", "PREREQUISITES")
+	for _, info := range infos {
+		template := info.Template
+		if template == "" {
+			template = "(built-in)"
+		}
+		var pres []string
+		for _, pre := range info.Prerequisites {
+			name := cfg.StageName(pre.Stage)
+			if !pre.Required {
+				name += " (optional)"
+			}
+			pres = append(pres, name)
+		}
+		prereqs := strings.Join(pres, ", ")
+		if prereqs == "" {
+			prereqs = "-"
+		}
+		fmt.Fprintf(w, "%-6d %-26s %-9d %-24s %s\n", info.Stage, info.Name, len(info.Sections), template, prereqs)
+	}
+
+	if *mermaid {
+		fmt.Fprintln(w)
+		fmt.Fprint(w, stagesMermaid(infos))
+	}
+	return nil
+}
+
+// stagesMermaid renders the stage prerequisite DAG as a Mermaid graph, with
+// an edge from each prerequisite to the stage that reads it.
+func stagesMermaid(infos []orchestrator.StageInfo) string {
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	for _, info := range infos {
+		fmt.Fprintf(&sb, "  S%d[\"%d: %s\"]\n", info.Stage, info.Stage, info.Name)
+	}
+	for _, info := range infos {
+		for _, pre := range info.Prerequisites {
+			arrow := "-->"
+			if !pre.Required {
+				arrow = "-.->"
+			}
+			fmt.Fprintf(&sb, "  S%d %s S%d\n", pre.Stage, arrow, info.Stage)
+		}
+	}
+	return sb.String()
+}
+
+// stageDefinitions converts the custom stages in decompose.yml into
+// orchestrator stage definitions and validates them. It returns nil when
+// the project uses the built-in stages.
+func stageDefinitions(projCfg *config.ProjectConfig) ([]orchestrator.StageDefinition, error) {
+	var stages []orchestrator.StageDefinition
+	for _, sc := range projCfg.Stages {
+		stages = append(stages, orchestrator.StageDefinition{
+			Name:          sc.Name,
+			Template:      sc.Template,
+			Prerequisites: sc.Prerequisites,
+			Merge:         orchestrator.MergeStrategy(sc.Merge),
+			Conflicts:     orchestrator.ConflictPolicy(sc.Conflicts),
+			OutputModes:   sc.OutputModes,
+		})
+	}
+	if err := orchestrator.ValidateStageDefinitions(stages); err != nil {
+		return nil, fmt.Errorf("decompose.yml stages: %w", err)
+	}
+	return stages, nil
+}
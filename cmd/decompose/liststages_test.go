@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onedusk/pd/internal/config"
+)
+
+func TestRunListStages_Default(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, runListStages(t.TempDir(), &config.ProjectConfig{}, []string{"--mermaid"}, &out))
+
+	lines := strings.Split(out.String(), "\n")
+	require.GreaterOrEqual(t, len(lines), 6)
+	wantStages := []struct{ name, prereqs string }{
+		{"development-standards", "-"},
+		{"design-pack", "development-standards (optional)"},
+		{"implementation-skeletons", "design-pack"},
+		{"task-index", "design-pack, implementation-skeletons"},
+		{"task-specifications", "task-index"},
+	}
+	for i, want := range wantStages {
+		fields := strings.Fields(lines[i+1])
+		require.GreaterOrEqual(t, len(fields), 5, lines[i+1])
+		assert.Equal(t, want.name, fields[1])
+		assert.True(t, strings.HasSuffix(lines[i+1], " "+want.prereqs), lines[i+1])
+	}
+
+	mermaid := out.String()[strings.Index(out.String(), "graph TD"):]
+	assert.Equal(t, "graph TD\n"+
+		"  S0[\"0: development-standards\"]\n"+
+		"  S1[\"1: design-pack\"]\n"+
+		"  S2[\"2: implementation-skeletons\"]\n"+
+		"  S3[\"3: task-index\"]\n"+
+		"  S4[\"4: task-specifications\"]\n"+
+		"  S0 -.-> S1\n"+
+		"  S1 --> S2\n"+
+		"  S1 --> S3\n"+
+		"  S2 --> S3\n"+
+		"  S3 --> S4\n", mermaid)
+}
+
+func TestRunListStages_Custom(t *testing.T) {
+	projCfg := &config.ProjectConfig{Stages: []config.StageConfig{
+		{Name: "research"},
+		{Name: "plan", Prerequisites: []string{"research"}},
+	}}
+	var out bytes.Buffer
+	require.NoError(t, runListStages(t.TempDir(), projCfg, nil, &out))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"0", "research"}, strings.Fields(lines[1])[:2])
+	assert.Equal(t, []string{"1", "plan"}, strings.Fields(lines[2])[:2])
+	assert.True(t, strings.HasSuffix(lines[2], " research"))
+
+	projCfg.Stages[1].Prerequisites = []string{"missing"}
+	assert.Error(t, runListStages(t.TempDir(), projCfg, nil, &out))
+}
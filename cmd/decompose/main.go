@@ -23,14 +23,34 @@ import (
 type cliFlags struct {
 	ProjectRoot      string
 	OutputDir        string
+	Layout           string
 	InputFile        string
+	InputDir         string
 	Agents           string
+	AgentsFile       string
+	Record           string
+	Replay           string
 	SingleAgent      bool
 	SkipVerification bool
+	Validate         bool
+	ValidateStrict   bool
 	ReviewMode       string
 	MaxConcurrent    int
+	MaxSections      int
+	MaxContextBytes  int
+	ParallelAgents   int
+	ProbeAttempts    int
+	FailFast         bool
+	KeepGoing        bool
+	MergeOnly        bool
+	Report           bool
+	WriteReport      bool
 	Verbose          bool
+	NoColor          bool
+	Profile          string
+	ProfileTrace     bool
 	ServeMCP         bool
+	AllowGraphQuery  bool
 	Force            bool
 	SkipReview       bool
 	Version          bool
@@ -42,7 +62,7 @@ var version = "dev"
 func main() {
 	if err := run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -52,16 +72,36 @@ func run(args []string) error {
 	fs := flag.NewFlagSet("decompose", flag.ContinueOnError)
 	fs.StringVar(&flags.ProjectRoot, "project-root", ".", "path to the target project")
 	fs.StringVar(&flags.OutputDir, "output-dir", "", "output directory for decomposition files")
+	fs.StringVar(&flags.Layout, "layout", "", "stage output layout: flat (default) or nested")
 	fs.StringVar(&flags.Agents, "agents", "", "comma-separated agent endpoint URLs")
+	fs.StringVar(&flags.AgentsFile, "agents-file", "", "file listing agent endpoints, one URL per line or a JSON array (merged with --agents)")
+	fs.StringVar(&flags.Record, "record", "", "record agent responses to a JSON file for later --replay")
+	fs.StringVar(&flags.Replay, "replay", "", "serve agent responses from a --record file instead of calling agents")
 	fs.BoolVar(&flags.SingleAgent, "single-agent", false, "force single-agent mode")
 	fs.BoolVar(&flags.Verbose, "verbose", false, "enable verbose output")
+	fs.BoolVar(&flags.NoColor, "no-color", false, "disable colored progress output (also honors NO_COLOR)")
 	fs.BoolVar(&flags.ServeMCP, "serve-mcp", false, "run as MCP server for Claude Code integration")
+	fs.BoolVar(&flags.AllowGraphQuery, "allow-graph-query", false, "with --serve-mcp, expose the graph_query tool for read-only Cypher against the code graph")
 	fs.StringVar(&flags.InputFile, "input", "", "path to a high-level input file (idea, spec, or plan) to seed Stage 1")
+	fs.StringVar(&flags.InputDir, "input-dir", "", "directory of .md/.txt input files, concatenated in filename order, to seed Stage 1 (exclusive with --input)")
 	fs.BoolVar(&flags.SkipVerification, "skip-verification", false, "skip post-stage verification")
+	fs.BoolVar(&flags.Validate, "validate", false, "compile the Go code blocks in Stage 2 output and report errors")
+	fs.BoolVar(&flags.ValidateStrict, "validate-strict", false, "like --validate, but fail Stage 2 when its Go does not compile")
 	fs.StringVar(&flags.ReviewMode, "review-mode", "cli", "review strategy for implement command: cli, pr, file")
 	fs.IntVar(&flags.MaxConcurrent, "max-concurrent", 3, "max parallel Claude Code sessions for implement command")
+	fs.IntVar(&flags.MaxSections, "max-sections-per-stage", 0, "max agent tasks per stage; extra sections are combined (0 = no cap)")
+	fs.IntVar(&flags.MaxContextBytes, "max-context-bytes", 0, "max bytes of prior-stage context per agent prompt; older sections are trimmed first (0 = no cap)")
+	fs.IntVar(&flags.ParallelAgents, "parallel-agents", 0, "max agent calls in flight across all stages (0 = no cap)")
+	fs.IntVar(&flags.ProbeAttempts, "probe-attempts", 0, "times each port is probed when auto-detecting agents, 1 to disable retries (0 = default 3)")
+	fs.BoolVar(&flags.Report, "report", false, "print a summary of each stage's status, duration, agents, and output to stderr after the run")
+	fs.BoolVar(&flags.WriteReport, "write-report", false, "like --report, and also write the summary to <output-dir>/report.md")
+	fs.BoolVar(&flags.MergeOnly, "merge-only", false, "rebuild stage files from the sections cached by the last agent run, without calling agents")
+	fs.BoolVar(&flags.FailFast, "fail-fast", false, "cancel a stage's remaining agent calls when one fails with a non-retryable error")
+	fs.BoolVar(&flags.KeepGoing, "keep-going", false, "continue past a failed stage, skipping only the stages that require it, and report all failures at the end")
 	fs.BoolVar(&flags.Force, "force", false, "overwrite existing files during init")
 	fs.BoolVar(&flags.SkipReview, "skip-review", false, "suppress review warnings when implementing")
+	fs.StringVar(&flags.Profile, "profile", "", "write CPU and heap pprof profiles for the run into this directory")
+	fs.BoolVar(&flags.ProfileTrace, "profile-trace", false, "with --profile, also write an execution trace")
 	fs.BoolVar(&flags.Version, "version", false, "print version and exit")
 
 	fs.Usage = func() { printUsage(fs) }
@@ -70,7 +110,7 @@ func run(args []string) error {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil // --help is not an error
 		}
-		return err
+		return &usageError{err}
 	}
 
 	if flags.Version {
@@ -78,6 +118,21 @@ func run(args []string) error {
 		return nil
 	}
 
+	if flags.ProfileTrace && flags.Profile == "" {
+		return usageErrorf("--profile-trace requires --profile")
+	}
+	if flags.Profile != "" {
+		prof, err := startProfile(flags.Profile, flags.ProfileTrace)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := prof.Stop(); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
+		}()
+	}
+
 	// Build Config from flags (project root needed for both MCP and CLI modes).
 	projectRoot := flags.ProjectRoot
 	if !filepath.IsAbs(projectRoot) {
@@ -108,10 +163,12 @@ func run(args []string) error {
 	// --serve-mcp: start unified MCP server on stdio with code intelligence.
 	if flags.ServeMCP {
 		cfg := orchestrator.Config{
-			ProjectRoot: projectRoot,
-			Capability:  orchestrator.CapMCPOnly,
-			SingleAgent: flags.SingleAgent,
-			Verbose:     flags.Verbose,
+			ProjectRoot:          projectRoot,
+			Capability:           orchestrator.CapMCPOnly,
+			SingleAgent:          flags.SingleAgent,
+			Verbose:              flags.Verbose,
+			SkipCoherence:        projCfg.SkipCoherence,
+			CoherenceMaxSections: projCfg.CoherenceMaxSections,
 		}
 		pipeline := orchestrator.NewPipeline(cfg, client)
 		defer pipeline.Close()
@@ -121,6 +178,8 @@ func run(args []string) error {
 		parser := graph.NewTreeSitterParser()
 		codeintel := mcptools.NewCodeIntelService(store, parser)
 		codeintel.SetProjectRoot(projectRoot)
+		codeintel.SetAllowGraphQuery(flags.AllowGraphQuery)
+		codeintel.SetParseCache(graph.NewParseCache())
 
 		fmt.Fprintf(os.Stderr, "decompose MCP server v%s starting on stdio (project: %s)\n", version, projectRoot)
 		server := mcptools.NewUnifiedMCPServer(pipeline, cfg, codeintel)
@@ -132,20 +191,43 @@ func run(args []string) error {
 	// Handle subcommands.
 	positional := fs.Args()
 	if len(positional) > 0 && positional[0] == "init" {
-		return runInit(projectRoot, flags.Force)
+		return runInit(projectRoot, flags.Force, positional[1:], os.Stdout)
 	}
 	if len(positional) > 0 && positional[0] == "status" {
-		name := ""
-		if len(positional) > 1 {
-			name = positional[1]
-		}
-		return runStatus(projectRoot, name)
+		return runStatus(projectRoot, positional[1:])
 	}
 	if len(positional) > 0 && positional[0] == "export" {
 		return runExport(projectRoot, positional[1:])
 	}
 	if len(positional) > 0 && positional[0] == "diagram" {
-		return runDiagram(projectRoot)
+		return runDiagram(projectRoot, positional[1:])
+	}
+	if len(positional) > 0 && positional[0] == "impact" {
+		return runImpact(ctx, projectRoot, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "graph-diff" {
+		return runGraphDiff(ctx, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "graph" {
+		return runGraph(ctx, projectRoot, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "compact" {
+		return runCompact(ctx, projectRoot, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "cancel" {
+		return runCancel(ctx, client, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "task-status" {
+		return runTaskStatus(ctx, client, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "list-skills" {
+		return runListSkills(ctx, client, flags, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "list-stages" {
+		return runListStages(projectRoot, projCfg, positional[1:], os.Stdout)
+	}
+	if len(positional) > 0 && positional[0] == "agent-card" {
+		return runAgentCard(ctx, client, positional[1:], os.Stdout)
 	}
 	if len(positional) > 0 && positional[0] == "augment" {
 		pattern := ""
@@ -156,19 +238,19 @@ func run(args []string) error {
 	}
 	if len(positional) > 0 && positional[0] == "review" {
 		if len(positional) < 2 {
-			return fmt.Errorf("usage: decompose review <name>")
+			return usageErrorf("usage: decompose review <name>")
 		}
 		return runReview(ctx, projectRoot, positional[1], flags)
 	}
 	if len(positional) > 0 && positional[0] == "review-interpret" {
 		if len(positional) < 2 {
-			return fmt.Errorf("usage: decompose review-interpret <name>")
+			return usageErrorf("usage: decompose review-interpret <name>")
 		}
 		return runReviewInterpret(ctx, projectRoot, positional[1], flags)
 	}
 	if len(positional) > 0 && positional[0] == "implement" {
 		if len(positional) < 2 {
-			return fmt.Errorf("usage: decompose implement <name>")
+			return usageErrorf("usage: decompose implement <name>")
 		}
 		return runImplement(ctx, projectRoot, positional[1], flags)
 	}
@@ -176,7 +258,7 @@ func run(args []string) error {
 	// Positional args: [name] [stage]
 	if len(positional) < 1 {
 		printUsage(fs)
-		return fmt.Errorf("missing command or decomposition name")
+		return usageErrorf("missing command or decomposition name")
 	}
 	name := positional[0]
 
@@ -188,20 +270,72 @@ func run(args []string) error {
 		outputDir = filepath.Join(projectRoot, "docs", "decompose", name)
 	}
 
-	// Determine capability level: use explicit --agents flag or auto-detect.
+	maxSections := flags.MaxSections
+	if maxSections == 0 {
+		maxSections = projCfg.MaxSectionsPerStage
+	}
+
+	parallelAgents := flags.ParallelAgents
+	if parallelAgents == 0 {
+		parallelAgents = projCfg.ParallelAgents
+	}
+
+	layoutName := flags.Layout
+	if layoutName == "" {
+		layoutName = projCfg.Layout
+	}
+	layout, err := orchestrator.ParseLayoutMode(layoutName)
+	if err != nil {
+		return err
+	}
+
+	stages, err := stageDefinitions(projCfg)
+	if err != nil {
+		return err
+	}
+
+	seed, err := orchestrator.LoadSeedInput(flags.InputFile, flags.InputDir)
+	if err != nil {
+		return err
+	}
+
+	if flags.Record != "" && flags.Replay != "" {
+		return usageErrorf("--record and --replay are mutually exclusive")
+	}
+
+	// Determine capability level: use a --replay recording, explicit --agents
+	// or --agents-file endpoints, or auto-detect.
 	cap := orchestrator.CapBasic
 	var agentEndpoints []string
-	if flags.Agents != "" {
-		agentEndpoints = strings.Split(flags.Agents, ",")
-		for i := range agentEndpoints {
-			agentEndpoints[i] = strings.TrimSpace(agentEndpoints[i])
+	var pipelineClient a2a.Client = client
+	if flags.Replay != "" {
+		rec, err := orchestrator.LoadRecording(flags.Replay)
+		if err != nil {
+			return err
 		}
-		if len(agentEndpoints) > 0 {
-			cap = orchestrator.CapA2AMCP
+		cap = orchestrator.CapA2AMCP
+		agentEndpoints = rec.Endpoints
+		pipelineClient = orchestrator.NewReplayClient(rec)
+	} else if flags.Agents != "" || flags.AgentsFile != "" {
+		var headers map[string]map[string]string
+		cap, agentEndpoints, headers, err = explicitAgents(flags)
+		if err != nil {
+			return err
 		}
-	} else if !flags.SingleAgent {
+		if len(headers) > 0 {
+			client = a2a.NewHTTPClient(a2a.WithEndpointHeaders(headers))
+			pipelineClient = client
+		}
+	} else if !flags.SingleAgent && !flags.MergeOnly {
 		// Auto-detect capabilities.
-		detector := orchestrator.NewDefaultDetector(client, flags.SingleAgent)
+		var detectorOpts []orchestrator.DetectorOption
+		if flags.ProbeAttempts > 0 {
+			detectorOpts = append(detectorOpts, orchestrator.WithProbeRetry(flags.ProbeAttempts, orchestrator.DefaultProbeBackoff))
+		}
+		// The cards detection fetches are reused by the pipeline.
+		cards := a2a.NewCardCache(client)
+		pipelineClient = cards
+		detector := orchestrator.NewDefaultDetector(cards, flags.SingleAgent, detectorOpts...)
 		detectedCap, detectedAgents, err := detector.Detect(ctx)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "warning: capability detection failed: %v\n", err)
@@ -219,57 +353,99 @@ func run(args []string) error {
 		cap = orchestrator.CapBasic
 	}
 
+	var recorder *orchestrator.RecordingClient
+	if flags.Record != "" {
+		recorder = orchestrator.NewRecordingClient(pipelineClient, agentEndpoints)
+		pipelineClient = recorder
+	}
+
+	var goValidation *orchestrator.GoValidation
+	if flags.Validate || flags.ValidateStrict {
+		goValidation = &orchestrator.GoValidation{FailStage: flags.ValidateStrict}
+	}
+
 	cfg := orchestrator.Config{
-		Name:             name,
-		ProjectRoot:      projectRoot,
-		OutputDir:        outputDir,
-		InputFile:        flags.InputFile,
-		Capability:       cap,
-		AgentEndpoints:   agentEndpoints,
-		SingleAgent:      flags.SingleAgent,
-		SkipVerification: flags.SkipVerification,
-		Verbose:          flags.Verbose,
+		Name:                 name,
+		ProjectRoot:          projectRoot,
+		OutputDir:            outputDir,
+		InputFile:            flags.InputFile,
+		InputContent:         seed,
+		Capability:           cap,
+		AgentEndpoints:       agentEndpoints,
+		SingleAgent:          flags.SingleAgent,
+		SkipVerification:     flags.SkipVerification,
+		SkipCoherence:        projCfg.SkipCoherence,
+		CoherenceMaxSections: projCfg.CoherenceMaxSections,
+		Verbose:              flags.Verbose,
+		LayoutMode:           layout,
+		MaxSectionsPerStage:  maxSections,
+		MaxContextBytes:      flags.MaxContextBytes,
+		ParallelAgents:       parallelAgents,
+		FailFast:             flags.FailFast,
+		KeepGoing:            flags.KeepGoing,
+		MergeOnly:            flags.MergeOnly,
+		Stages:               stages,
+		GoValidation:         goValidation,
 	}
 
 	// Create pipeline.
-	pipeline := orchestrator.NewPipeline(cfg, client)
+	pipeline := orchestrator.NewPipeline(cfg, pipelineClient)
+
+	// Explicit agents were never probed; discover them all up front so an
+	// unreachable one is reported before any stage runs.
+	if flags.Replay == "" && !flags.SingleAgent && !flags.MergeOnly && (flags.Agents != "" || flags.AgentsFile != "") {
+		if err := pipeline.Warmup(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
 
-	// Drain progress events to stderr in a background goroutine.
+	// Drain progress events to stderr in a background goroutine, feeding
+	// the run report when one was requested.
+	formatter := orchestrator.NewProgressFormatter(os.Stderr, flags.NoColor)
+	var collector *orchestrator.ReportCollector
+	if flags.Report || flags.WriteReport {
+		collector = orchestrator.NewReportCollector()
+	}
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
 		for ev := range pipeline.Progress() {
-			fmt.Fprintln(os.Stderr, orchestrator.FormatProgress(ev))
+			if collector != nil {
+				collector.Observe(ev)
+			}
+			fmt.Fprintln(os.Stderr, formatter.Format(ev))
 		}
 	}()
 
 	// Determine whether to run a single stage or the full pipeline.
 	var runErr error
+	var runResults []orchestrator.StageResult
 	if len(positional) >= 2 {
 		stageNum, err := strconv.Atoi(positional[1])
 		if err != nil {
 			pipeline.Close()
 			<-done
-			return fmt.Errorf("invalid stage number %q: %w", positional[1], err)
+			return usageErrorf("invalid stage number %q: %w", positional[1], err)
 		}
-		if stageNum < 0 || stageNum > 4 {
+		if last := int(cfg.LastStage()); stageNum < 0 || stageNum > last {
 			pipeline.Close()
 			<-done
-			return fmt.Errorf("stage must be 0-4, got %d", stageNum)
+			return usageErrorf("stage must be 0-%d, got %d", last, stageNum)
 		}
 		result, err := pipeline.RunStage(ctx, orchestrator.Stage(stageNum))
 		if err != nil {
 			runErr = err
 		} else {
+			runResults = []orchestrator.StageResult{*result}
 			for _, p := range result.FilePaths {
 				fmt.Println(p)
 			}
 		}
 	} else {
-		results, err := pipeline.RunPipeline(ctx, orchestrator.StageDevelopmentStandards, orchestrator.StageTaskSpecifications)
-		if err != nil {
-			runErr = err
-		} else {
+		results, err := pipeline.RunPipeline(ctx, orchestrator.StageDevelopmentStandards, cfg.LastStage())
+		runResults, runErr = results, err
+		// With --keep-going the stages that succeeded are still reported.
+		if err == nil || cfg.KeepGoing {
 			for _, r := range results {
 				for _, p := range r.FilePaths {
 					fmt.Println(p)
@@ -282,6 +458,26 @@ func run(args []string) error {
 	pipeline.Close()
 	<-done
 
+	if collector != nil {
+		report := collector.Report(cfg, runResults, runErr)
+		fmt.Fprint(os.Stderr, "\n"+report.Markdown())
+		if flags.WriteReport {
+			if path, err := report.WriteFile(cfg.OutputDir); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			} else if flags.Verbose {
+				fmt.Fprintf(os.Stderr, "Wrote run report to %s\n", path)
+			}
+		}
+	}
+
+	if recorder != nil {
+		if err := recorder.Recording().Save(flags.Record); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		} else if flags.Verbose {
+			fmt.Fprintf(os.Stderr, "Recorded agent responses to %s\n", flags.Record)
+		}
+	}
+
 	return runErr
 }
 
@@ -351,11 +547,12 @@ func runImplement(ctx context.Context, projectRoot, name string, flags cliFlags)
 	pipeline := orchestrator.NewImplementPipeline(cfg, scheduler, review, implementer)
 
 	// Drain progress events in background.
+	formatter := orchestrator.NewProgressFormatter(os.Stderr, flags.NoColor)
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
 		for ev := range pipeline.Progress() {
-			fmt.Fprintln(os.Stderr, orchestrator.FormatProgress(ev))
+			fmt.Fprintln(os.Stderr, formatter.Format(ev))
 		}
 	}()
 
@@ -404,10 +601,20 @@ func printUsage(fs *flag.FlagSet) {
 	fmt.Fprintln(w, "  decompose [flags] review <name>     Run review phase (codebase-plan cross-reference)")
 	fmt.Fprintln(w, "  decompose [flags] review-interpret <name>  Interpretive triage of review findings")
 	fmt.Fprintln(w, "  decompose [flags] implement <name>  Implement via Claude Code sessions")
-	fmt.Fprintln(w, "  decompose [flags] init              Install skill, hooks, and MCP config")
-	fmt.Fprintln(w, "  decompose [flags] status [name]     Show decomposition status")
-	fmt.Fprintln(w, "  decompose [flags] export <name>     Export decomposition as JSON")
-	fmt.Fprintln(w, "  decompose [flags] diagram           Generate Mermaid dependency diagram")
+	fmt.Fprintln(w, "  decompose [flags] init              Install skill, hooks, and MCP config (--dry-run to preview)")
+	fmt.Fprintln(w, "  decompose [flags] status [name]     Show decomposition status (--concurrency N, --progress)")
+	fmt.Fprintln(w, "  decompose [flags] export <name>     Export decomposition (--format json|yaml|toml)")
+	fmt.Fprintln(w, "  decompose [flags] diagram           Generate Mermaid dependency diagram (--by-cluster, --root file --depth N, --max-nodes N)")
+	fmt.Fprintln(w, "  decompose [flags] impact --since <rev>  Assess the impact of files changed since a git revision")
+	fmt.Fprintln(w, "  decompose graph-diff <snapshot-a> <snapshot-b>  List files and symbols changed between two graph snapshots")
+	fmt.Fprintln(w, "  decompose [flags] graph build [--languages go,ts] [--db path]  Index the project and print graph stats")
+	fmt.Fprintln(w, "  decompose [flags] graph stats [--db path]  Print stats of the persisted code graph")
+	fmt.Fprintln(w, "  decompose [flags] compact [--graph-db path]  Reclaim space in the persisted code graph")
+	fmt.Fprintln(w, "  decompose cancel --agent <url> --task <id>       Cancel a task on a remote agent")
+	fmt.Fprintln(w, "  decompose task-status --agent <url> --task <id>  Show a remote task's state")
+	fmt.Fprintln(w, "  decompose list-skills --agents <url1,url2,...>  List the skills offered by agents")
+	fmt.Fprintln(w, "  decompose agent-card <url> | --local <name>      Print a remote or built-in agent card")
+	fmt.Fprintln(w, "  decompose list-stages [--mermaid]   Show the configured stages and their prerequisites")
 	fmt.Fprintln(w, "  decompose --serve-mcp               Run as MCP server on stdio")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Stages:")
@@ -420,9 +627,19 @@ func printUsage(fs *flag.FlagSet) {
 	fmt.Fprintln(w, "Examples:")
 	fmt.Fprintln(w, "  decompose auth-system           Run full pipeline")
 	fmt.Fprintln(w, "  decompose auth-system 1         Run Stage 1 only")
+	fmt.Fprintln(w, "  decompose --replay run.json auth-system  Re-run from recorded agent responses")
+	fmt.Fprintln(w, "  decompose --merge-only auth-system 2     Re-merge Stage 2 from its cached sections")
 	fmt.Fprintln(w, "  decompose init                  Install into current project")
 	fmt.Fprintln(w, "  decompose status                Show all decompositions")
 	fmt.Fprintln(w, "  decompose --serve-mcp           Start MCP server")
+	fmt.Fprintln(w, "  decompose --profile prof auth-system  Run the pipeline and write pprof profiles to prof/")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes:")
+	fmt.Fprintln(w, "  1    Any other failure")
+	fmt.Fprintln(w, "  2    Usage error")
+	fmt.Fprintln(w, "  3    Agent or network error")
+	fmt.Fprintln(w, "  4    Validation or verification failure")
+	fmt.Fprintln(w, "  124  Timeout")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Flags:")
 	fs.PrintDefaults()
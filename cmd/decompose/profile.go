@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// Profile file names written into the --profile directory.
+const (
+	cpuProfileName  = "cpu.pprof"
+	heapProfileName = "heap.pprof"
+	traceName       = "trace.out"
+)
+
+// profiler records a CPU profile, and optionally an execution trace, from
+// startProfile until Stop, which also writes a heap profile.
+type profiler struct {
+	dir   string
+	cpu   *os.File
+	trace *os.File
+}
+
+// startProfile creates dir and starts CPU profiling into it, and execution
+// tracing when withTrace is set. Callers must Stop the returned profiler.
+func startProfile(dir string, withTrace bool) (*profiler, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+	p := &profiler{dir: dir}
+
+	cpu, err := os.Create(filepath.Join(dir, cpuProfileName))
+	if err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, fmt.Errorf("profile: start CPU profile: %w", err)
+	}
+	p.cpu = cpu
+
+	if withTrace {
+		tf, err := os.Create(filepath.Join(dir, traceName))
+		if err != nil {
+			p.Stop()
+			return nil, fmt.Errorf("profile: %w", err)
+		}
+		if err := trace.Start(tf); err != nil {
+			tf.Close()
+			p.Stop()
+			return nil, fmt.Errorf("profile: start trace: %w", err)
+		}
+		p.trace = tf
+	}
+	return p, nil
+}
+
+// Stop ends CPU profiling and tracing, then writes a heap profile taken
+// after a garbage collection so it reflects live memory.
+func (p *profiler) Stop() error {
+	var errs []error
+	if p.trace != nil {
+		trace.Stop()
+		errs = append(errs, p.trace.Close())
+	}
+	pprof.StopCPUProfile()
+	errs = append(errs, p.cpu.Close())
+
+	heap, err := os.Create(filepath.Join(p.dir, heapProfileName))
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("profile: %w", err))...)
+	}
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(heap); err != nil {
+		errs = append(errs, fmt.Errorf("profile: write heap profile: %w", err))
+	}
+	errs = append(errs, heap.Close())
+	return errors.Join(errs...)
+}
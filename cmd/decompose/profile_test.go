@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// busyWork burns some CPU and allocates so the profiles have samples.
+func busyWork() int {
+	var sb strings.Builder
+	for i := range 200000 {
+		sb.WriteString(strings.Repeat("x", i%7))
+	}
+	return sb.Len()
+}
+
+// requirePprof checks that path holds a gzip-compressed pprof profile.
+func requirePprof(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, data, path)
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err, "%s is not gzip-compressed", path)
+	raw, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.NotEmpty(t, raw, "%s has an empty profile", path)
+}
+
+func TestProfiler_WritesProfiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "prof")
+
+	prof, err := startProfile(dir, true)
+	require.NoError(t, err)
+	assert.Positive(t, busyWork())
+	require.NoError(t, prof.Stop())
+
+	requirePprof(t, filepath.Join(dir, cpuProfileName))
+	requirePprof(t, filepath.Join(dir, heapProfileName))
+
+	trace, err := os.ReadFile(filepath.Join(dir, traceName))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(trace, []byte("go 1.")), "trace should start with the Go trace header")
+}
+
+func TestRun_ProfileFlag(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "prof")
+
+	require.NoError(t, run([]string{"--profile", dir, "--project-root", t.TempDir(), "status"}))
+
+	requirePprof(t, filepath.Join(dir, cpuProfileName))
+	requirePprof(t, filepath.Join(dir, heapProfileName))
+	assert.NoFileExists(t, filepath.Join(dir, traceName))
+}
+
+func TestRun_ProfileTraceRequiresProfile(t *testing.T) {
+	err := run([]string{"--profile-trace", "status"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--profile")
+}
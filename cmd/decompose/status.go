@@ -1,16 +1,48 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/onedusk/pd/internal/status"
 )
 
-func runStatus(projectRoot string, name string) error {
+func runStatus(projectRoot string, args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	concurrency := fs.Int("concurrency", 8, "decompositions to scan at once")
+	progress := fs.Bool("progress", false, "show scan progress on stderr")
+
+	// Accept the name before or after the flags.
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return &usageError{err}
+	}
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+
 	if name != "" {
 		return printSingleStatus(projectRoot, name)
 	}
-	return printAllStatuses(projectRoot)
+	opts := []status.ListOption{status.WithConcurrency(*concurrency)}
+	if *progress {
+		opts = append(opts, status.WithProgress(func(done, total int) {
+			fmt.Fprintf(os.Stderr, "\rScanning decompositions: %d/%d", done, total)
+			if done == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		}))
+	}
+	return printAllStatuses(projectRoot, opts...)
 }
 
 func printSingleStatus(projectRoot, name string) error {
@@ -20,8 +52,8 @@ func printSingleStatus(projectRoot, name string) error {
 	return nil
 }
 
-func printAllStatuses(projectRoot string) error {
-	decompositions, hasStage0 := status.ListDecompositions(projectRoot)
+func printAllStatuses(projectRoot string, opts ...status.ListOption) error {
+	decompositions, hasStage0 := status.ListDecompositions(projectRoot, opts...)
 
 	if hasStage0 {
 		fmt.Println("Stage 0: Development Standards  [complete]")
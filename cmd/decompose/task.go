@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// runCancel cancels a task on a remote agent and prints its resulting state.
+func runCancel(ctx context.Context, client a2a.Client, args []string, w io.Writer) error {
+	agentURL, taskID, err := parseTaskFlags("cancel", args)
+	if err != nil || agentURL == "" {
+		return err
+	}
+
+	task, err := client.CancelTask(ctx, agentURL, a2a.CancelTaskRequest{ID: taskID})
+	if err != nil {
+		return fmt.Errorf("cancel task %s: %w", taskID, err)
+	}
+	printTaskState(w, task)
+	return nil
+}
+
+// runTaskStatus fetches a task from a remote agent and prints its state.
+func runTaskStatus(ctx context.Context, client a2a.Client, args []string, w io.Writer) error {
+	agentURL, taskID, err := parseTaskFlags("task-status", args)
+	if err != nil || agentURL == "" {
+		return err
+	}
+
+	task, err := client.GetTask(ctx, agentURL, a2a.GetTaskRequest{ID: taskID})
+	if err != nil {
+		return fmt.Errorf("get task %s: %w", taskID, err)
+	}
+	printTaskState(w, task)
+	return nil
+}
+
+// parseTaskFlags parses the --agent and --task flags shared by the remote
+// task commands. It returns an empty agent URL and nil error for --help.
+func parseTaskFlags(command string, args []string) (agentURL, taskID string, err error) {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.StringVar(&agentURL, "agent", "", "agent endpoint URL")
+	fs.StringVar(&taskID, "task", "", "task ID")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", "", nil
+		}
+		return "", "", &usageError{err}
+	}
+	if agentURL == "" || taskID == "" {
+		return "", "", usageErrorf("usage: decompose %s --agent <url> --task <id>", command)
+	}
+	return agentURL, taskID, nil
+}
+
+// printTaskState writes a task's ID, state, and any status message text.
+func printTaskState(w io.Writer, task *a2a.Task) {
+	fmt.Fprintf(w, "Task %s: %s\n", task.ID, task.Status.State)
+	if task.Status.Message == nil {
+		return
+	}
+	for _, p := range task.Status.Message.Parts {
+		if p.Text != "" {
+			fmt.Fprintf(w, "  %s\n", p.Text)
+		}
+	}
+}
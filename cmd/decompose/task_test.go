@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taskServer returns an A2A endpoint that answers every JSON-RPC call with
+// task, recording the methods it was called with.
+func taskServer(t *testing.T, task a2a.Task, methods *[]string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*methods = append(*methods, req.Method)
+
+		result, err := json.Marshal(task)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(a2a.JSONRPCResponse{
+			JSONRPC: a2a.JSONRPCVersion,
+			ID:      req.ID,
+			Result:  result,
+		}))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestRunCancel_PrintsCanceledState(t *testing.T) {
+	var methods []string
+	ts := taskServer(t, a2a.Task{
+		ID:     "task-1",
+		Status: a2a.TaskStatus{State: a2a.TaskStateCanceled},
+	}, &methods)
+
+	var out bytes.Buffer
+	err := runCancel(context.Background(), a2a.NewHTTPClient(), []string{"--agent", ts.URL, "--task", "task-1"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "Task task-1: canceled\n", out.String())
+	assert.Equal(t, []string{a2a.MethodCancelTask}, methods)
+}
+
+func TestRunTaskStatus_PrintsStateAndMessage(t *testing.T) {
+	var methods []string
+	ts := taskServer(t, a2a.Task{
+		ID: "task-2",
+		Status: a2a.TaskStatus{
+			State:   a2a.TaskStateFailed,
+			Message: &a2a.Message{Role: a2a.RoleAgent, Parts: []a2a.Part{a2a.TextPart("boom")}},
+		},
+	}, &methods)
+
+	var out bytes.Buffer
+	err := runTaskStatus(context.Background(), a2a.NewHTTPClient(), []string{"--agent", ts.URL, "--task", "task-2"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "Task task-2: failed\n  boom\n", out.String())
+	assert.Equal(t, []string{a2a.MethodGetTask}, methods)
+}
+
+func TestRunCancel_RequiresAgentAndTask(t *testing.T) {
+	err := runCancel(context.Background(), a2a.NewHTTPClient(), []string{"--task", "task-1"}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "usage: decompose cancel")
+}
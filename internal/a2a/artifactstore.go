@@ -0,0 +1,143 @@
+package a2a
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrArtifactNotFound is returned by an ArtifactStore for an unknown ID.
+var ErrArtifactNotFound = errors.New("a2a: artifact not found")
+
+// ArtifactRoute is the path prefix under which a Server with an
+// ArtifactStore serves stored artifact content.
+const ArtifactRoute = "/artifacts/"
+
+// ArtifactStore holds artifact content that is too large to inline in a
+// task, so that parts can refer to it by URL instead.
+type ArtifactStore interface {
+	// Put stores content and returns its ID.
+	Put(ctx context.Context, content []byte, mediaType string) (string, error)
+
+	// Get returns the content and media type stored under id, or
+	// ErrArtifactNotFound.
+	Get(ctx context.Context, id string) ([]byte, string, error)
+}
+
+// ArtifactURL returns the URL at which a Server reachable at baseURL serves
+// the artifact with the given ID.
+func ArtifactURL(baseURL, id string) string {
+	return strings.TrimRight(baseURL, "/") + ArtifactRoute + id
+}
+
+// artifactID derives a stable ID from content, so storing the same output
+// twice reuses one entry.
+func artifactID(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:16])
+}
+
+// validArtifactID reports whether id has the form produced by artifactID.
+// It keeps request paths from escaping a FileArtifactStore's directory.
+func validArtifactID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// Compile-time interface checks.
+var (
+	_ ArtifactStore = (*MemArtifactStore)(nil)
+	_ ArtifactStore = (*FileArtifactStore)(nil)
+)
+
+// MemArtifactStore is an ArtifactStore held in memory.
+type MemArtifactStore struct {
+	mu    sync.RWMutex
+	items map[string]storedArtifact
+}
+
+// storedArtifact is one entry of a MemArtifactStore.
+type storedArtifact struct {
+	content   []byte
+	mediaType string
+}
+
+// NewMemArtifactStore creates an empty in-memory artifact store.
+func NewMemArtifactStore() *MemArtifactStore {
+	return &MemArtifactStore{items: make(map[string]storedArtifact)}
+}
+
+// Put stores a copy of content.
+func (s *MemArtifactStore) Put(_ context.Context, content []byte, mediaType string) (string, error) {
+	id := artifactID(content)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[id] = storedArtifact{content: append([]byte(nil), content...), mediaType: mediaType}
+	return id, nil
+}
+
+// Get returns the content stored under id.
+func (s *MemArtifactStore) Get(_ context.Context, id string) ([]byte, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.items[id]
+	if !ok {
+		return nil, "", ErrArtifactNotFound
+	}
+	return append([]byte(nil), item.content...), item.mediaType, nil
+}
+
+// FileArtifactStore is an ArtifactStore backed by a directory. Each artifact
+// is written to a file named by its ID, with its media type alongside in
+// <id>.type.
+type FileArtifactStore struct {
+	dir string
+}
+
+// NewFileArtifactStore creates a FileArtifactStore in dir, creating the
+// directory if needed.
+func NewFileArtifactStore(dir string) (*FileArtifactStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("a2a: create artifact dir: %w", err)
+	}
+	return &FileArtifactStore{dir: dir}, nil
+}
+
+// Put writes content to the store's directory.
+func (s *FileArtifactStore) Put(_ context.Context, content []byte, mediaType string) (string, error) {
+	id := artifactID(content)
+	path := filepath.Join(s.dir, id)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", fmt.Errorf("a2a: write artifact: %w", err)
+	}
+	if err := os.WriteFile(path+".type", []byte(mediaType), 0o644); err != nil {
+		return "", fmt.Errorf("a2a: write artifact: %w", err)
+	}
+	return id, nil
+}
+
+// Get reads the content stored under id.
+func (s *FileArtifactStore) Get(_ context.Context, id string) ([]byte, string, error) {
+	if !validArtifactID(id) {
+		return nil, "", ErrArtifactNotFound
+	}
+	path := filepath.Join(s.dir, id)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", ErrArtifactNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("a2a: read artifact: %w", err)
+	}
+	mediaType, _ := os.ReadFile(path + ".type")
+	return content, string(mediaType), nil
+}
@@ -0,0 +1,28 @@
+package a2a
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileArtifactStore_RoundTrip(t *testing.T) {
+	store, err := NewFileArtifactStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	id, err := store.Put(ctx, []byte("large output"), "text/markdown")
+	require.NoError(t, err)
+
+	content, mediaType, err := store.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "large output", string(content))
+	assert.Equal(t, "text/markdown", mediaType)
+
+	_, _, err = store.Get(ctx, "../../etc/passwd")
+	assert.ErrorIs(t, err, ErrArtifactNotFound)
+	_, _, err = store.Get(ctx, "0123456789abcdef0123456789abcdef")
+	assert.ErrorIs(t, err, ErrArtifactNotFound)
+}
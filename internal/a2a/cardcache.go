@@ -0,0 +1,44 @@
+package a2a
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// CardCache is a Client that remembers the agent cards it discovers, so
+// that each agent is discovered over the network at most once. Failed
+// discoveries are not cached and are retried on the next call. All other
+// methods go straight to the wrapped Client.
+type CardCache struct {
+	Client
+
+	mu    sync.Mutex
+	cards map[string]*AgentCard // by base URL, without a trailing slash
+}
+
+// NewCardCache returns a CardCache wrapping client.
+func NewCardCache(client Client) *CardCache {
+	return &CardCache{Client: client, cards: make(map[string]*AgentCard)}
+}
+
+// DiscoverAgent returns the cached card of the agent at baseURL, discovering
+// it through the wrapped Client on first use.
+func (c *CardCache) DiscoverAgent(ctx context.Context, baseURL string) (*AgentCard, error) {
+	key := strings.TrimRight(baseURL, "/")
+	c.mu.Lock()
+	card, ok := c.cards[key]
+	c.mu.Unlock()
+	if ok {
+		return card, nil
+	}
+
+	card, err := c.Client.DiscoverAgent(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.cards[key] = card
+	c.mu.Unlock()
+	return card, nil
+}
@@ -0,0 +1,44 @@
+package a2a
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardCache_DiscoversEachAgentOnce(t *testing.T) {
+	var hits atomic.Int32
+	fail := atomic.Bool{}
+	fail.Store(true)
+	srv := NewServer(AgentCard{Name: "cached", Version: "1"}, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if fail.Load() {
+			http.Error(w, "starting up", http.StatusServiceUnavailable)
+			return
+		}
+		srv.Handler().ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	cache := NewCardCache(NewHTTPClient())
+	ctx := context.Background()
+
+	// A failed discovery is not cached.
+	_, err := cache.DiscoverAgent(ctx, ts.URL)
+	require.Error(t, err)
+	fail.Store(false)
+
+	card, err := cache.DiscoverAgent(ctx, ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", card.Name)
+	again, err := cache.DiscoverAgent(ctx, ts.URL+"/")
+	require.NoError(t, err)
+	assert.Same(t, card, again)
+	assert.Equal(t, int32(2), hits.Load())
+}
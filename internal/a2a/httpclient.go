@@ -7,8 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -19,10 +21,30 @@ var _ Client = (*HTTPClient)(nil)
 // ErrNotImplemented is returned for features that are not yet wired up.
 var ErrNotImplemented = errors.New("a2a: not implemented")
 
-// HTTPClient implements the Client interface using HTTP/JSON-RPC.
+// HTTPClient implements the Client interface using JSON-RPC. Calls go over
+// HTTP unless another Transport is supplied with WithTransport.
 type HTTPClient struct {
-	http      *http.Client
+	http      *httpTransport
+	transport Transport
+}
+
+// httpTransport is the default Transport: JSON-RPC over HTTP POST, with agent
+// cards fetched from the well-known URI.
+type httpTransport struct {
+	client    *http.Client
 	requestID atomic.Int64
+	headers   map[string]map[string]string // per-endpoint extra headers
+
+	// Agent cards served with an ETag, keyed by card URL, for conditional
+	// rediscovery.
+	cardsMu sync.Mutex
+	cards   map[string]cachedCard
+}
+
+// cachedCard is an agent card and the ETag it was served with.
+type cachedCard struct {
+	etag string
+	card AgentCard
 }
 
 // ClientOption configures an HTTPClient.
@@ -31,27 +53,70 @@ type ClientOption func(*HTTPClient)
 // WithTimeout sets the HTTP client timeout.
 func WithTimeout(d time.Duration) ClientOption {
 	return func(c *HTTPClient) {
-		c.http.Timeout = d
+		c.http.client.Timeout = d
+	}
+}
+
+// WithDialTimeout bounds how long establishing a connection to an agent may
+// take, so unreachable hosts fail fast while WithTimeout still governs the
+// whole request, including a slow agent's processing time. It has no effect
+// when the http.Client's Transport is not an *http.Transport; combined with
+// WithHTTPClient, it must come after it.
+func WithDialTimeout(d time.Duration) ClientOption {
+	return func(c *HTTPClient) {
+		base, ok := c.http.client.Transport.(*http.Transport)
+		if c.http.client.Transport == nil {
+			base, ok = http.DefaultTransport.(*http.Transport)
+		}
+		if !ok {
+			return
+		}
+		t := base.Clone()
+		dialer := &net.Dialer{Timeout: d, KeepAlive: 30 * time.Second}
+		t.DialContext = dialer.DialContext
+		c.http.client.Transport = t
 	}
 }
 
 // WithHTTPClient replaces the underlying *http.Client entirely.
 func WithHTTPClient(hc *http.Client) ClientOption {
 	return func(c *HTTPClient) {
-		c.http = hc
+		c.http.client = hc
+	}
+}
+
+// WithEndpointHeaders sets extra HTTP headers, such as Authorization, sent on
+// every request to an endpoint. headers is keyed by endpoint URL as passed to
+// the Client methods.
+func WithEndpointHeaders(headers map[string]map[string]string) ClientOption {
+	return func(c *HTTPClient) {
+		c.http.headers = headers
+	}
+}
+
+// WithTransport routes every call through t instead of HTTP. The HTTP
+// options have no effect on a client with a custom transport.
+func WithTransport(t Transport) ClientOption {
+	return func(c *HTTPClient) {
+		c.transport = t
 	}
 }
 
 // NewHTTPClient creates a new A2A HTTP client.
 func NewHTTPClient(opts ...ClientOption) *HTTPClient {
 	c := &HTTPClient{
-		http: &http.Client{
-			Timeout: 30 * time.Second,
+		http: &httpTransport{
+			client: &http.Client{
+				Timeout: 30 * time.Second,
+			},
 		},
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.transport == nil {
+		c.transport = c.http
+	}
 	return c
 }
 
@@ -97,8 +162,37 @@ func (c *HTTPClient) SubscribeToTask(ctx context.Context, endpoint string, taskI
 	return nil, ErrNotImplemented
 }
 
-// DiscoverAgent fetches the Agent Card from the well-known URI.
+// DiscoverAgent fetches the Agent Card of the agent at baseURL.
 func (c *HTTPClient) DiscoverAgent(ctx context.Context, baseURL string) (*AgentCard, error) {
+	return c.transport.DiscoverAgent(ctx, baseURL)
+}
+
+// call performs a JSON-RPC 2.0 call through the client's transport and
+// decodes the result into result.
+func (c *HTTPClient) call(ctx context.Context, endpoint, method string, params any, result any) error {
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("a2a: marshal params: %w", err)
+	}
+
+	raw, err := c.transport.RoundTrip(ctx, endpoint, method, paramsJSON)
+	if err != nil {
+		return err
+	}
+
+	// Unmarshal the result into the caller's target.
+	if result != nil && raw != nil {
+		if err := json.Unmarshal(raw, result); err != nil {
+			return fmt.Errorf("a2a: decode result: %w", err)
+		}
+	}
+	return nil
+}
+
+// DiscoverAgent fetches the Agent Card from the well-known URI. A card served
+// with an ETag is cached; later calls send If-None-Match and return the
+// cached card when the server answers 304 Not Modified.
+func (t *httpTransport) DiscoverAgent(ctx context.Context, baseURL string) (*AgentCard, error) {
 	url := strings.TrimRight(baseURL, "/") + "/.well-known/agent-card.json"
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -106,86 +200,111 @@ func (c *HTTPClient) DiscoverAgent(ctx context.Context, baseURL string) (*AgentC
 		return nil, fmt.Errorf("a2a: create request: %w", err)
 	}
 	httpReq.Header.Set("Accept", "application/json")
+	t.setEndpointHeaders(httpReq, baseURL)
+
+	t.cardsMu.Lock()
+	cached, haveCached := t.cards[url]
+	t.cardsMu.Unlock()
+	if haveCached {
+		httpReq.Header.Set("If-None-Match", cached.etag)
+	}
 
-	resp, err := c.http.Do(httpReq)
+	resp, err := t.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("a2a: discover agent: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && haveCached {
+		card := cached.card
+		return &card, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("a2a: discover agent: HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{Op: "discover agent", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var card AgentCard
 	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
 		return nil, fmt.Errorf("a2a: decode agent card: %w", err)
 	}
+
+	t.cardsMu.Lock()
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		if t.cards == nil {
+			t.cards = make(map[string]cachedCard)
+		}
+		t.cards[url] = cachedCard{etag: etag, card: card}
+	} else {
+		delete(t.cards, url)
+	}
+	t.cardsMu.Unlock()
 	return &card, nil
 }
 
-// nextID returns a monotonically increasing request ID for JSON-RPC calls.
-func (c *HTTPClient) nextID() int64 {
-	return c.requestID.Add(1)
+// setEndpointHeaders applies the WithEndpointHeaders headers for endpoint.
+func (t *httpTransport) setEndpointHeaders(req *http.Request, endpoint string) {
+	for k, v := range t.headers[endpoint] {
+		req.Header.Set(k, v)
+	}
 }
 
-// call performs a JSON-RPC 2.0 call over HTTP POST.
-func (c *HTTPClient) call(ctx context.Context, endpoint, method string, params any, result any) error {
-	// Marshal the params.
-	paramsJSON, err := json.Marshal(params)
-	if err != nil {
-		return fmt.Errorf("a2a: marshal params: %w", err)
-	}
+// nextID returns a monotonically increasing request ID for JSON-RPC calls.
+func (t *httpTransport) nextID() int64 {
+	return t.requestID.Add(1)
+}
 
+// RoundTrip performs a JSON-RPC 2.0 call over HTTP POST.
+func (t *httpTransport) RoundTrip(ctx context.Context, endpoint, method string, params json.RawMessage) (json.RawMessage, error) {
 	// Build the JSON-RPC request envelope.
 	rpcReq := JSONRPCRequest{
 		JSONRPC: JSONRPCVersion,
-		ID:      c.nextID(),
+		ID:      t.nextID(),
 		Method:  method,
-		Params:  paramsJSON,
+		Params:  params,
 	}
 
 	body, err := json.Marshal(rpcReq)
 	if err != nil {
-		return fmt.Errorf("a2a: marshal request: %w", err)
+		return nil, fmt.Errorf("a2a: marshal request: %w", err)
 	}
 
 	// Create the HTTP request.
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("a2a: create request: %w", err)
+		return nil, fmt.Errorf("a2a: create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
+	t.setEndpointHeaders(httpReq, endpoint)
 
 	// Execute the HTTP request.
-	resp, err := c.http.Do(httpReq)
+	resp, err := t.client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("a2a: %s: %w", method, err)
+		return nil, fmt.Errorf("a2a: %s: %w", method, err)
 	}
 	defer resp.Body.Close()
 
 	// Read the response body.
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("a2a: read response: %w", err)
+		return nil, fmt.Errorf("a2a: read response: %w", err)
 	}
 
 	// Check HTTP-level errors.
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("a2a: %s: HTTP %d: %s", method, resp.StatusCode, string(respBody))
+		return nil, &StatusError{Op: method, StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	// Decode JSON-RPC response.
 	var rpcResp JSONRPCResponse
 	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
-		return fmt.Errorf("a2a: decode response: %w", err)
+		return nil, fmt.Errorf("a2a: decode response: %w", err)
 	}
 
 	// Check JSON-RPC-level errors.
 	if rpcResp.Error != nil {
-		return &RPCError{
+		return nil, &RPCError{
 			Method:  method,
 			Code:    rpcResp.Error.Code,
 			Message: rpcResp.Error.Message,
@@ -193,14 +312,20 @@ func (c *HTTPClient) call(ctx context.Context, endpoint, method string, params a
 		}
 	}
 
-	// Unmarshal the result into the caller's target.
-	if result != nil && rpcResp.Result != nil {
-		if err := json.Unmarshal(rpcResp.Result, result); err != nil {
-			return fmt.Errorf("a2a: decode result: %w", err)
-		}
-	}
+	return rpcResp.Result, nil
+}
 
-	return nil
+// StatusError reports a non-200 HTTP response from a remote agent. Op is the
+// JSON-RPC method, or "discover agent" for agent card discovery.
+type StatusError struct {
+	Op         string
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("a2a: %s: HTTP %d: %s", e.Op, e.StatusCode, e.Body)
 }
 
 // RPCError represents a JSON-RPC error returned by a remote agent.
@@ -380,6 +380,53 @@ func TestDiscoverAgent_TrailingSlash(t *testing.T) {
 	assert.Equal(t, "Test", result.Name)
 }
 
+func TestDiscoverAgent_ConditionalRequest(t *testing.T) {
+	const etag = `"card-v1"`
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			assert.Empty(t, r.Header.Get("If-None-Match"), "first discovery has nothing cached")
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(AgentCard{Name: "Cached", Version: "1.0.0"})
+			return
+		}
+		assert.Equal(t, etag, r.Header.Get("If-None-Match"))
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer ts.Close()
+
+	client := NewHTTPClient()
+	first, err := client.DiscoverAgent(context.Background(), ts.URL)
+	require.NoError(t, err)
+
+	second, err := client.DiscoverAgent(context.Background(), ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, first, second)
+	assert.Equal(t, "Cached", second.Name)
+
+	// The cached card is returned by value; mutating it does not leak.
+	second.Name = "changed"
+	third, err := client.DiscoverAgent(context.Background(), ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "Cached", third.Name)
+}
+
+func TestDiscoverAgent_NotModifiedWithoutCache(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer ts.Close()
+
+	client := NewHTTPClient()
+	card, err := client.DiscoverAgent(context.Background(), ts.URL)
+	assert.Nil(t, card)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 304")
+}
+
 func TestContextTimeout(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Delay longer than the context deadline to force a timeout.
@@ -502,6 +549,45 @@ func TestWithTimeout_Option(t *testing.T) {
 	assert.Nil(t, task)
 }
 
+func TestWithDialTimeout_FailsFastOnUnreachableHost(t *testing.T) {
+	client := NewHTTPClient(WithTimeout(30*time.Second), WithDialTimeout(200*time.Millisecond))
+
+	// 10.255.255.1 is unroutable: connecting hangs until the dial timeout,
+	// or fails at once where the network is unreachable.
+	start := time.Now()
+	_, err := client.GetTask(context.Background(), "http://10.255.255.1:81", GetTaskRequest{ID: "task-1"})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "dial should give up long before the request timeout")
+}
+
+func TestWithDialTimeout_AllowsSlowAgents(t *testing.T) {
+	ts := httptest.NewServer(rpcHandler(t, func(req JSONRPCRequest) JSONRPCResponse {
+		time.Sleep(300 * time.Millisecond)
+		result, _ := json.Marshal(Task{ID: "task-slow"})
+		return JSONRPCResponse{JSONRPC: JSONRPCVersion, ID: req.ID, Result: result}
+	}))
+	defer ts.Close()
+
+	// The agent takes longer than the dial timeout to answer, which is fine:
+	// only connecting is bounded by it.
+	client := NewHTTPClient(WithTimeout(5*time.Second), WithDialTimeout(50*time.Millisecond))
+	task, err := client.GetTask(context.Background(), ts.URL, GetTaskRequest{ID: "task-slow"})
+	require.NoError(t, err)
+	assert.Equal(t, "task-slow", task.ID)
+}
+
+func TestWithDialTimeout_KeepsCustomTransportSettings(t *testing.T) {
+	base := &http.Transport{MaxIdleConnsPerHost: 7}
+	client := NewHTTPClient(WithHTTPClient(&http.Client{Transport: base}), WithDialTimeout(time.Second))
+
+	got, ok := client.http.client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.NotSame(t, base, got, "the caller's transport must not be modified")
+	assert.Equal(t, 7, got.MaxIdleConnsPerHost)
+	assert.NotNil(t, got.DialContext)
+	assert.Nil(t, base.DialContext)
+}
+
 func TestSubscribeToTask_NotImplemented(t *testing.T) {
 	client := NewHTTPClient()
 	ch, err := client.SubscribeToTask(context.Background(), "http://example.com", "task-1")
@@ -554,3 +640,30 @@ func TestDiscoverAgent_Non200(t *testing.T) {
 	assert.Nil(t, card)
 	assert.Contains(t, err.Error(), "HTTP 404")
 }
+
+func TestHTTPClient_WithEndpointHeaders(t *testing.T) {
+	var authSeen []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		authSeen = append(authSeen, r.Header.Get("Authorization"))
+		rpcHandler(t, func(req JSONRPCRequest) JSONRPCResponse {
+			result, _ := json.Marshal(Task{ID: "task-1", Status: TaskStatus{State: TaskStateCompleted}})
+			return JSONRPCResponse{JSONRPC: JSONRPCVersion, ID: req.ID, Result: result}
+		})(w, r)
+	}
+	secured := httptest.NewServer(http.HandlerFunc(handler))
+	defer secured.Close()
+	open := httptest.NewServer(http.HandlerFunc(handler))
+	defer open.Close()
+
+	client := NewHTTPClient(WithEndpointHeaders(map[string]map[string]string{
+		secured.URL: {"Authorization": "Bearer s3cret"},
+	}))
+	ctx := context.Background()
+
+	_, err := client.GetTask(ctx, secured.URL, GetTaskRequest{ID: "task-1"})
+	require.NoError(t, err)
+	_, err = client.GetTask(ctx, open.URL, GetTaskRequest{ID: "task-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Bearer s3cret", ""}, authSeen)
+}
@@ -2,22 +2,21 @@ package a2a
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // Start creates an HTTP server, registers routes, and begins serving.
 // It returns immediately after starting the server in a background goroutine.
 func (s *Server) Start(ctx context.Context, addr string) error {
-	mux := http.NewServeMux()
-
-	mux.HandleFunc("GET /.well-known/agent-card.json", s.handleAgentCard)
-	mux.HandleFunc("POST /", s.handleJSONRPC)
-
 	s.http = &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: s.Handler(),
 	}
 
 	go s.http.ListenAndServe()
@@ -25,113 +24,148 @@ func (s *Server) Start(ctx context.Context, addr string) error {
 	return nil
 }
 
+// Handler returns the server's routes as an http.Handler, for mounting the
+// agent in another server or a test server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /.well-known/agent-card.json", s.handleAgentCard)
+	mux.HandleFunc("POST /", s.handleJSONRPC)
+	mux.HandleFunc("GET "+ArtifactRoute+"{id}", s.handleArtifact)
+	return mux
+}
+
 // Stop gracefully shuts down the HTTP server.
 func (s *Server) Stop(ctx context.Context) error {
 	return s.http.Shutdown(ctx)
 }
 
 // handleAgentCard serves the agent card as JSON at the well-known endpoint.
+// The response carries a strong ETag derived from the encoded card; a
+// request whose If-None-Match lists it gets 304 Not Modified.
 func (s *Server) handleAgentCard(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	if err := json.NewEncoder(w).Encode(s.card); err != nil {
+	body, err := json.Marshal(s.card)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-}
-
-// handleJSONRPC processes incoming JSON-RPC 2.0 requests and dispatches them
-// to the appropriate handler method.
-func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	var req JSONRPCRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSONRPCError(w, nil, ErrCodeParse, "Parse error: "+err.Error())
+	etag := contentETag(body)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
 
-	ctx := r.Context()
+// contentETag returns a strong ETag for body: its quoted SHA-256 digest.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
 
-	switch req.Method {
-	case MethodSendMessage:
-		s.dispatchSendMessage(ctx, w, &req)
-	case MethodGetTask:
-		s.dispatchGetTask(ctx, w, &req)
-	case MethodListTasks:
-		s.dispatchListTasks(ctx, w, &req)
-	case MethodCancelTask:
-		s.dispatchCancelTask(ctx, w, &req)
-	default:
-		writeJSONRPCError(w, req.ID, ErrCodeMethodNotFound, fmt.Sprintf("Method not found: %s", req.Method))
+// etagMatches reports whether an If-None-Match header value matches etag.
+// The header may list several tags or be "*"; as RFC 9110 specifies for
+// If-None-Match, weak tags compare equal to their strong form.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
 	}
+	return false
 }
 
-// dispatchSendMessage unmarshals params and calls HandleSendMessage.
-func (s *Server) dispatchSendMessage(ctx context.Context, w http.ResponseWriter, req *JSONRPCRequest) {
-	var params SendMessageRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInvalidParams, "Invalid params: "+err.Error())
+// handleArtifact serves stored artifact content by ID. Without an
+// ArtifactStore every artifact is not found.
+func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
+	if s.artifacts == nil {
+		http.NotFound(w, r)
 		return
 	}
-
-	result, err := s.handler.HandleSendMessage(ctx, params)
-	if err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInternal, err.Error())
+	content, mediaType, err := s.artifacts.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, ErrArtifactNotFound) {
+		http.NotFound(w, r)
 		return
 	}
-
-	writeJSONRPCResult(w, req.ID, result)
-}
-
-// dispatchGetTask unmarshals params and calls HandleGetTask.
-func (s *Server) dispatchGetTask(ctx context.Context, w http.ResponseWriter, req *JSONRPCRequest) {
-	var params GetTaskRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInvalidParams, "Invalid params: "+err.Error())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if mediaType != "" {
+		w.Header().Set("Content-Type", mediaType)
+	}
+	w.Write(content)
+}
 
-	result, err := s.handler.HandleGetTask(ctx, params)
-	if err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInternal, err.Error())
+// handleJSONRPC processes incoming JSON-RPC 2.0 requests and dispatches them
+// to the appropriate handler method. The request ID is echoed back exactly as
+// sent: strings stay strings and numbers keep their original digits. A request
+// whose ID is null or absent is a notification; it is dispatched, but the
+// reply is an empty 204 No Content.
+func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
+	var req JSONRPCRequest
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		writeJSONRPCError(w, nil, ErrCodeParse, "Parse error: "+err.Error())
 		return
 	}
 
-	writeJSONRPCResult(w, req.ID, result)
-}
-
-// dispatchListTasks unmarshals params and calls HandleListTasks.
-func (s *Server) dispatchListTasks(ctx context.Context, w http.ResponseWriter, req *JSONRPCRequest) {
-	var params ListTasksRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInvalidParams, "Invalid params: "+err.Error())
+	if req.ID == nil {
+		dispatchRPC(r.Context(), s.handler, req.Method, req.Params)
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	result, err := s.handler.HandleListTasks(ctx, params)
-	if err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInternal, err.Error())
+	w.Header().Set("Content-Type", "application/json")
+	result, rpcErr := dispatchRPC(r.Context(), s.handler, req.Method, req.Params)
+	if rpcErr != nil {
+		writeJSONRPCError(w, req.ID, rpcErr.Code, rpcErr.Message)
 		return
 	}
 
 	writeJSONRPCResult(w, req.ID, result)
 }
 
-// dispatchCancelTask unmarshals params and calls HandleCancelTask.
-func (s *Server) dispatchCancelTask(ctx context.Context, w http.ResponseWriter, req *JSONRPCRequest) {
-	var params CancelTaskRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInvalidParams, "Invalid params: "+err.Error())
-		return
+// dispatchRPC unmarshals params for method and calls the matching Handler
+// method. It is shared by the HTTP server and the in-process transport.
+func dispatchRPC(ctx context.Context, h Handler, method string, params json.RawMessage) (any, *JSONRPCError) {
+	switch method {
+	case MethodSendMessage:
+		return invokeHandler(ctx, params, h.HandleSendMessage)
+	case MethodGetTask:
+		return invokeHandler(ctx, params, h.HandleGetTask)
+	case MethodListTasks:
+		return invokeHandler(ctx, params, h.HandleListTasks)
+	case MethodCancelTask:
+		return invokeHandler(ctx, params, h.HandleCancelTask)
+	default:
+		return nil, &JSONRPCError{Code: ErrCodeMethodNotFound, Message: fmt.Sprintf("Method not found: %s", method)}
 	}
+}
 
-	result, err := s.handler.HandleCancelTask(ctx, params)
-	if err != nil {
-		writeJSONRPCError(w, req.ID, ErrCodeInternal, err.Error())
-		return
+// invokeHandler decodes params into the handler's request type, validates
+// and normalizes requests that support it, and calls the handler.
+func invokeHandler[Req, Resp any](ctx context.Context, params json.RawMessage, handle func(context.Context, Req) (Resp, error)) (any, *JSONRPCError) {
+	var req Req
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &JSONRPCError{Code: ErrCodeInvalidParams, Message: "Invalid params: " + err.Error()}
+	}
+	if n, ok := any(&req).(interface{ normalize() error }); ok {
+		if err := n.normalize(); err != nil {
+			return nil, &JSONRPCError{Code: ErrCodeInvalidParams, Message: "Invalid params: " + err.Error()}
+		}
 	}
 
-	writeJSONRPCResult(w, req.ID, result)
+	result, err := handle(ctx, req)
+	if err != nil {
+		return nil, &JSONRPCError{Code: ErrCodeInternal, Message: err.Error()}
+	}
+	return result, nil
 }
 
 // writeJSONRPCResult writes a successful JSON-RPC response.
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -156,6 +157,44 @@ func TestServerAgentCard(t *testing.T) {
 	assert.Equal(t, "echo", got.Skills[0].ID)
 }
 
+func TestServerAgentCard_ETag(t *testing.T) {
+	baseURL, _ := startTestServer(t, &mockHandler{}, testCard())
+	url := baseURL + "/.well-known/agent-card.json"
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, contentETag(bytes.TrimSuffix(body, []byte("\n"))), etag)
+
+	for _, tt := range []struct {
+		name        string
+		ifNoneMatch string
+		want        int
+	}{
+		{"matching", etag, http.StatusNotModified},
+		{"weak form", "W/" + etag, http.StatusNotModified},
+		{"in list", `"other", ` + etag, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"stale", `"other"`, http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.want, resp.StatusCode)
+			assert.Equal(t, etag, resp.Header.Get("ETag"))
+		})
+	}
+}
+
 func TestServerSendMessage(t *testing.T) {
 	handler := &mockHandler{
 		sendMessage: func(ctx context.Context, req SendMessageRequest) (*Task, error) {
@@ -411,3 +450,109 @@ func TestServerInvalidParamsError(t *testing.T) {
 	assert.Equal(t, ErrCodeInvalidParams, rpcResp.Error.Code)
 	assert.Contains(t, rpcResp.Error.Message, "Invalid params")
 }
+
+func TestServerValidatesMessageParts(t *testing.T) {
+	var got []Part
+	handler := &mockHandler{
+		sendMessage: func(_ context.Context, req SendMessageRequest) (*Task, error) {
+			got = req.Message.Parts
+			return &Task{ID: "task-1", Status: TaskStatus{State: TaskStateCompleted}}, nil
+		},
+	}
+	baseURL, _ := startTestServer(t, handler, testCard())
+
+	// A part carrying both text and raw bytes is ambiguous.
+	resp := postJSONRPC(t, baseURL, MethodSendMessage, 1, SendMessageRequest{
+		Message: Message{Role: RoleUser, Parts: []Part{{Text: "hello", Raw: []byte("hello")}}},
+	})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, ErrCodeInvalidParams, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "message.parts[0]: part has multiple content forms (text, raw)")
+	assert.Nil(t, got, "handler must not see an invalid message")
+
+	// A well-formed text part is accepted and gets a default media type.
+	resp = postJSONRPC(t, baseURL, MethodSendMessage, 2, SendMessageRequest{
+		Message: Message{Role: RoleUser, Parts: []Part{{Text: "hello"}}},
+	})
+	require.Nil(t, resp.Error)
+	require.Len(t, got, 1)
+	assert.Equal(t, "text/plain", got[0].MediaType)
+}
+
+func TestServerPreservesRequestID(t *testing.T) {
+	var calls int
+	handler := &mockHandler{
+		getTask: func(_ context.Context, req GetTaskRequest) (*Task, error) {
+			calls++
+			return &Task{ID: req.ID}, nil
+		},
+	}
+	ts := httptest.NewServer(NewServer(testCard(), handler).Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, id string) *http.Response {
+		t.Helper()
+		idField := ""
+		if id != "" {
+			idField = `"id":` + id + `,`
+		}
+		body := `{"jsonrpc":"2.0",` + idField + `"method":"tasks/get","params":{"id":"task-1"}}`
+		resp, err := http.Post(ts.URL+"/", "application/json", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	for _, tc := range []struct {
+		name string
+		id   string
+	}{
+		{"string", `"req-abc"`},
+		{"integer", `7`},
+		{"large integer", `12345678901234567890`},
+		{"fraction", `1.50`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := post(t, tc.id)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var raw map[string]json.RawMessage
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+			assert.Equal(t, tc.id, string(raw["id"]), "ID must be echoed exactly as sent")
+			assert.NotContains(t, raw, "error")
+		})
+	}
+
+	for _, tc := range []struct {
+		name string
+		id   string
+	}{
+		{"null", `null`},
+		{"absent", ``},
+	} {
+		t.Run("notification "+tc.name, func(t *testing.T) {
+			before := calls
+			resp := post(t, tc.id)
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Empty(t, body)
+			assert.Equal(t, before+1, calls, "notifications are still dispatched")
+		})
+	}
+}
+
+func TestServerRoundTripsClientRequestID(t *testing.T) {
+	handler := &mockHandler{
+		getTask: func(_ context.Context, req GetTaskRequest) (*Task, error) {
+			return &Task{ID: req.ID}, nil
+		},
+	}
+	ts := httptest.NewServer(NewServer(testCard(), handler).Handler())
+	defer ts.Close()
+
+	client := NewHTTPClient()
+	task, err := client.GetTask(context.Background(), ts.URL, GetTaskRequest{ID: "task-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", task.ID)
+}
@@ -5,7 +5,8 @@ import "encoding/json"
 // JSONRPCVersion is the JSON-RPC protocol version.
 const JSONRPCVersion = "2.0"
 
-// JSONRPCRequest is a JSON-RPC 2.0 request envelope.
+// JSONRPCRequest is a JSON-RPC 2.0 request envelope. ID is a string, a number,
+// or nil for a notification, which gets no response.
 type JSONRPCRequest struct {
 	JSONRPC string          `json:"jsonrpc"`
 	ID      any             `json:"id,omitempty"`
@@ -22,9 +22,10 @@ type Handler interface {
 
 // Server is the HTTP server that exposes an A2A agent.
 type Server struct {
-	card    AgentCard
-	handler Handler
-	http    *http.Server
+	card      AgentCard
+	handler   Handler
+	artifacts ArtifactStore
+	http      *http.Server
 }
 
 // NewServer creates an A2A server for the given agent.
@@ -34,3 +35,9 @@ func NewServer(card AgentCard, handler Handler) *Server {
 		handler: handler,
 	}
 }
+
+// SetArtifactStore makes the server serve the content of store at
+// ArtifactRoute.
+func (s *Server) SetArtifactStore(store ArtifactStore) {
+	s.artifacts = store
+}
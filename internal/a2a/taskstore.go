@@ -4,7 +4,9 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
+	"time"
 )
 
 // NewTaskID generates a UUID v4 string using crypto/rand.
@@ -22,18 +24,52 @@ func NewTaskID() string {
 // TaskStore is a concurrency-safe in-memory store for agent-side task tracking.
 // Tasks are stored in a map keyed by ID with a separate slice maintaining
 // insertion order for deterministic pagination.
+//
+// With a TTL configured (see WithTaskTTL), tasks that have been in a terminal
+// state for longer than the TTL expire: Get, Update, and List treat them as
+// not found, and Create evicts them from memory. Non-terminal tasks never
+// expire.
 type TaskStore struct {
 	mu       sync.RWMutex
 	tasks    map[string]*Task
 	orderIDs []string // insertion-order task IDs
+
+	ttl        time.Duration        // 0 = tasks never expire
+	now        func() time.Time     // clock for expiry; time.Now by default
+	finishedAt map[string]time.Time // task ID → when the store first saw it terminal
+}
+
+// TaskStoreOption configures a TaskStore.
+type TaskStoreOption func(*TaskStore)
+
+// WithTaskTTL expires terminal tasks d after they reach a terminal state.
+// A zero or negative d disables expiry.
+func WithTaskTTL(d time.Duration) TaskStoreOption {
+	return func(s *TaskStore) {
+		s.ttl = d
+	}
+}
+
+// WithTaskClock replaces the clock used to age terminal tasks. Intended for
+// tests.
+func WithTaskClock(now func() time.Time) TaskStoreOption {
+	return func(s *TaskStore) {
+		s.now = now
+	}
 }
 
 // NewTaskStore returns an initialized TaskStore ready for use.
-func NewTaskStore() *TaskStore {
-	return &TaskStore{
-		tasks:    make(map[string]*Task),
-		orderIDs: make([]string, 0),
+func NewTaskStore(opts ...TaskStoreOption) *TaskStore {
+	s := &TaskStore{
+		tasks:      make(map[string]*Task),
+		orderIDs:   make([]string, 0),
+		now:        time.Now,
+		finishedAt: make(map[string]time.Time),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Create stores a new task. It returns an error if a task with the same ID
@@ -42,11 +78,13 @@ func (s *TaskStore) Create(task Task) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.evictExpired()
 	if _, exists := s.tasks[task.ID]; exists {
 		return fmt.Errorf("task %q already exists", task.ID)
 	}
 	s.tasks[task.ID] = &task
 	s.orderIDs = append(s.orderIDs, task.ID)
+	s.trackFinished(&task)
 	return nil
 }
 
@@ -58,7 +96,7 @@ func (s *TaskStore) Get(id string) (*Task, error) {
 	defer s.mu.RUnlock()
 
 	t, ok := s.tasks[id]
-	if !ok {
+	if !ok || s.expired(id) {
 		return nil, fmt.Errorf("task %q not found", id)
 	}
 	return deepCopyTask(t), nil
@@ -72,10 +110,11 @@ func (s *TaskStore) Update(id string, fn func(*Task)) error {
 	defer s.mu.Unlock()
 
 	t, ok := s.tasks[id]
-	if !ok {
+	if !ok || s.expired(id) {
 		return fmt.Errorf("task %q not found", id)
 	}
 	fn(t)
+	s.trackFinished(t)
 	return nil
 }
 
@@ -113,7 +152,7 @@ func (s *TaskStore) List(filter ListTasksRequest) (*ListTasksResponse, error) {
 	var matched []Task
 	for i := startIdx; i < len(s.orderIDs); i++ {
 		t := s.tasks[s.orderIDs[i]]
-		if !matchesFilter(t, filter) {
+		if s.expired(t.ID) || !matchesFilter(t, filter) {
 			continue
 		}
 		matched = append(matched, *deepCopyTask(t))
@@ -123,7 +162,7 @@ func (s *TaskStore) List(filter ListTasksRequest) (*ListTasksResponse, error) {
 	totalBefore := 0
 	for i := 0; i < startIdx; i++ {
 		t := s.tasks[s.orderIDs[i]]
-		if matchesFilter(t, filter) {
+		if !s.expired(t.ID) && matchesFilter(t, filter) {
 			totalBefore++
 		}
 	}
@@ -148,6 +187,97 @@ func (s *TaskStore) List(filter ListTasksRequest) (*ListTasksResponse, error) {
 	}, nil
 }
 
+// ExportAll writes every unexpired task, with its artifacts and history, to w
+// as a JSON array in insertion order, for offline inspection or
+// loading into another store with ImportAll.
+func (s *TaskStore) ExportAll(w io.Writer) error {
+	s.mu.RLock()
+	tasks := make([]*Task, 0, len(s.orderIDs))
+	for _, id := range s.orderIDs {
+		if !s.expired(id) {
+			tasks = append(tasks, deepCopyTask(s.tasks[id]))
+		}
+	}
+	s.mu.RUnlock()
+
+	// Not indented: the encoder would reformat embedded raw JSON such as
+	// Metadata, so an imported task would no longer match its original.
+	if err := json.NewEncoder(w).Encode(tasks); err != nil {
+		return fmt.Errorf("export tasks: %w", err)
+	}
+	return nil
+}
+
+// ImportAll loads tasks written by ExportAll, appending them in dump order.
+// It returns an error, and imports nothing, if the dump cannot be decoded or
+// repeats a task ID already in the store or earlier in the dump. Imported
+// terminal tasks start aging toward the TTL when they are loaded.
+func (s *TaskStore) ImportAll(r io.Reader) error {
+	var tasks []Task
+	if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+		return fmt.Errorf("import tasks: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	seen := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		if _, exists := s.tasks[t.ID]; exists || seen[t.ID] {
+			return fmt.Errorf("import tasks: task %q already exists", t.ID)
+		}
+		seen[t.ID] = true
+	}
+	for i := range tasks {
+		t := &tasks[i]
+		s.tasks[t.ID] = t
+		s.orderIDs = append(s.orderIDs, t.ID)
+		s.trackFinished(t)
+	}
+	return nil
+}
+
+// trackFinished records when t was first seen in a terminal state, and
+// forgets it if t has left one. Caller must hold the write lock.
+func (s *TaskStore) trackFinished(t *Task) {
+	if !t.Status.State.IsTerminal() {
+		delete(s.finishedAt, t.ID)
+		return
+	}
+	if _, ok := s.finishedAt[t.ID]; !ok {
+		s.finishedAt[t.ID] = s.now()
+	}
+}
+
+// expired reports whether the task has been terminal for longer than the TTL.
+// Caller must hold the lock.
+func (s *TaskStore) expired(id string) bool {
+	if s.ttl <= 0 {
+		return false
+	}
+	at, ok := s.finishedAt[id]
+	return ok && s.now().Sub(at) >= s.ttl
+}
+
+// evictExpired removes expired tasks from the store. Caller must hold the
+// write lock.
+func (s *TaskStore) evictExpired() {
+	if s.ttl <= 0 || len(s.finishedAt) == 0 {
+		return
+	}
+	kept := s.orderIDs[:0]
+	for _, id := range s.orderIDs {
+		if s.expired(id) {
+			delete(s.tasks, id)
+			delete(s.finishedAt, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	s.orderIDs = kept
+}
+
 // matchesFilter returns true if the task passes the context ID and status
 // filters specified in the request.
 func matchesFilter(t *Task, filter ListTasksRequest) bool {
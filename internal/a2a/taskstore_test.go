@@ -1,9 +1,11 @@
 package a2a
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -310,3 +312,113 @@ func TestTaskStore_UpdateMultipleTimes(t *testing.T) {
 	require.Len(t, got.Artifacts, 1)
 	assert.Equal(t, "final", got.Artifacts[0].ArtifactID)
 }
+
+func TestTaskStore_TTLExpiresTerminalTasks(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewTaskStore(WithTaskTTL(time.Minute), WithTaskClock(func() time.Time { return now }))
+
+	require.NoError(t, store.Create(Task{ID: "done", ContextID: "ctx-1", Status: TaskStatus{State: TaskStateWorking}}))
+	require.NoError(t, store.Create(Task{ID: "busy", ContextID: "ctx-1", Status: TaskStatus{State: TaskStateWorking}}))
+
+	// The TTL counts from completion, not creation.
+	now = now.Add(10 * time.Minute)
+	require.NoError(t, store.Update("done", func(t *Task) {
+		t.Status.State = TaskStateCompleted
+	}))
+
+	now = now.Add(59 * time.Second)
+	_, err := store.Get("done")
+	require.NoError(t, err, "completed task should be available within its TTL")
+
+	now = now.Add(time.Second)
+	_, err = store.Get("done")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Error(t, store.Update("done", func(*Task) {}))
+
+	resp, err := store.List(ListTasksRequest{ContextID: "ctx-1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Tasks, 1)
+	assert.Equal(t, "busy", resp.Tasks[0].ID)
+	assert.Equal(t, 1, resp.TotalSize)
+
+	// Working tasks never expire, and Create evicts expired ones from memory.
+	now = now.Add(24 * time.Hour)
+	_, err = store.Get("busy")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Create(Task{ID: "next", ContextID: "ctx-1", Status: TaskStatus{State: TaskStateSubmitted}}))
+	assert.NotContains(t, store.tasks, "done")
+	assert.Equal(t, []string{"busy", "next"}, store.orderIDs)
+}
+
+func TestTaskStore_NoTTLKeepsTerminalTasks(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewTaskStore(WithTaskClock(func() time.Time { return now }))
+
+	require.NoError(t, store.Create(Task{ID: "done", Status: TaskStatus{State: TaskStateCompleted}}))
+	now = now.Add(365 * 24 * time.Hour)
+
+	_, err := store.Get("done")
+	require.NoError(t, err)
+}
+
+func TestTaskStore_ExportImportRoundTrip(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	src := NewTaskStore()
+	tasks := []Task{
+		{
+			ID:        "task-1",
+			ContextID: "ctx-1",
+			Status: TaskStatus{
+				State:     TaskStateCompleted,
+				Timestamp: ts,
+				Message:   &Message{MessageID: "status-1", Role: RoleAgent, Parts: []Part{TextPart("done")}},
+			},
+			Artifacts: []Artifact{{
+				ArtifactID: "art-1",
+				Name:       "output",
+				Parts:      []Part{TextPart("hello"), {Raw: []byte{0, 1, 2}, MediaType: "application/octet-stream"}},
+				Metadata:   []byte(`{"stage":1}`),
+			}},
+			History: []Message{
+				{MessageID: "msg-1", Role: RoleUser, Parts: []Part{{Data: []byte(`{"k":"v"}`)}}, ReferenceTaskIDs: []string{"task-0"}},
+			},
+			Metadata: []byte(`{"agent":"research"}`),
+		},
+		{ID: "task-2", ContextID: "ctx-2", Status: TaskStatus{State: TaskStateWorking, Timestamp: ts}},
+		{ID: "task-3", ContextID: "ctx-1", Status: TaskStatus{State: TaskStateFailed, Timestamp: ts}},
+	}
+	for _, task := range tasks {
+		require.NoError(t, src.Create(task))
+	}
+
+	var dump bytes.Buffer
+	require.NoError(t, src.ExportAll(&dump))
+
+	dst := NewTaskStore()
+	require.NoError(t, dst.ImportAll(bytes.NewReader(dump.Bytes())))
+
+	for _, task := range tasks {
+		want, err := src.Get(task.ID)
+		require.NoError(t, err)
+		got, err := dst.Get(task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	for _, filter := range []ListTasksRequest{{}, {ContextID: "ctx-1"}, {Status: string(TaskStateWorking)}, {PageSize: 2}} {
+		want, err := src.List(filter)
+		require.NoError(t, err)
+		got, err := dst.List(filter)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "filter %+v", filter)
+	}
+
+	// Importing the same dump again would duplicate IDs and is rejected whole.
+	err := dst.ImportAll(bytes.NewReader(dump.Bytes()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task-1")
+	list, err := dst.List(ListTasksRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.TotalSize)
+}
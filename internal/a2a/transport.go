@@ -0,0 +1,92 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Transport carries a Client's requests to agents. Implementations exchange
+// JSON-RPC params and results for an endpoint and look up agent cards; the
+// default transport uses HTTP.
+type Transport interface {
+	// RoundTrip invokes method on the agent at endpoint and returns the raw
+	// JSON-RPC result. JSON-RPC errors are returned as *RPCError.
+	RoundTrip(ctx context.Context, endpoint, method string, params json.RawMessage) (json.RawMessage, error)
+
+	// DiscoverAgent returns the Agent Card of the agent at endpoint.
+	DiscoverAgent(ctx context.Context, endpoint string) (*AgentCard, error)
+}
+
+// Compile-time interface checks.
+var (
+	_ Transport = (*httpTransport)(nil)
+	_ Transport = (*InProcessTransport)(nil)
+)
+
+// InProcessTransport routes calls directly to Handlers in the same process,
+// keyed by endpoint, with no network involved. Params and results still go
+// through JSON so agents see exactly what they would over HTTP.
+type InProcessTransport struct {
+	mu     sync.RWMutex
+	agents map[string]inProcessAgent
+}
+
+// inProcessAgent is one agent registered with an InProcessTransport.
+type inProcessAgent struct {
+	card    AgentCard
+	handler Handler
+}
+
+// NewInProcessTransport creates an InProcessTransport with no agents.
+func NewInProcessTransport() *InProcessTransport {
+	return &InProcessTransport{agents: make(map[string]inProcessAgent)}
+}
+
+// Register makes handler reachable at endpoint, advertising card.
+func (t *InProcessTransport) Register(endpoint string, card AgentCard, handler Handler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.agents[endpoint] = inProcessAgent{card: card, handler: handler}
+}
+
+// lookup returns the agent registered at endpoint.
+func (t *InProcessTransport) lookup(endpoint string) (inProcessAgent, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	agent, ok := t.agents[endpoint]
+	if !ok {
+		return inProcessAgent{}, fmt.Errorf("a2a: no in-process agent registered at %s", endpoint)
+	}
+	return agent, nil
+}
+
+// RoundTrip dispatches method to the Handler registered at endpoint.
+func (t *InProcessTransport) RoundTrip(ctx context.Context, endpoint, method string, params json.RawMessage) (json.RawMessage, error) {
+	agent, err := t.lookup(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	result, rpcErr := dispatchRPC(ctx, agent.handler, method, params)
+	if rpcErr != nil {
+		return nil, &RPCError{Method: method, Code: rpcErr.Code, Message: rpcErr.Message}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("a2a: marshal result: %w", err)
+	}
+	return data, nil
+}
+
+// DiscoverAgent returns the card registered at endpoint.
+func (t *InProcessTransport) DiscoverAgent(_ context.Context, endpoint string) (*AgentCard, error) {
+	agent, err := t.lookup(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	card := agent.card
+	return &card, nil
+}
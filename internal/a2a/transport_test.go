@@ -0,0 +1,44 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInProcessTransport_RoutesToHandler(t *testing.T) {
+	handler := &mockHandler{
+		getTask: func(_ context.Context, req GetTaskRequest) (*Task, error) {
+			return &Task{ID: req.ID, Status: TaskStatus{State: TaskStateWorking}}, nil
+		},
+		cancelTask: func(_ context.Context, req CancelTaskRequest) (*Task, error) {
+			return nil, fmt.Errorf("task %s is not cancelable", req.ID)
+		},
+	}
+	transport := NewInProcessTransport()
+	transport.Register("mem://agent", AgentCard{Name: "mem-agent"}, handler)
+	client := NewHTTPClient(WithTransport(transport))
+	ctx := context.Background()
+
+	card, err := client.DiscoverAgent(ctx, "mem://agent")
+	require.NoError(t, err)
+	assert.Equal(t, "mem-agent", card.Name)
+
+	task, err := client.GetTask(ctx, "mem://agent", GetTaskRequest{ID: "t-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "t-1", task.ID)
+	assert.Equal(t, TaskStateWorking, task.Status.State)
+
+	_, err = client.CancelTask(ctx, "mem://agent", CancelTaskRequest{ID: "t-1"})
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, ErrCodeInternal, rpcErr.Code)
+	assert.Contains(t, rpcErr.Message, "not cancelable")
+
+	_, err = client.GetTask(ctx, "mem://other", GetTaskRequest{ID: "t-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no in-process agent registered at mem://other")
+}
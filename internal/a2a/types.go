@@ -2,6 +2,8 @@ package a2a
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -72,7 +74,7 @@ type Message struct {
 }
 
 // Part carries content within a message or artifact.
-// Exactly one of Text, Raw, URL, or Data must be set.
+// Exactly one of Text, Raw, URL, or Data must be set; see Validate.
 type Part struct {
 	Text      string          `json:"text,omitempty"`
 	Raw       []byte          `json:"raw,omitempty"`
@@ -88,6 +90,49 @@ func TextPart(text string) Part {
 	return Part{Text: text, MediaType: "text/plain"}
 }
 
+// Validate reports an error unless exactly one of Text, Raw, URL, or Data is
+// set.
+func (p Part) Validate() error {
+	var forms []string
+	if p.Text != "" {
+		forms = append(forms, "text")
+	}
+	if len(p.Raw) > 0 {
+		forms = append(forms, "raw")
+	}
+	if p.URL != "" {
+		forms = append(forms, "url")
+	}
+	if len(p.Data) > 0 {
+		forms = append(forms, "data")
+	}
+	switch len(forms) {
+	case 1:
+		return nil
+	case 0:
+		return fmt.Errorf("part has no content; exactly one of text, raw, url, or data is required")
+	default:
+		return fmt.Errorf("part has multiple content forms (%s); exactly one of text, raw, url, or data is required", strings.Join(forms, ", "))
+	}
+}
+
+// Normalize fills in a default MediaType for the part's content form when
+// none is set: text/plain for text, application/json for data, and
+// application/octet-stream for raw bytes. URL parts are left unchanged.
+func (p *Part) Normalize() {
+	if p.MediaType != "" {
+		return
+	}
+	switch {
+	case p.Text != "":
+		p.MediaType = "text/plain"
+	case len(p.Data) > 0:
+		p.MediaType = "application/json"
+	case len(p.Raw) > 0:
+		p.MediaType = "application/octet-stream"
+	}
+}
+
 // DataPart creates a Part with structured JSON data.
 func DataPart(v any) (Part, error) {
 	data, err := json.Marshal(v)
@@ -181,6 +226,18 @@ type SendMessageRequest struct {
 	Configuration *SendMessageConfig `json:"configuration,omitempty"`
 }
 
+// normalize validates and normalizes every part of the message. The server
+// calls it after decoding the request.
+func (r *SendMessageRequest) normalize() error {
+	for i := range r.Message.Parts {
+		if err := r.Message.Parts[i].Validate(); err != nil {
+			return fmt.Errorf("message.parts[%d]: %w", i, err)
+		}
+		r.Message.Parts[i].Normalize()
+	}
+	return nil
+}
+
 // SendMessageConfig controls message handling behavior.
 type SendMessageConfig struct {
 	AcceptedOutputModes []string `json:"acceptedOutputModes,omitempty"`
@@ -192,6 +249,51 @@ type SendMessageConfig struct {
 type GetTaskRequest struct {
 	ID            string `json:"id"`
 	HistoryLength *int   `json:"historyLength,omitempty"`
+	// ResolveArtifacts asks the agent to inline artifact parts it stored by
+	// reference, replacing their URL with the original text.
+	ResolveArtifacts bool `json:"resolveArtifacts,omitempty"`
+	// Fields limits the task to part of its content; see TaskFieldsStatus
+	// and TaskFieldsArtifacts. Empty returns the full task.
+	Fields string `json:"fields,omitempty"`
+}
+
+// Field selectors for GetTaskRequest.Fields. The task's ID, context ID, and
+// status are always returned.
+const (
+	// TaskFieldsStatus omits artifacts and history.
+	TaskFieldsStatus = "status"
+	// TaskFieldsArtifacts omits history.
+	TaskFieldsArtifacts = "artifacts"
+)
+
+// normalize validates Fields and HistoryLength. The server calls it after
+// decoding the request.
+func (r *GetTaskRequest) normalize() error {
+	switch r.Fields {
+	case "", TaskFieldsStatus, TaskFieldsArtifacts:
+	default:
+		return fmt.Errorf("fields: unknown selector %q", r.Fields)
+	}
+	if r.HistoryLength != nil && *r.HistoryLength < 0 {
+		return fmt.Errorf("historyLength: must not be negative, got %d", *r.HistoryLength)
+	}
+	return nil
+}
+
+// Apply trims task in place to what the request selects: history is cut to
+// the last HistoryLength messages and content excluded by Fields is dropped.
+// task should be a copy the caller owns.
+func (r GetTaskRequest) Apply(task *Task) {
+	if r.HistoryLength != nil && len(task.History) > *r.HistoryLength {
+		task.History = task.History[len(task.History)-max(*r.HistoryLength, 0):]
+	}
+	switch r.Fields {
+	case TaskFieldsStatus:
+		task.Artifacts = nil
+		task.History = nil
+	case TaskFieldsArtifacts:
+		task.History = nil
+	}
 }
 
 // ListTasksRequest queries tasks with filtering and pagination.
@@ -391,3 +391,76 @@ func TestDataPart_InvalidInput(t *testing.T) {
 	_, err := DataPart(make(chan int))
 	assert.Error(t, err)
 }
+
+func TestPart_Validate(t *testing.T) {
+	assert.NoError(t, TextPart("hi").Validate())
+	assert.NoError(t, Part{URL: "https://example.com/a"}.Validate())
+	assert.ErrorContains(t, Part{}.Validate(), "part has no content")
+	assert.ErrorContains(t, Part{Text: "hi", URL: "https://example.com/a"}.Validate(), "(text, url)")
+}
+
+func TestPart_Normalize(t *testing.T) {
+	for _, tc := range []struct {
+		part Part
+		want string
+	}{
+		{Part{Text: "hi"}, "text/plain"},
+		{Part{Data: json.RawMessage(`{}`)}, "application/json"},
+		{Part{Raw: []byte{1}}, "application/octet-stream"},
+		{Part{URL: "https://example.com/a"}, ""},
+		{Part{Text: "# hi", MediaType: "text/markdown"}, "text/markdown"},
+	} {
+		tc.part.Normalize()
+		assert.Equal(t, tc.want, tc.part.MediaType)
+	}
+}
+
+func TestGetTaskRequest_Apply(t *testing.T) {
+	store := NewTaskStore()
+	require.NoError(t, store.Create(Task{
+		ID:     "task-1",
+		Status: TaskStatus{State: TaskStateCompleted},
+		Artifacts: []Artifact{
+			{ArtifactID: "a1", Parts: []Part{TextPart("one")}},
+			{ArtifactID: "a2", Parts: []Part{TextPart("two")}},
+		},
+		History: []Message{
+			{MessageID: "m1", Role: RoleUser, Parts: []Part{TextPart("first")}},
+			{MessageID: "m2", Role: RoleAgent, Parts: []Part{TextPart("second")}},
+			{MessageID: "m3", Role: RoleUser, Parts: []Part{TextPart("third")}},
+		},
+	}))
+
+	get := func(req GetTaskRequest) *Task {
+		task, err := store.Get(req.ID)
+		require.NoError(t, err)
+		req.Apply(task)
+		return task
+	}
+
+	one := 1
+	task := get(GetTaskRequest{ID: "task-1", HistoryLength: &one})
+	require.Len(t, task.History, 1)
+	assert.Equal(t, "m3", task.History[0].MessageID)
+	assert.Len(t, task.Artifacts, 2, "artifacts should remain complete")
+
+	task = get(GetTaskRequest{ID: "task-1", Fields: TaskFieldsStatus})
+	assert.Equal(t, TaskStateCompleted, task.Status.State)
+	assert.Empty(t, task.Artifacts)
+	assert.Empty(t, task.History)
+
+	task = get(GetTaskRequest{ID: "task-1", Fields: TaskFieldsArtifacts})
+	assert.Len(t, task.Artifacts, 2)
+	assert.Empty(t, task.History)
+
+	// Trimming a copy leaves the stored task intact.
+	task = get(GetTaskRequest{ID: "task-1"})
+	assert.Len(t, task.History, 3)
+}
+
+func TestGetTaskRequest_NormalizeRejectsInvalid(t *testing.T) {
+	neg := -1
+	assert.Error(t, (&GetTaskRequest{ID: "t", Fields: "everything"}).normalize())
+	assert.Error(t, (&GetTaskRequest{ID: "t", HistoryLength: &neg}).normalize())
+	assert.NoError(t, (&GetTaskRequest{ID: "t", Fields: TaskFieldsStatus}).normalize())
+}
@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// artifactRef is the metadata of a part whose text was moved to the
+// agent's ArtifactStore.
+type artifactRef struct {
+	ArtifactID string `json:"artifactId"`
+	Size       int    `json:"size"`
+}
+
+// artifactID returns the ID for the nth artifact named name emitted while
+// processing msg: "<name>-<hash>", where the hash covers the agent name,
+// the artifact name, n, and the message parts. See SetRandomArtifactIDs.
+func (b *BaseAgent) artifactID(name string, msg a2a.Message, n int) string {
+	if b.randomArtifactIDs {
+		return a2a.NewTaskID()
+	}
+	if name == "" {
+		name = "artifact"
+	}
+	parts, _ := json.Marshal(msg.Parts)
+	return derivedArtifactID(name, []byte(b.card.Name), []byte(name), []byte(strconv.Itoa(n)), parts)
+}
+
+// derivedArtifactID returns "<name>-<hash>", where the hash covers fields.
+func derivedArtifactID(name string, fields ...[]byte) string {
+	h := sha256.New()
+	for _, field := range fields {
+		h.Write(field)
+		h.Write([]byte{0})
+	}
+	return name + "-" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// SetArtifactStore makes the agent store text parts longer than threshold
+// bytes in store and replace them with URL parts pointing at baseURL's
+// artifact route, which the agent's server then serves. An empty baseURL
+// is derived from the address passed to Start. Call before Start.
+func (b *BaseAgent) SetArtifactStore(store a2a.ArtifactStore, threshold int, baseURL string) {
+	b.artifacts = store
+	b.artifactThreshold = threshold
+	b.artifactBaseURL = baseURL
+	b.server.SetArtifactStore(store)
+}
+
+// offloadArtifact moves oversized text parts of art into the artifact store.
+// Parts that cannot be stored stay inline.
+func (b *BaseAgent) offloadArtifact(ctx context.Context, art a2a.Artifact) a2a.Artifact {
+	if b.artifacts == nil {
+		return art
+	}
+	parts := make([]a2a.Part, len(art.Parts))
+	for i, p := range art.Parts {
+		parts[i] = p
+		if len(p.Text) <= b.artifactThreshold {
+			continue
+		}
+		id, err := b.artifacts.Put(ctx, []byte(p.Text), p.MediaType)
+		if err != nil {
+			continue
+		}
+		meta, _ := json.Marshal(artifactRef{ArtifactID: id, Size: len(p.Text)})
+		parts[i] = a2a.Part{
+			URL:       a2a.ArtifactURL(b.artifactBaseURL, id),
+			MediaType: p.MediaType,
+			Filename:  p.Filename,
+			Metadata:  meta,
+		}
+	}
+	art.Parts = parts
+	return art
+}
+
+// rehydrateArtifacts returns a copy of task with parts stored by
+// offloadArtifact inlined again.
+func (b *BaseAgent) rehydrateArtifacts(ctx context.Context, task *a2a.Task) *a2a.Task {
+	if b.artifacts == nil {
+		return task
+	}
+	out := *task
+	out.Artifacts = make([]a2a.Artifact, len(task.Artifacts))
+	for i, art := range task.Artifacts {
+		parts := make([]a2a.Part, len(art.Parts))
+		for j, p := range art.Parts {
+			parts[j] = p
+			var ref artifactRef
+			if p.URL == "" || json.Unmarshal(p.Metadata, &ref) != nil || ref.ArtifactID == "" {
+				continue
+			}
+			content, _, err := b.artifacts.Get(ctx, ref.ArtifactID)
+			if err != nil {
+				continue
+			}
+			parts[j] = a2a.Part{Text: string(content), MediaType: p.MediaType, Filename: p.Filename}
+		}
+		art.Parts = parts
+		out.Artifacts[i] = art
+	}
+	return &out
+}
+
+// artifactBaseFromAddr turns a listen address into a base URL, defaulting
+// the host to localhost.
+func artifactBaseFromAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	return "http://" + addr
+}
@@ -2,7 +2,10 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/onedusk/pd/internal/a2a"
@@ -16,25 +19,79 @@ var (
 
 // ProcessFunc is the function that specialist agents implement to handle
 // incoming messages. It receives the task (in WORKING state) and the message,
-// and returns artifacts to attach to the completed task.
+// and returns artifacts to attach to the completed task. Artifacts returned
+// with an error are attached to the failed task. Artifacts without an
+// ArtifactID are given one; see SetRandomArtifactIDs. To ask the caller for
+// more input instead of finishing, return an InputRequiredError.
 type ProcessFunc func(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error)
 
+// StreamingProcessFunc is an alternative to ProcessFunc for specialists that
+// produce output incrementally. It calls emit once per artifact as soon as the
+// artifact is ready; BaseAgent attaches each one to the task and streams it to
+// subscribers. Returning nil completes the task with every emitted artifact.
+type StreamingProcessFunc func(ctx context.Context, task *a2a.Task, msg a2a.Message, emit func(a2a.Artifact)) error
+
+// streamingAdapter adapts a ProcessFunc to the StreamingProcessFunc shape by
+// emitting its artifacts after it returns. Artifacts returned alongside an
+// error are still emitted, so a failed task can carry a report of why.
+func streamingAdapter(process ProcessFunc) StreamingProcessFunc {
+	return func(ctx context.Context, task *a2a.Task, msg a2a.Message, emit func(a2a.Artifact)) error {
+		artifacts, err := process(ctx, task, msg)
+		for _, art := range artifacts {
+			emit(art)
+		}
+		return err
+	}
+}
+
 // BaseAgent provides shared boilerplate for specialist agents. It composes an
 // A2A server and task store, implementing both the Agent and a2a.Handler
-// interfaces. Specialist agents embed BaseAgent and provide a ProcessFunc.
+// interfaces. Specialist agents embed BaseAgent and provide a ProcessFunc or
+// StreamingProcessFunc.
 type BaseAgent struct {
 	server  *a2a.Server
 	store   *a2a.TaskStore
 	card    a2a.AgentCard
-	process ProcessFunc
+	process StreamingProcessFunc
+
+	// cancelOnStop makes Stop cancel in-flight tasks; see SetCancelOnStop.
+	cancelOnStop bool
+
+	// randomArtifactIDs replaces derived artifact IDs with random ones; see
+	// SetRandomArtifactIDs.
+	randomArtifactIDs bool
+
+	// Large text parts are stored by reference; see SetArtifactStore.
+	artifacts         a2a.ArtifactStore
+	artifactThreshold int
+	artifactBaseURL   string
+
+	mu       sync.Mutex
+	inFlight map[string]context.CancelFunc // task ID → cancel for running process functions
+	closed   bool                          // set by Stop; track refuses new tasks once set
+	wg       sync.WaitGroup
 }
 
+// stopWaitTimeout bounds how long Stop waits for canceled ProcessFuncs to
+// return when the caller's context has no earlier deadline.
+const stopWaitTimeout = 10 * time.Second
+
+// shutdownMessage is attached to tasks canceled by Stop.
+const shutdownMessage = "agent shutting down"
+
 // NewBaseAgent creates a BaseAgent with the given card and process function.
 func NewBaseAgent(card a2a.AgentCard, process ProcessFunc) *BaseAgent {
+	return NewStreamingBaseAgent(card, streamingAdapter(process))
+}
+
+// NewStreamingBaseAgent creates a BaseAgent whose process function emits
+// artifacts incrementally.
+func NewStreamingBaseAgent(card a2a.AgentCard, process StreamingProcessFunc) *BaseAgent {
 	b := &BaseAgent{
-		store:   a2a.NewTaskStore(),
-		card:    card,
-		process: process,
+		store:    a2a.NewTaskStore(),
+		card:     card,
+		process:  process,
+		inFlight: make(map[string]context.CancelFunc),
 	}
 	b.server = a2a.NewServer(card, b)
 	return b
@@ -47,11 +104,21 @@ func (b *BaseAgent) Card() a2a.AgentCard {
 
 // HandleTask processes an A2A task with a message and returns the completed task.
 func (b *BaseAgent) HandleTask(ctx context.Context, task a2a.Task, msg a2a.Message) (*a2a.Task, error) {
+	return b.HandleTaskStream(ctx, task, msg, nil)
+}
+
+// HandleTaskStream processes an A2A task like HandleTask, additionally
+// reporting progress to onEvent: one ArtifactUpdate event per emitted
+// artifact, followed by a final StatusUpdate event when the task reaches a
+// terminal state or INPUT_REQUIRED. onEvent may be nil and is called
+// synchronously from the process function's goroutine.
+func (b *BaseAgent) HandleTaskStream(ctx context.Context, task a2a.Task, msg a2a.Message, onEvent func(a2a.StreamEvent)) (*a2a.Task, error) {
 	// Store the task in SUBMITTED state.
 	task.Status = a2a.TaskStatus{
 		State:     a2a.TaskStateSubmitted,
 		Timestamp: time.Now(),
 	}
+	task.History = append(task.History, msg)
 	if err := b.store.Create(task); err != nil {
 		return nil, fmt.Errorf("create task: %w", err)
 	}
@@ -65,50 +132,286 @@ func (b *BaseAgent) HandleTask(ctx context.Context, task a2a.Task, msg a2a.Messa
 	}); err != nil {
 		return nil, fmt.Errorf("update task to working: %w", err)
 	}
+	task.Status.State = a2a.TaskStateWorking
+	return b.run(ctx, task, msg, onEvent)
+}
+
+// ResumeTask continues a task that an InputRequiredError left in
+// INPUT_REQUIRED, passing msg (the caller's reply) to the process function
+// along with the task, whose History holds the earlier messages.
+func (b *BaseAgent) ResumeTask(ctx context.Context, taskID string, msg a2a.Message) (*a2a.Task, error) {
+	// Claim the task under the store lock so only one reply resumes it.
+	awaiting := false
+	if err := b.store.Update(taskID, func(t *a2a.Task) {
+		if t.Status.State != a2a.TaskStateInputRequired {
+			return
+		}
+		awaiting = true
+		t.History = append(t.History, msg)
+		t.Status = a2a.TaskStatus{
+			State:     a2a.TaskStateWorking,
+			Timestamp: time.Now(),
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if !awaiting {
+		return nil, fmt.Errorf("task %s is not awaiting input", taskID)
+	}
+	task, err := b.store.Get(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return b.run(ctx, *task, msg, nil)
+}
+
+// run calls the process function for a task in WORKING state and records
+// its outcome.
+func (b *BaseAgent) run(ctx context.Context, task a2a.Task, msg a2a.Message, onEvent func(a2a.StreamEvent)) (*a2a.Task, error) {
+	// emit attaches each artifact to the stored task as soon as it is
+	// produced so that GetTask observes partial output.
+	named := make(map[string]int)
+	emit := func(art a2a.Artifact) {
+		if art.ArtifactID == "" {
+			art.ArtifactID = b.artifactID(art.Name, msg, named[art.Name])
+			named[art.Name]++
+		}
+		art = b.offloadArtifact(ctx, art)
+		appended := false
+		_ = b.store.Update(task.ID, func(t *a2a.Task) {
+			if t.Status.State.IsTerminal() {
+				return
+			}
+			t.Artifacts = append(t.Artifacts, art)
+			appended = true
+		})
+		if appended && onEvent != nil {
+			onEvent(a2a.StreamEvent{ArtifactUpdate: &a2a.TaskArtifactUpdateEvent{
+				TaskID:    task.ID,
+				ContextID: task.ContextID,
+				Artifact:  art,
+			}})
+		}
+	}
+
+	// Run the specialist's process function under a context that Stop can
+	// cancel.
+	procCtx, cancel := context.WithCancel(ctx)
+	if !b.track(task.ID, cancel) {
+		// Stop is canceling in-flight tasks; don't start another.
+		cancel()
+		b.markShutdown(task.ID)
+		result, err := b.store.Get(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		b.emitStatus(result, onEvent)
+		return result, context.Canceled
+	}
+	err := b.process(procCtx, &task, msg, emit)
+	b.untrack(task.ID)
+	cancel()
+
+	var inputErr *InputRequiredError
+	if errors.As(err, &inputErr) {
+		// Pause in INPUT_REQUIRED with the prompt, unless the task was
+		// canceled meanwhile; ResumeTask continues it.
+		prompt := a2a.Message{
+			MessageID: a2a.NewTaskID(),
+			ContextID: task.ContextID,
+			TaskID:    task.ID,
+			Role:      a2a.RoleAgent,
+			Parts:     []a2a.Part{a2a.TextPart(inputErr.Prompt)},
+		}
+		_ = b.store.Update(task.ID, func(t *a2a.Task) {
+			if t.Status.State.IsTerminal() {
+				return
+			}
+			t.History = append(t.History, prompt)
+			t.Status = a2a.TaskStatus{
+				State:     a2a.TaskStateInputRequired,
+				Timestamp: time.Now(),
+				Message:   &prompt,
+			}
+		})
+		result, err := b.store.Get(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		b.emitStatus(result, onEvent)
+		return result, nil
+	}
 
-	// Run the specialist's process function.
-	artifacts, err := b.process(ctx, &task, msg)
 	if err != nil {
-		// Transition to FAILED.
+		// Transition to FAILED unless the task was already canceled. The
+		// status message metadata records whether the failure is retryable;
+		// see TaskFailure.
+		meta, _ := json.Marshal(failureMetadata(err))
 		_ = b.store.Update(task.ID, func(t *a2a.Task) {
+			if t.Status.State.IsTerminal() {
+				return
+			}
 			t.Status = a2a.TaskStatus{
 				State:     a2a.TaskStateFailed,
 				Timestamp: time.Now(),
-				Message:   &a2a.Message{Role: a2a.RoleAgent, Parts: []a2a.Part{a2a.TextPart(err.Error())}},
+				Message: &a2a.Message{
+					Role:     a2a.RoleAgent,
+					Parts:    []a2a.Part{a2a.TextPart(err.Error())},
+					Metadata: meta,
+				},
 			}
 		})
 		result, _ := b.store.Get(task.ID)
+		b.emitStatus(result, onEvent)
 		return result, err
 	}
 
-	// Transition to COMPLETED with artifacts.
+	// Transition to COMPLETED unless the task was canceled while the process
+	// function was running.
 	if err := b.store.Update(task.ID, func(t *a2a.Task) {
+		if t.Status.State.IsTerminal() {
+			return
+		}
 		t.Status = a2a.TaskStatus{
 			State:     a2a.TaskStateCompleted,
 			Timestamp: time.Now(),
 		}
-		t.Artifacts = artifacts
 	}); err != nil {
 		return nil, fmt.Errorf("update task to completed: %w", err)
 	}
 
-	return b.store.Get(task.ID)
+	result, err := b.store.Get(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	b.emitStatus(result, onEvent)
+	return result, nil
+}
+
+// emitStatus reports the final status of task to onEvent, if set.
+func (b *BaseAgent) emitStatus(task *a2a.Task, onEvent func(a2a.StreamEvent)) {
+	if task == nil || onEvent == nil {
+		return
+	}
+	onEvent(a2a.StreamEvent{StatusUpdate: &a2a.TaskStatusUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Status:    task.Status,
+	}})
 }
 
 // Start launches the agent's HTTP server on the given address.
 func (b *BaseAgent) Start(ctx context.Context, addr string) error {
+	if b.artifacts != nil && b.artifactBaseURL == "" {
+		b.artifactBaseURL = artifactBaseFromAddr(addr)
+	}
 	return b.server.Start(ctx, addr)
 }
 
+// SetCancelOnStop controls whether Stop cancels in-flight tasks. When
+// enabled, Stop marks every running task CANCELED with a shutdown message,
+// cancels the context passed to its ProcessFunc, and waits (bounded by ctx or
+// stopWaitTimeout) for the functions to return before shutting down the
+// server. Disabled by default.
+func (b *BaseAgent) SetCancelOnStop(enabled bool) {
+	b.cancelOnStop = enabled
+}
+
+// SetRandomArtifactIDs controls how artifacts emitted without an ArtifactID
+// are identified. By default the ID is derived from the agent, the artifact
+// name, and the message parts, so identical requests yield identical IDs;
+// when enabled, each artifact gets a random ID instead.
+func (b *BaseAgent) SetRandomArtifactIDs(enabled bool) {
+	b.randomArtifactIDs = enabled
+}
+
 // Stop gracefully shuts down the agent.
 func (b *BaseAgent) Stop(ctx context.Context) error {
+	if b.cancelOnStop {
+		b.cancelInFlight()
+		b.waitInFlight(ctx)
+	}
 	return b.server.Stop(ctx)
 }
 
+// track registers the cancel function of a running ProcessFunc. It reports
+// false, registering nothing, once Stop has begun canceling in-flight tasks.
+func (b *BaseAgent) track(taskID string, cancel context.CancelFunc) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return false
+	}
+	b.inFlight[taskID] = cancel
+	b.wg.Add(1)
+	return true
+}
+
+// untrack removes a task registered with track.
+func (b *BaseAgent) untrack(taskID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.inFlight[taskID]; ok {
+		delete(b.inFlight, taskID)
+		b.wg.Done()
+	}
+}
+
+// cancelInFlight closes the agent to new tasks, then transitions every
+// running task to CANCELED and cancels its ProcessFunc context.
+func (b *BaseAgent) cancelInFlight() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for id, cancel := range b.inFlight {
+		b.markShutdown(id)
+		cancel()
+	}
+}
+
+// markShutdown transitions a task to CANCELED with shutdownMessage unless it
+// is already terminal.
+func (b *BaseAgent) markShutdown(taskID string) {
+	_ = b.store.Update(taskID, func(t *a2a.Task) {
+		if t.Status.State.IsTerminal() {
+			return
+		}
+		t.Status = a2a.TaskStatus{
+			State:     a2a.TaskStateCanceled,
+			Timestamp: time.Now(),
+			Message:   &a2a.Message{Role: a2a.RoleAgent, Parts: []a2a.Part{a2a.TextPart(shutdownMessage)}},
+		}
+	})
+}
+
+// waitInFlight blocks until all tracked ProcessFuncs return, ctx is done, or
+// stopWaitTimeout elapses, whichever comes first. cancelInFlight must run
+// first: it closes the agent to new tasks, so no wg.Add races the wg.Wait.
+func (b *BaseAgent) waitInFlight(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(stopWaitTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
 // --- a2a.Handler implementation ---
 
-// HandleSendMessage creates a task from the incoming message and processes it.
+// HandleSendMessage creates a task from the incoming message and processes
+// it. A message naming an existing task by TaskID is a reply to an
+// INPUT_REQUIRED task and resumes it; see ResumeTask.
 func (b *BaseAgent) HandleSendMessage(ctx context.Context, req a2a.SendMessageRequest) (*a2a.Task, error) {
+	if req.Message.TaskID != "" {
+		return b.ResumeTask(ctx, req.Message.TaskID, req.Message)
+	}
 	task := a2a.Task{
 		ID:        a2a.NewTaskID(),
 		ContextID: req.Message.ContextID,
@@ -116,9 +419,19 @@ func (b *BaseAgent) HandleSendMessage(ctx context.Context, req a2a.SendMessageRe
 	return b.HandleTask(ctx, task, req.Message)
 }
 
-// HandleGetTask retrieves a task by ID from the store.
-func (b *BaseAgent) HandleGetTask(_ context.Context, req a2a.GetTaskRequest) (*a2a.Task, error) {
-	return b.store.Get(req.ID)
+// HandleGetTask retrieves a task by ID from the store, inlining artifacts
+// stored by reference when req.ResolveArtifacts is set. The history length
+// and field selector in req are applied to the store's copy of the task.
+func (b *BaseAgent) HandleGetTask(ctx context.Context, req a2a.GetTaskRequest) (*a2a.Task, error) {
+	task, err := b.store.Get(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	req.Apply(task)
+	if req.ResolveArtifacts {
+		task = b.rehydrateArtifacts(ctx, task)
+	}
+	return task, nil
 }
 
 // HandleListTasks returns tasks matching the filter.
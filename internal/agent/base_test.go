@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -130,6 +134,16 @@ func TestBaseAgent_HandleTask_Failure(t *testing.T) {
 	assert.Equal(t, a2a.RoleAgent, result.Status.Message.Role)
 	require.Len(t, result.Status.Message.Parts, 1)
 	assert.Contains(t, result.Status.Message.Parts[0].Text, "processing failed")
+
+	// A processing failure is not a SkillError and may be retried.
+	var skillErr *SkillError
+	assert.False(t, errors.As(err, &skillErr))
+	assert.True(t, IsRetryable(err))
+
+	failure, ok := TaskFailure(result)
+	require.True(t, ok)
+	assert.Equal(t, FailureProcessing, failure.Kind)
+	assert.True(t, failure.Retryable)
 }
 
 func TestBaseAgent_HandleTask_MultipleArtifacts(t *testing.T) {
@@ -150,6 +164,107 @@ func TestBaseAgent_HandleTask_MultipleArtifacts(t *testing.T) {
 	assert.Equal(t, "a2", result.Artifacts[1].ArtifactID)
 }
 
+func TestBaseAgent_HandleTask_DerivesArtifactIDs(t *testing.T) {
+	agent := NewBaseAgent(testCard(), func(_ context.Context, _ *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
+		return []a2a.Artifact{
+			{Name: "chunk", Parts: msg.Parts},
+			{Name: "chunk", Parts: msg.Parts},
+			{ArtifactID: "fixed", Name: "chunk", Parts: msg.Parts},
+		}, nil
+	})
+	ids := func(text string) []string {
+		msg := testMessage()
+		msg.Parts = []a2a.Part{a2a.TextPart(text)}
+		result, err := agent.HandleTask(context.Background(), a2a.Task{ID: a2a.NewTaskID(), ContextID: "ctx-1"}, msg)
+		require.NoError(t, err)
+		var ids []string
+		for _, art := range result.Artifacts {
+			ids = append(ids, art.ArtifactID)
+		}
+		return ids
+	}
+
+	first := ids("input")
+	require.Len(t, first, 3)
+	assert.NotEqual(t, first[0], first[1], "repeated names get distinct IDs")
+	assert.Equal(t, "fixed", first[2], "IDs set by the process function are kept")
+	assert.Equal(t, first, ids("input"))
+	assert.NotEqual(t, first[0], ids("other input")[0])
+
+	agent.SetRandomArtifactIDs(true)
+	random := ids("input")
+	assert.NotEqual(t, first[0], random[0])
+	assert.NotEqual(t, random[0], ids("input")[0])
+}
+
+// greeterProcess asks for a name on the first message and greets on the
+// reply, reading the original request back from the task history.
+func greeterProcess(_ context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
+	if len(task.History) == 1 {
+		return nil, &InputRequiredError{Prompt: "what is your name?"}
+	}
+	greeting := task.History[0].Parts[0].Text + ", " + msg.Parts[0].Text
+	return []a2a.Artifact{{Name: "greeting", Parts: []a2a.Part{a2a.TextPart(greeting)}}}, nil
+}
+
+func TestBaseAgent_InputRequired_ResumesOnReply(t *testing.T) {
+	agent := NewBaseAgent(testCard(), greeterProcess)
+	ctx := context.Background()
+
+	first, err := agent.HandleSendMessage(ctx, a2a.SendMessageRequest{
+		Message: a2a.Message{MessageID: "m1", ContextID: "ctx-1", Role: a2a.RoleUser, Parts: []a2a.Part{a2a.TextPart("hello")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, a2a.TaskStateInputRequired, first.Status.State)
+	require.NotNil(t, first.Status.Message)
+	assert.Equal(t, a2a.RoleAgent, first.Status.Message.Role)
+	assert.Equal(t, "what is your name?", first.Status.Message.Parts[0].Text)
+	assert.Empty(t, first.Artifacts)
+	_, failed := TaskFailure(first)
+	assert.False(t, failed)
+
+	reply := a2a.Message{MessageID: "m2", ContextID: "ctx-1", TaskID: first.ID, Role: a2a.RoleUser, Parts: []a2a.Part{a2a.TextPart("Ada")}}
+	done, err := agent.HandleSendMessage(ctx, a2a.SendMessageRequest{Message: reply})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, done.ID, "the reply continues the same task")
+	assert.Equal(t, a2a.TaskStateCompleted, done.Status.State)
+	require.Len(t, done.Artifacts, 1)
+	assert.Equal(t, "hello, Ada", done.Artifacts[0].Parts[0].Text)
+
+	var roles []a2a.Role
+	for _, m := range done.History {
+		roles = append(roles, m.Role)
+	}
+	assert.Equal(t, []a2a.Role{a2a.RoleUser, a2a.RoleAgent, a2a.RoleUser}, roles)
+
+	// A completed task no longer accepts replies.
+	_, err = agent.HandleSendMessage(ctx, a2a.SendMessageRequest{Message: reply})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not awaiting input")
+}
+
+func TestBaseAgent_InputRequired_StreamsStatus(t *testing.T) {
+	agent := NewBaseAgent(testCard(), greeterProcess)
+
+	var events []a2a.StreamEvent
+	task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "ctx-1"}
+	result, err := agent.HandleTaskStream(context.Background(), task, testMessage(), func(ev a2a.StreamEvent) {
+		events = append(events, ev)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, a2a.TaskStateInputRequired, result.Status.State)
+	require.Len(t, events, 1)
+	require.NotNil(t, events[0].StatusUpdate)
+	assert.Equal(t, a2a.TaskStateInputRequired, events[0].StatusUpdate.Status.State)
+}
+
+func TestBaseAgent_ResumeTask_UnknownTask(t *testing.T) {
+	agent := NewBaseAgent(testCard(), greeterProcess)
+
+	_, err := agent.ResumeTask(context.Background(), "missing", testMessage())
+	require.Error(t, err)
+}
+
 func TestBaseAgent_HandleSendMessage(t *testing.T) {
 	agent := NewBaseAgent(testCard(), successProcess())
 	ctx := context.Background()
@@ -344,6 +459,114 @@ func TestBaseAgent_StartStop(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestBaseAgent_Stop_CancelsInFlightTasks(t *testing.T) {
+	started := make(chan struct{})
+	process := func(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	agent := NewBaseAgent(testCard(), process)
+	agent.SetCancelOnStop(true)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+	require.NoError(t, agent.Start(context.Background(), addr))
+
+	task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "ctx-1"}
+	type outcome struct {
+		task *a2a.Task
+		err  error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := agent.HandleTask(context.Background(), task, testMessage())
+		done <- outcome{res, err}
+	}()
+
+	<-started
+	got, err := agent.HandleGetTask(context.Background(), a2a.GetTaskRequest{ID: task.ID})
+	require.NoError(t, err)
+	assert.Equal(t, a2a.TaskStateWorking, got.Status.State)
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, agent.Stop(stopCtx))
+
+	select {
+	case out := <-done:
+		assert.ErrorIs(t, out.err, context.Canceled)
+		require.NotNil(t, out.task)
+		assert.Equal(t, a2a.TaskStateCanceled, out.task.Status.State)
+	case <-time.After(time.Second):
+		t.Fatal("HandleTask did not return after Stop")
+	}
+
+	got, err = agent.HandleGetTask(context.Background(), a2a.GetTaskRequest{ID: task.ID})
+	require.NoError(t, err)
+	assert.True(t, got.Status.State.IsTerminal(), "task should be terminal after Stop")
+	require.NotNil(t, got.Status.Message)
+	assert.Equal(t, "agent shutting down", got.Status.Message.Parts[0].Text)
+}
+
+func TestBaseAgent_StreamingProcess_EmitsArtifacts(t *testing.T) {
+	process := func(ctx context.Context, task *a2a.Task, msg a2a.Message, emit func(a2a.Artifact)) error {
+		emit(a2a.Artifact{ArtifactID: "a1", Name: "part-1", Parts: []a2a.Part{a2a.TextPart("first")}})
+		time.Sleep(10 * time.Millisecond)
+		emit(a2a.Artifact{ArtifactID: "a2", Name: "part-2", Parts: []a2a.Part{a2a.TextPart("second")}})
+		return nil
+	}
+	agent := NewStreamingBaseAgent(testCard(), process)
+
+	var events []a2a.StreamEvent
+	task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "ctx-1"}
+	result, err := agent.HandleTaskStream(context.Background(), task, testMessage(), func(ev a2a.StreamEvent) {
+		events = append(events, ev)
+	})
+	require.NoError(t, err)
+
+	var artifactEvents []*a2a.TaskArtifactUpdateEvent
+	var statusEvents []*a2a.TaskStatusUpdateEvent
+	for _, ev := range events {
+		if ev.ArtifactUpdate != nil {
+			artifactEvents = append(artifactEvents, ev.ArtifactUpdate)
+		}
+		if ev.StatusUpdate != nil {
+			statusEvents = append(statusEvents, ev.StatusUpdate)
+		}
+	}
+	require.Len(t, artifactEvents, 2)
+	assert.Equal(t, "a1", artifactEvents[0].Artifact.ArtifactID)
+	assert.Equal(t, "a2", artifactEvents[1].Artifact.ArtifactID)
+	assert.Equal(t, task.ID, artifactEvents[0].TaskID)
+	require.Len(t, statusEvents, 1)
+	assert.Equal(t, a2a.TaskStateCompleted, statusEvents[0].Status.State)
+
+	assert.Equal(t, a2a.TaskStateCompleted, result.Status.State)
+	require.Len(t, result.Artifacts, 2)
+	assert.Equal(t, "a1", result.Artifacts[0].ArtifactID)
+	assert.Equal(t, "a2", result.Artifacts[1].ArtifactID)
+}
+
+func TestBaseAgent_HandleTaskStream_AdaptsProcessFunc(t *testing.T) {
+	agent := NewBaseAgent(testCard(), successProcess())
+
+	var artifactEvents int
+	task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "ctx-1"}
+	result, err := agent.HandleTaskStream(context.Background(), task, testMessage(), func(ev a2a.StreamEvent) {
+		if ev.ArtifactUpdate != nil {
+			artifactEvents++
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, artifactEvents)
+	require.Len(t, result.Artifacts, 1)
+	assert.Equal(t, "art-1", result.Artifacts[0].ArtifactID)
+}
+
 func TestBaseAgent_HandleTask_DuplicateID(t *testing.T) {
 	agent := NewBaseAgent(testCard(), successProcess())
 	ctx := context.Background()
@@ -409,3 +632,94 @@ func TestBaseAgent_HandleSendMessage_GeneratesUniqueIDs(t *testing.T) {
 	}
 	assert.Len(t, ids, 10)
 }
+
+func TestBaseAgent_InProcessTransport(t *testing.T) {
+	agent := NewTaskWriterAgent()
+	transport := a2a.NewInProcessTransport()
+	transport.Register("mem://task-writer", agent.Card(), agent)
+	client := a2a.NewHTTPClient(a2a.WithTransport(transport))
+	ctx := context.Background()
+
+	card, err := client.DiscoverAgent(ctx, "mem://task-writer")
+	require.NoError(t, err)
+	assert.Equal(t, "task-writer-agent", card.Name)
+
+	task, err := client.SendMessage(ctx, "mem://task-writer", a2a.SendMessageRequest{
+		Message: a2a.Message{
+			Role:  a2a.RoleUser,
+			Parts: []a2a.Part{a2a.TextPart("write-task-specs\nMilestone 1\n1. internal/app/main.go (CREATE) - entry point")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, a2a.TaskStateCompleted, task.Status.State)
+	require.Len(t, task.Artifacts, 1)
+	assert.Contains(t, task.Artifacts[0].Parts[0].Text, "T-01.01")
+
+	got, err := client.GetTask(ctx, "mem://task-writer", a2a.GetTaskRequest{ID: task.ID})
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, got.ID)
+	assert.Equal(t, a2a.TaskStateCompleted, got.Status.State)
+}
+
+func TestBaseAgent_ArtifactStoreByReference(t *testing.T) {
+	large := strings.Repeat("x", 4096)
+	process := func(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
+		return []a2a.Artifact{{
+			ArtifactID: "art-1",
+			Name:       "output",
+			Parts:      []a2a.Part{a2a.TextPart(large), a2a.TextPart("small")},
+		}}, nil
+	}
+	agent := NewBaseAgent(testCard(), process)
+	ts := httptest.NewServer(agent.server.Handler())
+	defer ts.Close()
+	agent.SetArtifactStore(a2a.NewMemArtifactStore(), 1024, ts.URL)
+	ctx := context.Background()
+
+	created, err := agent.HandleSendMessage(ctx, a2a.SendMessageRequest{Message: testMessage()})
+	require.NoError(t, err)
+
+	stored, err := agent.HandleGetTask(ctx, a2a.GetTaskRequest{ID: created.ID})
+	require.NoError(t, err)
+	require.Len(t, stored.Artifacts, 1)
+	parts := stored.Artifacts[0].Parts
+	require.Len(t, parts, 2)
+	assert.Empty(t, parts[0].Text)
+	assert.True(t, strings.HasPrefix(parts[0].URL, ts.URL+a2a.ArtifactRoute), parts[0].URL)
+	assert.Equal(t, "small", parts[1].Text)
+
+	resp, err := http.Get(parts[0].URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	resolved, err := agent.HandleGetTask(ctx, a2a.GetTaskRequest{ID: created.ID, ResolveArtifacts: true})
+	require.NoError(t, err)
+	assert.Equal(t, large, resolved.Artifacts[0].Parts[0].Text)
+	assert.Empty(t, resolved.Artifacts[0].Parts[0].URL)
+}
+
+// TestBaseAgent_Stop_RefusesNewTasks verifies that a task arriving after Stop
+// has begun is canceled without running its ProcessFunc.
+func TestBaseAgent_Stop_RefusesNewTasks(t *testing.T) {
+	var ran atomic.Bool
+	process := func(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
+		ran.Store(true)
+		return nil, nil
+	}
+
+	agent := NewBaseAgent(testCard(), process)
+	agent.SetCancelOnStop(true)
+	require.NoError(t, agent.Start(context.Background(), "127.0.0.1:0"))
+	require.NoError(t, agent.Stop(context.Background()))
+
+	task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "ctx-1"}
+	res, err := agent.HandleTask(context.Background(), task, testMessage())
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, res)
+	assert.Equal(t, a2a.TaskStateCanceled, res.Status.State)
+	assert.False(t, ran.Load(), "ProcessFunc must not run after Stop")
+}
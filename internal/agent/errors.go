@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// SkillError reports that a message did not select a skill the agent offers.
+// Resending the same message cannot succeed, so it is not retryable; any
+// other error returned by a ProcessFunc is a processing failure that may be.
+type SkillError struct {
+	Skill      string   // the requested skill, if one could be identified
+	Candidates []string // skills the message matched equally well, if ambiguous
+	Reason     string
+}
+
+func (e *SkillError) Error() string {
+	if len(e.Candidates) > 0 {
+		return "ambiguous skill: " + e.Reason
+	}
+	if e.Skill != "" {
+		return fmt.Sprintf("unknown skill %q: %s", e.Skill, e.Reason)
+	}
+	return "unknown skill: " + e.Reason
+}
+
+// InputRequiredError is returned by a ProcessFunc that cannot finish
+// without more input from the caller. BaseAgent moves the task to
+// INPUT_REQUIRED with Prompt as its status message instead of failing it; a
+// follow-up message naming the task by TaskID resumes it.
+type InputRequiredError struct {
+	Prompt string // what the agent needs, shown to the caller
+}
+
+func (e *InputRequiredError) Error() string {
+	return "input required: " + e.Prompt
+}
+
+// Failure kinds recorded in a failed task's status message metadata.
+const (
+	FailureSkill      = "skill"
+	FailureProcessing = "processing"
+)
+
+// FailureMetadata is attached as metadata to the status message of a task
+// that failed, so callers across the A2A boundary can tell a rejected skill
+// from a processing failure without parsing the error text.
+type FailureMetadata struct {
+	Kind       string   `json:"errorKind"`
+	Skill      string   `json:"skill,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
+	Retryable  bool     `json:"retryable"`
+}
+
+// IsRetryable reports whether a ProcessFunc error may succeed on retry.
+// SkillErrors are not retryable; all other errors are.
+func IsRetryable(err error) bool {
+	var skillErr *SkillError
+	return err != nil && !errors.As(err, &skillErr)
+}
+
+// failureMetadata classifies err for a failed task's status message.
+func failureMetadata(err error) FailureMetadata {
+	var skillErr *SkillError
+	if errors.As(err, &skillErr) {
+		return FailureMetadata{Kind: FailureSkill, Skill: skillErr.Skill, Candidates: skillErr.Candidates}
+	}
+	return FailureMetadata{Kind: FailureProcessing, Retryable: true}
+}
+
+// TaskFailure returns the FailureMetadata recorded on a failed task, or false
+// if the task has none (for example, it did not fail, or a non-BaseAgent
+// server produced it).
+func TaskFailure(task *a2a.Task) (FailureMetadata, bool) {
+	if task == nil || task.Status.State != a2a.TaskStateFailed || task.Status.Message == nil {
+		return FailureMetadata{}, false
+	}
+	var meta FailureMetadata
+	if err := json.Unmarshal(task.Status.Message.Metadata, &meta); err != nil || meta.Kind == "" {
+		return FailureMetadata{}, false
+	}
+	return meta, true
+}
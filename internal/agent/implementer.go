@@ -3,6 +3,7 @@ package agent
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -194,17 +195,20 @@ func (ia *ImplementerAgent) collectChangedFiles(ctx context.Context) ([]orchestr
 }
 
 // buildA2AArtifacts converts implementation artifacts to A2A artifacts
-// for protocol compatibility.
+// for protocol compatibility. The artifact ID is derived from the milestone
+// and the implementation artifacts, so identical results share an ID.
 func buildA2AArtifacts(milestone *orchestrator.MilestoneNode, implArtifacts []orchestrator.ImplementationArtifact) []a2a.Artifact {
 	var parts []a2a.Part
 	for _, ia := range implArtifacts {
 		parts = append(parts, a2a.TextPart(fmt.Sprintf("[%s] %s", ia.Action, ia.Path)))
 	}
 
+	name := fmt.Sprintf("implementation-%s", milestone.ID)
+	content, _ := json.Marshal(implArtifacts)
 	return []a2a.Artifact{
 		{
-			ArtifactID:  a2a.NewTaskID(),
-			Name:        fmt.Sprintf("implementation-%s", milestone.ID),
+			ArtifactID:  derivedArtifactID(name, []byte(milestone.ID), content),
+			Name:        name,
 			Description: fmt.Sprintf("Implementation results for %s: %s", milestone.ID, milestone.Name),
 			Parts:       parts,
 		},
@@ -0,0 +1,23 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/onedusk/pd/internal/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildA2AArtifacts_DerivesArtifactID(t *testing.T) {
+	milestone := &orchestrator.MilestoneNode{ID: "M1", Name: "Storage"}
+	impl := []orchestrator.ImplementationArtifact{{Path: "store.go", Action: "CREATE"}}
+
+	first := buildA2AArtifacts(milestone, impl)
+	second := buildA2AArtifacts(milestone, impl)
+	require.Len(t, first, 1)
+	assert.Equal(t, first[0].ArtifactID, second[0].ArtifactID)
+	assert.Regexp(t, `^implementation-M1-[0-9a-f]{16}$`, first[0].ArtifactID)
+
+	changed := buildA2AArtifacts(milestone, []orchestrator.ImplementationArtifact{{Path: "store.go", Action: "MODIFY"}})
+	assert.NotEqual(t, first[0].ArtifactID, changed[0].ArtifactID)
+}
@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// resolvedVersion is a single dependency pinned by a lockfile.
+type resolvedVersion struct {
+	Name    string
+	Version string
+}
+
+// lockfileParser describes a lockfile the verify-versions skill can read
+// offline and the function that extracts its resolved versions.
+type lockfileParser struct {
+	File      string
+	Ecosystem string
+	Parse     func(data []byte) ([]resolvedVersion, error)
+}
+
+// lockfileParsers lists the supported lockfiles in the order they are
+// reported.
+var lockfileParsers = []lockfileParser{
+	{File: "go.sum", Ecosystem: "Go", Parse: parseGoSum},
+	{File: "package-lock.json", Ecosystem: "Node.js", Parse: parsePackageLock},
+	{File: "Cargo.lock", Ecosystem: "Rust", Parse: parseTOMLPackageLock},
+	{File: "poetry.lock", Ecosystem: "Python", Parse: parseTOMLPackageLock},
+}
+
+// parseGoSum extracts module versions from a go.sum file. Each module
+// appears once; the "/go.mod" hash lines only contribute a version when the
+// module has no full-content hash line.
+func parseGoSum(data []byte) ([]resolvedVersion, error) {
+	seen := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		name := fields[0]
+		version, modOnly := strings.CutSuffix(fields[1], "/go.mod")
+		if existing, ok := seen[name]; ok && (modOnly || existing == version) {
+			continue
+		}
+		seen[name] = version
+	}
+
+	versions := make([]resolvedVersion, 0, len(seen))
+	for name, version := range seen {
+		versions = append(versions, resolvedVersion{Name: name, Version: version})
+	}
+	sortResolved(versions)
+	return versions, nil
+}
+
+// packageLock is the subset of package-lock.json needed to read resolved
+// versions. Lockfile v2/v3 use "packages"; v1 uses "dependencies".
+type packageLock struct {
+	Packages map[string]struct {
+		Version string `json:"version"`
+	} `json:"packages"`
+	Dependencies map[string]struct {
+		Version string `json:"version"`
+	} `json:"dependencies"`
+}
+
+// parsePackageLock extracts installed package versions from a
+// package-lock.json file.
+func parsePackageLock(data []byte) ([]resolvedVersion, error) {
+	var lock packageLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parse package-lock.json: %w", err)
+	}
+
+	seen := make(map[resolvedVersion]bool)
+	var versions []resolvedVersion
+	add := func(name, version string) {
+		rv := resolvedVersion{Name: name, Version: version}
+		if name == "" || version == "" || seen[rv] {
+			return
+		}
+		seen[rv] = true
+		versions = append(versions, rv)
+	}
+
+	for key, pkg := range lock.Packages {
+		// The root project is keyed by ""; nested installs are keyed by
+		// their full node_modules path.
+		idx := strings.LastIndex(key, "node_modules/")
+		if idx == -1 {
+			continue
+		}
+		add(key[idx+len("node_modules/"):], pkg.Version)
+	}
+	if len(lock.Packages) == 0 {
+		for name, dep := range lock.Dependencies {
+			add(name, dep.Version)
+		}
+	}
+
+	sortResolved(versions)
+	return versions, nil
+}
+
+// parseTOMLPackageLock extracts name/version pairs from the [[package]]
+// tables of a TOML lockfile such as Cargo.lock or poetry.lock.
+func parseTOMLPackageLock(data []byte) ([]resolvedVersion, error) {
+	var versions []resolvedVersion
+	var current *resolvedVersion
+	flush := func() {
+		if current != nil && current.Name != "" && current.Version != "" {
+			versions = append(versions, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			flush()
+			if trimmed == "[[package]]" {
+				current = &resolvedVersion{}
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.TrimSpace(key) {
+		case "name":
+			current.Name = value
+		case "version":
+			current.Version = value
+		}
+	}
+	flush()
+
+	sortResolved(versions)
+	return versions, nil
+}
+
+// sortResolved orders versions by name, then version.
+func sortResolved(versions []resolvedVersion) {
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Name != versions[j].Name {
+			return versions[i].Name < versions[j].Name
+		}
+		return versions[i].Version < versions[j].Version
+	})
+}
@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/graph"
 	"github.com/onedusk/pd/internal/mcptools"
+	"github.com/onedusk/pd/internal/msgtext"
 )
 
 // PlanningAgent is a specialist agent that builds code graphs, analyzes
@@ -76,7 +78,10 @@ func NewPlanningAgent(opts ...PlanningOption) *PlanningAgent {
 // processMessage routes incoming messages to the appropriate skill handler.
 func (pa *PlanningAgent) processMessage(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
 	text := planningExtractText(msg)
-	skill := detectPlanningSkill(text)
+	skill, candidates := detectPlanningSkill(text)
+	if len(candidates) > 0 {
+		return nil, ambiguousSkillError(candidates)
+	}
 
 	switch skill {
 	case "build-code-graph":
@@ -88,7 +93,7 @@ func (pa *PlanningAgent) processMessage(ctx context.Context, task *a2a.Task, msg
 	case "plan-milestones":
 		return pa.handlePlanMilestones(text)
 	default:
-		return nil, fmt.Errorf("unknown skill %q: supported skills are build-code-graph, analyze-dependencies, assess-impact, plan-milestones", skill)
+		return nil, &SkillError{Skill: skill, Reason: "supported skills are build-code-graph, analyze-dependencies, assess-impact, plan-milestones"}
 	}
 }
 
@@ -98,7 +103,7 @@ func (pa *PlanningAgent) handleBuildCodeGraph(ctx context.Context, text string)
 		return nil, fmt.Errorf("MCP CodeIntelService is required for build-code-graph; configure with WithCodeIntelService")
 	}
 
-	path := extractRepoPath(text)
+	path := msgtext.AbsPath(text)
 	if path == "" {
 		return nil, fmt.Errorf("could not extract repository path from message; include a path like /path/to/repo")
 	}
@@ -119,7 +124,6 @@ func (pa *PlanningAgent) handleBuildCodeGraph(ctx context.Context, text string)
 
 	return []a2a.Artifact{
 		{
-			ArtifactID:  a2a.NewTaskID(),
 			Name:        "graph-stats",
 			Description: "Code intelligence graph statistics",
 			Parts:       []a2a.Part{a2a.TextPart(md)},
@@ -127,7 +131,18 @@ func (pa *PlanningAgent) handleBuildCodeGraph(ctx context.Context, text string)
 	}, nil
 }
 
-// handleAnalyzeDependencies traverses dependencies from a node.
+// dependencyReport is the JSON payload attached to the dependency-chains
+// artifact alongside the markdown table.
+type dependencyReport struct {
+	NodeID    string                  `json:"nodeId"`
+	Direction string                  `json:"direction"`
+	EdgeKinds []graph.EdgeKind        `json:"edgeKinds,omitempty"`
+	Chains    []graph.DependencyChain `json:"chains"`
+}
+
+// handleAnalyzeDependencies traverses dependencies from a node. Edge kinds
+// named in the message (e.g. "CALLS") select which relationships to follow,
+// and each hop in the report is annotated with its edge kind and weight.
 func (pa *PlanningAgent) handleAnalyzeDependencies(ctx context.Context, text string) ([]a2a.Artifact, error) {
 	if pa.mcpSvc == nil {
 		return nil, fmt.Errorf("MCP CodeIntelService is required for analyze-dependencies; configure with WithCodeIntelService")
@@ -138,9 +153,11 @@ func (pa *PlanningAgent) handleAnalyzeDependencies(ctx context.Context, text str
 		return nil, fmt.Errorf("could not extract node ID from message; include a file path or symbol name")
 	}
 
+	kinds := extractEdgeKinds(text)
 	_, out, err := pa.mcpSvc.GetDependencies(ctx, nil, mcptools.GetDependenciesInput{
 		NodeID:    nodeID,
 		Direction: direction,
+		EdgeKinds: kinds,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("get dependencies: %w", err)
@@ -154,27 +171,55 @@ func (pa *PlanningAgent) handleAnalyzeDependencies(ctx context.Context, text str
 		sb.WriteString("| Chain | Depth |\n")
 		sb.WriteString("|-------|-------|\n")
 		for _, chain := range out.Chains {
-			sb.WriteString(fmt.Sprintf("| %s | %d |\n", strings.Join(chain.Nodes, " -> "), chain.Depth))
+			sb.WriteString(fmt.Sprintf("| %s | %d |\n", formatChain(chain), chain.Depth))
 		}
 	}
 
+	chains := out.Chains
+	if chains == nil {
+		chains = []graph.DependencyChain{}
+	}
+	data, err := a2a.DataPart(dependencyReport{
+		NodeID:    nodeID,
+		Direction: direction,
+		EdgeKinds: kinds,
+		Chains:    chains,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode dependency chains: %w", err)
+	}
+
 	return []a2a.Artifact{
 		{
-			ArtifactID:  a2a.NewTaskID(),
 			Name:        "dependency-chains",
 			Description: "Dependency chain analysis",
-			Parts:       []a2a.Part{a2a.TextPart(sb.String())},
+			Parts:       []a2a.Part{a2a.TextPart(sb.String()), data},
 		},
 	}, nil
 }
 
+// formatChain renders a chain with each hop annotated by its edge kind and
+// weight: "a.go:main -[CALLS x2]-> b.go:run". Chains without hop details
+// fall back to plain arrows.
+func formatChain(chain graph.DependencyChain) string {
+	if len(chain.Hops) != len(chain.Nodes)-1 {
+		return strings.Join(chain.Nodes, " -> ")
+	}
+	var sb strings.Builder
+	sb.WriteString(chain.Nodes[0])
+	for i, hop := range chain.Hops {
+		sb.WriteString(fmt.Sprintf(" -[%s x%d]-> %s", hop.Kind, hop.Weight, chain.Nodes[i+1]))
+	}
+	return sb.String()
+}
+
 // handleAssessImpact computes the blast radius of file changes.
 func (pa *PlanningAgent) handleAssessImpact(ctx context.Context, text string) ([]a2a.Artifact, error) {
 	if pa.mcpSvc == nil {
 		return nil, fmt.Errorf("MCP CodeIntelService is required for assess-impact; configure with WithCodeIntelService")
 	}
 
-	files := extractFilePaths(text)
+	files := msgtext.Paths(text, "assess-impact", "assess", "impact")
 	if len(files) == 0 {
 		return nil, fmt.Errorf("could not extract file paths from message; include file paths to assess")
 	}
@@ -211,7 +256,6 @@ func (pa *PlanningAgent) handleAssessImpact(ctx context.Context, text string) ([
 
 	return []a2a.Artifact{
 		{
-			ArtifactID:  a2a.NewTaskID(),
 			Name:        "impact-assessment",
 			Description: "Change impact analysis",
 			Parts:       []a2a.Part{a2a.TextPart(sb.String())},
@@ -253,7 +297,6 @@ func (pa *PlanningAgent) handlePlanMilestones(text string) ([]a2a.Artifact, erro
 
 	return []a2a.Artifact{
 		{
-			ArtifactID:  a2a.NewTaskID(),
 			Name:        "milestone-plan",
 			Description: "Stage 3 milestone plan",
 			Parts:       []a2a.Part{a2a.TextPart(sb.String())},
@@ -282,116 +325,71 @@ func planningExtractText(msg a2a.Message) string {
 	return strings.Join(parts, "\n")
 }
 
-// detectPlanningSkill determines which planning skill is being requested from the message text.
-// It looks for skill keywords in the text.
-func detectPlanningSkill(text string) string {
-	lower := strings.ToLower(text)
-
-	// Check for explicit skill references first.
-	skills := []struct {
-		id       string
-		keywords []string
-	}{
-		{"build-code-graph", []string{"build-code-graph", "build graph", "build code graph", "index repo"}},
-		{"analyze-dependencies", []string{"analyze-dependencies", "analyze dependencies", "get dependencies", "dependency chain"}},
-		{"assess-impact", []string{"assess-impact", "assess impact", "impact assessment", "blast radius"}},
-		{"plan-milestones", []string{"plan-milestones", "plan milestones", "milestone", "design pack"}},
-	}
-
-	for _, s := range skills {
-		for _, kw := range s.keywords {
-			if strings.Contains(lower, kw) {
-				return s.id
-			}
-		}
-	}
+// planningSkillRules ranks explicit skill names above topical hints.
+var planningSkillRules = []skillRule{
+	{"build-code-graph", 2, []string{"build-code-graph", "build graph", "build code graph"}},
+	{"analyze-dependencies", 2, []string{"analyze-dependencies", "analyze dependencies"}},
+	{"assess-impact", 2, []string{"assess-impact", "assess impact"}},
+	{"plan-milestones", 2, []string{"plan-milestones", "plan milestones"}},
+	{"build-code-graph", 1, []string{"index repo"}},
+	{"analyze-dependencies", 1, []string{"get dependencies", "dependency chain"}},
+	{"assess-impact", 1, []string{"impact assessment", "blast radius"}},
+	{"plan-milestones", 1, []string{"milestone", "design pack"}},
+}
 
-	return ""
+// detectPlanningSkill determines which planning skill is being requested from
+// the message text. When several skills match equally well it returns no
+// skill and the tied candidates.
+func detectPlanningSkill(text string) (string, []string) {
+	return matchSkill(text, planningSkillRules)
 }
 
-// extractRepoPath extracts a file system path from the message text.
-// Looks for common path patterns.
-func extractRepoPath(text string) string {
-	for _, line := range strings.Split(text, "\n") {
-		trimmed := strings.TrimSpace(line)
-		// Look for lines that look like absolute paths.
-		if strings.HasPrefix(trimmed, "/") && !strings.HasPrefix(trimmed, "//") {
-			// Take just the path portion (stop at whitespace or end).
-			parts := strings.Fields(trimmed)
-			if len(parts) > 0 {
-				return parts[0]
-			}
-		}
-	}
-	// Fallback: look for path-like tokens anywhere.
-	for _, token := range strings.Fields(text) {
-		if strings.HasPrefix(token, "/") && strings.Contains(token, "/") && len(token) > 1 {
-			return token
-		}
-	}
-	return ""
+// dependencyKeywords are words in an analyze-dependencies message that are
+// never the node to analyze.
+var dependencyKeywords = []string{
+	"analyze", "analyze-dependencies", "get", "dependency", "dependencies",
+	"chain", "upstream", "downstream",
 }
 
-// extractNodeAndDirection extracts a node ID and optional direction from message text.
+// extractNodeAndDirection extracts a node ID and optional direction from
+// message text. The node is the first path-like token; the direction is
+// upstream when the message says so and downstream otherwise.
 func extractNodeAndDirection(text string) (nodeID, direction string) {
 	direction = "downstream" // default
-
-	lower := strings.ToLower(text)
-	if strings.Contains(lower, "upstream") {
-		direction = "upstream"
+	for _, tok := range msgtext.Tokens(text) {
+		if strings.EqualFold(tok, "upstream") {
+			direction = "upstream"
+			break
+		}
 	}
 
-	// Look for path-like or symbol-like tokens.
-	for _, token := range strings.Fields(text) {
-		clean := strings.Trim(token, "`\"',;:")
-		if clean == "" {
-			continue
-		}
-		// Skip common keywords.
-		lowerClean := strings.ToLower(clean)
-		if lowerClean == "upstream" || lowerClean == "downstream" || lowerClean == "analyze" ||
-			lowerClean == "dependencies" || lowerClean == "analyze-dependencies" ||
-			lowerClean == "get" || lowerClean == "dependency" || lowerClean == "chain" {
-			continue
-		}
-		// Accept paths or qualified names.
-		if strings.Contains(clean, "/") || strings.Contains(clean, ".") {
-			nodeID = clean
-			return nodeID, direction
+	for _, tok := range msgtext.Tokens(text, dependencyKeywords...) {
+		if msgtext.IsPathLike(tok) {
+			return tok, direction
 		}
 	}
-
-	return nodeID, direction
+	return "", direction
 }
 
-// extractFilePaths extracts file paths from the message text.
-func extractFilePaths(text string) []string {
-	var paths []string
-	seen := make(map[string]bool)
-
-	for _, token := range strings.Fields(text) {
-		clean := strings.Trim(token, "`\"',;:()[]")
-		if clean == "" {
-			continue
-		}
-		// Look for things that look like file paths.
-		if (strings.Contains(clean, "/") || strings.Contains(clean, ".")) &&
-			!strings.HasPrefix(clean, "http") &&
-			strings.ContainsAny(clean, "/.") &&
-			len(clean) > 2 {
-			// Skip known keywords.
-			lowerClean := strings.ToLower(clean)
-			if lowerClean == "assess-impact" || lowerClean == "assess" || lowerClean == "impact" {
-				continue
-			}
-			if !seen[clean] {
-				seen[clean] = true
-				paths = append(paths, clean)
+// extractEdgeKinds returns the edge kinds named in the message, such as
+// "CALLS" or "implements". Nil means the default (IMPORTS).
+func extractEdgeKinds(text string) []graph.EdgeKind {
+	known := []graph.EdgeKind{
+		graph.EdgeKindImports, graph.EdgeKindCalls, graph.EdgeKindInherits,
+		graph.EdgeKindImplements, graph.EdgeKindHasMethod, graph.EdgeKindDefines,
+	}
+	var kinds []graph.EdgeKind
+	seen := make(map[graph.EdgeKind]bool)
+	for _, token := range msgtext.Tokens(text) {
+		clean := graph.EdgeKind(strings.ToUpper(token))
+		for _, kind := range known {
+			if clean == kind && !seen[kind] {
+				seen[kind] = true
+				kinds = append(kinds, kind)
 			}
 		}
 	}
-
-	return paths
+	return kinds
 }
 
 // section represents a parsed markdown section from a design pack.
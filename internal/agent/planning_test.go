@@ -4,6 +4,7 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -68,6 +69,46 @@ func TestPlanningAgent_BuildCodeGraph_WithMCP(t *testing.T) {
 		"artifact text should mention symbols: %s", text)
 }
 
+func TestPlanningAgent_AnalyzeDependencies_EdgeKinds(t *testing.T) {
+	store := graph.NewMemStore()
+	ctx := context.Background()
+	for _, e := range []graph.Edge{
+		{SourceID: "a.go:main", TargetID: "b.go:run", Kind: graph.EdgeKindCalls},
+		{SourceID: "a.go:main", TargetID: "b.go:run", Kind: graph.EdgeKindCalls},
+		{SourceID: "b.go:run", TargetID: "c.go:helper", Kind: graph.EdgeKindCalls},
+		{SourceID: "a.go", TargetID: "b.go", Kind: graph.EdgeKindImports},
+	} {
+		require.NoError(t, store.AddEdge(ctx, e))
+	}
+	agent := NewPlanningAgent(WithCodeIntelService(mcptools.NewCodeIntelService(store, nil)))
+
+	msg := a2a.Message{
+		Role:  a2a.RoleUser,
+		Parts: []a2a.Part{a2a.TextPart("analyze-dependencies\na.go:main downstream CALLS")},
+	}
+	result, err := agent.HandleTask(ctx, a2a.Task{ID: a2a.NewTaskID(), ContextID: "test-deps-calls"}, msg)
+	require.NoError(t, err)
+	assert.Equal(t, a2a.TaskStateCompleted, result.Status.State)
+	require.Len(t, result.Artifacts, 1)
+
+	art := result.Artifacts[0]
+	require.Len(t, art.Parts, 2)
+	text := art.Parts[0].Text
+	assert.Contains(t, text, "| a.go:main -[CALLS x2]-> b.go:run | 1 |")
+	assert.Contains(t, text, "| a.go:main -[CALLS x2]-> b.go:run -[CALLS x1]-> c.go:helper | 2 |")
+	assert.NotContains(t, text, "IMPORTS")
+
+	var report dependencyReport
+	require.NoError(t, json.Unmarshal(art.Parts[1].Data, &report))
+	assert.Equal(t, "a.go:main", report.NodeID)
+	assert.Equal(t, []graph.EdgeKind{graph.EdgeKindCalls}, report.EdgeKinds)
+	require.Len(t, report.Chains, 2)
+	assert.Equal(t, []graph.ChainHop{
+		{Kind: graph.EdgeKindCalls, Weight: 2},
+		{Kind: graph.EdgeKindCalls, Weight: 1},
+	}, report.Chains[1].Hops)
+}
+
 func TestPlanningAgent_PlanMilestones(t *testing.T) {
 	// plan-milestones works without MCP tools, so no CodeIntelService needed.
 	agent := NewPlanningAgent()
@@ -105,6 +146,24 @@ func TestPlanningAgent_PlanMilestones(t *testing.T) {
 		"dependency graph should reference milestone IDs: %s", text)
 }
 
+func TestPlanningAgent_PlanMilestones_DeterministicArtifactID(t *testing.T) {
+	agent := NewPlanningAgent()
+	planID := func(text string) string {
+		msg := a2a.Message{Role: a2a.RoleUser, Parts: []a2a.Part{a2a.TextPart(text)}}
+		task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "test-artifact-id"}
+		result, err := agent.HandleTask(context.Background(), task, msg)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Artifacts)
+		assert.Equal(t, "milestone-plan", result.Artifacts[0].Name)
+		return result.Artifacts[0].ArtifactID
+	}
+
+	first := planID(designPackText)
+	assert.True(t, strings.HasPrefix(first, "milestone-plan-"), first)
+	assert.Equal(t, first, planID(designPackText), "same design pack should yield the same artifact ID")
+	assert.NotEqual(t, first, planID(designPackText+"\n## Audit Log\nRecord every admin action.\n"))
+}
+
 func TestPlanningAgent_FallbackMode_NoMCP(t *testing.T) {
 	// Create agent without CodeIntelService — MCP-dependent skills should fail,
 	// but plan-milestones should still work.
@@ -193,3 +252,13 @@ func TestPlanningAgent_AgentCard(t *testing.T) {
 		assert.Contains(t, card.DefaultOutputModes, "application/json")
 	})
 }
+
+func TestDetectPlanningSkill_Ambiguous(t *testing.T) {
+	skill, candidates := detectPlanningSkill("show the blast radius and dependency chain for this milestone")
+	assert.Empty(t, skill)
+	assert.Equal(t, []string{"analyze-dependencies", "assess-impact", "plan-milestones"}, candidates)
+
+	skill, candidates = detectPlanningSkill("assess-impact of the milestone on internal/graph")
+	assert.Equal(t, "assess-impact", skill)
+	assert.Empty(t, candidates)
+}
@@ -6,10 +6,13 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/graph"
+	"github.com/onedusk/pd/internal/msgtext"
 )
 
 // skipDirs is the set of directory names to skip when walking a project tree.
@@ -98,12 +101,38 @@ var extToLanguage = map[string]string{
 // handling.
 type ResearchAgent struct {
 	*BaseAgent
+	followSymlinks bool
+	redact         []*regexp.Regexp
+}
+
+// ResearchOption configures a ResearchAgent during construction.
+type ResearchOption func(*ResearchAgent)
+
+// WithFollowSymlinks makes explore-codebase descend into symlinked
+// directories, visiting each real directory once. By default they are
+// skipped and listed in the summary.
+func WithFollowSymlinks() ResearchOption {
+	return func(ra *ResearchAgent) {
+		ra.followSymlinks = true
+	}
+}
+
+// WithRedactionPatterns replaces the patterns research-platform uses to mask
+// secrets in quoted config files; see DefaultRedactionPatterns. Passing no
+// patterns disables redaction.
+func WithRedactionPatterns(patterns ...*regexp.Regexp) ResearchOption {
+	return func(ra *ResearchAgent) {
+		ra.redact = patterns
+	}
 }
 
 // NewResearchAgent creates a new ResearchAgent with its agent card and
 // process function wired up.
-func NewResearchAgent() *ResearchAgent {
-	ra := &ResearchAgent{}
+func NewResearchAgent(opts ...ResearchOption) *ResearchAgent {
+	ra := &ResearchAgent{redact: DefaultRedactionPatterns()}
+	for _, opt := range opts {
+		opt(ra)
+	}
 
 	card := a2a.AgentCard{
 		Name:        "research-agent",
@@ -140,7 +169,7 @@ func NewResearchAgent() *ResearchAgent {
 // processMessage is the ProcessFunc that dispatches to the appropriate skill
 // based on the message text content.
 func (ra *ResearchAgent) processMessage(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
-	text := extractText(msg)
+	text := msgtext.Text(msg)
 
 	switch {
 	case strings.Contains(text, "explore-codebase"):
@@ -150,71 +179,55 @@ func (ra *ResearchAgent) processMessage(ctx context.Context, task *a2a.Task, msg
 	case strings.Contains(text, "verify-versions"):
 		return ra.verifyVersions(ctx, text)
 	default:
-		return nil, fmt.Errorf("unknown skill: message does not contain a recognized skill ID (explore-codebase, research-platform, verify-versions)")
+		return nil, &SkillError{Reason: "message does not contain a recognized skill ID (explore-codebase, research-platform, verify-versions)"}
 	}
 }
 
-// extractText concatenates all text parts from a message.
-func extractText(msg a2a.Message) string {
-	var parts []string
-	for _, p := range msg.Parts {
-		if p.Text != "" {
-			parts = append(parts, p.Text)
-		}
-	}
-	return strings.Join(parts, "\n")
-}
+// researchSkillIDs are the skill IDs a research message may name alongside
+// its path.
+var researchSkillIDs = []string{"explore-codebase", "research-platform", "verify-versions"}
 
-// extractPath attempts to find an absolute path in the message text. It looks
-// for the first line that starts with "/" or, failing that, returns the first
-// non-empty line that is not the skill ID itself.
+// extractPath returns the absolute path named in the message text. Failing
+// that, it returns the first token that is not a skill ID, or "." when the
+// message names nothing else.
 func extractPath(text string) string {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			continue
-		}
-		if strings.HasPrefix(trimmed, "/") {
-			// Strip any trailing skill ID or extra text after the path.
-			// A simple heuristic: take the first whitespace-delimited token
-			// that looks like a path.
-			fields := strings.Fields(trimmed)
-			for _, f := range fields {
-				if strings.HasPrefix(f, "/") {
-					return f
-				}
-			}
-		}
+	if path := msgtext.AbsPath(text); path != "" {
+		return path
 	}
-	// Fallback: try the first non-empty line.
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			continue
-		}
-		// Skip lines that are just the skill ID.
-		if trimmed == "explore-codebase" || trimmed == "research-platform" || trimmed == "verify-versions" {
-			continue
-		}
-		fields := strings.Fields(trimmed)
-		if len(fields) > 0 {
-			return fields[0]
-		}
+	if tok := msgtext.FirstToken(text, researchSkillIDs...); tok != "" {
+		return tok
 	}
 	return "."
 }
 
+// extractPaths returns every absolute path in the message text, in order
+// and without duplicates. It falls back to extractPath when the text names
+// at most one path.
+func extractPaths(text string) []string {
+	paths := msgtext.AbsPaths(text)
+	if len(paths) <= 1 {
+		return []string{extractPath(text)}
+	}
+	return paths
+}
+
 // exploreCodebase walks the project directory and produces a markdown summary.
+// When the message names several directories, each is walked and entries are
+// shown relative to their common parent.
 func (ra *ResearchAgent) exploreCodebase(_ context.Context, text string) ([]a2a.Artifact, error) {
-	root := extractPath(text)
-
-	info, err := os.Stat(root)
-	if err != nil {
-		return nil, fmt.Errorf("explore-codebase: cannot access path %q: %w", root, err)
+	roots := extractPaths(text)
+	for _, r := range roots {
+		info, err := os.Stat(r)
+		if err != nil {
+			return nil, fmt.Errorf("explore-codebase: cannot access path %q: %w", r, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("explore-codebase: path %q is not a directory", r)
+		}
 	}
-	if !info.IsDir() {
-		return nil, fmt.Errorf("explore-codebase: path %q is not a directory", root)
+	root := graph.CommonBase(roots)
+	if len(roots) == 1 {
+		root = roots[0]
 	}
 
 	type dirEntry struct {
@@ -231,7 +244,8 @@ func (ra *ResearchAgent) exploreCodebase(_ context.Context, text string) ([]a2a.
 		knownConfigSet[cf] = true
 	}
 
-	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+	walkRoot := root
+	walk := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil // skip entries we cannot read
 		}
@@ -239,7 +253,7 @@ func (ra *ResearchAgent) exploreCodebase(_ context.Context, text string) ([]a2a.
 		name := d.Name()
 
 		// Skip hidden directories (except root) and known noisy directories.
-		if d.IsDir() && path != root {
+		if d.IsDir() && path != walkRoot {
 			if skipDirs[name] || (strings.HasPrefix(name, ".") && name != ".") {
 				return filepath.SkipDir
 			}
@@ -269,9 +283,19 @@ func (ra *ResearchAgent) exploreCodebase(_ context.Context, text string) ([]a2a.
 		}
 
 		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("explore-codebase: walk error: %w", err)
+	}
+	var skippedLinks []string
+	for _, walkRoot = range roots {
+		skipped, err := graph.WalkTree(walkRoot, graph.WalkOptions{FollowSymlinks: ra.followSymlinks}, walk)
+		if err != nil {
+			return nil, fmt.Errorf("explore-codebase: walk error: %w", err)
+		}
+		for _, link := range skipped {
+			if rel, err := filepath.Rel(root, link); err == nil {
+				link = rel
+			}
+			skippedLinks = append(skippedLinks, link)
+		}
 	}
 
 	// Build directory tree.
@@ -325,24 +349,30 @@ func (ra *ResearchAgent) exploreCodebase(_ context.Context, text string) ([]a2a.
 
 	// Combine into markdown.
 	var md strings.Builder
-	md.WriteString(fmt.Sprintf("# Codebase Exploration: %s\n\n", root))
+	md.WriteString(fmt.Sprintf("# Codebase Exploration: %s\n\n", strings.Join(roots, ", ")))
 	md.WriteString(tree.String())
 	md.WriteString("\n")
 	md.WriteString(counts.String())
 	md.WriteString("\n")
 	md.WriteString(configs.String())
+	if len(skippedLinks) > 0 {
+		md.WriteString("\n## Skipped Symlinks\n\n")
+		for _, link := range skippedLinks {
+			md.WriteString(fmt.Sprintf("- `%s`\n", link))
+		}
+	}
 
 	artifact := a2a.Artifact{
-		ArtifactID:  a2a.NewTaskID(),
 		Name:        "codebase-exploration",
-		Description: fmt.Sprintf("Structural summary of %s", root),
+		Description: fmt.Sprintf("Structural summary of %s", strings.Join(roots, ", ")),
 		Parts:       []a2a.Part{a2a.TextPart(md.String())},
 	}
 
 	return []a2a.Artifact{artifact}, nil
 }
 
-// researchPlatform reads project config files and produces a platform baseline.
+// researchPlatform reads project config files and produces a platform
+// baseline, with secret-like values masked.
 func (ra *ResearchAgent) researchPlatform(_ context.Context, text string) ([]a2a.Artifact, error) {
 	root := extractPath(text)
 
@@ -413,10 +443,9 @@ func (ra *ResearchAgent) researchPlatform(_ context.Context, text string) ([]a2a
 	}
 
 	artifact := a2a.Artifact{
-		ArtifactID:  a2a.NewTaskID(),
 		Name:        "platform-baseline",
 		Description: fmt.Sprintf("Platform & tooling baseline for %s", root),
-		Parts:       []a2a.Part{a2a.TextPart(md.String())},
+		Parts:       []a2a.Part{a2a.TextPart(redactSecrets(md.String(), ra.redact))},
 	}
 
 	return []a2a.Artifact{artifact}, nil
@@ -475,20 +504,58 @@ func parseGoMod(content string) string {
 	return md.String()
 }
 
-// verifyVersions is a stub that returns a fallback-mode notice.
-func (ra *ResearchAgent) verifyVersions(_ context.Context, _ string) ([]a2a.Artifact, error) {
-	md := "# Version Verification\n\n" +
-		"**Note**: Web search is not available in fallback mode.\n\n" +
-		"Version verification requires access to external package registries " +
-		"(e.g., pkg.go.dev, npmjs.com, crates.io) which is not available without " +
-		"MCP tool integration. This skill will produce full results once MCP " +
-		"tools are configured.\n"
+// verifyVersions reports the dependency versions resolved by the project's
+// lockfiles. Registries are never contacted, so the report notes that the
+// registry check was skipped; when no lockfile is found it falls back to a
+// notice explaining that verification needs MCP tool integration.
+func (ra *ResearchAgent) verifyVersions(_ context.Context, text string) ([]a2a.Artifact, error) {
+	root := extractPath(text)
+
+	var md strings.Builder
+	md.WriteString("# Version Verification\n\n")
+
+	found := false
+	for _, lp := range lockfileParsers {
+		data, err := os.ReadFile(filepath.Join(root, lp.File))
+		if err != nil {
+			continue
+		}
+		versions, err := lp.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("verify-versions: %w", err)
+		}
+		if !found {
+			md.WriteString("**Note**: registry check skipped (offline). Versions below are " +
+				"the exact versions resolved by the project's lockfiles.\n\n")
+			found = true
+		}
+		md.WriteString(fmt.Sprintf("## %s (%s)\n\n", lp.Ecosystem, lp.File))
+		if len(versions) == 0 {
+			md.WriteString("_No resolved versions found._\n\n")
+			continue
+		}
+		md.WriteString("| Package | Resolved Version |\n")
+		md.WriteString("|---------|------------------|\n")
+		for _, v := range versions {
+			md.WriteString(fmt.Sprintf("| `%s` | `%s` |\n", v.Name, v.Version))
+		}
+		md.WriteString("\n")
+	}
+
+	description := "Resolved versions from lockfiles (offline)"
+	if !found {
+		description = "Version verification (fallback mode)"
+		md.WriteString("**Note**: Web search is not available in fallback mode.\n\n" +
+			"Version verification requires access to external package registries " +
+			"(e.g., pkg.go.dev, npmjs.com, crates.io) which is not available without " +
+			"MCP tool integration. No lockfile (go.sum, package-lock.json, Cargo.lock, " +
+			"poetry.lock) was found to report resolved versions from.\n")
+	}
 
 	artifact := a2a.Artifact{
-		ArtifactID:  a2a.NewTaskID(),
 		Name:        "version-verification",
-		Description: "Version verification (fallback mode)",
-		Parts:       []a2a.Part{a2a.TextPart(md)},
+		Description: description,
+		Parts:       []a2a.Part{a2a.TextPart(md.String())},
 	}
 
 	return []a2a.Artifact{artifact}, nil
@@ -8,6 +8,7 @@ import (
 	"unicode"
 
 	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/msgtext"
 )
 
 // Compile-time interface check.
@@ -57,8 +58,11 @@ func NewSchemaAgent() *SchemaAgent {
 
 // processMessage routes incoming messages to the appropriate skill handler.
 func (sa *SchemaAgent) processMessage(_ context.Context, _ *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
-	text := extractText(msg)
-	skill := detectSchemaSkill(text)
+	text := msgtext.Text(msg)
+	skill, candidates := detectSchemaSkill(text)
+	if len(candidates) > 0 {
+		return nil, ambiguousSkillError(candidates)
+	}
 
 	switch skill {
 	case "translate-schema":
@@ -68,25 +72,27 @@ func (sa *SchemaAgent) processMessage(_ context.Context, _ *a2a.Task, msg a2a.Me
 	case "write-contracts":
 		return sa.handleWriteContracts(text)
 	default:
-		return nil, fmt.Errorf("unknown skill: could not determine skill from message text")
+		return nil, &SkillError{Reason: "could not determine skill from message text"}
 	}
 }
 
-// detectSchemaSkill determines which schema skill to invoke based on message text keywords.
-func detectSchemaSkill(text string) string {
-	lower := strings.ToLower(text)
-	switch {
-	case strings.Contains(lower, "translate-schema") || strings.Contains(lower, "translate schema"):
-		return "translate-schema"
-	case strings.Contains(lower, "validate-types") || strings.Contains(lower, "validate types") || strings.Contains(lower, "validate"):
-		return "validate-types"
-	case strings.Contains(lower, "write-contracts") || strings.Contains(lower, "write contracts") || strings.Contains(lower, "endpoint") || strings.Contains(lower, "api contract"):
-		return "write-contracts"
-	case strings.Contains(lower, "entity") || strings.Contains(lower, "struct") || strings.Contains(lower, "schema") || strings.Contains(lower, "type "):
-		return "translate-schema"
-	default:
-		return ""
-	}
+// schemaSkillRules ranks explicit skill names above topical hints, and
+// generic schema vocabulary below both so it only selects translate-schema
+// when nothing more specific matches.
+var schemaSkillRules = []skillRule{
+	{"translate-schema", 3, []string{"translate-schema", "translate schema"}},
+	{"validate-types", 3, []string{"validate-types", "validate types"}},
+	{"write-contracts", 3, []string{"write-contracts", "write contracts", "api contract"}},
+	{"validate-types", 2, []string{"validate"}},
+	{"write-contracts", 2, []string{"endpoint", "query param"}},
+	{"translate-schema", 1, []string{"entity", "struct", "schema", "type "}},
+}
+
+// detectSchemaSkill determines which schema skill to invoke based on message
+// text keywords. When several skills match equally well it returns no skill
+// and the tied candidates.
+func detectSchemaSkill(text string) (string, []string) {
+	return matchSkill(text, schemaSkillRules)
 }
 
 // --- translate-schema skill ---
@@ -479,27 +485,41 @@ var endpointPutPattern = regexp.MustCompile(
 	`(?i)(?:endpoint\s+)?PUT\s+(/\S+)\s+takes?\s+(\w+)\s+returns?\s+(\w+)`,
 )
 
+// endpointPatchPattern matches "PATCH /path takes Input returns Output" style.
+var endpointPatchPattern = regexp.MustCompile(
+	`(?i)(?:endpoint\s+)?PATCH\s+(/\S+)\s+takes?\s+(\w+)\s+returns?\s+(\w+)`,
+)
+
 // endpointDeletePattern matches "DELETE /path returns Type" or "DELETE /path" style.
 var endpointDeletePattern = regexp.MustCompile(
 	`(?i)(?:endpoint\s+)?DELETE\s+(/\S+)(?:\s+returns?\s+(\w+))?`,
 )
 
+// queryParamsPattern matches a trailing "with query params a, b and c" clause.
+var queryParamsPattern = regexp.MustCompile(`(?i)\s*,?\s+with\s+query\s+params?\s+(.+)$`)
+
 // parsedEndpoint holds a parsed API endpoint description.
 type parsedEndpoint struct {
-	method     string
-	path       string
-	inputType  string
-	outputType string
-	isList     bool
+	method      string
+	path        string
+	inputType   string
+	outputType  string
+	isList      bool
+	queryParams []string
 }
 
 // handleWriteContracts parses API endpoint descriptions and generates
-// request/response struct pairs.
+// request/response struct pairs. Entities described in the same message
+// give the fields of the endpoints' input types.
 func (sa *SchemaAgent) handleWriteContracts(text string) ([]a2a.Artifact, error) {
 	endpoints := parseEndpoints(text)
 	if len(endpoints) == 0 {
 		return nil, fmt.Errorf("write-contracts: no API endpoint descriptions found in message")
 	}
+	entities := make(map[string][]entityField)
+	for _, e := range parseEntities(text) {
+		entities[exportName(e.name)] = e.fields
+	}
 
 	var sb strings.Builder
 	sb.WriteString("# Generated API Contracts\n\n```go\n")
@@ -507,7 +527,7 @@ func (sa *SchemaAgent) handleWriteContracts(text string) ([]a2a.Artifact, error)
 		if i > 0 {
 			sb.WriteString("\n")
 		}
-		sb.WriteString(formatContract(ep))
+		sb.WriteString(formatContract(ep, entities))
 	}
 	sb.WriteString("```\n")
 
@@ -531,14 +551,16 @@ func parseEndpoints(text string) []parsedEndpoint {
 		if line == "" {
 			continue
 		}
+		line, query := splitQueryParams(line)
 
 		// Try POST pattern (most specific with input+output).
 		if match := endpointPostPattern.FindStringSubmatch(line); match != nil {
 			endpoints = append(endpoints, parsedEndpoint{
-				method:     "POST",
-				path:       match[1],
-				inputType:  match[2],
-				outputType: match[3],
+				method:      "POST",
+				path:        match[1],
+				inputType:   match[2],
+				outputType:  match[3],
+				queryParams: query,
 			})
 			continue
 		}
@@ -546,10 +568,23 @@ func parseEndpoints(text string) []parsedEndpoint {
 		// Try PUT pattern.
 		if match := endpointPutPattern.FindStringSubmatch(line); match != nil {
 			endpoints = append(endpoints, parsedEndpoint{
-				method:     "PUT",
-				path:       match[1],
-				inputType:  match[2],
-				outputType: match[3],
+				method:      "PUT",
+				path:        match[1],
+				inputType:   match[2],
+				outputType:  match[3],
+				queryParams: query,
+			})
+			continue
+		}
+
+		// Try PATCH pattern.
+		if match := endpointPatchPattern.FindStringSubmatch(line); match != nil {
+			endpoints = append(endpoints, parsedEndpoint{
+				method:      "PATCH",
+				path:        match[1],
+				inputType:   match[2],
+				outputType:  match[3],
+				queryParams: query,
 			})
 			continue
 		}
@@ -558,10 +593,11 @@ func parseEndpoints(text string) []parsedEndpoint {
 		if match := endpointGetPattern.FindStringSubmatch(line); match != nil {
 			isList := strings.Contains(strings.ToLower(line), "list")
 			endpoints = append(endpoints, parsedEndpoint{
-				method:     "GET",
-				path:       match[1],
-				outputType: match[2],
-				isList:     isList,
+				method:      "GET",
+				path:        match[1],
+				outputType:  match[2],
+				isList:      isList,
+				queryParams: query,
 			})
 			continue
 		}
@@ -569,8 +605,9 @@ func parseEndpoints(text string) []parsedEndpoint {
 		// Try DELETE pattern.
 		if match := endpointDeletePattern.FindStringSubmatch(line); match != nil {
 			ep := parsedEndpoint{
-				method: "DELETE",
-				path:   match[1],
+				method:      "DELETE",
+				path:        match[1],
+				queryParams: query,
 			}
 			if match[2] != "" {
 				ep.outputType = match[2]
@@ -583,6 +620,24 @@ func parseEndpoints(text string) []parsedEndpoint {
 	return endpoints
 }
 
+// splitQueryParams removes a "with query params a, b" clause from line and
+// returns the remaining text and the parameter names.
+func splitQueryParams(line string) (string, []string) {
+	loc := queryParamsPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line, nil
+	}
+	list := strings.NewReplacer(" and ", ",", " or ", ",").Replace(line[loc[2]:loc[3]])
+	var params []string
+	for _, p := range strings.Split(list, ",") {
+		p = strings.Trim(strings.TrimSpace(p), ".")
+		if p != "" {
+			params = append(params, p)
+		}
+	}
+	return line[:loc[0]], params
+}
+
 // contractName generates a struct name prefix from an endpoint path.
 // For example, "/users" becomes "Users", "/users/{id}" becomes "UsersByID".
 func contractName(method, path string) string {
@@ -613,27 +668,73 @@ func contractName(method, path string) string {
 }
 
 // formatContract generates request/response struct pairs for an endpoint.
-func formatContract(ep parsedEndpoint) string {
+// entities maps type names to their fields; a PUT or PATCH whose input type
+// is among them gets a body struct of that type's fields made optional.
+func formatContract(ep parsedEndpoint, entities map[string][]entityField) string {
 	var sb strings.Builder
 	name := contractName(ep.method, ep.path)
 
+	// PUT and PATCH may update a resource partially, so their optional
+	// fields are pointers: nil means the client omitted the field.
+	partial := ep.method == "PUT" || ep.method == "PATCH"
+	optional := ""
+	if partial {
+		optional = "*"
+	}
+
 	// Generate request struct.
 	sb.WriteString(fmt.Sprintf("// %sRequest is the request for %s %s.\n", name, ep.method, ep.path))
+	if partial {
+		sb.WriteString("// Nil fields were omitted by the client and should be left unchanged.\n")
+	}
 	sb.WriteString(fmt.Sprintf("type %sRequest struct {\n", name))
 
 	// Add path parameters from the path.
+	seen := make(map[string]bool)
 	pathParams := extractPathParams(ep.path)
 	for _, param := range pathParams {
 		exported := exportName(param)
+		seen[exported] = true
 		sb.WriteString(fmt.Sprintf("\t%s string `json:\"%s\"`\n", exported, jsonTag(param)))
 	}
 
-	// If there is an input type, embed it as Body.
+	// Add query-string parameters, skipping any that repeat a path parameter.
+	for _, param := range ep.queryParams {
+		exported := exportName(param)
+		if seen[exported] {
+			continue
+		}
+		seen[exported] = true
+		sb.WriteString(fmt.Sprintf("\t%s %sstring `json:\"%s,omitempty\"`\n", exported, optional, jsonTag(param)))
+	}
+
+	// If there is an input type, embed it as Body. A partial update of a
+	// known type gets its own body struct, whose fields are optional too.
+	inputType := exportName(ep.inputType)
+	bodyFields, ok := entities[inputType]
+	bodyType := inputType
+	if partial && ok {
+		bodyType = name + "Body"
+	}
 	if ep.inputType != "" {
-		sb.WriteString(fmt.Sprintf("\tBody %s `json:\"body\"`\n", exportName(ep.inputType)))
+		if partial {
+			sb.WriteString(fmt.Sprintf("\tBody *%s `json:\"body,omitempty\"`\n", bodyType))
+		} else {
+			sb.WriteString(fmt.Sprintf("\tBody %s `json:\"body\"`\n", bodyType))
+		}
 	}
 	sb.WriteString("}\n\n")
 
+	if bodyType != inputType {
+		sb.WriteString(fmt.Sprintf("// %s holds the %s fields of %s %s.\n", bodyType, inputType, ep.method, ep.path))
+		sb.WriteString("// Nil fields were omitted by the client and should be left unchanged.\n")
+		sb.WriteString(fmt.Sprintf("type %s struct {\n", bodyType))
+		for _, f := range bodyFields {
+			sb.WriteString(fmt.Sprintf("\t%s %s `json:\"%s,omitempty\"`\n", exportName(f.Name), optionalType(f.Type), jsonTag(f.Name)))
+		}
+		sb.WriteString("}\n\n")
+	}
+
 	// Generate response struct.
 	sb.WriteString(fmt.Sprintf("// %sResponse is the response for %s %s.\n", name, ep.method, ep.path))
 	sb.WriteString(fmt.Sprintf("type %sResponse struct {\n", name))
@@ -650,6 +751,17 @@ func formatContract(ep parsedEndpoint) string {
 	return sb.String()
 }
 
+// optionalType returns the type of an optional field: a pointer to t, unless
+// t is already nil when absent.
+func optionalType(t string) string {
+	for _, prefix := range []string{"*", "[]", "map["} {
+		if strings.HasPrefix(t, prefix) {
+			return t
+		}
+	}
+	return "*" + t
+}
+
 // extractPathParams extracts parameter names from a URL path.
 // Recognizes both {param} and :param styles.
 func extractPathParams(path string) []string {
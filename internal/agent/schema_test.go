@@ -81,6 +81,61 @@ func TestSchemaAgent_WriteContracts(t *testing.T) {
 	assert.Contains(t, text, "UserOutput")
 }
 
+func TestSchemaAgent_WriteContracts_PatchWithQueryParams(t *testing.T) {
+	agent := NewSchemaAgent()
+
+	desc := "PATCH /users/{id} takes UserPatch returns User with query params dry_run, fields"
+	skill, _ := detectSchemaSkill(desc)
+	assert.Equal(t, "write-contracts", skill)
+
+	msg := schemaMsg(desc)
+	result, err := agent.HandleTask(context.Background(), schemaTask(), msg)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Artifacts)
+	text := result.Artifacts[0].Parts[0].Text
+	assert.Contains(t, text, "type PatchUsersByIDRequest struct {")
+	assert.Contains(t, text, "\tID string `json:\"id\"`")
+	assert.Contains(t, text, "\tDryRun *string `json:\"dryRun,omitempty\"`")
+	assert.Contains(t, text, "\tFields *string `json:\"fields,omitempty\"`")
+	assert.Contains(t, text, "\tBody *UserPatch `json:\"body,omitempty\"`")
+	assert.Contains(t, text, "\tUser User `json:\"user\"`")
+}
+
+func TestSchemaAgent_WriteContracts_PatchBodyFields(t *testing.T) {
+	agent := NewSchemaAgent()
+
+	desc := "Write contracts for this endpoint: PATCH /users/{id} takes UserPatch returns User\ntype UserPatch { name: string, tags: []string }"
+	skill, _ := detectSchemaSkill(desc)
+	require.Equal(t, "write-contracts", skill)
+
+	result, err := agent.HandleTask(context.Background(), schemaTask(), schemaMsg(desc))
+
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Artifacts)
+	text := result.Artifacts[0].Parts[0].Text
+	assert.Contains(t, text, "\tBody *PatchUsersByIDBody `json:\"body,omitempty\"`")
+	assert.Contains(t, text, "type PatchUsersByIDBody struct {")
+	assert.Contains(t, text, "\tName *string `json:\"name,omitempty\"`")
+	// Slices are already nil when omitted.
+	assert.Contains(t, text, "\tTags []string `json:\"tags,omitempty\"`")
+}
+
+func TestParseEndpoints_QueryParams(t *testing.T) {
+	endpoints := parseEndpoints("GET /users returns User list with query params limit and cursor\nPOST /users takes UserInput returns User")
+	require.Len(t, endpoints, 2)
+
+	assert.Equal(t, "GET", endpoints[0].method)
+	assert.True(t, endpoints[0].isList)
+	assert.Equal(t, []string{"limit", "cursor"}, endpoints[0].queryParams)
+	assert.Empty(t, endpoints[1].queryParams)
+
+	// Query params on non-partial methods are plain strings.
+	contract := formatContract(endpoints[0], nil)
+	assert.Contains(t, contract, "\tLimit string `json:\"limit,omitempty\"`")
+	assert.Contains(t, contract, "\tItems []User `json:\"items\"`")
+}
+
 func TestSchemaAgent_ValidateTypesFallback(t *testing.T) {
 	agent := NewSchemaAgent()
 
@@ -134,3 +189,28 @@ func TestSchemaAgent_UnknownSkill(t *testing.T) {
 	require.NotNil(t, result)
 	assert.Equal(t, a2a.TaskStateFailed, result.Status.State)
 }
+
+func TestSchemaAgent_AmbiguousSkill(t *testing.T) {
+	agent := NewSchemaAgent()
+
+	msg := schemaMsg("Please validate types and write contracts for the user service.")
+	result, err := agent.HandleTask(context.Background(), schemaTask(), msg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous skill")
+	assert.Contains(t, err.Error(), "validate-types")
+	assert.Contains(t, err.Error(), "write-contracts")
+	require.NotNil(t, result)
+	assert.Equal(t, a2a.TaskStateFailed, result.Status.State)
+
+	meta, ok := TaskFailure(result)
+	require.True(t, ok)
+	assert.Equal(t, FailureSkill, meta.Kind)
+	assert.Equal(t, []string{"validate-types", "write-contracts"}, meta.Candidates)
+	assert.False(t, meta.Retryable)
+
+	// An explicit skill name outweighs a topical hint for another skill.
+	skill, candidates := detectSchemaSkill("write-contracts: validate the /users endpoint")
+	assert.Equal(t, "write-contracts", skill)
+	assert.Empty(t, candidates)
+}
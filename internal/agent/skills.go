@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// skillRule maps message keywords to a skill. Rules with a higher weight
+// are stronger evidence: an explicit skill name outweighs a topical hint.
+type skillRule struct {
+	skill    string
+	weight   int
+	keywords []string
+}
+
+// matchSkill scores every skill against text using rules, taking each
+// skill's heaviest matching rule as its score. It returns the single
+// highest-scoring skill, or, when several skills tie for the highest score,
+// an empty skill and the tied candidates in configured order: the order in
+// which the skills first appear in rules, whichever rules matched. It
+// returns "", nil when no rule matches.
+func matchSkill(text string, rules []skillRule) (string, []string) {
+	lower := strings.ToLower(text)
+
+	scores := make(map[string]int)
+	var order []string
+	for _, r := range rules {
+		if !slices.Contains(order, r.skill) {
+			order = append(order, r.skill)
+		}
+		for _, kw := range r.keywords {
+			if !strings.Contains(lower, kw) {
+				continue
+			}
+			scores[r.skill] = max(scores[r.skill], r.weight)
+			break
+		}
+	}
+
+	best := 0
+	for _, s := range scores {
+		best = max(best, s)
+	}
+	var top []string
+	for _, skill := range order {
+		if best > 0 && scores[skill] == best {
+			top = append(top, skill)
+		}
+	}
+	switch len(top) {
+	case 0:
+		return "", nil
+	case 1:
+		return top[0], nil
+	default:
+		return "", top
+	}
+}
+
+// ambiguousSkillError reports that a message matched several skills equally.
+func ambiguousSkillError(candidates []string) *SkillError {
+	return &SkillError{
+		Candidates: candidates,
+		Reason:     fmt.Sprintf("message matches %s equally; name the skill explicitly", strings.Join(candidates, ", ")),
+	}
+}
@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSkill_TiesInConfiguredOrder(t *testing.T) {
+	rules := []skillRule{
+		{"alpha", 2, []string{"alpha"}},
+		{"beta", 2, []string{"beta"}},
+		{"alpha", 1, []string{"first"}},
+		{"gamma", 1, []string{"gamma"}},
+		{"beta", 1, []string{"second"}},
+	}
+
+	// gamma's matching rule comes before beta's, but beta is configured
+	// first, so it is listed first.
+	skill, candidates := matchSkill("second gamma", rules)
+	assert.Empty(t, skill)
+	assert.Equal(t, []string{"beta", "gamma"}, candidates)
+
+	// The order of the keywords in the message does not matter either.
+	_, reversed := matchSkill("gamma second", rules)
+	assert.Equal(t, candidates, reversed)
+
+	skill, candidates = matchSkill("beta gamma", rules)
+	assert.Equal(t, "beta", skill, "a heavier rule wins outright")
+	assert.Empty(t, candidates)
+
+	skill, candidates = matchSkill("nothing relevant", rules)
+	assert.Empty(t, skill)
+	assert.Nil(t, candidates)
+}
@@ -1,25 +1,55 @@
 package agent
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/msgtext"
+	"gopkg.in/yaml.v3"
 )
 
 // TaskWriterAgent writes detailed task specifications and validates
 // cross-milestone dependencies. It supports two skills:
 //   - write-task-specs: generates a tasks_mNN.md file from a milestone description
-//   - validate-dependencies: checks task dependency graphs for missing/circular refs
+//   - validate-dependencies: checks task dependency graphs for missing/circular refs;
+//     with "strict" (or "--strict") after the skill name, problems fail the task
 type TaskWriterAgent struct {
 	*BaseAgent
+	format TaskSpecFormat
+}
+
+// TaskSpecFormat selects how write-task-specs renders its output.
+type TaskSpecFormat string
+
+const (
+	TaskSpecMarkdown TaskSpecFormat = "markdown"
+	TaskSpecJSON     TaskSpecFormat = "json"
+	TaskSpecYAML     TaskSpecFormat = "yaml"
+)
+
+// TaskWriterOption configures a TaskWriterAgent during construction.
+type TaskWriterOption func(*TaskWriterAgent)
+
+// WithTaskSpecFormat sets the default output format for write-task-specs.
+// A message can still override it with an "output-format: json" line.
+func WithTaskSpecFormat(format TaskSpecFormat) TaskWriterOption {
+	return func(tw *TaskWriterAgent) {
+		tw.format = format
+	}
 }
 
 // NewTaskWriterAgent creates a TaskWriterAgent with its agent card and process function.
-func NewTaskWriterAgent() *TaskWriterAgent {
-	tw := &TaskWriterAgent{}
+func NewTaskWriterAgent(opts ...TaskWriterOption) *TaskWriterAgent {
+	tw := &TaskWriterAgent{format: TaskSpecMarkdown}
+	for _, opt := range opts {
+		opt(tw)
+	}
 	card := a2a.AgentCard{
 		Name:        "task-writer-agent",
 		Description: "Writes detailed task specifications and validates cross-milestone dependencies",
@@ -34,12 +64,12 @@ func NewTaskWriterAgent() *TaskWriterAgent {
 			{
 				ID:          "validate-dependencies",
 				Name:        "Validate Dependencies",
-				Description: "Validates cross-milestone task dependencies for missing or circular references",
+				Description: "Validates cross-milestone task dependencies for missing or circular references; in strict mode, any problem fails the task",
 				Tags:        []string{"task", "dependency", "validation"},
 			},
 		},
 		DefaultInputModes:  []string{"text/plain", "text/markdown"},
-		DefaultOutputModes: []string{"text/markdown"},
+		DefaultOutputModes: []string{"text/markdown", "application/json", "application/yaml"},
 	}
 	tw.BaseAgent = NewBaseAgent(card, tw.processMessage)
 	return tw
@@ -48,7 +78,7 @@ func NewTaskWriterAgent() *TaskWriterAgent {
 // processMessage dispatches to the appropriate skill handler based on the
 // message text content.
 func (tw *TaskWriterAgent) processMessage(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
-	text := extractText(msg)
+	text := msgtext.Text(msg)
 
 	switch {
 	case strings.Contains(strings.ToLower(text), "write-task-specs"):
@@ -56,22 +86,91 @@ func (tw *TaskWriterAgent) processMessage(ctx context.Context, task *a2a.Task, m
 	case strings.Contains(strings.ToLower(text), "validate-dependencies"):
 		return tw.validateDependencies(ctx, text)
 	default:
-		return nil, fmt.Errorf("unknown skill: could not determine skill from message text")
+		return nil, &SkillError{Reason: "could not determine skill from message text"}
+	}
+}
+
+// taskSpec is one parsed task, as emitted by the JSON and YAML formats.
+type taskSpec struct {
+	ID         string   `json:"id" yaml:"id"`
+	File       string   `json:"file" yaml:"file"`
+	Action     string   `json:"action" yaml:"action"`
+	DependsOn  []string `json:"dependsOn" yaml:"dependsOn"`
+	Outline    []string `json:"outline" yaml:"outline"`
+	Acceptance []string `json:"acceptance" yaml:"acceptance"`
+}
+
+// taskSpecFormatRe matches an "output-format: json" directive in a message.
+var taskSpecFormatRe = regexp.MustCompile(`(?i)output-format[:=\s]+(markdown|md|json|yaml|yml)\b`)
+
+// taskSpecFormat returns the format requested in text, or def when the
+// message does not name one.
+func taskSpecFormat(text string, def TaskSpecFormat) TaskSpecFormat {
+	m := taskSpecFormatRe.FindStringSubmatch(text)
+	if m == nil {
+		return def
+	}
+	switch strings.ToLower(m[1]) {
+	case "json":
+		return TaskSpecJSON
+	case "yaml", "yml":
+		return TaskSpecYAML
+	default:
+		return TaskSpecMarkdown
 	}
 }
 
 // writeTaskSpecs parses a milestone description and generates task
-// specifications in T-MM.SS format.
+// specifications in T-MM.SS format, rendered as markdown by default or as a
+// JSON or YAML array when requested.
 func (tw *TaskWriterAgent) writeTaskSpecs(_ context.Context, text string) ([]a2a.Artifact, error) {
 	milestoneNum := parseMilestoneNumber(text)
+	specs := parseTaskSpecs(text, milestoneNum)
+
+	var (
+		body      string
+		ext       string
+		mediaType string
+	)
+	switch taskSpecFormat(text, tw.format) {
+	case TaskSpecJSON:
+		out, err := json.MarshalIndent(specs, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal task specs: %w", err)
+		}
+		body, ext, mediaType = string(out)+"\n", "json", "application/json"
+	case TaskSpecYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(specs); err != nil {
+			return nil, fmt.Errorf("marshal task specs: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("marshal task specs: %w", err)
+		}
+		body, ext, mediaType = buf.String(), "yaml", "application/yaml"
+	default:
+		body, ext, mediaType = renderTaskSpecsMarkdown(specs, milestoneNum), "md", "text/markdown"
+	}
 
+	artifact := a2a.Artifact{
+		ArtifactID:  fmt.Sprintf("tasks_m%02d", milestoneNum),
+		Name:        fmt.Sprintf("tasks_m%02d.%s", milestoneNum, ext),
+		Description: fmt.Sprintf("Task specifications for milestone %02d", milestoneNum),
+		Parts: []a2a.Part{
+			{Text: body, MediaType: mediaType},
+		},
+	}
+	return []a2a.Artifact{artifact}, nil
+}
+
+// parseTaskSpecs extracts one taskSpec per block of text that names a file.
+func parseTaskSpecs(text string, milestoneNum int) []taskSpec {
 	// Split the input into blocks separated by blank lines or numbered items.
 	blocks := splitIntoBlocks(text)
 
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# Tasks for Milestone %02d\n\n", milestoneNum))
-
-	taskSeq := 1
+	specs := []taskSpec{}
 	for _, block := range blocks {
 		block = strings.TrimSpace(block)
 		if block == "" {
@@ -83,42 +182,59 @@ func (tw *TaskWriterAgent) writeTaskSpecs(_ context.Context, text string) ([]a2a
 			continue
 		}
 
-		taskID := fmt.Sprintf("T-%02d.%02d", milestoneNum, taskSeq)
-		action := determineAction(block)
-		deps := extractDependsOn(block)
-		outline := extractOutline(block)
-		acceptance := extractAcceptance(block)
-
-		sb.WriteString(fmt.Sprintf("## %s\n\n", taskID))
-		sb.WriteString(fmt.Sprintf("- **File**: `%s`\n", filePath))
-		sb.WriteString(fmt.Sprintf("- **Action**: %s\n", action))
-		if deps != "" {
-			sb.WriteString(fmt.Sprintf("- **Depends on**: %s\n", deps))
+		deps := []string{} // an empty list, not null, in JSON and YAML
+		if d := extractDependsOn(block); d != "" {
+			for _, dep := range strings.Split(d, ",") {
+				deps = append(deps, strings.TrimSpace(dep))
+			}
 		}
-		sb.WriteString(fmt.Sprintf("\n### Implementation Outline\n\n%s\n", outline))
-		sb.WriteString(fmt.Sprintf("\n### Acceptance Criteria\n\n%s\n\n---\n\n", acceptance))
 
-		taskSeq++
+		specs = append(specs, taskSpec{
+			ID:         fmt.Sprintf("T-%02d.%02d", milestoneNum, len(specs)+1),
+			File:       filePath,
+			Action:     determineAction(block),
+			DependsOn:  deps,
+			Outline:    extractOutline(block),
+			Acceptance: extractAcceptance(block),
+		})
 	}
+	return specs
+}
 
-	if taskSeq == 1 {
-		sb.WriteString("_No tasks could be extracted from the provided milestone description._\n")
+// renderTaskSpecsMarkdown formats specs as a tasks_mNN.md document.
+func renderTaskSpecsMarkdown(specs []taskSpec, milestoneNum int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Tasks for Milestone %02d\n\n", milestoneNum))
+
+	for _, spec := range specs {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", spec.ID))
+		sb.WriteString(fmt.Sprintf("- **File**: `%s`\n", spec.File))
+		sb.WriteString(fmt.Sprintf("- **Action**: %s\n", spec.Action))
+		if len(spec.DependsOn) > 0 {
+			sb.WriteString(fmt.Sprintf("- **Depends on**: %s\n", strings.Join(spec.DependsOn, ", ")))
+		}
+		sb.WriteString("\n### Implementation Outline\n\n")
+		for _, step := range spec.Outline {
+			sb.WriteString(fmt.Sprintf("- %s\n", step))
+		}
+		sb.WriteString("\n### Acceptance Criteria\n\n")
+		for _, criterion := range spec.Acceptance {
+			sb.WriteString(fmt.Sprintf("- [ ] %s\n", criterion))
+		}
+		sb.WriteString("\n---\n\n")
 	}
 
-	artifact := a2a.Artifact{
-		ArtifactID:  fmt.Sprintf("tasks_m%02d", milestoneNum),
-		Name:        fmt.Sprintf("tasks_m%02d.md", milestoneNum),
-		Description: fmt.Sprintf("Task specifications for milestone %02d", milestoneNum),
-		Parts: []a2a.Part{
-			{Text: sb.String(), MediaType: "text/markdown"},
-		},
+	if len(specs) == 0 {
+		sb.WriteString("_No tasks could be extracted from the provided milestone description._\n")
 	}
-	return []a2a.Artifact{artifact}, nil
+	return sb.String()
 }
 
 // validateDependencies parses task specs from text, checks that all
 // referenced task IDs exist, and detects circular dependencies using
-// Kahn's algorithm for topological sorting.
+// Kahn's algorithm for topological sorting. In strict mode (see
+// strictValidation) a missing reference or cycle also returns an error,
+// alongside the report, so the task fails.
 func (tw *TaskWriterAgent) validateDependencies(_ context.Context, text string) ([]a2a.Artifact, error) {
 	// Extract all defined task IDs (T-XX.YY at the start of headings or list items).
 	definedPattern := regexp.MustCompile(`(?m)(?:^##?\s+|^- \*\*Task\*\*:\s*)(T-\d{2}\.\d{2})`)
@@ -218,6 +334,7 @@ func (tw *TaskWriterAgent) validateDependencies(_ context.Context, text string)
 				circular = append(circular, node)
 			}
 		}
+		sort.Strings(circular)
 	}
 
 	// Build report.
@@ -256,9 +373,35 @@ func (tw *TaskWriterAgent) validateDependencies(_ context.Context, text string)
 			{Text: sb.String(), MediaType: "text/markdown"},
 		},
 	}
+	if strictValidation(text) && (len(missing) > 0 || len(circular) > 0) {
+		var problems []string
+		if len(circular) > 0 {
+			problems = append(problems, "circular dependencies among "+strings.Join(circular, ", "))
+		}
+		if len(missing) > 0 {
+			problems = append(problems, strings.Join(missing, "; "))
+		}
+		return []a2a.Artifact{artifact}, fmt.Errorf("dependency validation failed: %s", strings.Join(problems, "; "))
+	}
 	return []a2a.Artifact{artifact}, nil
 }
 
+// strictValidation reports whether the validate-dependencies request asks
+// for strict mode: "strict" or "--strict" on the line naming the skill.
+func strictValidation(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.Contains(strings.ToLower(line), "validate-dependencies") {
+			continue
+		}
+		for _, tok := range msgtext.Tokens(line) {
+			if strings.EqualFold(strings.TrimLeft(tok, "-"), "strict") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // --- Helper functions ---
 
 // parseMilestoneNumber extracts a milestone number from text like
@@ -290,6 +433,9 @@ func splitIntoBlocks(text string) []string {
 		if strings.Contains(lower, "write-task-specs") && !strings.Contains(lower, "/") {
 			continue
 		}
+		if taskSpecFormatRe.MatchString(line) {
+			continue
+		}
 		filtered = append(filtered, line)
 	}
 
@@ -355,8 +501,8 @@ func extractDependsOn(text string) string {
 	return ""
 }
 
-// extractOutline generates an implementation outline from the block text.
-func extractOutline(text string) string {
+// extractOutline generates implementation outline steps from the block text.
+func extractOutline(text string) []string {
 	// Look for lines that describe implementation steps.
 	lines := strings.Split(text, "\n")
 	var outline []string
@@ -370,27 +516,27 @@ func extractOutline(text string) string {
 		if strings.Contains(lower, "depends on") {
 			continue
 		}
-		outline = append(outline, fmt.Sprintf("- %s", line))
+		outline = append(outline, line)
 	}
 	if len(outline) == 0 {
-		return "- Implementation details to be specified"
+		return []string{"Implementation details to be specified"}
 	}
-	return strings.Join(outline, "\n")
+	return outline
 }
 
 // extractAcceptance generates acceptance criteria from the block text.
-func extractAcceptance(text string) string {
+func extractAcceptance(text string) []string {
 	filePath := extractFilePath(text)
 	action := determineAction(text)
 
 	var criteria []string
 	if action == "CREATE" {
-		criteria = append(criteria, fmt.Sprintf("- [ ] File `%s` exists", filePath))
+		criteria = append(criteria, fmt.Sprintf("File `%s` exists", filePath))
 	}
-	criteria = append(criteria, fmt.Sprintf("- [ ] `%s` compiles without errors", filePath))
-	criteria = append(criteria, "- [ ] Unit tests pass")
+	criteria = append(criteria, fmt.Sprintf("`%s` compiles without errors", filePath))
+	criteria = append(criteria, "Unit tests pass")
 
-	return strings.Join(criteria, "\n")
+	return criteria
 }
 
 // countRefs counts the total number of dependency references in the graph.
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"regexp"
 	"strings"
 	"testing"
@@ -59,6 +60,58 @@ Milestone 2: Code Intelligence
 	assert.Equal(t, "tasks_m02.md", result.Artifacts[0].Name)
 }
 
+func TestTaskWriter_WriteTaskSpecs_JSON(t *testing.T) {
+	input := `write-task-specs
+output-format: json
+Milestone 3: Storage
+1. internal/store/store.go (CREATE) - Store interface
+2. internal/store/mem.go (CREATE) - In-memory store. Depends on: T-03.01`
+
+	run := func(t *testing.T, agent *TaskWriterAgent, text string) a2a.Artifact {
+		t.Helper()
+		msg := a2a.Message{Role: a2a.RoleUser, Parts: []a2a.Part{a2a.TextPart(text)}}
+		task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "test"}
+		result, err := agent.HandleTask(context.Background(), task, msg)
+		require.NoError(t, err)
+		require.Equal(t, a2a.TaskStateCompleted, result.Status.State)
+		require.Len(t, result.Artifacts, 1)
+		return result.Artifacts[0]
+	}
+
+	art := run(t, NewTaskWriterAgent(), input)
+	assert.Equal(t, "tasks_m03.json", art.Name)
+	assert.Equal(t, "application/json", art.Parts[0].MediaType)
+
+	var specs []struct {
+		ID         string   `json:"id"`
+		File       string   `json:"file"`
+		Action     string   `json:"action"`
+		DependsOn  []string `json:"dependsOn"`
+		Outline    []string `json:"outline"`
+		Acceptance []string `json:"acceptance"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(art.Parts[0].Text), &specs))
+	require.Len(t, specs, 2)
+	assert.Equal(t, "T-03.01", specs[0].ID)
+	assert.Equal(t, "internal/store/store.go", specs[0].File)
+	assert.Equal(t, "CREATE", specs[0].Action)
+	assert.NotNil(t, specs[0].DependsOn, "no dependencies is [], not null")
+	assert.Empty(t, specs[0].DependsOn)
+	assert.NotEmpty(t, specs[0].Outline)
+	assert.Contains(t, specs[0].Acceptance, "Unit tests pass")
+	assert.Equal(t, "T-03.02", specs[1].ID)
+	assert.Equal(t, []string{"T-03.01"}, specs[1].DependsOn)
+
+	t.Run("option selects YAML without a message keyword", func(t *testing.T) {
+		plain := strings.Replace(input, "output-format: json\n", "", 1)
+		art := run(t, NewTaskWriterAgent(WithTaskSpecFormat(TaskSpecYAML)), plain)
+		assert.Equal(t, "tasks_m03.yaml", art.Name)
+		assert.Contains(t, art.Parts[0].Text, "- id: T-03.01\n")
+		assert.Contains(t, art.Parts[0].Text, "dependsOn: []\n")
+		assert.Contains(t, art.Parts[0].Text, "dependsOn:\n    - T-03.01\n")
+	})
+}
+
 func TestTaskWriter_TaskOrdering(t *testing.T) {
 	agent := NewTaskWriterAgent()
 
@@ -161,6 +214,64 @@ func TestTaskWriter_ValidateDependencies_CircularDependency(t *testing.T) {
 		"report should mention T-01.02 as part of the cycle")
 }
 
+func TestTaskWriter_ValidateDependencies_StrictFailsOnCycle(t *testing.T) {
+	agent := NewTaskWriterAgent()
+
+	input := `validate-dependencies --strict
+
+## T-01.01 — Setup
+- Depends on: T-01.02
+
+## T-01.02 — Config
+- Depends on: T-01.01`
+
+	msg := a2a.Message{
+		Role:  a2a.RoleUser,
+		Parts: []a2a.Part{a2a.TextPart(input)},
+	}
+	task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "test-strict-cycle"}
+	result, err := agent.HandleTask(context.Background(), task, msg)
+	require.Error(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, a2a.TaskStateFailed, result.Status.State)
+
+	require.NotNil(t, result.Status.Message)
+	status := result.Status.Message.Parts[0].Text
+	assert.Contains(t, status, "circular dependencies among T-01.01, T-01.02")
+
+	// The report is still attached to the failed task.
+	require.Len(t, result.Artifacts, 1)
+	assert.Equal(t, "dep-validation", result.Artifacts[0].ArtifactID)
+	assert.Contains(t, result.Artifacts[0].Parts[0].Text, "Circular Dependencies")
+}
+
+func TestTaskWriter_ValidateDependencies_StrictPassesValidGraph(t *testing.T) {
+	agent := NewTaskWriterAgent()
+
+	input := `validate-dependencies strict
+
+## T-01.01 — Setup
+
+## T-01.02 — Config
+- Depends on: T-01.01`
+
+	msg := a2a.Message{
+		Role:  a2a.RoleUser,
+		Parts: []a2a.Part{a2a.TextPart(input)},
+	}
+	task := a2a.Task{ID: a2a.NewTaskID(), ContextID: "test-strict-valid"}
+	result, err := agent.HandleTask(context.Background(), task, msg)
+	require.NoError(t, err)
+	assert.Equal(t, a2a.TaskStateCompleted, result.Status.State)
+	require.Len(t, result.Artifacts, 1)
+}
+
+func TestStrictValidation(t *testing.T) {
+	assert.True(t, strictValidation("validate-dependencies --strict\n## T-01.01"))
+	assert.True(t, strictValidation("Please validate-dependencies (strict)"))
+	assert.False(t, strictValidation("validate-dependencies\n## T-01.01 — Strict mode parser"))
+}
+
 func TestTaskWriter_AgentCard(t *testing.T) {
 	agent := NewTaskWriterAgent()
 	card := agent.Card()
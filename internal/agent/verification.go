@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/onedusk/pd/internal/a2a"
+	"github.com/onedusk/pd/internal/msgtext"
 	"github.com/onedusk/pd/internal/orchestrator"
 )
 
@@ -51,7 +52,7 @@ func NewVerificationAgent() *VerificationAgent {
 
 // processMessage is the ProcessFunc that dispatches to the appropriate skill.
 func (va *VerificationAgent) processMessage(ctx context.Context, task *a2a.Task, msg a2a.Message) ([]a2a.Artifact, error) {
-	text := extractText(msg)
+	text := msgtext.Text(msg)
 
 	switch {
 	case strings.Contains(text, "verify-cross-stage"):
@@ -59,7 +60,7 @@ func (va *VerificationAgent) processMessage(ctx context.Context, task *a2a.Task,
 	case strings.Contains(text, "verify-stage"):
 		return va.verifyStage(ctx, text)
 	default:
-		return nil, fmt.Errorf("unknown skill: message does not contain a recognized skill ID (verify-stage, verify-cross-stage)")
+		return nil, &SkillError{Reason: "message does not contain a recognized skill ID (verify-stage, verify-cross-stage)"}
 	}
 }
 
@@ -116,14 +117,12 @@ func reportToArtifacts(report *orchestrator.VerificationReport) ([]a2a.Artifact,
 	}
 
 	mdArtifact := a2a.Artifact{
-		ArtifactID:  a2a.NewTaskID(),
 		Name:        "verification-report",
 		Description: fmt.Sprintf("Verification report for stage %d (%s)", int(report.Stage), report.Stage),
 		Parts:       []a2a.Part{a2a.TextPart(report.Markdown())},
 	}
 
 	jsonArtifact := a2a.Artifact{
-		ArtifactID:  a2a.NewTaskID(),
 		Name:        "verification-data",
 		Description: "Machine-readable verification report",
 		Parts: []a2a.Part{
@@ -9,13 +9,34 @@ import (
 
 // ProjectConfig holds project-level settings loaded from decompose.yml.
 type ProjectConfig struct {
-	OutputDir     string   `yaml:"outputDir,omitempty"`
-	Languages     []string `yaml:"languages,omitempty"`
-	ExcludeDirs   []string `yaml:"excludeDirs,omitempty"`
-	TemplatePath  string   `yaml:"templatePath,omitempty"`
-	Verbose       bool     `yaml:"verbose,omitempty"`
-	SingleAgent   bool     `yaml:"singleAgent,omitempty"`
-	GraphExcludes []string `yaml:"graphExcludes,omitempty"`
+	OutputDir            string        `yaml:"outputDir,omitempty"`
+	Layout               string        `yaml:"layout,omitempty"`
+	Languages            []string      `yaml:"languages,omitempty"`
+	ExcludeDirs          []string      `yaml:"excludeDirs,omitempty"`
+	TemplatePath         string        `yaml:"templatePath,omitempty"`
+	Verbose              bool          `yaml:"verbose,omitempty"`
+	SingleAgent          bool          `yaml:"singleAgent,omitempty"`
+	GraphExcludes        []string      `yaml:"graphExcludes,omitempty"`
+	MaxSectionsPerStage  int           `yaml:"maxSectionsPerStage,omitempty"`
+	ParallelAgents       int           `yaml:"parallelAgents,omitempty"`
+	SkipCoherence        bool          `yaml:"skipCoherence,omitempty"`
+	CoherenceMaxSections int           `yaml:"coherenceMaxSections,omitempty"`
+	Stages               []StageConfig `yaml:"stages,omitempty"`
+}
+
+// StageConfig defines one stage of a custom pipeline. When ProjectConfig
+// lists any stages they replace the built-in five, in order.
+type StageConfig struct {
+	Name          string   `yaml:"name"`
+	Template      string   `yaml:"template,omitempty"`
+	Prerequisites []string `yaml:"prerequisites,omitempty"`
+	Merge         string   `yaml:"merge,omitempty"` // "concatenate" (default) or "dedup"
+	// Conflicts is "first-wins", "last-wins", "longest-wins", or "concat";
+	// empty rejects duplicate section names.
+	Conflicts string `yaml:"conflicts,omitempty"`
+	// OutputModes lists the media types the stage's agents may answer
+	// with, in order of preference; empty uses the built-in defaults.
+	OutputModes []string `yaml:"outputModes,omitempty"`
 }
 
 // Load attempts to read decompose.yml or decompose.yaml from the given
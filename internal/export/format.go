@@ -0,0 +1,66 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format names a serialization format for Encode.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// ParseFormat converts a string to a Format. An empty string maps to
+// FormatJSON.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	case FormatTOML:
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (want json, yaml, or toml)", s)
+	}
+}
+
+// Encode serializes data in the given format. The output always ends with a
+// newline.
+func Encode(data *DecompositionExport, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON, "":
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal JSON: %w", err)
+		}
+		return append(out, '\n'), nil
+	case FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(data); err != nil {
+			return nil, fmt.Errorf("marshal YAML: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("marshal YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(data); err != nil {
+			return nil, fmt.Errorf("marshal TOML: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func sampleExport() *DecompositionExport {
+	return &DecompositionExport{
+		Name:       "auth-system",
+		ExportedAt: "2026-01-02T03:04:05Z",
+		Stages: []StageExport{
+			{Stage: 0, Name: "Development Standards", Status: "complete", FilePath: "docs/decompose/stage-0-development-standards.md"},
+			{Stage: 1, Name: "Design Pack", Status: "pending"},
+		},
+		Tasks: []TaskExport{
+			{
+				ID:           "T-01.01",
+				Milestone:    "M1",
+				Title:        "Add login handler",
+				FileActions:  []string{"CREATE internal/auth/login.go"},
+				Dependencies: []string{"T-01.00"},
+				Acceptance:   []string{"returns 401 on bad password"},
+			},
+		},
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	data := sampleExport()
+
+	jsonOut, err := Encode(data, FormatJSON)
+	require.NoError(t, err)
+	var fromJSON DecompositionExport
+	require.NoError(t, json.Unmarshal(jsonOut, &fromJSON))
+
+	yamlOut, err := Encode(data, FormatYAML)
+	require.NoError(t, err)
+	var fromYAML DecompositionExport
+	require.NoError(t, yaml.Unmarshal(yamlOut, &fromYAML))
+	assert.Equal(t, fromJSON, fromYAML)
+	assert.Contains(t, string(yamlOut), "exportedAt:")
+
+	tomlOut, err := Encode(data, FormatTOML)
+	require.NoError(t, err)
+	var fromTOML DecompositionExport
+	_, err = toml.Decode(string(tomlOut), &fromTOML)
+	require.NoError(t, err)
+	assert.Equal(t, fromJSON, fromTOML)
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{
+		"":     FormatJSON,
+		"json": FormatJSON,
+		"yaml": FormatYAML,
+		"yml":  FormatYAML,
+		"toml": FormatTOML,
+	} {
+		got, err := ParseFormat(in)
+		require.NoError(t, err, "input %q", in)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseFormat("xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "xml")
+}
@@ -12,34 +12,52 @@ import (
 	"github.com/onedusk/pd/internal/status"
 )
 
-// DecompositionExport is the top-level JSON export structure.
+// DecompositionExport is the top-level export structure, serialized as JSON,
+// YAML, or TOML by Encode.
 type DecompositionExport struct {
-	Name       string        `json:"name"`
-	ExportedAt string        `json:"exportedAt"`
-	Stages     []StageExport `json:"stages"`
-	Tasks      []TaskExport  `json:"tasks,omitempty"`
+	Name       string        `json:"name" yaml:"name" toml:"name"`
+	ExportedAt string        `json:"exportedAt" yaml:"exportedAt" toml:"exportedAt"`
+	Stages     []StageExport `json:"stages" yaml:"stages" toml:"stages"`
+	Tasks      []TaskExport  `json:"tasks,omitempty" yaml:"tasks,omitempty" toml:"tasks,omitempty"`
 }
 
 // StageExport describes one pipeline stage.
 type StageExport struct {
-	Stage    int    `json:"stage"`
-	Name     string `json:"name"`
-	Status   string `json:"status"`
-	FilePath string `json:"filePath,omitempty"`
+	Stage    int    `json:"stage" yaml:"stage" toml:"stage"`
+	Name     string `json:"name" yaml:"name" toml:"name"`
+	Status   string `json:"status" yaml:"status" toml:"status"`
+	FilePath string `json:"filePath,omitempty" yaml:"filePath,omitempty" toml:"filePath,omitempty"`
 }
 
 // TaskExport describes a single task from Stage 4.
 type TaskExport struct {
-	ID           string   `json:"id"`
-	Milestone    string   `json:"milestone"`
-	Title        string   `json:"title"`
-	FileActions  []string `json:"fileActions,omitempty"`
-	Dependencies []string `json:"dependencies,omitempty"`
-	Acceptance   []string `json:"acceptanceCriteria,omitempty"`
+	ID           string   `json:"id" yaml:"id" toml:"id"`
+	Milestone    string   `json:"milestone" yaml:"milestone" toml:"milestone"`
+	Title        string   `json:"title" yaml:"title" toml:"title"`
+	FileActions  []string `json:"fileActions,omitempty" yaml:"fileActions,omitempty" toml:"fileActions,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty" yaml:"dependencies,omitempty" toml:"dependencies,omitempty"`
+	Acceptance   []string `json:"acceptanceCriteria,omitempty" yaml:"acceptanceCriteria,omitempty" toml:"acceptanceCriteria,omitempty"`
 }
 
 // ExportDecomposition builds a DecompositionExport from the filesystem.
 func ExportDecomposition(projectRoot, name string) (*DecompositionExport, error) {
+	export := exportHeader(projectRoot, name)
+
+	// Parse task files from Stage 4 output.
+	for _, tf := range taskFiles(projectRoot, name) {
+		tasks, err := parseTaskFile(tf)
+		if err != nil {
+			continue
+		}
+		export.Tasks = append(export.Tasks, tasks...)
+	}
+
+	return export, nil
+}
+
+// exportHeader returns the export for name with its stages filled in and no
+// tasks.
+func exportHeader(projectRoot, name string) *DecompositionExport {
 	ds := status.GetDecompositionStatus(projectRoot, name)
 
 	export := &DecompositionExport{
@@ -59,19 +77,14 @@ func ExportDecomposition(projectRoot, name string) (*DecompositionExport, error)
 			FilePath: si.FilePath,
 		})
 	}
+	return export
+}
 
-	// Parse task files from Stage 4 output.
+// taskFiles returns the Stage 4 task files of a decomposition in name order.
+func taskFiles(projectRoot, name string) []string {
 	outputDir := filepath.Join(projectRoot, "docs", "decompose", name)
-	taskFiles, _ := filepath.Glob(filepath.Join(outputDir, "tasks_m*.md"))
-	for _, tf := range taskFiles {
-		tasks, err := parseTaskFile(tf)
-		if err != nil {
-			continue
-		}
-		export.Tasks = append(export.Tasks, tasks...)
-	}
-
-	return export, nil
+	files, _ := filepath.Glob(filepath.Join(outputDir, "tasks_m*.md"))
+	return files
 }
 
 var (
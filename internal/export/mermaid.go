@@ -10,9 +10,68 @@ import (
 	"github.com/onedusk/pd/internal/graph"
 )
 
+// clusterPalette holds the fill/stroke pairs assigned to clusters in order.
+// Colors repeat once there are more clusters than entries.
+var clusterPalette = []struct{ fill, stroke string }{
+	{"#cfe2ff", "#3d7bd9"},
+	{"#d1f2d9", "#2e9e4f"},
+	{"#ffe5c2", "#d9822b"},
+	{"#f5d0e6", "#b8468c"},
+	{"#e2d9f3", "#6f42c1"},
+	{"#fff3bf", "#c9a227"},
+	{"#d2f0f2", "#1b9aaa"},
+	{"#f8d7da", "#c0392b"},
+}
+
+// unclusteredClass is the neutral style for nodes with no cluster.
+const unclusteredClass = "unclustered"
+
+// mermaidConfig holds the settings applied by MermaidOption values.
+type mermaidConfig struct {
+	byCluster bool
+	root      string
+	depth     int
+	maxNodes  int
+}
+
+// MermaidOption configures GenerateMermaid.
+type MermaidOption func(*mermaidConfig)
+
+// WithClusterStyles colors each file node by the cluster it belongs to and
+// adds a legend subgraph mapping colors to cluster names. Nodes with no
+// cluster get a neutral style.
+func WithClusterStyles() MermaidOption {
+	return func(c *mermaidConfig) {
+		c.byCluster = true
+	}
+}
+
+// WithRoot limits the diagram to root and the files it reaches by following
+// at most depth IMPORTS edges. Depths below 1 are treated as 1.
+func WithRoot(root string, depth int) MermaidOption {
+	return func(c *mermaidConfig) {
+		c.root = graph.NormalizePath(root)
+		c.depth = max(depth, 1)
+	}
+}
+
+// WithMaxNodes caps the diagram at n file nodes, keeping those closest to the
+// root (see WithRoot), or the first by path, and noting how many were left
+// out. Zero or less means no cap.
+func WithMaxNodes(n int) MermaidOption {
+	return func(c *mermaidConfig) {
+		c.maxNodes = n
+	}
+}
+
 // GenerateMermaid produces a Mermaid graph TD diagram from a graph store.
 // Files are grouped by cluster; IMPORTS edges become arrows.
-func GenerateMermaid(ctx context.Context, store graph.Store) (string, error) {
+func GenerateMermaid(ctx context.Context, store graph.Store, opts ...MermaidOption) (string, error) {
+	var cfg mermaidConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	clusters, err := store.GetClusters(ctx)
 	if err != nil {
 		return "", fmt.Errorf("get clusters: %w", err)
@@ -23,6 +82,11 @@ func GenerateMermaid(ctx context.Context, store graph.Store) (string, error) {
 		return "", fmt.Errorf("get edges: %w", err)
 	}
 
+	keep, omitted, err := scopeFiles(ctx, store, cfg, clusters, edges)
+	if err != nil {
+		return "", err
+	}
+
 	// Build node → ID mapping for Mermaid (alphanumeric only).
 	nodeIDs := make(map[string]string)
 	nextID := 0
@@ -36,27 +100,39 @@ func GenerateMermaid(ctx context.Context, store graph.Store) (string, error) {
 		return id
 	}
 
-	// Track which files are in clusters.
+	// Track which files are in clusters, dropping those out of scope.
 	clustered := make(map[string]string) // file → cluster name
-	for _, c := range clusters {
+	for i, c := range clusters {
+		var members []string
 		for _, member := range c.Members {
-			clustered[member] = c.Name
+			if keep == nil || keep[member] {
+				members = append(members, member)
+				clustered[member] = c.Name
+			}
 		}
+		clusters[i].Members = members
 	}
 
 	var sb strings.Builder
 	sb.WriteString("graph TD\n")
 
 	// Emit cluster subgraphs.
+	var classNames []string // class name per emitted cluster, in order
+	var legendNames []string
+	subgraphIDs := make(map[string]bool)
 	for _, c := range clusters {
 		if len(c.Members) == 0 {
 			continue
 		}
+		classNames = append(classNames, fmt.Sprintf("cluster%d", len(classNames)))
+		legendNames = append(legendNames, c.Name)
 		sorted := make([]string, len(c.Members))
 		copy(sorted, c.Members)
 		sort.Strings(sorted)
 
-		sb.WriteString(fmt.Sprintf("  subgraph %s[\"%.40s\"]\n", getID(c.Name+"_cluster"), c.Name))
+		subgraphID := getID(c.Name + "_cluster")
+		subgraphIDs[subgraphID] = true
+		sb.WriteString(fmt.Sprintf("  subgraph %s[\"%.40s\"]\n", subgraphID, c.Name))
 		for _, member := range sorted {
 			label := shortPath(member)
 			sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", getID(member), label))
@@ -64,19 +140,160 @@ func GenerateMermaid(ctx context.Context, store graph.Store) (string, error) {
 		sb.WriteString("  end\n")
 	}
 
+	// A rooted diagram always labels its root, even outside any cluster.
+	if cfg.root != "" && clustered[cfg.root] == "" {
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", getID(cfg.root), shortPath(cfg.root)))
+	}
+
 	// Emit IMPORTS edges.
 	for _, e := range edges {
 		if e.Kind != graph.EdgeKindImports {
 			continue
 		}
+		if keep != nil && (!keep[e.SourceID] || !keep[e.TargetID]) {
+			continue
+		}
 		srcID := getID(e.SourceID)
 		tgtID := getID(e.TargetID)
 		sb.WriteString(fmt.Sprintf("  %s --> %s\n", srcID, tgtID))
 	}
 
+	if omitted > 0 {
+		sb.WriteString(fmt.Sprintf("  Truncated[\"%d more files not shown\"]\n", omitted))
+	}
+
+	if cfg.byCluster {
+		writeClusterStyles(&sb, nodeIDs, subgraphIDs, clustered, classNames, legendNames)
+	}
+
 	return sb.String(), nil
 }
 
+// scopeFiles returns the set of files the diagram may show and how many
+// otherwise shown files the node cap left out. A nil set means every file.
+func scopeFiles(ctx context.Context, store graph.Store, cfg mermaidConfig, clusters []graph.ClusterNode, edges []graph.Edge) (map[string]bool, int, error) {
+	var ordered []string
+	if cfg.root != "" {
+		f, err := store.GetFile(ctx, cfg.root)
+		if err != nil {
+			return nil, 0, fmt.Errorf("look up root: %w", err)
+		}
+		if f == nil {
+			return nil, 0, fmt.Errorf("root %s is not in the graph", cfg.root)
+		}
+		chains, err := store.GetDependencies(ctx, cfg.root, graph.DirectionDownstream, cfg.depth, graph.EdgeKindImports)
+		if err != nil {
+			return nil, 0, fmt.Errorf("get dependencies of %s: %w", cfg.root, err)
+		}
+		// Order files by hop count from the root, then by path, so a node
+		// cap keeps the nearest ones.
+		hops := map[string]int{cfg.root: 0}
+		for _, c := range chains {
+			file := c.Nodes[len(c.Nodes)-1]
+			if h, ok := hops[file]; !ok || len(c.Nodes)-1 < h {
+				hops[file] = len(c.Nodes) - 1
+			}
+		}
+		for file := range hops {
+			ordered = append(ordered, file)
+		}
+		sort.Slice(ordered, func(i, j int) bool {
+			a, b := ordered[i], ordered[j]
+			if hops[a] != hops[b] {
+				return hops[a] < hops[b]
+			}
+			return a < b
+		})
+	} else {
+		if cfg.maxNodes <= 0 {
+			return nil, 0, nil
+		}
+		seen := make(map[string]bool)
+		add := func(file string) {
+			if !seen[file] {
+				seen[file] = true
+				ordered = append(ordered, file)
+			}
+		}
+		for _, c := range clusters {
+			for _, member := range c.Members {
+				add(member)
+			}
+		}
+		for _, e := range edges {
+			if e.Kind == graph.EdgeKindImports {
+				add(e.SourceID)
+				add(e.TargetID)
+			}
+		}
+		sort.Strings(ordered)
+	}
+
+	omitted := 0
+	if cfg.maxNodes > 0 && len(ordered) > cfg.maxNodes {
+		omitted = len(ordered) - cfg.maxNodes
+		ordered = ordered[:cfg.maxNodes]
+	}
+	keep := make(map[string]bool, len(ordered))
+	for _, file := range ordered {
+		keep[file] = true
+	}
+	return keep, omitted, nil
+}
+
+// writeClusterStyles emits the legend, classDefs, and class assignments for a
+// diagram colored by cluster. clustered maps each member file to its cluster
+// name; classNames and legendNames are parallel, one entry per cluster.
+func writeClusterStyles(
+	sb *strings.Builder,
+	nodeIDs map[string]string,
+	subgraphIDs map[string]bool,
+	clustered map[string]string,
+	classNames, legendNames []string,
+) {
+	classByCluster := make(map[string]string, len(classNames))
+	for i, name := range legendNames {
+		classByCluster[name] = classNames[i]
+	}
+
+	// Group node IDs by class. Subgraphs are not file nodes and stay unstyled.
+	members := make(map[string][]string)
+	for path, id := range nodeIDs {
+		if subgraphIDs[id] {
+			continue
+		}
+		class := unclusteredClass
+		if name, ok := clustered[path]; ok {
+			class = classByCluster[name]
+		}
+		members[class] = append(members[class], id)
+	}
+
+	sb.WriteString("  subgraph Legend[\"Legend\"]\n")
+	for i, name := range legendNames {
+		sb.WriteString(fmt.Sprintf("    L%d[\"%.40s\"]:::%s\n", i, name, classNames[i]))
+	}
+	if len(members[unclusteredClass]) > 0 {
+		sb.WriteString(fmt.Sprintf("    L%d[\"no cluster\"]:::%s\n", len(legendNames), unclusteredClass))
+	}
+	sb.WriteString("  end\n")
+
+	for i, class := range classNames {
+		color := clusterPalette[i%len(clusterPalette)]
+		sb.WriteString(fmt.Sprintf("  classDef %s fill:%s,stroke:%s\n", class, color.fill, color.stroke))
+	}
+	sb.WriteString(fmt.Sprintf("  classDef %s fill:#f0f0f0,stroke:#999999,color:#555555\n", unclusteredClass))
+
+	for _, class := range append(append([]string{}, classNames...), unclusteredClass) {
+		ids := members[class]
+		if len(ids) == 0 {
+			continue
+		}
+		sort.Strings(ids)
+		sb.WriteString(fmt.Sprintf("  class %s %s\n", strings.Join(ids, ","), class))
+	}
+}
+
 // shortPath returns the last 2 path segments for readability.
 func shortPath(path string) string {
 	parts := strings.Split(filepath.ToSlash(path), "/")
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/onedusk/pd/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedClusteredStore(t *testing.T) *graph.MemStore {
+	t.Helper()
+	ctx := context.Background()
+	store := graph.NewMemStore()
+
+	for _, path := range []string{"auth/login.go", "auth/token.go", "db/conn.go", "main.go"} {
+		require.NoError(t, store.AddFile(ctx, graph.FileNode{Path: path, Language: graph.LangGo}))
+	}
+	edges := []graph.Edge{
+		{SourceID: "main.go", TargetID: "auth/login.go", Kind: graph.EdgeKindImports},
+		{SourceID: "auth/login.go", TargetID: "auth/token.go", Kind: graph.EdgeKindImports},
+		{SourceID: "auth/token.go", TargetID: "db/conn.go", Kind: graph.EdgeKindImports},
+	}
+	for _, e := range edges {
+		require.NoError(t, store.AddEdge(ctx, e))
+	}
+	require.NoError(t, store.AddCluster(ctx, graph.ClusterNode{Name: "auth", Members: []string{"auth/login.go", "auth/token.go"}}))
+	require.NoError(t, store.AddCluster(ctx, graph.ClusterNode{Name: "db", Members: []string{"db/conn.go"}}))
+	return store
+}
+
+// nodeID returns the Mermaid ID of the node labeled label.
+func nodeID(t *testing.T, mermaid, label string) string {
+	t.Helper()
+	m := regexp.MustCompile(`(N\d+)\["` + regexp.QuoteMeta(label) + `"\]`).FindStringSubmatch(mermaid)
+	require.NotNil(t, m, "node %q not found in:\n%s", label, mermaid)
+	return m[1]
+}
+
+// nodeClass returns the class assigned to id by a "class" statement.
+func nodeClass(t *testing.T, mermaid, id string) string {
+	t.Helper()
+	m := regexp.MustCompile(`(?m)^  class (?:\S+,)?` + id + `(?:,\S+)? (\S+)$`).FindStringSubmatch(mermaid)
+	require.NotNil(t, m, "no class assignment for %s in:\n%s", id, mermaid)
+	return m[1]
+}
+
+func TestGenerateMermaid_ByCluster(t *testing.T) {
+	store := seedClusteredStore(t)
+
+	out, err := GenerateMermaid(context.Background(), store, WithClusterStyles())
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "classDef cluster0 ")
+	assert.Contains(t, out, "classDef cluster1 ")
+	assert.Contains(t, out, "classDef unclustered ")
+	assert.Contains(t, out, `L0["auth"]:::cluster0`)
+	assert.Contains(t, out, `L1["db"]:::cluster1`)
+
+	login := nodeClass(t, out, nodeID(t, out, "auth/login.go"))
+	token := nodeClass(t, out, nodeID(t, out, "auth/token.go"))
+	conn := nodeClass(t, out, nodeID(t, out, "db/conn.go"))
+	assert.Equal(t, login, token, "files in the same cluster share a class")
+	assert.NotEqual(t, login, conn)
+
+	// main.go has no cluster and only appears as an edge endpoint.
+	mainID := regexp.MustCompile(`(?m)^  (N\d+) --> `).FindStringSubmatch(out)
+	require.NotNil(t, mainID)
+	assert.Equal(t, "unclustered", nodeClass(t, out, mainID[1]))
+}
+
+func TestGenerateMermaid_DefaultHasNoStyles(t *testing.T) {
+	store := seedClusteredStore(t)
+
+	out, err := GenerateMermaid(context.Background(), store)
+	require.NoError(t, err)
+	assert.NotContains(t, out, "classDef")
+	assert.NotContains(t, out, "Legend")
+}
+
+func TestGenerateMermaid_RootDepth(t *testing.T) {
+	store := seedClusteredStore(t)
+
+	out, err := GenerateMermaid(context.Background(), store, WithRoot("auth/login.go", 1))
+	require.NoError(t, err)
+
+	login := nodeID(t, out, "auth/login.go")
+	token := nodeID(t, out, "auth/token.go")
+	assert.Contains(t, out, "  "+login+" --> "+token+"\n")
+	assert.Equal(t, 1, strings.Count(out, " --> "), "only the root's direct import is drawn:\n%s", out)
+	assert.NotContains(t, out, "db/conn.go")
+	assert.NotContains(t, out, `["db"]`, "clusters with no files in scope are omitted")
+}
+
+func TestGenerateMermaid_RootNotInGraph(t *testing.T) {
+	_, err := GenerateMermaid(context.Background(), seedClusteredStore(t), WithRoot("missing.go", 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in the graph")
+}
+
+func TestGenerateMermaid_MaxNodes(t *testing.T) {
+	store := seedClusteredStore(t)
+
+	out, err := GenerateMermaid(context.Background(), store, WithRoot("main.go", 3), WithMaxNodes(2))
+	require.NoError(t, err)
+
+	// main.go and login.go are nearest the root; the other two are cut.
+	assert.Contains(t, out, `["main.go"]`)
+	assert.Contains(t, out, `["auth/login.go"]`)
+	assert.NotContains(t, out, "auth/token.go")
+	assert.Contains(t, out, `Truncated["2 more files not shown"]`)
+}
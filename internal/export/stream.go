@@ -0,0 +1,124 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// StreamJSON writes data to w as JSON, marshaling one stage or task at a
+// time. The output is byte-identical to Encode(data, FormatJSON).
+func StreamJSON(w io.Writer, data *DecompositionExport) error {
+	return streamJSON(w, data, func(yield func([]TaskExport) bool) {
+		yield(data.Tasks)
+	})
+}
+
+// StreamDecompositionJSON exports the named decomposition to w as JSON
+// without holding all of its tasks in memory: each task file is parsed and
+// written before the next is read. The output matches encoding the result
+// of ExportDecomposition with FormatJSON.
+func StreamDecompositionJSON(w io.Writer, projectRoot, name string) error {
+	return streamJSON(w, exportHeader(projectRoot, name), func(yield func([]TaskExport) bool) {
+		for _, tf := range taskFiles(projectRoot, name) {
+			tasks, err := parseTaskFile(tf)
+			if err != nil {
+				continue
+			}
+			if !yield(tasks) {
+				return
+			}
+		}
+	})
+}
+
+// streamJSON writes head's scalar fields and stages followed by the tasks
+// produced by batches, reproducing json.MarshalIndent(data, "", "  ")
+// element by element.
+func streamJSON(w io.Writer, head *DecompositionExport, batches iter.Seq[[]TaskExport]) error {
+	s := &jsonStream{w: bufio.NewWriter(w)}
+
+	s.raw("{\n")
+	s.field("name", head.Name)
+	s.field("exportedAt", head.ExportedAt)
+	s.raw(`  "stages": `)
+	switch {
+	case head.Stages == nil:
+		s.raw("null")
+	case len(head.Stages) == 0:
+		s.raw("[]")
+	default:
+		s.raw("[")
+		for i, stage := range head.Stages {
+			s.element(stage, i == 0)
+		}
+		s.raw("\n  ]")
+	}
+
+	// Tasks are omitted when empty, so the key is written with the first one.
+	n := 0
+	for tasks := range batches {
+		for _, task := range tasks {
+			if n == 0 {
+				s.raw(",\n" + `  "tasks": [`)
+			}
+			s.element(task, n == 0)
+			n++
+		}
+		if s.err != nil {
+			break
+		}
+	}
+	if n > 0 {
+		s.raw("\n  ]")
+	}
+	s.raw("\n}\n")
+
+	if s.err != nil {
+		return s.err
+	}
+	return s.w.Flush()
+}
+
+// jsonStream writes indented JSON piecewise, remembering the first error.
+type jsonStream struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (s *jsonStream) raw(text string) {
+	if s.err == nil {
+		_, s.err = s.w.WriteString(text)
+	}
+}
+
+// field writes a top-level "key": value pair followed by a comma.
+func (s *jsonStream) field(key string, value any) {
+	s.raw(fmt.Sprintf("  %q: ", key))
+	s.value(value, "  ")
+	s.raw(",\n")
+}
+
+// element writes one array element of a top-level array.
+func (s *jsonStream) element(v any, first bool) {
+	if !first {
+		s.raw(",")
+	}
+	s.raw("\n    ")
+	s.value(v, "    ")
+}
+
+// value marshals v indented as if nested at prefix.
+func (s *jsonStream) value(v any, prefix string) {
+	if s.err != nil {
+		return
+	}
+	out, err := json.MarshalIndent(v, prefix, "  ")
+	if err != nil {
+		s.err = fmt.Errorf("marshal JSON: %w", err)
+		return
+	}
+	_, s.err = s.w.Write(out)
+}
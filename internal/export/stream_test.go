@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingWriter records the total bytes written and the largest single write.
+type countingWriter struct {
+	total    int
+	maxWrite int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.total += len(p)
+	w.maxWrite = max(w.maxWrite, len(p))
+	return len(p), nil
+}
+
+func TestStreamJSON_MatchesEncode(t *testing.T) {
+	noTasks := sampleExport()
+	noTasks.Tasks = nil
+	escaped := sampleExport()
+	escaped.Tasks[0].Title = `Render <a href="x">&</a>`
+
+	for name, data := range map[string]*DecompositionExport{
+		"full":     sampleExport(),
+		"no tasks": noTasks,
+		"escaped":  escaped,
+		"empty":    {Name: "empty"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			want, err := Encode(data, FormatJSON)
+			require.NoError(t, err)
+
+			var got bytes.Buffer
+			require.NoError(t, StreamJSON(&got, data))
+			assert.Equal(t, string(want), got.String())
+		})
+	}
+}
+
+func TestStreamDecompositionJSON_LargeExport(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs", "decompose", "big")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	const milestones, perMilestone = 50, 200
+	for m := 1; m <= milestones; m++ {
+		var b strings.Builder
+		for i := 1; i <= perMilestone; i++ {
+			fmt.Fprintf(&b, "### T-%02d.%03d — Task %d of milestone %d\n\n", m, i, i, m)
+			fmt.Fprintf(&b, "- **File:** `internal/m%02d/file%03d.go` (CREATE)\n", m, i)
+			if i > 1 {
+				fmt.Fprintf(&b, "- **Depends on:** T-%02d.%03d\n", m, i-1)
+			}
+			b.WriteString("\n**Acceptance criteria:**\n\n- [ ] compiles\n- [ ] tests pass\n\n---\n\n")
+		}
+		name := filepath.Join(dir, fmt.Sprintf("tasks_m%02d.md", m))
+		require.NoError(t, os.WriteFile(name, []byte(b.String()), 0o644))
+	}
+
+	// The stream reaches the writer in buffer-sized pieces, never as one
+	// marshaled document.
+	var cw countingWriter
+	require.NoError(t, StreamDecompositionJSON(&cw, root, "big"))
+	assert.Greater(t, cw.total, 1<<20)
+	assert.LessOrEqual(t, cw.maxWrite, 4096)
+
+	var buf bytes.Buffer
+	require.NoError(t, StreamDecompositionJSON(&buf, root, "big"))
+	var got DecompositionExport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	want, err := ExportDecomposition(root, "big")
+	require.NoError(t, err)
+	want.ExportedAt = got.ExportedAt
+	require.Len(t, got.Tasks, milestones*perMilestone)
+	assert.Equal(t, *want, got)
+	assert.Equal(t, []string{"T-50.199"}, got.Tasks[len(got.Tasks)-1].Dependencies)
+}
@@ -2,17 +2,58 @@ package graph
 
 import (
 	"context"
+	"sort"
 	"strings"
 )
 
+// DefaultCrossLanguageWeight is the weight ComputeClusters gives an IMPORTS
+// edge between files of different languages, relative to 1 for an edge
+// between files of the same language.
+const DefaultCrossLanguageWeight = 0.25
+
+// clusterJoinWeight is the lightest edge that joins two files into one
+// cluster. Lighter edges only lower cohesion, as external edges.
+const clusterJoinWeight = 0.5
+
+// clusterConfig holds the settings applied by ClusterOptions.
+type clusterConfig struct {
+	crossLanguageWeight float64
+}
+
+// ClusterOption configures ComputeClusters and RecomputeClusters.
+type ClusterOption func(*clusterConfig)
+
+// WithCrossLanguageWeight sets the weight of IMPORTS edges between files of
+// different languages (DefaultCrossLanguageWeight by default). Edges
+// weighing less than half a same-language edge do not join clusters, so by
+// default clusters stop at language boundaries; a weight of 1 treats every
+// edge alike. Files without a language never count as cross-language.
+func WithCrossLanguageWeight(w float64) ClusterOption {
+	return func(c *clusterConfig) {
+		c.crossLanguageWeight = w
+	}
+}
+
 // ComputeClusters finds connected components in the file-to-file graph
-// (IMPORTS edges only) and stores them as ClusterNodes.
+// (IMPORTS edges only) and stores them as ClusterNodes, replacing any
+// clusters already in the store.
 //
 // Algorithm:
-//  1. Build an undirected adjacency list from IMPORTS edges among the given files.
-//  2. Find connected components via BFS.
+//  1. Build an undirected, weighted adjacency list from IMPORTS edges among
+//     the given files; see WithCrossLanguageWeight.
+//  2. Find connected components via BFS over edges heavy enough to join.
 //  3. For each component with >= 2 files, compute a cohesion score and store the cluster.
-func ComputeClusters(ctx context.Context, store Store, files []FileNode) ([]ClusterNode, error) {
+func ComputeClusters(ctx context.Context, store Store, files []FileNode, opts ...ClusterOption) ([]ClusterNode, error) {
+	cfg := clusterConfig{crossLanguageWeight: DefaultCrossLanguageWeight}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// Replace clusters from any earlier build rather than duplicating them.
+	if err := store.ClearClusters(ctx); err != nil {
+		return nil, err
+	}
+
 	filePaths := make(map[string]bool, len(files))
 	for _, f := range files {
 		filePaths[f.Path] = true
@@ -24,7 +65,7 @@ func ComputeClusters(ctx context.Context, store Store, files []FileNode) ([]Clus
 	// However, that would be expensive. Instead, for MemStore usage
 	// we build adjacency from individual file dependency queries.
 
-	adj := buildAdjacency(ctx, store, files)
+	adj := buildAdjacency(ctx, store, files, cfg.crossLanguageWeight)
 
 	// BFS to find connected components.
 	visited := make(map[string]bool, len(files))
@@ -65,12 +106,53 @@ func ComputeClusters(ctx context.Context, store Store, files []FileNode) ([]Clus
 	return clusters, nil
 }
 
-// buildAdjacency constructs a bidirectional adjacency list from IMPORTS edges
-// using a single pass over all edges (O(E) instead of O(N*E)).
-func buildAdjacency(ctx context.Context, store Store, files []FileNode) map[string]map[string]bool {
-	adj := make(map[string]map[string]bool, len(files))
+// RecomputeClusters reruns ComputeClusters over the files already in store,
+// without reparsing anything, so clusters reflect edges added since the last
+// build. Only files with IMPORTS edges can join a cluster, so those are the
+// files considered.
+func RecomputeClusters(ctx context.Context, store Store, opts ...ClusterOption) ([]ClusterNode, error) {
+	edges, err := store.GetAllEdges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var files []FileNode
+	for _, e := range edges {
+		if e.Kind != EdgeKindImports {
+			continue
+		}
+		for _, path := range []string{e.SourceID, e.TargetID} {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			f, err := store.GetFile(ctx, path)
+			if err != nil {
+				return nil, err
+			}
+			if f != nil {
+				files = append(files, *f)
+			}
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return ComputeClusters(ctx, store, files, opts...)
+}
+
+// buildAdjacency constructs a bidirectional, weighted adjacency list from
+// IMPORTS edges using a single pass over all edges (O(E) instead of O(N*E)).
+// Edges between files of different languages weigh crossLanguageWeight;
+// all others weigh 1. Generated files are left out: they are widely
+// imported, so they would join otherwise unrelated files into one cluster.
+func buildAdjacency(ctx context.Context, store Store, files []FileNode, crossLanguageWeight float64) map[string]map[string]float64 {
+	adj := make(map[string]map[string]float64, len(files))
+	langs := make(map[string]Language, len(files))
 	for _, f := range files {
-		adj[f.Path] = make(map[string]bool)
+		if f.Generated {
+			continue
+		}
+		adj[f.Path] = make(map[string]float64)
+		langs[f.Path] = f.Language
 	}
 
 	// Single pass: retrieve all edges and filter to IMPORTS between known files.
@@ -84,17 +166,22 @@ func buildAdjacency(ctx context.Context, store Store, files []FileNode) map[stri
 		}
 		// Only include edges between known files.
 		if adj[e.SourceID] != nil && adj[e.TargetID] != nil {
-			adj[e.SourceID][e.TargetID] = true
-			adj[e.TargetID][e.SourceID] = true
+			weight := 1.0
+			if a, b := langs[e.SourceID], langs[e.TargetID]; a != "" && b != "" && a != b {
+				weight = crossLanguageWeight
+			}
+			adj[e.SourceID][e.TargetID] = weight
+			adj[e.TargetID][e.SourceID] = weight
 		}
 	}
 
 	return adj
 }
 
-// bfsComponent performs BFS from start on the adjacency list and returns
-// all reachable nodes. It marks visited nodes as it goes.
-func bfsComponent(start string, adj map[string]map[string]bool, visited map[string]bool) []string {
+// bfsComponent performs BFS from start on the adjacency list, following
+// edges of at least clusterJoinWeight, and returns all reachable nodes. It
+// marks visited nodes as it goes.
+func bfsComponent(start string, adj map[string]map[string]float64, visited map[string]bool) []string {
 	var component []string
 	queue := []string{start}
 	visited[start] = true
@@ -103,8 +190,8 @@ func bfsComponent(start string, adj map[string]map[string]bool, visited map[stri
 		node := queue[0]
 		queue = queue[1:]
 		component = append(component, node)
-		for neighbor := range adj[node] {
-			if !visited[neighbor] {
+		for neighbor, weight := range adj[node] {
+			if !visited[neighbor] && weight >= clusterJoinWeight {
 				visited[neighbor] = true
 				queue = append(queue, neighbor)
 			}
@@ -114,38 +201,38 @@ func bfsComponent(start string, adj map[string]map[string]bool, visited map[stri
 	return component
 }
 
-// computeCohesion calculates internal_edges / (internal_edges + external_edges)
+// computeCohesion calculates internal_weight / (internal_weight + external_weight)
 // for a connected component. Internal edges connect two members; external edges
-// connect a member to a non-member.
-func computeCohesion(component []string, adj map[string]map[string]bool, allFiles map[string]bool) float64 {
+// connect a member to a non-member. Each edge counts at its weight.
+func computeCohesion(component []string, adj map[string]map[string]float64, allFiles map[string]bool) float64 {
 	memberSet := make(map[string]bool, len(component))
 	for _, m := range component {
 		memberSet[m] = true
 	}
 
-	internalEdges := 0
-	externalEdges := 0
+	internal := 0.0
+	external := 0.0
 
 	// Count each undirected edge once by only counting when source < target
 	// for internal, and always counting outbound for external.
 	for _, m := range component {
-		for neighbor := range adj[m] {
+		for neighbor, weight := range adj[m] {
 			if memberSet[neighbor] {
 				// Count each internal edge once (when m < neighbor alphabetically).
 				if m < neighbor {
-					internalEdges++
+					internal += weight
 				}
 			} else if allFiles[neighbor] {
-				externalEdges++
+				external += weight
 			}
 		}
 	}
 
-	total := internalEdges + externalEdges
+	total := internal + external
 	if total == 0 {
 		return 0
 	}
-	return float64(internalEdges) / float64(total)
+	return internal / total
 }
 
 // longestCommonPrefix finds the longest common path prefix among a set of
@@ -91,6 +91,28 @@ func TestComputeClusters_OnePair(t *testing.T) {
 	assert.Equal(t, 3, stats.EdgeCount, "expected 1 IMPORTS + 2 BELONGS edges")
 }
 
+func TestComputeClusters_SkipsGeneratedFiles(t *testing.T) {
+	// Two unrelated packages both import one generated file, which must not
+	// join them; only the a.go–b.go pair clusters.
+	files := []FileNode{
+		{Path: "api/api.pb.go", Language: LangGo, Generated: true},
+		{Path: "orders/a.go", Language: LangGo},
+		{Path: "orders/b.go", Language: LangGo},
+		{Path: "users/c.go", Language: LangGo},
+	}
+	edges := []Edge{
+		{SourceID: "orders/a.go", TargetID: "orders/b.go", Kind: EdgeKindImports},
+		{SourceID: "orders/a.go", TargetID: "api/api.pb.go", Kind: EdgeKindImports},
+		{SourceID: "users/c.go", TargetID: "api/api.pb.go", Kind: EdgeKindImports},
+	}
+
+	store := setupStore(t, files, edges)
+	clusters, err := ComputeClusters(context.Background(), store, files)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, []string{"orders/a.go", "orders/b.go"}, sortedMembers(clusters[0].Members))
+}
+
 func TestComputeClusters_TwoGroups(t *testing.T) {
 	// Six files in two separate groups of 3, each fully connected within the group.
 	// Group 1: src/alpha/a.go, src/alpha/b.go, src/alpha/c.go
@@ -135,11 +157,12 @@ func TestComputeClusters_TwoGroups(t *testing.T) {
 
 func TestComputeClusters_CohesionScore(t *testing.T) {
 	// Because buildAdjacency creates bidirectional edges and BFS finds all
-	// reachable nodes, any file connected by an edge to a component member
-	// will be pulled into that component. This means external edges (edges
-	// to known files outside the component) are structurally impossible:
-	// cohesion = internal / (internal + external) is always 1.0 for any
-	// non-trivial cluster produced by ComputeClusters.
+	// reachable nodes, any file connected by a same-language edge to a
+	// component member will be pulled into that component. This means
+	// external edges (edges to known files outside the component) are
+	// structurally impossible within one language: cohesion =
+	// internal / (internal + external) is always 1.0 for any non-trivial
+	// single-language cluster produced by ComputeClusters.
 	//
 	// We verify this property with two scenarios:
 	// 1. A fully connected 3-node cluster (3 internal edges) -> 1.0
@@ -219,3 +242,47 @@ func TestComputeClusters_ClusterNames(t *testing.T) {
 	assert.Equal(t, "src/beta/sub/", clusters[1].Name,
 		"cluster name should be the common path prefix 'src/beta/sub/'")
 }
+
+// mixedLanguageFixture is a Go service and a TypeScript client, each
+// importing within itself, plus one generated-client edge from Go to TS.
+func mixedLanguageFixture(t *testing.T) (*MemStore, []FileNode) {
+	files := []FileNode{
+		{Path: "server/api.go", Language: LangGo, LOC: 80},
+		{Path: "server/handlers.go", Language: LangGo, LOC: 120},
+		{Path: "web/client.ts", Language: LangTypeScript, LOC: 60},
+		{Path: "web/app.ts", Language: LangTypeScript, LOC: 90},
+	}
+	edges := []Edge{
+		{SourceID: "server/handlers.go", TargetID: "server/api.go", Kind: EdgeKindImports},
+		{SourceID: "web/app.ts", TargetID: "web/client.ts", Kind: EdgeKindImports},
+		{SourceID: "server/api.go", TargetID: "web/client.ts", Kind: EdgeKindImports},
+	}
+	return setupStore(t, files, edges), files
+}
+
+func TestComputeClusters_CrossLanguageEdgesKeepClustersApart(t *testing.T) {
+	store, files := mixedLanguageFixture(t)
+
+	clusters, err := ComputeClusters(context.Background(), store, files)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	assert.Equal(t, []string{"server/api.go", "server/handlers.go"}, sortedMembers(clusters[0].Members))
+	assert.Equal(t, []string{"web/app.ts", "web/client.ts"}, sortedMembers(clusters[1].Members))
+
+	// The cross-language edge counts against cohesion at its weight:
+	// 1 internal / (1 internal + 0.25 external).
+	for _, c := range clusters {
+		assert.InDelta(t, 1/(1+DefaultCrossLanguageWeight), c.CohesionScore, 1e-9, c.Name)
+	}
+}
+
+func TestComputeClusters_CrossLanguageWeightOfOneJoinsLanguages(t *testing.T) {
+	store, files := mixedLanguageFixture(t)
+
+	clusters, err := ComputeClusters(context.Background(), store, files, WithCrossLanguageWeight(1))
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Len(t, clusters[0].Members, 4)
+	assert.Equal(t, 1.0, clusters[0].CohesionScore)
+}
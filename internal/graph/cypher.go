@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// readClauses are the clauses a read-only query may start with.
+var readClauses = map[string]bool{
+	"MATCH":    true,
+	"OPTIONAL": true,
+	"WITH":     true,
+	"UNWIND":   true,
+	"RETURN":   true,
+}
+
+// writeKeywords are rejected anywhere in a read-only query. CALL is included
+// because procedures can change database state.
+var writeKeywords = map[string]bool{
+	"CREATE": true, "MERGE": true, "SET": true, "DELETE": true, "DETACH": true,
+	"REMOVE": true, "DROP": true, "ALTER": true, "COPY": true, "LOAD": true,
+	"INSTALL": true, "ATTACH": true, "USE": true, "CALL": true, "EXPORT": true,
+	"IMPORT": true, "BEGIN": true, "COMMIT": true, "ROLLBACK": true,
+	"CHECKPOINT": true,
+}
+
+// CheckReadOnlyCypher reports an error unless cypher is a single statement
+// that starts with a read clause and uses no mutating keyword. The check is
+// lexical and deliberately conservative: keywords inside string literals,
+// comments, and backquoted names are ignored, as are property, label, and
+// parameter names, but any other use of a write keyword is rejected.
+func CheckReadOnlyCypher(cypher string) error {
+	words, multi := cypherKeywords(cypher)
+	if multi {
+		return fmt.Errorf("graph: read query must be a single statement")
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("graph: read query is empty")
+	}
+	if !readClauses[words[0]] {
+		return fmt.Errorf("graph: read query must start with MATCH, OPTIONAL MATCH, WITH, UNWIND, or RETURN, not %s", words[0])
+	}
+	for _, w := range words {
+		if writeKeywords[w] {
+			return fmt.Errorf("graph: read query must not use %s", w)
+		}
+	}
+	return nil
+}
+
+// cypherKeywords returns the upper-cased bare words of cypher outside string
+// literals, comments, and backquoted names, skipping words that follow '.',
+// ':', or '$'. multi reports a ';' followed by further non-space input.
+func cypherKeywords(cypher string) (words []string, multi bool) {
+	rs := []rune(cypher)
+	var prev rune // last significant rune before the current word
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			i = skipQuoted(rs, i)
+			prev = r
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			for i += 2; i+1 < len(rs) && !(rs[i] == '*' && rs[i+1] == '/'); i++ {
+			}
+			i += 2
+		case r == ';':
+			if strings.TrimSpace(string(rs[i+1:])) != "" {
+				multi = true
+			}
+			i++
+			prev = r
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_') {
+				i++
+			}
+			if prev != '.' && prev != ':' && prev != '$' {
+				words = append(words, strings.ToUpper(string(rs[start:i])))
+			}
+			prev = 'a'
+		default:
+			if !unicode.IsSpace(r) {
+				prev = r
+			}
+			i++
+		}
+	}
+	return words, multi
+}
+
+// skipQuoted returns the index just past the quoted run starting at rs[i].
+// Backslash escapes are honored inside string literals.
+func skipQuoted(rs []rune, i int) int {
+	quote := rs[i]
+	for i++; i < len(rs); i++ {
+		switch {
+		case rs[i] == '\\' && quote != '`':
+			i++
+		case rs[i] == quote:
+			return i + 1
+		}
+	}
+	return i
+}
@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReadOnlyCypher(t *testing.T) {
+	allowed := []string{
+		"MATCH (f:File) RETURN f.path",
+		"match (s:Symbol) where s.name = 'CREATE' return s.id;",
+		"OPTIONAL MATCH (a:File)-[:IMPORTS]->(b:File) RETURN a.path, b.path",
+		"MATCH (n:Symbol) WHERE n.kind = $set RETURN n.name // DELETE in a comment",
+		"UNWIND [1, 2] AS x RETURN x",
+		"MATCH (`Create`:File) RETURN `Create`.path",
+	}
+	for _, q := range allowed {
+		assert.NoError(t, CheckReadOnlyCypher(q), q)
+	}
+
+	rejected := []string{
+		"",
+		"CREATE (f:File {path: 'x'})",
+		"MATCH (f:File) DETACH DELETE f",
+		"MATCH (f:File) SET f.loc = 0",
+		"MATCH (f:File) MERGE (g:File {path: 'y'})",
+		"MATCH (f:File) REMOVE f.sloc",
+		"MATCH (f:File) RETURN f; DROP TABLE File",
+		"CALL show_tables() RETURN *",
+		"DROP TABLE File",
+	}
+	for _, q := range rejected {
+		assert.Error(t, CheckReadOnlyCypher(q), q)
+	}
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Symbol change kinds reported in SymbolChange.Changes.
+const (
+	ChangeMoved    = "moved"    // defined in a different file
+	ChangeRenamed  = "renamed"  // name differs
+	ChangeEdited   = "edited"   // StableID differs: signature or body changed
+	ChangeLines    = "lines"    // start or end line differs
+	ChangeKind     = "kind"     // symbol kind differs
+	ChangeExported = "exported" // visibility differs
+)
+
+// GraphDiff lists what changed between two graph snapshots. Files are
+// identified by path; symbols are paired across snapshots as described on
+// DiffGraphs.
+type GraphDiff struct {
+	AddedFiles    []string `json:"addedFiles"`
+	RemovedFiles  []string `json:"removedFiles"`
+	ModifiedFiles []string `json:"modifiedFiles"`
+
+	AddedSymbols    []SymbolNode   `json:"addedSymbols"`
+	RemovedSymbols  []SymbolNode   `json:"removedSymbols"`
+	ModifiedSymbols []SymbolChange `json:"modifiedSymbols"`
+}
+
+// SymbolChange is a symbol present in both snapshots whose location or
+// source differs.
+type SymbolChange struct {
+	Old     SymbolNode `json:"old"`
+	New     SymbolNode `json:"new"`
+	Changes []string   `json:"changes"` // Change* constants, in a fixed order
+}
+
+// Empty reports whether the snapshots are identical.
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedFiles) == 0 && len(d.RemovedFiles) == 0 && len(d.ModifiedFiles) == 0 &&
+		len(d.AddedSymbols) == 0 && len(d.RemovedSymbols) == 0 && len(d.ModifiedSymbols) == 0
+}
+
+// DiffGraphs compares the files and symbols of graph snapshot before with
+// those of the later snapshot after.
+//
+// A file is modified when its content hash differs, or, when either snapshot
+// lacks a hash, when its language or line counts differ.
+//
+// Symbols are paired in three passes, each over those still unpaired: first
+// by "filePath:name" ID, then by StableID (a move or rename with unchanged
+// source), then by name and kind when exactly one symbol on each side has
+// them (a move combined with an edit). Paired symbols that differ are
+// modified; the rest are added or removed.
+func DiffGraphs(ctx context.Context, before, after Store) (*GraphDiff, error) {
+	oldFiles, err := before.GetAllFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("graph diff: list old files: %w", err)
+	}
+	newFiles, err := after.GetAllFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("graph diff: list new files: %w", err)
+	}
+	oldSymbols, err := before.GetAllSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("graph diff: list old symbols: %w", err)
+	}
+	newSymbols, err := after.GetAllSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("graph diff: list new symbols: %w", err)
+	}
+
+	diff := &GraphDiff{}
+	diffFiles(diff, oldFiles, newFiles)
+	diffSymbols(diff, oldSymbols, newSymbols)
+	return diff, nil
+}
+
+// diffFiles fills in the file lists of diff.
+func diffFiles(diff *GraphDiff, oldFiles, newFiles []FileNode) {
+	removed := make(map[string]FileNode, len(oldFiles))
+	for _, f := range oldFiles {
+		removed[f.Path] = f
+	}
+	for _, f := range newFiles {
+		prev, ok := removed[f.Path]
+		delete(removed, f.Path)
+		switch {
+		case !ok:
+			diff.AddedFiles = append(diff.AddedFiles, f.Path)
+		case fileChanged(prev, f):
+			diff.ModifiedFiles = append(diff.ModifiedFiles, f.Path)
+		}
+	}
+	for path := range removed {
+		diff.RemovedFiles = append(diff.RemovedFiles, path)
+	}
+	sort.Strings(diff.AddedFiles)
+	sort.Strings(diff.RemovedFiles)
+	sort.Strings(diff.ModifiedFiles)
+}
+
+// fileChanged reports whether a file's indexed content differs.
+func fileChanged(a, b FileNode) bool {
+	if a.ContentHash != "" && b.ContentHash != "" {
+		return a.ContentHash != b.ContentHash
+	}
+	return a.Language != b.Language || a.LOC != b.LOC || a.SLOC != b.SLOC
+}
+
+// diffSymbols pairs oldSymbols with newSymbols and fills in the symbol lists
+// of diff.
+func diffSymbols(diff *GraphDiff, oldSymbols, newSymbols []SymbolNode) {
+	oldLeft := make(map[string]SymbolNode, len(oldSymbols))
+	for _, sym := range oldSymbols {
+		oldLeft[symbolKey(sym.FilePath, sym.Name)] = sym
+	}
+	newLeft := make(map[string]SymbolNode, len(newSymbols))
+	for _, sym := range newSymbols {
+		newLeft[symbolKey(sym.FilePath, sym.Name)] = sym
+	}
+
+	pair := func(oldKey, newKey string) {
+		a, b := oldLeft[oldKey], newLeft[newKey]
+		delete(oldLeft, oldKey)
+		delete(newLeft, newKey)
+		if changes := symbolChanges(a, b); len(changes) > 0 {
+			diff.ModifiedSymbols = append(diff.ModifiedSymbols, SymbolChange{Old: a, New: b, Changes: changes})
+		}
+	}
+
+	for key := range oldLeft {
+		if _, ok := newLeft[key]; ok {
+			pair(key, key)
+		}
+	}
+	pairUnique(oldLeft, newLeft, func(sym SymbolNode) string { return sym.StableID }, pair)
+	pairUnique(oldLeft, newLeft, func(sym SymbolNode) string { return string(sym.Kind) + "\x00" + sym.Name }, pair)
+
+	for _, sym := range oldLeft {
+		diff.RemovedSymbols = append(diff.RemovedSymbols, sym)
+	}
+	for _, sym := range newLeft {
+		diff.AddedSymbols = append(diff.AddedSymbols, sym)
+	}
+	sortSymbols(diff.RemovedSymbols)
+	sortSymbols(diff.AddedSymbols)
+	sort.Slice(diff.ModifiedSymbols, func(i, j int) bool {
+		a, b := diff.ModifiedSymbols[i].New, diff.ModifiedSymbols[j].New
+		return symbolKey(a.FilePath, a.Name) < symbolKey(b.FilePath, b.Name)
+	})
+}
+
+// pairUnique calls pair for each match key (skipping empty ones) held by
+// exactly one symbol in oldLeft and exactly one in newLeft.
+func pairUnique(oldLeft, newLeft map[string]SymbolNode, matchKey func(SymbolNode) string, pair func(oldKey, newKey string)) {
+	index := func(syms map[string]SymbolNode) map[string]string {
+		byMatch := make(map[string]string)
+		ambiguous := make(map[string]bool)
+		for key, sym := range syms {
+			m := matchKey(sym)
+			if m == "" {
+				continue
+			}
+			if _, ok := byMatch[m]; ok {
+				ambiguous[m] = true
+			}
+			byMatch[m] = key
+		}
+		for m := range ambiguous {
+			delete(byMatch, m)
+		}
+		return byMatch
+	}
+	oldByMatch, newByMatch := index(oldLeft), index(newLeft)
+	for m, oldKey := range oldByMatch {
+		if newKey, ok := newByMatch[m]; ok {
+			pair(oldKey, newKey)
+		}
+	}
+}
+
+// symbolChanges lists how b differs from a. StableIDs are only compared when
+// both symbols have one.
+func symbolChanges(a, b SymbolNode) []string {
+	var changes []string
+	if a.FilePath != b.FilePath {
+		changes = append(changes, ChangeMoved)
+	}
+	if a.Name != b.Name {
+		changes = append(changes, ChangeRenamed)
+	}
+	if a.StableID != "" && b.StableID != "" && a.StableID != b.StableID {
+		changes = append(changes, ChangeEdited)
+	}
+	if a.StartLine != b.StartLine || a.EndLine != b.EndLine {
+		changes = append(changes, ChangeLines)
+	}
+	if a.Kind != b.Kind {
+		changes = append(changes, ChangeKind)
+	}
+	if a.Exported != b.Exported {
+		changes = append(changes, ChangeExported)
+	}
+	return changes
+}
+
+// sortSymbols orders symbols by file path and name.
+func sortSymbols(syms []SymbolNode) {
+	sort.Slice(syms, func(i, j int) bool {
+		return symbolKey(syms[i].FilePath, syms[i].Name) < symbolKey(syms[j].FilePath, syms[j].Name)
+	})
+}
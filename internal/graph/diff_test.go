@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshot builds a MemStore holding files and symbols.
+func snapshot(t *testing.T, files []FileNode, symbols []SymbolNode) *MemStore {
+	t.Helper()
+	ctx := context.Background()
+	s := NewMemStore()
+	for _, f := range files {
+		require.NoError(t, s.AddFile(ctx, f))
+	}
+	for _, sym := range symbols {
+		require.NoError(t, s.AddSymbol(ctx, sym))
+	}
+	return s
+}
+
+func symbolIDs(syms []SymbolNode) []string {
+	var ids []string
+	for _, s := range syms {
+		ids = append(ids, symbolKey(s.FilePath, s.Name))
+	}
+	return ids
+}
+
+func TestDiffGraphs(t *testing.T) {
+	before := snapshot(t,
+		[]FileNode{
+			{Path: "a.go", Language: LangGo, LOC: 40, ContentHash: "a1"},
+			{Path: "old.go", Language: LangGo, LOC: 5, ContentHash: "o1"},
+			{Path: "same.go", Language: LangGo, LOC: 8, ContentHash: "s1"},
+		},
+		[]SymbolNode{
+			{Name: "Handle", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 10, EndLine: 20, StableID: "handle-v1"},
+			{Name: "parse", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 22, EndLine: 30, StableID: "parse-v1"},
+			{Name: "Keep", Kind: SymbolKindType, FilePath: "same.go", StartLine: 1, EndLine: 3, StableID: "keep"},
+			{Name: "Shift", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 32, EndLine: 35, StableID: "shift"},
+			{Name: "Gone", Kind: SymbolKindFunction, FilePath: "old.go", StartLine: 1, EndLine: 4, StableID: "gone"},
+		})
+	after := snapshot(t,
+		[]FileNode{
+			{Path: "a.go", Language: LangGo, LOC: 30, ContentHash: "a2"},
+			{Path: "b.go", Language: LangGo, LOC: 25, ContentHash: "b1"},
+			{Path: "same.go", Language: LangGo, LOC: 8, ContentHash: "s1"},
+		},
+		[]SymbolNode{
+			// Moved to b.go and its body edited.
+			{Name: "Handle", Kind: SymbolKindFunction, FilePath: "b.go", StartLine: 3, EndLine: 15, StableID: "handle-v2"},
+			// Moved and renamed, source unchanged.
+			{Name: "parseInput", Kind: SymbolKindFunction, FilePath: "b.go", StartLine: 17, EndLine: 25, StableID: "parse-v1"},
+			{Name: "Keep", Kind: SymbolKindType, FilePath: "same.go", StartLine: 1, EndLine: 3, StableID: "keep"},
+			// Same file, new lines.
+			{Name: "Shift", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 12, EndLine: 15, StableID: "shift"},
+			{Name: "Fresh", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 1, EndLine: 5, StableID: "fresh"},
+		})
+
+	diff, err := DiffGraphs(context.Background(), before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"b.go"}, diff.AddedFiles)
+	assert.Equal(t, []string{"old.go"}, diff.RemovedFiles)
+	assert.Equal(t, []string{"a.go"}, diff.ModifiedFiles)
+
+	assert.Equal(t, []string{"a.go:Fresh"}, symbolIDs(diff.AddedSymbols))
+	assert.Equal(t, []string{"old.go:Gone"}, symbolIDs(diff.RemovedSymbols))
+
+	require.Len(t, diff.ModifiedSymbols, 3)
+	shift, handle, parse := diff.ModifiedSymbols[0], diff.ModifiedSymbols[1], diff.ModifiedSymbols[2]
+
+	assert.Equal(t, "a.go", shift.Old.FilePath)
+	assert.Equal(t, []string{ChangeLines}, shift.Changes)
+
+	assert.Equal(t, "a.go", handle.Old.FilePath)
+	assert.Equal(t, "b.go", handle.New.FilePath)
+	assert.Equal(t, []string{ChangeMoved, ChangeEdited, ChangeLines}, handle.Changes)
+
+	assert.Equal(t, "parse", parse.Old.Name)
+	assert.Equal(t, "parseInput", parse.New.Name)
+	assert.Equal(t, []string{ChangeMoved, ChangeRenamed, ChangeLines}, parse.Changes)
+
+	assert.False(t, diff.Empty())
+}
+
+func TestDiffGraphs_AmbiguousNamesAreNotPaired(t *testing.T) {
+	// Two edited symbols named New move to different files: pairing by name
+	// would be a guess, so they are reported as removed and added.
+	before := snapshot(t, nil, []SymbolNode{
+		{Name: "New", Kind: SymbolKindFunction, FilePath: "x.go", StableID: "x1"},
+		{Name: "New", Kind: SymbolKindFunction, FilePath: "y.go", StableID: "y1"},
+	})
+	after := snapshot(t, nil, []SymbolNode{
+		{Name: "New", Kind: SymbolKindFunction, FilePath: "x2.go", StableID: "x2"},
+		{Name: "New", Kind: SymbolKindFunction, FilePath: "y2.go", StableID: "y2"},
+	})
+
+	diff, err := DiffGraphs(context.Background(), before, after)
+	require.NoError(t, err)
+	assert.Empty(t, diff.ModifiedSymbols)
+	assert.Equal(t, []string{"x.go:New", "y.go:New"}, symbolIDs(diff.RemovedSymbols))
+	assert.Equal(t, []string{"x2.go:New", "y2.go:New"}, symbolIDs(diff.AddedSymbols))
+}
+
+func TestDiffGraphs_IdenticalSnapshots(t *testing.T) {
+	files := []FileNode{{Path: "a.go", Language: LangGo, LOC: 3}}
+	symbols := []SymbolNode{{Name: "A", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 1, EndLine: 3}}
+
+	diff, err := DiffGraphs(context.Background(), snapshot(t, files, symbols), snapshot(t, files, symbols))
+	require.NoError(t, err)
+	assert.True(t, diff.Empty())
+}
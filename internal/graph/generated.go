@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// generatedSuffixes are file name endings used by common code generators.
+var generatedSuffixes = []string{".pb.go", ".pb.gw.go", "_gen.go", "_generated.go", ".gen.go", ".gen.ts", "_pb2.py"}
+
+// generatedHeaderLines is how many leading lines IsGeneratedFile searches for
+// a generated-code header.
+const generatedHeaderLines = 20
+
+// generatedHeaderRe matches Go's generated-code header; see
+// https://go.dev/s/generatedcode.
+var generatedHeaderRe = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// IsGeneratedFile reports whether the file at filePath with content source was
+// produced by a code generator: its name matches a generator's naming
+// convention, such as "*.pb.go" or "*_gen.go", or one of its leading lines is
+// Go's "// Code generated ... DO NOT EDIT." header.
+func IsGeneratedFile(filePath string, source []byte) bool {
+	name := path.Base(NormalizePath(filePath))
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(source))
+	for i := 0; i < generatedHeaderLines && sc.Scan(); i++ {
+		if generatedHeaderRe.MatchString(strings.TrimSuffix(sc.Text(), "\r")) {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryOption configures AssessImpact and GetClusters.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	excludeGenerated bool
+}
+
+// ExcludeGenerated leaves generated files (FileNode.Generated) out of query
+// results: they are dropped from the affected lists of AssessImpact, which
+// also scores risk against hand-written files only, and from cluster members
+// in GetClusters, which omits clusters left empty.
+func ExcludeGenerated() QueryOption {
+	return func(o *queryOptions) {
+		o.excludeGenerated = true
+	}
+}
+
+func applyQueryOptions(opts []QueryOption) queryOptions {
+	var o queryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// withoutGenerated returns paths minus those in generated.
+func withoutGenerated(paths []string, generated map[string]bool) []string {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !generated[p] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// clustersWithoutGenerated drops generated files from each cluster's members
+// and omits clusters with no members left.
+func clustersWithoutGenerated(clusters []ClusterNode, generated map[string]bool) []ClusterNode {
+	out := make([]ClusterNode, 0, len(clusters))
+	for _, c := range clusters {
+		c.Members = withoutGenerated(c.Members, generated)
+		if len(c.Members) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
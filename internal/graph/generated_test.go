@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsGeneratedFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		source string
+		want   bool
+	}{
+		{"go header", "api/types.go", "// Code generated by stringer; DO NOT EDIT.\n\npackage api\n", true},
+		{"header after license", "api/types.go", "// Copyright 2024\n\n// Code generated by mockgen. DO NOT EDIT.\npackage api\n", true},
+		{"crlf header", "api/types.go", "// Code generated by stringer; DO NOT EDIT.\r\npackage api\r\n", true},
+		{"other marker", "gen/models.py", "# @generated by protoc\nimport x\n", false},
+		{"block comment marker", "lib/util.ts", "/*\n * DO NOT EDIT this file by hand.\n */\nexport {}\n", false},
+		{"marker in doc comment", "api/types.go", "// Keep in sync; DO NOT EDIT the table below.\npackage api\n", false},
+		{"protobuf name", "api/v1/service.pb.go", "package v1\n", true},
+		{"gen suffix", "internal/db/queries_gen.go", "package db\n", true},
+		{"hand-written", "main.go", "package main\n\nfunc main() {}\n", false},
+		{"marker in code", "main.go", "package main\n\nconst s = \"DO NOT EDIT\"\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsGeneratedFile(tt.path, []byte(tt.source)))
+		})
+	}
+}
+
+func TestAssessImpact_ExcludeGenerated(t *testing.T) {
+	ctx := context.Background()
+	source := []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n\ntype Request struct{}\n")
+	result, err := NewTreeSitterParser().Parse(ctx, "api/api.go", source, LangGo)
+	require.NoError(t, err)
+	assert.True(t, result.File.Generated)
+
+	// core.go is imported by the generated api.go, which handler.go imports.
+	s := NewMemStore()
+	require.NoError(t, s.AddFile(ctx, result.File))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "core.go", Language: LangGo}))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "handler.go", Language: LangGo}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "api/api.go", TargetID: "core.go", Kind: EdgeKindImports}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "handler.go", TargetID: "api/api.go", Kind: EdgeKindImports}))
+
+	all, err := s.AssessImpact(ctx, []string{"core.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api/api.go", "handler.go"}, sortedMembers(all.TransitivelyAffected))
+
+	impact, err := s.AssessImpact(ctx, []string{"core.go"}, ExcludeGenerated())
+	require.NoError(t, err)
+	assert.Empty(t, impact.DirectlyAffected)
+	assert.Equal(t, []string{"handler.go"}, impact.TransitivelyAffected)
+	assert.InDelta(t, 0.5, impact.RiskScore, 0.01)
+}
+
+func TestGetClusters_ExcludeGenerated(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemStore()
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "a.go", Language: LangGo}))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "a.pb.go", Language: LangGo, Generated: true}))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "b.pb.go", Language: LangGo, Generated: true}))
+	require.NoError(t, s.AddCluster(ctx, ClusterNode{Name: "api", Members: []string{"a.go", "a.pb.go"}}))
+	require.NoError(t, s.AddCluster(ctx, ClusterNode{Name: "proto", Members: []string{"b.pb.go"}}))
+
+	clusters, err := s.GetClusters(ctx, ExcludeGenerated())
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, []string{"a.go"}, clusters[0].Members)
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IdentifierTokens splits an identifier or free-text query into lowercase
+// words, breaking at camelCase and PascalCase boundaries, underscores,
+// hyphens, dots, spaces, and letter-digit transitions, so that "UserService",
+// "user_service", and "user service" all yield [user service]. An upper-case
+// run keeps its acronym together: "HTTPServer" yields [http server].
+func IdentifierTokens(name string) []string {
+	var tokens []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			tokens = append(tokens, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(cur) > 0 {
+			prev := cur[len(cur)-1]
+			switch {
+			case unicode.IsDigit(r) != unicode.IsDigit(prev):
+				// letter-digit transition: "v2" -> [v 2]
+				flush()
+			case unicode.IsUpper(r) && unicode.IsLower(prev):
+				// camel hump: "userService" -> [user service]
+				flush()
+			case unicode.IsUpper(r) && unicode.IsUpper(prev) &&
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				// end of an acronym: "HTTPServer" -> [http server]
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return tokens
+}
@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifierTokens(t *testing.T) {
+	tests := map[string][]string{
+		"UserService":     {"user", "service"},
+		"userService":     {"user", "service"},
+		"user_service":    {"user", "service"},
+		"USER_SERVICE":    {"user", "service"},
+		"user service":    {"user", "service"},
+		"user-service":    {"user", "service"},
+		"HTTPServer":      {"http", "server"},
+		"Base64Encode":    {"base", "64", "encode"},
+		"parseJSONBody":   {"parse", "json", "body"},
+		"__init__":        {"init"},
+		"UserServiceImpl": {"user", "service", "impl"},
+		"":                nil,
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, IdentifierTokens(in), in)
+	}
+}
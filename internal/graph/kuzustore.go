@@ -4,10 +4,13 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 
 	kuzu "github.com/kuzudb/go-kuzu"
 )
@@ -17,6 +20,7 @@ import (
 type KuzuStore struct {
 	db   *kuzu.Database
 	conn *kuzu.Connection
+	path string // database path for file-based stores; empty in memory
 }
 
 // Compile-time check that KuzuStore satisfies Store.
@@ -46,17 +50,44 @@ func NewKuzuFileStore(dbPath string) (*KuzuStore, error) {
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
 		return nil, fmt.Errorf("kuzu: create parent directory: %w", err)
 	}
+	db, conn, err := openKuzuFile(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	return &KuzuStore{db: db, conn: conn, path: dbPath}, nil
+}
+
+// NewKuzuReadOnlyFileStore opens the existing file-based KuzuDB at dbPath in
+// read-only mode: every write, including InitSchema, fails, and the database
+// is left exactly as it was. Use it to inspect snapshots.
+func NewKuzuReadOnlyFileStore(dbPath string) (*KuzuStore, error) {
+	db, conn, err := openKuzuFileMode(dbPath, true)
+	if err != nil {
+		return nil, err
+	}
+	return &KuzuStore{db: db, conn: conn, path: dbPath}, nil
+}
+
+// openKuzuFile opens the file-based KuzuDB at dbPath and a connection to it.
+func openKuzuFile(dbPath string) (*kuzu.Database, *kuzu.Connection, error) {
+	return openKuzuFileMode(dbPath, false)
+}
+
+// openKuzuFileMode is openKuzuFile, optionally opening the database
+// read-only.
+func openKuzuFileMode(dbPath string, readOnly bool) (*kuzu.Database, *kuzu.Connection, error) {
 	cfg := kuzu.DefaultSystemConfig()
+	cfg.ReadOnly = readOnly
 	db, err := kuzu.OpenDatabase(dbPath, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("kuzu: open file database: %w", err)
+		return nil, nil, fmt.Errorf("kuzu: open file database: %w", err)
 	}
 	conn, err := kuzu.OpenConnection(db)
 	if err != nil {
 		db.Close()
-		return nil, fmt.Errorf("kuzu: open connection: %w", err)
+		return nil, nil, fmt.Errorf("kuzu: open connection: %w", err)
 	}
-	return &KuzuStore{db: db, conn: conn}, nil
+	return db, conn, nil
 }
 
 // Close releases the KuzuDB connection and database.
@@ -72,7 +103,9 @@ func (s *KuzuStore) Close() error {
 
 // ---------- Schema setup ----------
 
-// ddlStatements defines the Cypher DDL executed by InitSchema.
+// ddlStatements defines the Cypher DDL for schema version 1, the layout
+// every store starts from. It must not change: later changes are expressed
+// as schemaMigrations so existing file-based stores can be upgraded in place.
 // Order matters: node tables must precede relationship tables.
 var ddlStatements = []string{
 	`CREATE NODE TABLE IF NOT EXISTS File(
@@ -104,8 +137,73 @@ var ddlStatements = []string{
 	`CREATE REL TABLE IF NOT EXISTS BELONGS_TO(FROM File TO Cluster)`,
 }
 
-// InitSchema creates all node and relationship tables if they do not exist.
-func (s *KuzuStore) InitSchema(_ context.Context) error {
+// schemaVersionDDL creates the single-row table that records the schema
+// version a store has been migrated to. Stores created before versioning
+// have no row and are treated as version 1.
+const schemaVersionDDL = `CREATE NODE TABLE IF NOT EXISTS SchemaVersion(
+	id INT64,
+	version INT64,
+	PRIMARY KEY(id)
+)`
+
+// schemaMigration upgrades the schema from Version-1 to Version. Statements
+// must be safe to re-run (e.g. ADD IF NOT EXISTS) so that a migration
+// interrupted before the version bump can be applied again.
+type schemaMigration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// schemaMigrations lists the migrations in ascending version order. Append new
+// entries here; never edit or reorder applied ones.
+var schemaMigrations = []schemaMigration{
+	{
+		Version:     2,
+		Description: "add File.content_hash for incremental builds",
+		Statements: []string{
+			`ALTER TABLE File ADD IF NOT EXISTS content_hash STRING DEFAULT ''`,
+		},
+	},
+	{
+		Version:     3,
+		Description: "add Symbol.stable_id for move and rename detection",
+		Statements: []string{
+			`ALTER TABLE Symbol ADD IF NOT EXISTS stable_id STRING DEFAULT ''`,
+		},
+	},
+	{
+		Version:     4,
+		Description: "add Symbol.decorators for Python decorators",
+		Statements: []string{
+			`ALTER TABLE Symbol ADD IF NOT EXISTS decorators STRING[] DEFAULT []`,
+		},
+	},
+	{
+		Version:     5,
+		Description: "add File.generated for generated-file exclusion",
+		Statements: []string{
+			`ALTER TABLE File ADD IF NOT EXISTS generated BOOLEAN DEFAULT false`,
+		},
+	},
+	{
+		Version:     6,
+		Description: "add File.sloc and the HAS_METHOD table, missing from version 1",
+		Statements: []string{
+			`ALTER TABLE File ADD IF NOT EXISTS sloc INT64 DEFAULT 0`,
+			`CREATE REL TABLE IF NOT EXISTS HAS_METHOD(FROM Symbol TO Symbol)`,
+		},
+	},
+}
+
+// CurrentSchemaVersion is the schema version InitSchema migrates stores to.
+var CurrentSchemaVersion = schemaMigrations[len(schemaMigrations)-1].Version
+
+// InitSchema creates all node and relationship tables if they do not exist,
+// then applies any pending schemaMigrations in order, bumping the recorded
+// version after each one. Existing data is left intact, and calling it on an
+// up-to-date store is a no-op.
+func (s *KuzuStore) InitSchema(ctx context.Context) error {
 	for _, stmt := range ddlStatements {
 		res, err := s.conn.Query(stmt)
 		if err != nil {
@@ -113,26 +211,106 @@ func (s *KuzuStore) InitSchema(_ context.Context) error {
 		}
 		res.Close()
 	}
+	res, err := s.conn.Query(schemaVersionDDL)
+	if err != nil {
+		return fmt.Errorf("kuzu: init schema: %w", err)
+	}
+	res.Close()
+
+	version, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range schemaMigrations {
+		if m.Version <= version {
+			continue
+		}
+		for _, stmt := range m.Statements {
+			if err := s.exec(ctx, stmt, nil); err != nil {
+				return fmt.Errorf("kuzu: migrate schema to v%d (%s): %w", m.Version, m.Description, err)
+			}
+		}
+		if err := s.setSchemaVersion(ctx, m.Version); err != nil {
+			return err
+		}
+		version = m.Version
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version recorded in the store, or 1 for a
+// store that predates versioning.
+func (s *KuzuStore) SchemaVersion(ctx context.Context) (int, error) {
+	rows, err := s.query(ctx, "MATCH (v:SchemaVersion {id: 0}) RETURN v.version", nil)
+	if err != nil {
+		return 0, fmt.Errorf("kuzu: read schema version: %w", err)
+	}
+	if len(rows) == 0 {
+		return 1, nil
+	}
+	return toInt(rows[0][0]), nil
+}
+
+// setSchemaVersion records version as the store's schema version.
+func (s *KuzuStore) setSchemaVersion(ctx context.Context, version int) error {
+	err := s.exec(ctx, "MERGE (v:SchemaVersion {id: 0}) SET v.version = $version",
+		map[string]any{"version": int64(version)})
+	if err != nil {
+		return fmt.Errorf("kuzu: record schema version %d: %w", version, err)
+	}
 	return nil
 }
 
 // ---------- Write operations ----------
 
 // AddFile inserts a File node.
-func (s *KuzuStore) AddFile(_ context.Context, node FileNode) error {
+func (s *KuzuStore) AddFile(ctx context.Context, node FileNode) error {
+	node.Path = NormalizePath(node.Path)
 	return s.exec(
-		"CREATE (f:File {path: $path, language: $lang, loc: $loc})",
+		ctx,
+		"CREATE (f:File {path: $path, language: $lang, loc: $loc, sloc: $sloc, content_hash: $hash, generated: $gen})",
 		map[string]any{
 			"path": node.Path,
 			"lang": string(node.Language),
 			"loc":  int64(node.LOC),
+			"sloc": int64(node.SLOC),
+			"hash": node.ContentHash,
+			"gen":  node.Generated,
 		},
 	)
 }
 
+// SetContentHash updates the content hash of the File node at path.
+func (s *KuzuStore) SetContentHash(ctx context.Context, path, hash string) error {
+	return s.exec(
+		ctx,
+		"MATCH (f:File {path: $path}) SET f.content_hash = $hash",
+		map[string]any{"path": NormalizePath(path), "hash": hash},
+	)
+}
+
 // AddSymbol inserts a Symbol node.
-func (s *KuzuStore) AddSymbol(_ context.Context, node SymbolNode) error {
+func (s *KuzuStore) AddSymbol(ctx context.Context, node SymbolNode) error {
+	node.FilePath = NormalizePath(node.FilePath)
+	params := map[string]any{
+		"id":       symbolID(node.FilePath, node.Name),
+		"name":     node.Name,
+		"kind":     string(node.Kind),
+		"exported": node.Exported,
+		"fp":       node.FilePath,
+		"sl":       int64(node.StartLine),
+		"el":       int64(node.EndLine),
+		"sid":      node.StableID,
+	}
+	// Kuzu cannot bind an empty list, so undecorated symbols keep the
+	// column's empty default.
+	decorators := ""
+	if len(node.Decorators) > 0 {
+		decorators = ", decorators: $dec"
+		params["dec"] = decoratorList(node.Decorators)
+	}
 	return s.exec(
+		ctx,
 		`CREATE (s:Symbol {
 			id: $id,
 			name: $name,
@@ -140,23 +318,17 @@ func (s *KuzuStore) AddSymbol(_ context.Context, node SymbolNode) error {
 			exported: $exported,
 			file_path: $fp,
 			start_line: $sl,
-			end_line: $el
+			end_line: $el,
+			stable_id: $sid`+decorators+`
 		})`,
-		map[string]any{
-			"id":       symbolID(node.FilePath, node.Name),
-			"name":     node.Name,
-			"kind":     string(node.Kind),
-			"exported": node.Exported,
-			"fp":       node.FilePath,
-			"sl":       int64(node.StartLine),
-			"el":       int64(node.EndLine),
-		},
+		params,
 	)
 }
 
 // AddCluster inserts a Cluster node.
-func (s *KuzuStore) AddCluster(_ context.Context, node ClusterNode) error {
+func (s *KuzuStore) AddCluster(ctx context.Context, node ClusterNode) error {
 	return s.exec(
+		ctx,
 		"CREATE (c:Cluster {name: $name, cohesion_score: $score})",
 		map[string]any{
 			"name":  node.Name,
@@ -167,17 +339,34 @@ func (s *KuzuStore) AddCluster(_ context.Context, node ClusterNode) error {
 
 // AddEdge inserts a relationship edge between two nodes.
 // The Cypher statement is chosen based on the EdgeKind.
-func (s *KuzuStore) AddEdge(_ context.Context, edge Edge) error {
+func (s *KuzuStore) AddEdge(ctx context.Context, edge Edge) error {
+	edge.SourceID, edge.TargetID = NormalizePath(edge.SourceID), NormalizePath(edge.TargetID)
 	cypher, err := edgeCypher(edge.Kind)
 	if err != nil {
 		return err
 	}
-	return s.exec(cypher, map[string]any{
+	return s.exec(ctx, cypher, map[string]any{
 		"src": edge.SourceID,
 		"dst": edge.TargetID,
 	})
 }
 
+// RemoveFile deletes the File node at path and the Symbols it defines,
+// detaching every relationship that touches them.
+func (s *KuzuStore) RemoveFile(ctx context.Context, path string) error {
+	path = NormalizePath(path)
+	params := map[string]any{"path": path}
+	if err := s.exec(ctx, "MATCH (s:Symbol {file_path: $path}) DETACH DELETE s", params); err != nil {
+		return err
+	}
+	return s.exec(ctx, "MATCH (f:File {path: $path}) DETACH DELETE f", params)
+}
+
+// ClearClusters deletes all Cluster nodes and their BELONGS_TO edges.
+func (s *KuzuStore) ClearClusters(ctx context.Context) error {
+	return s.exec(ctx, "MATCH (c:Cluster) DETACH DELETE c", nil)
+}
+
 // edgeCypher returns the MATCH-CREATE Cypher for the given edge kind.
 func edgeCypher(kind EdgeKind) (string, error) {
 	switch kind {
@@ -199,6 +388,9 @@ func edgeCypher(kind EdgeKind) (string, error) {
 	case EdgeKindBelongs:
 		return `MATCH (a:File {path: $src}), (b:Cluster {name: $dst})
 				CREATE (a)-[:BELONGS_TO]->(b)`, nil
+	case EdgeKindHasMethod:
+		return `MATCH (a:Symbol {id: $src}), (b:Symbol {id: $dst})
+				CREATE (a)-[:HAS_METHOD]->(b)`, nil
 	default:
 		return "", fmt.Errorf("kuzu: unsupported edge kind: %s", kind)
 	}
@@ -207,9 +399,11 @@ func edgeCypher(kind EdgeKind) (string, error) {
 // ---------- Read operations ----------
 
 // GetFile retrieves a single File node by path, or returns nil if not found.
-func (s *KuzuStore) GetFile(_ context.Context, path string) (*FileNode, error) {
+func (s *KuzuStore) GetFile(ctx context.Context, path string) (*FileNode, error) {
+	path = NormalizePath(path)
 	rows, err := s.query(
-		"MATCH (f:File {path: $path}) RETURN f.path, f.language, f.loc",
+		ctx,
+		"MATCH (f:File {path: $path}) RETURN f.path, f.language, f.loc, f.sloc, f.content_hash, f.generated",
 		map[string]any{"path": path},
 	)
 	if err != nil {
@@ -220,17 +414,22 @@ func (s *KuzuStore) GetFile(_ context.Context, path string) (*FileNode, error) {
 	}
 	r := rows[0]
 	return &FileNode{
-		Path:     toString(r[0]),
-		Language: Language(toString(r[1])),
-		LOC:      toInt(r[2]),
+		Path:        toString(r[0]),
+		Language:    Language(toString(r[1])),
+		LOC:         toInt(r[2]),
+		SLOC:        toInt(r[3]),
+		ContentHash: toString(r[4]),
+		Generated:   toBool(r[5]),
 	}, nil
 }
 
 // GetSymbol retrieves a single Symbol node by file path and name, or nil if not found.
-func (s *KuzuStore) GetSymbol(_ context.Context, filePath, name string) (*SymbolNode, error) {
+func (s *KuzuStore) GetSymbol(ctx context.Context, filePath, name string) (*SymbolNode, error) {
+	filePath = NormalizePath(filePath)
 	rows, err := s.query(
+		ctx,
 		`MATCH (s:Symbol {id: $id})
-		 RETURN s.name, s.kind, s.exported, s.file_path, s.start_line, s.end_line`,
+		 RETURN s.name, s.kind, s.exported, s.file_path, s.start_line, s.end_line, s.stable_id, s.decorators`,
 		map[string]any{"id": symbolID(filePath, name)},
 	)
 	if err != nil {
@@ -243,10 +442,11 @@ func (s *KuzuStore) GetSymbol(_ context.Context, filePath, name string) (*Symbol
 }
 
 // QuerySymbols returns symbols whose name contains the query string.
-func (s *KuzuStore) QuerySymbols(_ context.Context, queryStr string, limit int) ([]SymbolNode, error) {
+func (s *KuzuStore) QuerySymbols(ctx context.Context, queryStr string, limit int) ([]SymbolNode, error) {
 	rows, err := s.query(
+		ctx,
 		`MATCH (s:Symbol) WHERE s.name CONTAINS $q
-		 RETURN s.name, s.kind, s.exported, s.file_path, s.start_line, s.end_line
+		 RETURN s.name, s.kind, s.exported, s.file_path, s.start_line, s.end_line, s.stable_id, s.decorators
 		 LIMIT $lim`,
 		map[string]any{
 			"q":   queryStr,
@@ -263,18 +463,53 @@ func (s *KuzuStore) QuerySymbols(_ context.Context, queryStr string, limit int)
 	return out, nil
 }
 
+// GetMethods returns the methods linked to a type symbol by HAS_METHOD edges,
+// ordered by file and start line.
+func (s *KuzuStore) GetMethods(ctx context.Context, filePath, typeName string) ([]SymbolNode, error) {
+	filePath = NormalizePath(filePath)
+	rows, err := s.query(
+		ctx,
+		`MATCH (t:Symbol {id: $id})-[:HAS_METHOD]->(s:Symbol)
+		 RETURN s.name, s.kind, s.exported, s.file_path, s.start_line, s.end_line, s.stable_id, s.decorators
+		 ORDER BY s.file_path, s.start_line`,
+		map[string]any{"id": symbolID(filePath, typeName)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SymbolNode, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *rowToSymbol(r))
+	}
+	return out, nil
+}
+
 // ---------- Graph traversal ----------
 
-// GetDependencies performs a BFS over IMPORTS edges starting from the given
-// file path. It returns one DependencyChain per reachable file.
-func (s *KuzuStore) GetDependencies(_ context.Context, nodeID string, dir Direction, maxDepth int) ([]DependencyChain, error) {
+// GetDependencies performs a BFS over edges of the given kinds (IMPORTS by
+// default) starting from the given file path or symbol ID. It returns one
+// DependencyChain per reachable node.
+func (s *KuzuStore) GetDependencies(ctx context.Context, nodeID string, dir Direction, maxDepth int, kinds ...EdgeKind) ([]DependencyChain, error) {
+	chains, _, err := s.GetDependenciesLimited(ctx, nodeID, dir, maxDepth, 0, kinds...)
+	return chains, err
+}
+
+// GetDependenciesLimited is GetDependencies with a cap of maxNodes visited
+// nodes (no cap when maxNodes <= 0). It reports whether the cap cut the
+// traversal short.
+func (s *KuzuStore) GetDependenciesLimited(ctx context.Context, nodeID string, dir Direction, maxDepth, maxNodes int, kinds ...EdgeKind) ([]DependencyChain, bool, error) {
+	nodeID = NormalizePath(nodeID)
 	if maxDepth <= 0 {
 		maxDepth = 10
 	}
+	if len(kinds) == 0 {
+		kinds = []EdgeKind{EdgeKindImports}
+	}
 
 	// BFS state.
 	type bfsEntry struct {
 		path  []string
+		hops  []ChainHop
 		depth int
 	}
 	visited := map[string]bool{nodeID: true}
@@ -288,46 +523,85 @@ func (s *KuzuStore) GetDependencies(_ context.Context, nodeID string, dir Direct
 			continue
 		}
 		tip := cur.path[len(cur.path)-1]
-		neighbors, err := s.fileNeighbors(tip, dir)
-		if err != nil {
-			return nil, err
+		var neighbors []neighbor
+		for _, kind := range kinds {
+			nbs, err := s.neighbors(ctx, tip, dir, kind)
+			if err != nil {
+				return nil, false, err
+			}
+			neighbors = append(neighbors, nbs...)
 		}
 		for _, nb := range neighbors {
-			if visited[nb] {
+			if visited[nb.id] {
 				continue
 			}
-			visited[nb] = true
+			if maxNodes > 0 && len(chains) >= maxNodes {
+				return chains, true, nil
+			}
+			visited[nb.id] = true
 			newPath := make([]string, len(cur.path)+1)
 			copy(newPath, cur.path)
-			newPath[len(cur.path)] = nb
+			newPath[len(cur.path)] = nb.id
+			newHops := make([]ChainHop, len(cur.hops)+1)
+			copy(newHops, cur.hops)
+			newHops[len(cur.hops)] = nb.hop
 			chains = append(chains, DependencyChain{
 				Nodes: newPath,
 				Depth: cur.depth + 1,
+				Hops:  newHops,
 			})
-			queue = append(queue, bfsEntry{path: newPath, depth: cur.depth + 1})
+			queue = append(queue, bfsEntry{path: newPath, hops: newHops, depth: cur.depth + 1})
 		}
 	}
-	return chains, nil
+	return chains, false, nil
+}
+
+// relEndpoints describes the node tables and key properties joined by a
+// relationship table.
+type relEndpoints struct {
+	rel              string
+	fromNode, fromID string
+	toNode, toID     string
+}
+
+// relTables maps each EdgeKind to its relationship table.
+var relTables = map[EdgeKind]relEndpoints{
+	EdgeKindDefines:    {"DEFINES", "File", "path", "Symbol", "id"},
+	EdgeKindImports:    {"IMPORTS", "File", "path", "File", "path"},
+	EdgeKindCalls:      {"CALLS", "Symbol", "id", "Symbol", "id"},
+	EdgeKindInherits:   {"INHERITS_FROM", "Symbol", "id", "Symbol", "id"},
+	EdgeKindImplements: {"IMPLEMENTS", "Symbol", "id", "Symbol", "id"},
+	EdgeKindBelongs:    {"BELONGS_TO", "File", "path", "Cluster", "name"},
+	EdgeKindHasMethod:  {"HAS_METHOD", "Symbol", "id", "Symbol", "id"},
 }
 
-// fileNeighbors returns immediate file neighbors along IMPORTS edges.
-func (s *KuzuStore) fileNeighbors(path string, dir Direction) ([]string, error) {
+// neighbors returns the immediate neighbors of id along edges of one kind,
+// weighted by the number of parallel edges. id is matched against the key of
+// the node table on the near side, so a file path never matches a
+// Symbol-to-Symbol relationship and vice versa.
+func (s *KuzuStore) neighbors(ctx context.Context, id string, dir Direction, kind EdgeKind) ([]neighbor, error) {
+	t, ok := relTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("kuzu: unsupported edge kind: %s", kind)
+	}
 	var cypher string
 	switch dir {
 	case DirectionDownstream:
-		cypher = "MATCH (a:File {path: $path})-[:IMPORTS]->(b:File) RETURN b.path"
+		cypher = fmt.Sprintf("MATCH (a:%s {%s: $id})-[:%s]->(b:%s) RETURN b.%s, count(*)",
+			t.fromNode, t.fromID, t.rel, t.toNode, t.toID)
 	case DirectionUpstream:
-		cypher = "MATCH (a:File)-[:IMPORTS]->(b:File {path: $path}) RETURN a.path"
+		cypher = fmt.Sprintf("MATCH (a:%s)-[:%s]->(b:%s {%s: $id}) RETURN a.%s, count(*)",
+			t.fromNode, t.rel, t.toNode, t.toID, t.fromID)
 	default:
 		return nil, fmt.Errorf("kuzu: unknown direction: %s", dir)
 	}
-	rows, err := s.query(cypher, map[string]any{"path": path})
+	rows, err := s.query(ctx, cypher, map[string]any{"id": id})
 	if err != nil {
 		return nil, err
 	}
-	out := make([]string, 0, len(rows))
+	out := make([]neighbor, 0, len(rows))
 	for _, r := range rows {
-		out = append(out, toString(r[0]))
+		out = append(out, neighbor{id: toString(r[0]), hop: ChainHop{Kind: kind, Weight: toInt(r[1])}})
 	}
 	return out, nil
 }
@@ -335,8 +609,9 @@ func (s *KuzuStore) fileNeighbors(path string, dir Direction) ([]string, error)
 // AssessImpact computes the blast radius of the given set of changed files.
 // It walks IMPORTS edges downstream to find direct and transitive dependents,
 // then computes a risk score from the fan-out ratio.
-func (s *KuzuStore) AssessImpact(ctx context.Context, changedFiles []string) (*ImpactResult, error) {
-	totalFiles, err := s.countTable("File")
+func (s *KuzuStore) AssessImpact(ctx context.Context, changedFiles []string, opts ...QueryOption) (*ImpactResult, error) {
+	changedFiles = normalizePaths(changedFiles)
+	totalFiles, err := s.countTable(ctx, "File")
 	if err != nil {
 		return nil, err
 	}
@@ -372,6 +647,16 @@ func (s *KuzuStore) AssessImpact(ctx context.Context, changedFiles []string) (*I
 	direct := filterKeys(directSet, changedMap)
 	transitive := filterKeys(transitiveSet, changedMap)
 
+	if applyQueryOptions(opts).excludeGenerated {
+		generated, err := s.generatedFiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		direct = withoutGenerated(direct, generated)
+		transitive = withoutGenerated(transitive, generated)
+		totalFiles -= len(generated)
+	}
+
 	risk := 0.0
 	if totalFiles > 0 {
 		risk = math.Min(1.0, float64(len(transitive))/float64(totalFiles))
@@ -384,9 +669,23 @@ func (s *KuzuStore) AssessImpact(ctx context.Context, changedFiles []string) (*I
 	}, nil
 }
 
+// generatedFiles returns the set of paths of File nodes marked generated.
+func (s *KuzuStore) generatedFiles(ctx context.Context) (map[string]bool, error) {
+	rows, err := s.query(ctx, "MATCH (f:File) WHERE f.generated RETURN f.path", nil)
+	if err != nil {
+		return nil, err
+	}
+	generated := make(map[string]bool, len(rows))
+	for _, r := range rows {
+		generated[toString(r[0])] = true
+	}
+	return generated, nil
+}
+
 // GetClusters returns all Cluster nodes.
-func (s *KuzuStore) GetClusters(_ context.Context) ([]ClusterNode, error) {
+func (s *KuzuStore) GetClusters(ctx context.Context, opts ...QueryOption) ([]ClusterNode, error) {
 	rows, err := s.query(
+		ctx,
 		"MATCH (c:Cluster) RETURN c.name, c.cohesion_score",
 		nil,
 	)
@@ -400,6 +699,7 @@ func (s *KuzuStore) GetClusters(_ context.Context) ([]ClusterNode, error) {
 
 		// Fetch cluster members via BELONGS_TO edges.
 		memberRows, err := s.query(
+			ctx,
 			"MATCH (f:File)-[:BELONGS_TO]->(c:Cluster {name: $name}) RETURN f.path",
 			map[string]any{"name": name},
 		)
@@ -417,13 +717,63 @@ func (s *KuzuStore) GetClusters(_ context.Context) ([]ClusterNode, error) {
 			Members:       members,
 		})
 	}
+	if applyQueryOptions(opts).excludeGenerated {
+		generated, err := s.generatedFiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return clustersWithoutGenerated(out, generated), nil
+	}
 	return out, nil
 }
 
-// ---------- Edge enumeration ----------
+// ---------- Enumeration ----------
+
+// GetAllFiles returns all File nodes ordered by path.
+func (s *KuzuStore) GetAllFiles(ctx context.Context) ([]FileNode, error) {
+	rows, err := s.query(
+		ctx,
+		"MATCH (f:File) RETURN f.path, f.language, f.loc, f.sloc, f.content_hash, f.generated ORDER BY f.path",
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FileNode, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, FileNode{
+			Path:        toString(r[0]),
+			Language:    Language(toString(r[1])),
+			LOC:         toInt(r[2]),
+			SLOC:        toInt(r[3]),
+			ContentHash: toString(r[4]),
+			Generated:   toBool(r[5]),
+		})
+	}
+	return out, nil
+}
+
+// GetAllSymbols returns all Symbol nodes ordered by file path and name.
+func (s *KuzuStore) GetAllSymbols(ctx context.Context) ([]SymbolNode, error) {
+	rows, err := s.query(
+		ctx,
+		`MATCH (s:Symbol)
+		 RETURN s.name, s.kind, s.exported, s.file_path, s.start_line, s.end_line, s.stable_id, s.decorators
+		 ORDER BY s.file_path, s.name`,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SymbolNode, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *rowToSymbol(r))
+	}
+	return out, nil
+}
 
 // GetAllEdges returns all edges across all relationship tables.
-func (s *KuzuStore) GetAllEdges(_ context.Context) ([]Edge, error) {
+func (s *KuzuStore) GetAllEdges(ctx context.Context) ([]Edge, error) {
 	type relQuery struct {
 		cypher string
 		kind   EdgeKind
@@ -436,14 +786,14 @@ func (s *KuzuStore) GetAllEdges(_ context.Context) ([]Edge, error) {
 		{"MATCH (a:Symbol)-[:INHERITS_FROM]->(b:Symbol) RETURN a.id, b.id", EdgeKindInherits},
 		{"MATCH (a:Symbol)-[:IMPLEMENTS]->(b:Symbol) RETURN a.id, b.id", EdgeKindImplements},
 		{"MATCH (a:File)-[:BELONGS_TO]->(b:Cluster) RETURN a.path, b.name", EdgeKindBelongs},
+		{"MATCH (a:Symbol)-[:HAS_METHOD]->(b:Symbol) RETURN a.id, b.id", EdgeKindHasMethod},
 	}
 
 	var edges []Edge
 	for _, q := range queries {
-		rows, err := s.query(q.cypher, nil)
+		rows, err := s.query(ctx, q.cypher, nil)
 		if err != nil {
-			// Table may not exist yet; skip.
-			continue
+			return nil, fmt.Errorf("kuzu: get %s edges: %w", q.kind, err)
 		}
 		for _, r := range rows {
 			edges = append(edges, Edge{
@@ -459,52 +809,296 @@ func (s *KuzuStore) GetAllEdges(_ context.Context) ([]Edge, error) {
 // ---------- Stats ----------
 
 // Stats returns counts of all node and edge tables.
-func (s *KuzuStore) Stats(_ context.Context) (*GraphStats, error) {
-	files, err := s.countTable("File")
+func (s *KuzuStore) Stats(ctx context.Context) (*GraphStats, error) {
+	files, err := s.countTable(ctx, "File")
 	if err != nil {
 		return nil, err
 	}
-	symbols, err := s.countTable("Symbol")
+	symbols, err := s.countTable(ctx, "Symbol")
 	if err != nil {
 		return nil, err
 	}
-	clusters, err := s.countTable("Cluster")
+	clusters, err := s.countTable(ctx, "Cluster")
 	if err != nil {
 		return nil, err
 	}
-	edges, err := s.countEdges()
+	edges, edgesByKind, err := s.countEdges(ctx)
 	if err != nil {
 		return nil, err
 	}
+	slocRows, err := s.query(ctx, "MATCH (f:File) RETURN f.sloc", nil)
+	if err != nil {
+		return nil, err
+	}
+	sloc := 0
+	for _, r := range slocRows {
+		sloc += toInt(r[0])
+	}
 	return &GraphStats{
 		FileCount:    files,
 		SymbolCount:  symbols,
 		ClusterCount: clusters,
 		EdgeCount:    edges,
+		SLOC:         sloc,
+		EdgesByKind:  edgesByKind,
 	}, nil
 }
 
-// ---------- Internal helpers ----------
+// ---------- Maintenance ----------
 
-// exec runs a parameterized Cypher statement that produces no result rows.
-func (s *KuzuStore) exec(cypher string, params map[string]any) error {
-	stmt, err := s.conn.Prepare(cypher)
+// Compact reclaims the space left behind by deleted nodes and edges. KuzuDB
+// does not shrink its database file in place, so the live data is copied into
+// a fresh database beside the original, which then replaces it. The store is
+// reopened on the compacted database and stays usable. Only file-based stores
+// can be compacted.
+func (s *KuzuStore) Compact(ctx context.Context) error {
+	if s.path == "" {
+		return fmt.Errorf("kuzu: compact: store is not file-based")
+	}
+	// Bring older stores up to date so every column can be copied.
+	if err := s.InitSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.exec(ctx, "CHECKPOINT", nil); err != nil {
+		return fmt.Errorf("kuzu: compact: checkpoint: %w", err)
+	}
+
+	tmpPath := s.path + ".compact"
+	oldPath := s.path + ".old"
+	for _, p := range []string{tmpPath, oldPath} {
+		if err := removeKuzuFiles(p); err != nil {
+			return fmt.Errorf("kuzu: compact: remove stale %s: %w", p, err)
+		}
+	}
+	dst, err := NewKuzuFileStore(tmpPath)
+	if err != nil {
+		return fmt.Errorf("kuzu: compact: %w", err)
+	}
+	err = s.copyTo(ctx, dst)
+	if err == nil {
+		err = s.verifyCopy(ctx, dst)
+	}
+	dst.Close()
 	if err != nil {
-		return fmt.Errorf("kuzu: prepare: %w", err)
+		return discardKuzuFiles(fmt.Errorf("kuzu: compact: %w", err), tmpPath)
+	}
+
+	// Swap the compacted database in. The original is moved aside rather
+	// than deleted first so a failed rename leaves it recoverable.
+	s.conn.Close()
+	s.db.Close()
+	if err := os.Rename(s.path, oldPath); err != nil {
+		cause := discardKuzuFiles(fmt.Errorf("kuzu: compact: move original aside: %w", err), tmpPath)
+		return s.reopen(cause)
+	}
+	if err := os.Remove(s.path + ".wal"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return s.reopen(s.restoreOriginal(fmt.Errorf("kuzu: compact: remove write-ahead log: %w", err), oldPath, tmpPath))
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return s.reopen(s.restoreOriginal(fmt.Errorf("kuzu: compact: replace database: %w", err), oldPath, tmpPath))
 	}
-	defer stmt.Close()
+	if err := removeKuzuFiles(oldPath); err != nil {
+		return s.reopen(fmt.Errorf("kuzu: compact: remove original at %s: %w", oldPath, err))
+	}
+	return s.reopen(nil)
+}
 
-	res, err := s.conn.Execute(stmt, params)
+// verifyCopy compares the node and edge counts of dst with those of s, so
+// that Compact never replaces the original with a copy that lost data.
+func (s *KuzuStore) verifyCopy(ctx context.Context, dst *KuzuStore) error {
+	want, err := s.Stats(ctx)
 	if err != nil {
-		return fmt.Errorf("kuzu: execute: %w", err)
+		return err
+	}
+	got, err := dst.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	return compareCopyStats(want, got)
+}
+
+// compareCopyStats reports the first count in which the copy got differs
+// from the original want.
+func compareCopyStats(want, got *GraphStats) error {
+	type count struct {
+		what      string
+		want, got int
+	}
+	counts := []count{
+		{"files", want.FileCount, got.FileCount},
+		{"symbols", want.SymbolCount, got.SymbolCount},
+		{"clusters", want.ClusterCount, got.ClusterCount},
+		{"edges", want.EdgeCount, got.EdgeCount},
+	}
+	kinds := make([]string, 0, len(relTables))
+	for kind := range relTables {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		k := EdgeKind(kind)
+		counts = append(counts, count{kind + " edges", want.EdgesByKind[k], got.EdgesByKind[k]})
+	}
+	for _, c := range counts {
+		if c.want != c.got {
+			return fmt.Errorf("copy has %d %s, original has %d", c.got, c.what, c.want)
+		}
 	}
-	res.Close()
 	return nil
 }
 
+// restoreOriginal moves the original database back from oldPath after a
+// failed swap and discards the copy at tmpPath, appending any error from
+// either step to cause.
+func (s *KuzuStore) restoreOriginal(cause error, oldPath, tmpPath string) error {
+	if err := os.Rename(oldPath, s.path); err != nil {
+		return fmt.Errorf("%w (restore original: %v; it remains at %s)", cause, err, oldPath)
+	}
+	return discardKuzuFiles(cause, tmpPath)
+}
+
+// reopen reconnects a file-based store after Compact has closed it and
+// returns cause, or the open error if reconnecting fails.
+func (s *KuzuStore) reopen(cause error) error {
+	db, conn, err := openKuzuFile(s.path)
+	if err != nil {
+		s.db, s.conn = nil, nil
+		if cause != nil {
+			return fmt.Errorf("%w (reopen: %v)", cause, err)
+		}
+		return err
+	}
+	s.db, s.conn = db, conn
+	return cause
+}
+
+// copyTo copies every node and edge into dst, initializing its schema first.
+func (s *KuzuStore) copyTo(ctx context.Context, dst *KuzuStore) error {
+	if err := dst.InitSchema(ctx); err != nil {
+		return err
+	}
+
+	files, err := s.GetAllFiles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := dst.AddFile(ctx, f); err != nil {
+			return fmt.Errorf("copy file %s: %w", f.Path, err)
+		}
+	}
+
+	symbols, err := s.GetAllSymbols(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sym := range symbols {
+		if err := dst.AddSymbol(ctx, sym); err != nil {
+			return fmt.Errorf("copy symbol %s: %w", symbolID(sym.FilePath, sym.Name), err)
+		}
+	}
+
+	// Cluster membership is carried by the BELONGS_TO edges copied below.
+	clusters, err := s.GetClusters(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range clusters {
+		if err := dst.AddCluster(ctx, c); err != nil {
+			return fmt.Errorf("copy cluster %s: %w", c.Name, err)
+		}
+	}
+
+	edges, err := s.GetAllEdges(ctx)
+	if err != nil {
+		return err
+	}
+	for _, e := range edges {
+		if err := dst.AddEdge(ctx, e); err != nil {
+			return fmt.Errorf("copy %s edge %s -> %s: %w", e.Kind, e.SourceID, e.TargetID, err)
+		}
+	}
+	return nil
+}
+
+// removeKuzuFiles deletes a file-based database at path and its write-ahead
+// log, ignoring files that do not exist.
+func removeKuzuFiles(path string) error {
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	if err := os.Remove(path + ".wal"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// discardKuzuFiles removes the database at path after a failed step and
+// returns cause, with any removal error appended.
+func discardKuzuFiles(cause error, path string) error {
+	if err := removeKuzuFiles(path); err != nil {
+		return fmt.Errorf("%w (remove %s: %v)", cause, path, err)
+	}
+	return cause
+}
+
+// ---------- Internal helpers ----------
+
+// interruptible runs fn, a call into the connection, so that it honors ctx.
+// If ctx is done before fn returns, the running query is interrupted and
+// ctx's error is returned once fn has unwound, leaving the connection free
+// for the next statement.
+func (s *KuzuStore) interruptible(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("kuzu: %w", err)
+	}
+	if ctx.Done() == nil {
+		return fn()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		s.conn.Interrupt()
+		<-done
+		return fmt.Errorf("kuzu: %w", ctx.Err())
+	}
+}
+
+// exec runs a parameterized Cypher statement that produces no result rows.
+func (s *KuzuStore) exec(ctx context.Context, cypher string, params map[string]any) error {
+	return s.interruptible(ctx, func() error {
+		stmt, err := s.conn.Prepare(cypher)
+		if err != nil {
+			return fmt.Errorf("kuzu: prepare: %w", err)
+		}
+		defer stmt.Close()
+
+		res, err := s.conn.Execute(stmt, params)
+		if err != nil {
+			return fmt.Errorf("kuzu: execute: %w", err)
+		}
+		res.Close()
+		return nil
+	})
+}
+
 // query runs a parameterized Cypher statement and collects all result rows.
 // Each row is a []any slice with values in column order.
-func (s *KuzuStore) query(cypher string, params map[string]any) ([][]any, error) {
+func (s *KuzuStore) query(ctx context.Context, cypher string, params map[string]any) ([][]any, error) {
+	var rows [][]any
+	err := s.interruptible(ctx, func() (err error) {
+		rows, err = s.queryRows(cypher, params)
+		return err
+	})
+	return rows, err
+}
+
+// queryRows is query without cancellation.
+func (s *KuzuStore) queryRows(cypher string, params map[string]any) ([][]any, error) {
 	var res *kuzu.QueryResult
 	var err error
 
@@ -539,11 +1133,20 @@ func (s *KuzuStore) query(cypher string, params map[string]any) ([][]any, error)
 	return rows, nil
 }
 
+// RunReadQuery runs a read-only Cypher query and returns its rows. Queries
+// that fail CheckReadOnlyCypher are rejected before reaching KuzuDB.
+func (s *KuzuStore) RunReadQuery(ctx context.Context, cypher string, params map[string]any) ([][]any, error) {
+	if err := CheckReadOnlyCypher(cypher); err != nil {
+		return nil, err
+	}
+	return s.query(ctx, cypher, params)
+}
+
 // countTable returns the number of rows in a node table.
-func (s *KuzuStore) countTable(table string) (int, error) {
+func (s *KuzuStore) countTable(ctx context.Context, table string) (int, error) {
 	// Table name is a fixed internal constant, not user input.
 	cypher := fmt.Sprintf("MATCH (n:%s) RETURN count(n)", table)
-	rows, err := s.query(cypher, nil)
+	rows, err := s.query(ctx, cypher, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -553,22 +1156,29 @@ func (s *KuzuStore) countTable(table string) (int, error) {
 	return toInt(rows[0][0]), nil
 }
 
-// countEdges returns the total number of edges across all relationship tables.
-func (s *KuzuStore) countEdges() (int, error) {
-	tables := []string{"DEFINES", "IMPORTS", "CALLS", "INHERITS_FROM", "IMPLEMENTS", "BELONGS_TO"}
+// countEdges returns the total number of edges across all relationship
+// tables, and the number per edge kind for kinds that have any.
+func (s *KuzuStore) countEdges(ctx context.Context) (int, map[EdgeKind]int, error) {
 	total := 0
-	for _, t := range tables {
-		cypher := fmt.Sprintf("MATCH ()-[r:%s]->() RETURN count(r)", t)
-		rows, err := s.query(cypher, nil)
+	byKind := make(map[EdgeKind]int)
+	for kind, rel := range relTables {
+		cypher := fmt.Sprintf("MATCH ()-[r:%s]->() RETURN count(r)", rel.rel)
+		rows, err := s.query(ctx, cypher, nil)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, nil, fmt.Errorf("kuzu: %w", ctxErr)
+		}
 		if err != nil {
 			// Table may not exist yet; treat as zero.
 			continue
 		}
 		if len(rows) > 0 && len(rows[0]) > 0 {
-			total += toInt(rows[0][0])
+			if n := toInt(rows[0][0]); n > 0 {
+				byKind[kind] = n
+				total += n
+			}
 		}
 	}
-	return total, nil
+	return total, byKind, nil
 }
 
 // symbolID produces a deterministic identifier for a symbol: "filePath:name".
@@ -576,19 +1186,31 @@ func symbolID(filePath, name string) string {
 	return filePath + ":" + name
 }
 
-// rowToSymbol converts a 6-column result row into a SymbolNode.
-// Column order: name, kind, exported, file_path, start_line, end_line.
+// rowToSymbol converts an 8-column result row into a SymbolNode.
+// Column order: name, kind, exported, file_path, start_line, end_line,
+// stable_id, decorators.
 func rowToSymbol(r []any) *SymbolNode {
 	return &SymbolNode{
-		Name:      toString(r[0]),
-		Kind:      SymbolKind(toString(r[1])),
-		Exported:  toBool(r[2]),
-		FilePath:  toString(r[3]),
-		StartLine: toInt(r[4]),
-		EndLine:   toInt(r[5]),
+		Name:       toString(r[0]),
+		Kind:       SymbolKind(toString(r[1])),
+		Exported:   toBool(r[2]),
+		FilePath:   toString(r[3]),
+		StartLine:  toInt(r[4]),
+		EndLine:    toInt(r[5]),
+		StableID:   toString(r[6]),
+		Decorators: toStrings(r[7]),
 	}
 }
 
+// decoratorList returns decorators as a Kuzu STRING[] parameter value.
+func decoratorList(decorators []string) []any {
+	out := make([]any, len(decorators))
+	for i, d := range decorators {
+		out[i] = d
+	}
+	return out
+}
+
 // filterKeys returns keys from set that are not in exclude, as a sorted slice.
 func filterKeys(set, exclude map[string]bool) []string {
 	out := make([]string, 0, len(set))
@@ -645,3 +1267,17 @@ func toBool(v any) bool {
 	}
 	return false
 }
+
+// toStrings converts a Kuzu list value to a []string, returning nil for an
+// empty or missing list.
+func toStrings(v any) []string {
+	list, ok := v.([]any)
+	if !ok || len(list) == 0 {
+		return nil
+	}
+	out := make([]string, len(list))
+	for i, item := range list {
+		out[i] = toString(item)
+	}
+	return out
+}
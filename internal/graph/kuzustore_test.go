@@ -4,8 +4,12 @@ package graph
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,9 +60,10 @@ func TestKuzuStore_FileRoundTrip(t *testing.T) {
 	ctx := context.Background()
 
 	file := FileNode{
-		Path:     "internal/graph/kuzustore.go",
-		Language: LangGo,
-		LOC:      420,
+		Path:        "internal/graph/kuzustore.go",
+		Language:    LangGo,
+		LOC:         420,
+		ContentHash: ContentHash([]byte("package graph\n")),
 	}
 
 	require.NoError(t, s.AddFile(ctx, file))
@@ -70,6 +75,36 @@ func TestKuzuStore_FileRoundTrip(t *testing.T) {
 	assert.Equal(t, file.Path, got.Path)
 	assert.Equal(t, file.Language, got.Language)
 	assert.Equal(t, file.LOC, got.LOC)
+	assert.Equal(t, file.ContentHash, got.ContentHash)
+}
+
+func TestKuzuStore_RemoveFile(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, f := range []FileNode{{Path: "a.go", Language: LangGo}, {Path: "b.go", Language: LangGo}} {
+		require.NoError(t, s.AddFile(ctx, f))
+	}
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{Name: "A", Kind: SymbolKindFunction, FilePath: "a.go"}))
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{Name: "B", Kind: SymbolKindFunction, FilePath: "b.go"}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "a.go", TargetID: "a.go:A", Kind: EdgeKindDefines}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "b.go", TargetID: "a.go", Kind: EdgeKindImports}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "b.go:B", TargetID: "a.go:A", Kind: EdgeKindCalls}))
+
+	require.NoError(t, s.RemoveFile(ctx, "a.go"))
+
+	got, err := s.GetFile(ctx, "a.go")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	sym, err := s.GetSymbol(ctx, "a.go", "A")
+	require.NoError(t, err)
+	assert.Nil(t, sym)
+
+	stats, err := s.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.FileCount)
+	assert.Equal(t, 1, stats.SymbolCount)
+	assert.Equal(t, 0, stats.EdgeCount)
 }
 
 func TestKuzuStore_GetFile_NotFound(t *testing.T) {
@@ -92,6 +127,7 @@ func TestKuzuStore_SymbolRoundTrip(t *testing.T) {
 		FilePath:  "internal/graph/kuzustore.go",
 		StartLine: 24,
 		EndLine:   36,
+		StableID:  "0123456789abcdef",
 	}
 
 	require.NoError(t, s.AddSymbol(ctx, sym))
@@ -104,6 +140,7 @@ func TestKuzuStore_SymbolRoundTrip(t *testing.T) {
 	assert.Equal(t, sym.Kind, got.Kind)
 	assert.Equal(t, sym.Exported, got.Exported)
 	assert.Equal(t, sym.FilePath, got.FilePath)
+	assert.Equal(t, sym.StableID, got.StableID)
 	assert.Equal(t, sym.StartLine, got.StartLine)
 	assert.Equal(t, sym.EndLine, got.EndLine)
 }
@@ -261,6 +298,47 @@ func TestKuzuStore_Dependencies_Downstream(t *testing.T) {
 	})
 }
 
+func TestKuzuStore_Dependencies_Calls(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	// Files a -> b import each other while main -> run -> helper call
+	// across them; a CALLS traversal must follow only the symbol chain.
+	for _, f := range []FileNode{
+		{Path: "a.go", Language: LangGo, LOC: 10},
+		{Path: "b.go", Language: LangGo, LOC: 10},
+	} {
+		require.NoError(t, s.AddFile(ctx, f))
+	}
+	for _, sym := range []SymbolNode{
+		{Name: "main", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 1, EndLine: 5},
+		{Name: "run", Kind: SymbolKindFunction, FilePath: "b.go", StartLine: 1, EndLine: 5},
+		{Name: "helper", Kind: SymbolKindFunction, FilePath: "b.go", StartLine: 7, EndLine: 9},
+	} {
+		require.NoError(t, s.AddSymbol(ctx, sym))
+	}
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "a.go", TargetID: "b.go", Kind: EdgeKindImports}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "a.go:main", TargetID: "b.go:run", Kind: EdgeKindCalls}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "b.go:run", TargetID: "b.go:helper", Kind: EdgeKindCalls}))
+
+	chains, err := s.GetDependencies(ctx, "a.go:main", DirectionDownstream, 10, EdgeKindCalls)
+	require.NoError(t, err)
+	require.Len(t, chains, 2)
+	assert.Equal(t, []string{"a.go:main", "b.go:run", "b.go:helper"}, chains[1].Nodes)
+	assert.Equal(t, []ChainHop{{Kind: EdgeKindCalls, Weight: 1}, {Kind: EdgeKindCalls, Weight: 1}}, chains[1].Hops)
+
+	// Upstream CALLS from helper walks back to main.
+	chains, err = s.GetDependencies(ctx, "b.go:helper", DirectionUpstream, 10, EdgeKindCalls)
+	require.NoError(t, err)
+	require.Len(t, chains, 2)
+
+	// The default still follows IMPORTS only.
+	chains, err = s.GetDependencies(ctx, "a.go", DirectionDownstream, 10)
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+	assert.Equal(t, []string{"a.go", "b.go"}, chains[0].Nodes)
+}
+
 func TestKuzuStore_Dependencies_Upstream(t *testing.T) {
 	s := newTestStore(t)
 	ctx := context.Background()
@@ -326,6 +404,35 @@ func TestKuzuStore_AssessImpact(t *testing.T) {
 	assert.InDelta(t, 0.75, result.RiskScore, 0.01)
 }
 
+func TestKuzuStore_ExcludeGenerated(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "core.go", Language: LangGo}))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "api.pb.go", Language: LangGo, Generated: true}))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "handler.go", Language: LangGo}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "core.go", TargetID: "api.pb.go", Kind: EdgeKindImports}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "api.pb.go", TargetID: "handler.go", Kind: EdgeKindImports}))
+	require.NoError(t, s.AddCluster(ctx, ClusterNode{Name: "api"}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "api.pb.go", TargetID: "api", Kind: EdgeKindBelongs}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "handler.go", TargetID: "api", Kind: EdgeKindBelongs}))
+
+	f, err := s.GetFile(ctx, "api.pb.go")
+	require.NoError(t, err)
+	assert.True(t, f.Generated)
+
+	result, err := s.AssessImpact(ctx, []string{"core.go"}, ExcludeGenerated())
+	require.NoError(t, err)
+	assert.Empty(t, result.DirectlyAffected)
+	assert.Equal(t, []string{"handler.go"}, result.TransitivelyAffected)
+	assert.InDelta(t, 0.5, result.RiskScore, 0.01)
+
+	clusters, err := s.GetClusters(ctx, ExcludeGenerated())
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, []string{"handler.go"}, clusters[0].Members)
+}
+
 func TestKuzuStore_AssessImpact_NoImpact(t *testing.T) {
 	s := newTestStore(t)
 	ctx := context.Background()
@@ -409,6 +516,53 @@ func TestKuzuStore_Stats(t *testing.T) {
 	assert.Equal(t, 3, stats.EdgeCount)
 }
 
+func TestStats_EdgesByKind(t *testing.T) {
+	stores := map[string]Store{
+		"kuzu":   newTestStore(t),
+		"memory": NewMemStore(),
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, f := range []string{"a.go", "b.go", "c.go"} {
+				require.NoError(t, s.AddFile(ctx, FileNode{Path: f, Language: LangGo}))
+			}
+			for _, sym := range []SymbolNode{
+				{Name: "Run", Kind: SymbolKindFunction, FilePath: "a.go"},
+				{Name: "Store", Kind: SymbolKindInterface, FilePath: "b.go"},
+				{Name: "Mem", Kind: SymbolKindType, FilePath: "c.go"},
+			} {
+				require.NoError(t, s.AddSymbol(ctx, sym))
+			}
+			for _, e := range []Edge{
+				{SourceID: "a.go", TargetID: "b.go", Kind: EdgeKindImports},
+				{SourceID: "c.go", TargetID: "b.go", Kind: EdgeKindImports},
+				{SourceID: "a.go", TargetID: "a.go:Run", Kind: EdgeKindDefines},
+				{SourceID: "b.go", TargetID: "b.go:Store", Kind: EdgeKindDefines},
+				{SourceID: "a.go:Run", TargetID: "c.go:Mem", Kind: EdgeKindCalls},
+				{SourceID: "c.go:Mem", TargetID: "b.go:Store", Kind: EdgeKindImplements},
+			} {
+				require.NoError(t, s.AddEdge(ctx, e))
+			}
+
+			stats, err := s.Stats(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[EdgeKind]int{
+				EdgeKindImports:    2,
+				EdgeKindDefines:    2,
+				EdgeKindCalls:      1,
+				EdgeKindImplements: 1,
+			}, stats.EdgesByKind)
+
+			sum := 0
+			for _, n := range stats.EdgesByKind {
+				sum += n
+			}
+			assert.Equal(t, stats.EdgeCount, sum)
+		})
+	}
+}
+
 func TestKuzuStore_Close(t *testing.T) {
 	s, err := NewKuzuStore()
 	require.NoError(t, err)
@@ -517,6 +671,47 @@ func TestKuzuStore_Dependencies_DiamondGraph(t *testing.T) {
 	}
 }
 
+func TestGetDependenciesLimited_FanOut(t *testing.T) {
+	stores := map[string]Store{
+		"kuzu":   newTestStore(t),
+		"memory": NewMemStore(),
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// root imports ten files, each of which imports a leaf of its own.
+			require.NoError(t, s.AddFile(ctx, FileNode{Path: "root.go", Language: LangGo}))
+			for i := range 10 {
+				mid := fmt.Sprintf("mid%d.go", i)
+				leaf := fmt.Sprintf("leaf%d.go", i)
+				require.NoError(t, s.AddFile(ctx, FileNode{Path: mid, Language: LangGo}))
+				require.NoError(t, s.AddFile(ctx, FileNode{Path: leaf, Language: LangGo}))
+				require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "root.go", TargetID: mid, Kind: EdgeKindImports}))
+				require.NoError(t, s.AddEdge(ctx, Edge{SourceID: mid, TargetID: leaf, Kind: EdgeKindImports}))
+			}
+
+			chains, truncated, err := s.GetDependenciesLimited(ctx, "root.go", DirectionDownstream, 10, 5)
+			require.NoError(t, err)
+			assert.True(t, truncated)
+			require.Len(t, chains, 5)
+			for _, c := range chains {
+				assert.Equal(t, 1, c.Depth, "BFS visits the first hop before going deeper")
+			}
+
+			chains, truncated, err = s.GetDependenciesLimited(ctx, "root.go", DirectionDownstream, 10, 20)
+			require.NoError(t, err)
+			assert.False(t, truncated, "a cap equal to the reachable node count does not truncate")
+			assert.Len(t, chains, 20)
+
+			chains, truncated, err = s.GetDependenciesLimited(ctx, "root.go", DirectionDownstream, 10, 0)
+			require.NoError(t, err)
+			assert.False(t, truncated)
+			assert.Len(t, chains, 20)
+		})
+	}
+}
+
 func TestKuzuStore_AssessImpact_DiamondGraph(t *testing.T) {
 	s := newTestStore(t)
 	ctx := context.Background()
@@ -631,3 +826,345 @@ func TestKuzuStore_EdgeKindImplements(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, stats.EdgeCount)
 }
+
+func TestKuzuStore_SymbolDecorators(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{
+		Name: "currency", Kind: SymbolKindMethod, Exported: true, FilePath: "account.py",
+		StartLine: 5, EndLine: 8, Decorators: []string{"staticmethod", "cached"},
+	}))
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{
+		Name: "refresh", Kind: SymbolKindMethod, Exported: true, FilePath: "account.py",
+		StartLine: 10, EndLine: 11,
+	}))
+
+	sym, err := s.GetSymbol(ctx, "account.py", "currency")
+	require.NoError(t, err)
+	require.NotNil(t, sym)
+	assert.Equal(t, []string{"staticmethod", "cached"}, sym.Decorators)
+
+	sym, err = s.GetSymbol(ctx, "account.py", "refresh")
+	require.NoError(t, err)
+	require.NotNil(t, sym)
+	assert.Empty(t, sym.Decorators)
+}
+
+func TestKuzuStore_GetMethods(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, sym := range []SymbolNode{
+		{Name: "UserService", Kind: SymbolKindType, Exported: true, FilePath: "service.go", StartLine: 6, EndLine: 8},
+		{Name: "CreateUser", Kind: SymbolKindMethod, Exported: true, FilePath: "service.go", StartLine: 25, EndLine: 31},
+		{Name: "GetUser", Kind: SymbolKindMethod, Exported: true, FilePath: "service.go", StartLine: 16, EndLine: 22},
+	} {
+		require.NoError(t, s.AddSymbol(ctx, sym))
+	}
+	for _, method := range []string{"CreateUser", "GetUser"} {
+		require.NoError(t, s.AddEdge(ctx, Edge{
+			SourceID: "service.go:UserService",
+			TargetID: "service.go:" + method,
+			Kind:     EdgeKindHasMethod,
+		}))
+	}
+
+	methods, err := s.GetMethods(ctx, "service.go", "UserService")
+	require.NoError(t, err)
+	require.Len(t, methods, 2)
+	assert.Equal(t, "GetUser", methods[0].Name)
+	assert.Equal(t, "CreateUser", methods[1].Name)
+
+	edges, err := s.GetAllEdges(ctx)
+	require.NoError(t, err)
+	assert.Len(t, findEdgesByKind(edges, EdgeKindHasMethod), 2)
+
+	stats, err := s.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.EdgeCount)
+}
+
+func TestKuzuStore_RunReadQuery(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "a.go", Language: LangGo, LOC: 3}))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "b.go", Language: LangGo, LOC: 5}))
+
+	rows, err := s.RunReadQuery(ctx,
+		"MATCH (f:File) WHERE f.loc > $min RETURN f.path, f.loc", map[string]any{"min": int64(4)})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "b.go", rows[0][0])
+	assert.EqualValues(t, 5, rows[0][1])
+
+	_, err = s.RunReadQuery(ctx, "MATCH (f:File {path: 'a.go'}) DETACH DELETE f", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DETACH")
+
+	got, err := s.GetFile(ctx, "a.go")
+	require.NoError(t, err)
+	assert.NotNil(t, got, "rejected query must not modify the graph")
+}
+
+// version1DDL is the schema of stores written before schema versioning,
+// kept verbatim so the migration test starts from a real old store.
+var version1DDL = []string{
+	`CREATE NODE TABLE IF NOT EXISTS File(
+		path STRING,
+		language STRING,
+		loc INT64,
+		PRIMARY KEY(path)
+	)`,
+	`CREATE NODE TABLE IF NOT EXISTS Symbol(
+		id STRING,
+		name STRING,
+		kind STRING,
+		exported BOOLEAN,
+		file_path STRING,
+		start_line INT64,
+		end_line INT64,
+		PRIMARY KEY(id)
+	)`,
+	`CREATE NODE TABLE IF NOT EXISTS Cluster(
+		name STRING,
+		cohesion_score DOUBLE,
+		PRIMARY KEY(name)
+	)`,
+	`CREATE REL TABLE IF NOT EXISTS DEFINES(FROM File TO Symbol)`,
+	`CREATE REL TABLE IF NOT EXISTS IMPORTS(FROM File TO File)`,
+	`CREATE REL TABLE IF NOT EXISTS CALLS(FROM Symbol TO Symbol)`,
+	`CREATE REL TABLE IF NOT EXISTS INHERITS_FROM(FROM Symbol TO Symbol)`,
+	`CREATE REL TABLE IF NOT EXISTS IMPLEMENTS(FROM Symbol TO Symbol)`,
+	`CREATE REL TABLE IF NOT EXISTS BELONGS_TO(FROM File TO Cluster)`,
+}
+
+func TestKuzuStore_InitSchema_MigratesVersion1Store(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "graph")
+
+	// Build a pre-versioning store: the v1 tables only, with none of the
+	// migrated columns and no SchemaVersion row.
+	old, err := NewKuzuFileStore(dbPath)
+	require.NoError(t, err)
+	for _, stmt := range version1DDL {
+		require.NoError(t, old.exec(ctx, stmt, nil))
+	}
+	require.NoError(t, old.exec(
+		ctx,
+		"CREATE (f:File {path: 'main.go', language: 'go', loc: 12})", nil))
+	require.NoError(t, old.exec(
+		ctx,
+		"CREATE (s:Symbol {id: 'main.go:main', name: 'main', kind: 'function', exported: false, file_path: 'main.go', start_line: 1, end_line: 5})", nil))
+	require.NoError(t, old.Close())
+
+	s, err := NewKuzuFileStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	require.NoError(t, s.InitSchema(ctx))
+	version, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+
+	// Re-running is a no-op.
+	require.NoError(t, s.InitSchema(ctx))
+	version, err = s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+
+	// Existing rows survive, and the migrated column is usable.
+	f, err := s.GetFile(ctx, "main.go")
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	assert.Equal(t, 12, f.LOC)
+	assert.Equal(t, "", f.ContentHash)
+	sym, err := s.GetSymbol(ctx, "main.go", "main")
+	require.NoError(t, err)
+	require.NotNil(t, sym)
+	assert.Equal(t, "", sym.StableID)
+
+	assert.Equal(t, 0, f.SLOC)
+	assert.False(t, f.Generated)
+
+	require.NoError(t, s.AddFile(ctx, FileNode{
+		Path: "util_gen.go", Language: LangGo, LOC: 9, SLOC: 7, ContentHash: "abc", Generated: true,
+	}))
+	f, err = s.GetFile(ctx, "util_gen.go")
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	assert.Equal(t, 7, f.SLOC)
+	assert.Equal(t, "abc", f.ContentHash)
+	assert.True(t, f.Generated)
+
+	files, err := s.GetAllFiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// Tables added by migrations accept edges.
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{Name: "Run", Kind: SymbolKindMethod, FilePath: "main.go", StartLine: 6, EndLine: 8}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "main.go:main", TargetID: "main.go:Run", Kind: EdgeKindHasMethod}))
+}
+
+func TestKuzuStore_InitSchema_NewStoreIsCurrent(t *testing.T) {
+	s := newTestStore(t)
+	version, err := s.SchemaVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+}
+
+func TestKuzuStore_CanceledContextAbortsQuery(t *testing.T) {
+	s := newTestStore(t)
+
+	t.Run("already canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := s.GetFile(ctx, "main.go")
+		require.ErrorIs(t, err, context.Canceled)
+		_, err = s.AssessImpact(ctx, []string{"main.go"})
+		require.ErrorIs(t, err, context.Canceled)
+		edges, err := s.GetAllEdges(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, edges)
+	})
+
+	t.Run("canceled mid-query", func(t *testing.T) {
+		// Long enough to run for many seconds if not interrupted.
+		const slow = "UNWIND range(1, 100000) AS x UNWIND range(1, 100000) AS y RETURN count(*)"
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := s.RunReadQuery(ctx, slow, nil)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 5*time.Second, "query should stop promptly")
+
+		// The connection is usable again afterwards.
+		require.NoError(t, s.AddFile(context.Background(), FileNode{Path: "after.go", Language: LangGo}))
+		f, err := s.GetFile(context.Background(), "after.go")
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+}
+
+func TestKuzuStore_Compact(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "graph")
+	s, err := NewKuzuFileStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	require.NoError(t, s.InitSchema(ctx))
+
+	const total, kept = 400, 4
+	for i := 0; i < total; i++ {
+		path := fmt.Sprintf("pkg/f%03d.go", i)
+		require.NoError(t, s.AddFile(ctx, FileNode{Path: path, Language: LangGo, LOC: i, SLOC: i, ContentHash: "h"}))
+		require.NoError(t, s.AddSymbol(ctx, SymbolNode{
+			Name: fmt.Sprintf("F%d", i), Kind: SymbolKindFunction, Exported: true,
+			FilePath: path, StartLine: 1, EndLine: 3, StableID: "sid", Decorators: []string{"d"},
+		}))
+		require.NoError(t, s.AddEdge(ctx, Edge{SourceID: path, TargetID: symbolID(path, fmt.Sprintf("F%d", i)), Kind: EdgeKindDefines}))
+	}
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "pkg/f001.go", TargetID: "pkg/f000.go", Kind: EdgeKindImports}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "pkg/f001.go:F1", TargetID: "pkg/f000.go:F0", Kind: EdgeKindCalls}))
+	require.NoError(t, s.AddCluster(ctx, ClusterNode{Name: "core", CohesionScore: 0.5}))
+	require.NoError(t, s.AddEdge(ctx, Edge{SourceID: "pkg/f000.go", TargetID: "core", Kind: EdgeKindBelongs}))
+	for i := kept; i < total; i++ {
+		require.NoError(t, s.RemoveFile(ctx, fmt.Sprintf("pkg/f%03d.go", i)))
+	}
+	before, err := s.Stats(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Compact(ctx))
+
+	// The store is reopened on the compacted database with nothing lost.
+	after, err := s.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.Equal(t, kept, after.FileCount)
+
+	f, err := s.GetFile(ctx, "pkg/f002.go")
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	assert.Equal(t, FileNode{Path: "pkg/f002.go", Language: LangGo, LOC: 2, SLOC: 2, ContentHash: "h"}, *f)
+	sym, err := s.GetSymbol(ctx, "pkg/f001.go", "F1")
+	require.NoError(t, err)
+	require.NotNil(t, sym)
+	assert.Equal(t, "sid", sym.StableID)
+	assert.Equal(t, []string{"d"}, sym.Decorators)
+	clusters, err := s.GetClusters(ctx)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, []string{"pkg/f000.go"}, clusters[0].Members)
+	version, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+
+	// No temporary databases are left beside the store.
+	entries, err := os.ReadDir(filepath.Dir(dbPath))
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".compact")
+		assert.NotContains(t, e.Name(), ".old")
+	}
+
+	// The compacted store accepts writes and survives a reopen.
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "new.go", Language: LangGo}))
+	require.NoError(t, s.Close())
+	s, err = NewKuzuFileStore(dbPath)
+	require.NoError(t, err)
+	f, err = s.GetFile(ctx, "new.go")
+	require.NoError(t, err)
+	assert.NotNil(t, f)
+}
+
+func TestCompareCopyStats(t *testing.T) {
+	orig := &GraphStats{FileCount: 2, SymbolCount: 3, EdgeCount: 4,
+		EdgesByKind: map[EdgeKind]int{EdgeKindDefines: 3, EdgeKindCalls: 1}}
+	require.NoError(t, compareCopyStats(orig, orig))
+
+	lostCalls := *orig
+	lostCalls.EdgesByKind = map[EdgeKind]int{EdgeKindDefines: 3}
+	err := compareCopyStats(orig, &lostCalls)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "0 CALLS edges, original has 1")
+
+	lostFile := *orig
+	lostFile.FileCount = 1
+	assert.Error(t, compareCopyStats(orig, &lostFile))
+}
+
+func TestKuzuStore_Compact_InMemory(t *testing.T) {
+	s := newTestStore(t)
+	require.Error(t, s.Compact(context.Background()))
+}
+
+func TestKuzuStore_GetAllFilesAndSymbols(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "b.go", Language: LangGo, LOC: 2}))
+	require.NoError(t, s.AddFile(ctx, FileNode{Path: "a.go", Language: LangGo, LOC: 1, ContentHash: "h"}))
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{Name: "Z", Kind: SymbolKindFunction, FilePath: "a.go", StableID: "z"}))
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{Name: "Y", Kind: SymbolKindType, FilePath: "b.go"}))
+	require.NoError(t, s.AddSymbol(ctx, SymbolNode{Name: "A", Kind: SymbolKindFunction, FilePath: "b.go"}))
+
+	files, err := s.GetAllFiles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []FileNode{
+		{Path: "a.go", Language: LangGo, LOC: 1, ContentHash: "h"},
+		{Path: "b.go", Language: LangGo, LOC: 2},
+	}, files)
+
+	symbols, err := s.GetAllSymbols(ctx)
+	require.NoError(t, err)
+	var ids []string
+	for _, sym := range symbols {
+		ids = append(ids, symbolID(sym.FilePath, sym.Name))
+	}
+	assert.Equal(t, []string{"a.go:Z", "b.go:A", "b.go:Y"}, ids)
+	assert.Equal(t, "z", symbols[0].StableID)
+}
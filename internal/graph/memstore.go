@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -38,14 +39,28 @@ func (m *MemStore) InitSchema(_ context.Context) error {
 
 // AddFile stores a file node keyed by its path.
 func (m *MemStore) AddFile(_ context.Context, node FileNode) error {
+	node.Path = NormalizePath(node.Path)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.files[node.Path] = node
 	return nil
 }
 
+// SetContentHash updates the content hash of the file node at path, if any.
+func (m *MemStore) SetContentHash(_ context.Context, path, hash string) error {
+	path = NormalizePath(path)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.files[path]; ok {
+		f.ContentHash = hash
+		m.files[path] = f
+	}
+	return nil
+}
+
 // AddSymbol stores a symbol node keyed by "filePath:name".
 func (m *MemStore) AddSymbol(_ context.Context, node SymbolNode) error {
+	node.FilePath = NormalizePath(node.FilePath)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.symbols[symbolKey(node.FilePath, node.Name)] = node
@@ -62,14 +77,47 @@ func (m *MemStore) AddCluster(_ context.Context, node ClusterNode) error {
 
 // AddEdge appends an edge to the internal slice.
 func (m *MemStore) AddEdge(_ context.Context, edge Edge) error {
+	edge.SourceID, edge.TargetID = NormalizePath(edge.SourceID), NormalizePath(edge.TargetID)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.edges = append(m.edges, edge)
 	return nil
 }
 
+// RemoveFile deletes the file node at path, the symbols it defines, and all
+// edges whose source or target is one of them.
+func (m *MemStore) RemoveFile(_ context.Context, path string) error {
+	path = NormalizePath(path)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed := map[string]bool{path: true}
+	delete(m.files, path)
+	for key, sym := range m.symbols {
+		if sym.FilePath == path {
+			removed[key] = true
+			delete(m.symbols, key)
+		}
+	}
+	m.edges = filterEdges(m.edges, func(e Edge) bool {
+		return !removed[e.SourceID] && !removed[e.TargetID]
+	})
+	return nil
+}
+
+// ClearClusters deletes all clusters and BELONGS edges.
+func (m *MemStore) ClearClusters(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clusters = nil
+	m.edges = filterEdges(m.edges, func(e Edge) bool {
+		return e.Kind != EdgeKindBelongs
+	})
+	return nil
+}
+
 // GetFile returns the file node for the given path, or nil if not found.
 func (m *MemStore) GetFile(_ context.Context, path string) (*FileNode, error) {
+	path = NormalizePath(path)
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	f, ok := m.files[path]
@@ -81,6 +129,7 @@ func (m *MemStore) GetFile(_ context.Context, path string) (*FileNode, error) {
 
 // GetSymbol returns the symbol for the given file path and name, or nil if not found.
 func (m *MemStore) GetSymbol(_ context.Context, filePath, name string) (*SymbolNode, error) {
+	filePath = NormalizePath(filePath)
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	s, ok := m.symbols[symbolKey(filePath, name)]
@@ -108,20 +157,57 @@ func (m *MemStore) QuerySymbols(_ context.Context, query string, limit int) ([]S
 	return results, nil
 }
 
-// GetDependencies performs a BFS on edges from nodeID in the given direction,
-// up to maxDepth hops. It returns one DependencyChain per reachable node.
-func (m *MemStore) GetDependencies(_ context.Context, nodeID string, direction Direction, maxDepth int) ([]DependencyChain, error) {
+// GetMethods returns the methods linked to a type symbol by HAS_METHOD edges,
+// ordered by file and start line.
+func (m *MemStore) GetMethods(_ context.Context, filePath, typeName string) ([]SymbolNode, error) {
+	filePath = NormalizePath(filePath)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	owner := symbolKey(filePath, typeName)
+	var methods []SymbolNode
+	for _, e := range m.edges {
+		if e.Kind != EdgeKindHasMethod || e.SourceID != owner {
+			continue
+		}
+		if sym, ok := m.symbols[e.TargetID]; ok {
+			methods = append(methods, sym)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if methods[i].FilePath != methods[j].FilePath {
+			return methods[i].FilePath < methods[j].FilePath
+		}
+		return methods[i].StartLine < methods[j].StartLine
+	})
+	return methods, nil
+}
+
+// GetDependencies performs a BFS on edges of the given kinds (IMPORTS by
+// default) from nodeID in the given direction, up to maxDepth hops. It
+// returns one DependencyChain per reachable node.
+func (m *MemStore) GetDependencies(ctx context.Context, nodeID string, direction Direction, maxDepth int, kinds ...EdgeKind) ([]DependencyChain, error) {
+	chains, _, err := m.GetDependenciesLimited(ctx, nodeID, direction, maxDepth, 0, kinds...)
+	return chains, err
+}
+
+// GetDependenciesLimited is GetDependencies with a cap of maxNodes visited
+// nodes (no cap when maxNodes <= 0). It reports whether the cap cut the
+// traversal short.
+func (m *MemStore) GetDependenciesLimited(_ context.Context, nodeID string, direction Direction, maxDepth, maxNodes int, kinds ...EdgeKind) ([]DependencyChain, bool, error) {
+	nodeID = NormalizePath(nodeID)
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	if maxDepth <= 0 {
-		return nil, nil
+		return nil, false, nil
 	}
+	follow := edgeKindSet(kinds)
 
 	// BFS state: each entry tracks the path from nodeID to the current node.
 	type bfsEntry struct {
 		id   string
 		path []string
+		hops []ChainHop
 	}
 
 	visited := map[string]bool{nodeID: true}
@@ -131,51 +217,77 @@ func (m *MemStore) GetDependencies(_ context.Context, nodeID string, direction D
 	for depth := 0; depth < maxDepth && len(queue) > 0; depth++ {
 		var nextQueue []bfsEntry
 		for _, entry := range queue {
-			neighbors := m.neighbors(entry.id, direction)
+			neighbors := m.neighbors(entry.id, direction, follow)
 			for _, nb := range neighbors {
-				if visited[nb] {
+				if visited[nb.id] {
 					continue
 				}
-				visited[nb] = true
+				if maxNodes > 0 && len(chains) >= maxNodes {
+					return chains, true, nil
+				}
+				visited[nb.id] = true
 				newPath := make([]string, len(entry.path), len(entry.path)+1)
 				copy(newPath, entry.path)
-				newPath = append(newPath, nb)
+				newPath = append(newPath, nb.id)
+				newHops := make([]ChainHop, len(entry.hops), len(entry.hops)+1)
+				copy(newHops, entry.hops)
+				newHops = append(newHops, nb.hop)
 				chains = append(chains, DependencyChain{
 					Nodes: newPath,
 					Depth: len(newPath) - 1,
+					Hops:  newHops,
 				})
-				nextQueue = append(nextQueue, bfsEntry{id: nb, path: newPath})
+				nextQueue = append(nextQueue, bfsEntry{id: nb.id, path: newPath, hops: newHops})
 			}
 		}
 		queue = nextQueue
 	}
 
-	return chains, nil
+	return chains, false, nil
 }
 
-// neighbors returns IDs reachable from id in one hop along the given direction.
-func (m *MemStore) neighbors(id string, direction Direction) []string {
-	var result []string
+// neighbors returns the nodes reachable from id in one hop along the given
+// direction, following only edges whose kind is in follow. Parallel edges of
+// the same kind collapse into one neighbor whose hop weight is the edge count.
+func (m *MemStore) neighbors(id string, direction Direction, follow map[EdgeKind]bool) []neighbor {
+	var result []neighbor
+	index := make(map[neighbor]int) // (id, kind) with zero weight -> result index
 	for _, e := range m.edges {
+		if !follow[e.Kind] {
+			continue
+		}
+		var nb string
 		switch direction {
 		case DirectionDownstream:
 			// downstream: id is a dependency of others -> follow edges where SourceID matches
-			if e.SourceID == id {
-				result = append(result, e.TargetID)
+			if e.SourceID != id {
+				continue
 			}
+			nb = e.TargetID
 		case DirectionUpstream:
 			// upstream: id depends on others -> follow edges where TargetID matches
-			if e.TargetID == id {
-				result = append(result, e.SourceID)
+			if e.TargetID != id {
+				continue
 			}
+			nb = e.SourceID
+		default:
+			continue
 		}
+		key := neighbor{id: nb, hop: ChainHop{Kind: e.Kind}}
+		if i, ok := index[key]; ok {
+			result[i].hop.Weight++
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, neighbor{id: nb, hop: ChainHop{Kind: e.Kind, Weight: 1}})
 	}
 	return result
 }
 
 // AssessImpact computes the blast radius of changing the given files.
 // It follows IMPORTS edges to find direct and transitive dependents.
-func (m *MemStore) AssessImpact(_ context.Context, changedFiles []string) (*ImpactResult, error) {
+func (m *MemStore) AssessImpact(_ context.Context, changedFiles []string, opts ...QueryOption) (*ImpactResult, error) {
+	changedFiles = normalizePaths(changedFiles)
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -230,9 +342,17 @@ func (m *MemStore) AssessImpact(_ context.Context, changedFiles []string) (*Impa
 
 	transitivelyAffected := setToSlice(allAffected)
 
+	totalFiles := len(m.files)
+	if applyQueryOptions(opts).excludeGenerated {
+		generated := m.generatedFiles()
+		directlyAffected = withoutGenerated(directlyAffected, generated)
+		transitivelyAffected = withoutGenerated(transitivelyAffected, generated)
+		totalFiles -= len(generated)
+	}
+
 	var riskScore float64
-	if len(m.files) > 0 {
-		riskScore = float64(len(transitivelyAffected)) / float64(len(m.files))
+	if totalFiles > 0 {
+		riskScore = float64(len(transitivelyAffected)) / float64(totalFiles)
 	}
 
 	return &ImpactResult{
@@ -243,14 +363,58 @@ func (m *MemStore) AssessImpact(_ context.Context, changedFiles []string) (*Impa
 }
 
 // GetClusters returns all stored clusters.
-func (m *MemStore) GetClusters(_ context.Context) ([]ClusterNode, error) {
+func (m *MemStore) GetClusters(_ context.Context, opts ...QueryOption) ([]ClusterNode, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	if applyQueryOptions(opts).excludeGenerated {
+		return clustersWithoutGenerated(m.clusters, m.generatedFiles()), nil
+	}
 	out := make([]ClusterNode, len(m.clusters))
 	copy(out, m.clusters)
 	return out, nil
 }
 
+// generatedFiles returns the set of paths of generated files. The caller
+// must hold m.mu.
+func (m *MemStore) generatedFiles() map[string]bool {
+	generated := make(map[string]bool)
+	for path, f := range m.files {
+		if f.Generated {
+			generated[path] = true
+		}
+	}
+	return generated
+}
+
+// GetAllFiles returns all file nodes ordered by path.
+func (m *MemStore) GetAllFiles(_ context.Context) ([]FileNode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]FileNode, 0, len(m.files))
+	for _, f := range m.files {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+// GetAllSymbols returns all symbol nodes ordered by file path and name.
+func (m *MemStore) GetAllSymbols(_ context.Context) ([]SymbolNode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]SymbolNode, 0, len(m.symbols))
+	for _, sym := range m.symbols {
+		out = append(out, sym)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].FilePath != out[j].FilePath {
+			return out[i].FilePath < out[j].FilePath
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
 // GetAllEdges returns a copy of all edges in the store.
 func (m *MemStore) GetAllEdges(_ context.Context) ([]Edge, error) {
 	m.mu.RLock()
@@ -260,15 +424,30 @@ func (m *MemStore) GetAllEdges(_ context.Context) ([]Edge, error) {
 	return out, nil
 }
 
+// RunReadQuery is not supported by the in-memory store.
+func (m *MemStore) RunReadQuery(_ context.Context, _ string, _ map[string]any) ([][]any, error) {
+	return nil, ErrQueryUnsupported
+}
+
 // Stats returns counts of all node and edge types in the graph.
 func (m *MemStore) Stats(_ context.Context) (*GraphStats, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	sloc := 0
+	for _, f := range m.files {
+		sloc += f.SLOC
+	}
+	byKind := make(map[EdgeKind]int)
+	for _, e := range m.edges {
+		byKind[e.Kind]++
+	}
 	return &GraphStats{
 		FileCount:    len(m.files),
 		SymbolCount:  len(m.symbols),
 		ClusterCount: len(m.clusters),
 		EdgeCount:    len(m.edges),
+		SLOC:         sloc,
+		EdgesByKind:  byKind,
 	}, nil
 }
 
@@ -277,6 +456,30 @@ func (m *MemStore) Close() error {
 	return nil
 }
 
+// edgeKindSet returns kinds as a set, defaulting to IMPORTS when empty.
+func edgeKindSet(kinds []EdgeKind) map[EdgeKind]bool {
+	if len(kinds) == 0 {
+		return map[EdgeKind]bool{EdgeKindImports: true}
+	}
+	set := make(map[EdgeKind]bool, len(kinds))
+	for _, k := range kinds {
+		set[k] = true
+	}
+	return set
+}
+
+// filterEdges returns the edges for which keep returns true, reusing the
+// backing array of edges.
+func filterEdges(edges []Edge, keep func(Edge) bool) []Edge {
+	out := edges[:0]
+	for _, e := range edges {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
 // setToSlice converts a string bool map to a slice.
 func setToSlice(s map[string]bool) []string {
 	out := make([]string, 0, len(s))
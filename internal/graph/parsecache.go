@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// parseCacheFormat versions the on-disk cache entries. Bump it when
+// extractors change what they produce so stale entries are ignored.
+const parseCacheFormat = 2
+
+// parseCacheKey identifies cached parse output by content rather than path.
+type parseCacheKey struct {
+	lang Language
+	hash string
+}
+
+// ParseCache memoizes ParseResults by (language, content hash) so identical
+// file contents, such as vendored copies, are parsed once. Symbol line
+// numbers are relative to the content, so a cached result is valid for any
+// file with the same bytes; Get rewrites the path-derived fields for the
+// requesting file. A ParseCache is safe for concurrent use.
+type ParseCache struct {
+	mu      sync.Mutex
+	entries map[parseCacheKey]*ParseResult
+	dir     string // optional on-disk backing; empty for memory only
+}
+
+// NewParseCache creates an in-memory ParseCache.
+func NewParseCache() *ParseCache {
+	return &ParseCache{entries: make(map[parseCacheKey]*ParseResult)}
+}
+
+// NewDiskParseCache creates a ParseCache that also persists entries as JSON
+// files under dir, so they survive across processes. The directory is
+// created if needed.
+func NewDiskParseCache(dir string) (*ParseCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("parse cache: create directory: %w", err)
+	}
+	c := NewParseCache()
+	c.dir = dir
+	return c, nil
+}
+
+// Get returns the cached result for content with the given language and hash,
+// rebased onto path, or false on a miss. Unreadable disk entries are misses.
+func (c *ParseCache) Get(lang Language, hash, path string) (*ParseResult, bool) {
+	key := parseCacheKey{lang: lang, hash: hash}
+
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok && c.dir != "" {
+		data, err := os.ReadFile(c.entryPath(key))
+		if err != nil {
+			return nil, false
+		}
+		var result ParseResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, false
+		}
+		cached, ok = &result, true
+
+		c.mu.Lock()
+		c.entries[key] = cached
+		c.mu.Unlock()
+	}
+	if !ok {
+		return nil, false
+	}
+	return cached.withPath(path), true
+}
+
+// Put stores result for content with the given language and hash. The result
+// is copied, so the caller may keep modifying its own value.
+func (c *ParseCache) Put(lang Language, hash string, result *ParseResult) error {
+	key := parseCacheKey{lang: lang, hash: hash}
+	stored := result.withPath(result.File.Path)
+
+	c.mu.Lock()
+	c.entries[key] = stored
+	c.mu.Unlock()
+
+	if c.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("parse cache: encode: %w", err)
+	}
+	if err := os.WriteFile(c.entryPath(key), data, 0o644); err != nil {
+		return fmt.Errorf("parse cache: write: %w", err)
+	}
+	return nil
+}
+
+// entryPath returns the on-disk location of key's entry.
+func (c *ParseCache) entryPath(key parseCacheKey) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s.v%d.json", key.lang, key.hash, parseCacheFormat))
+}
+
+// withPath returns a deep copy of r with every path-derived field moved from
+// r.File.Path to path: the file node, symbol file paths, and edge endpoints
+// that are the file itself or one of its "file:name" symbol keys.
+func (r *ParseResult) withPath(path string) *ParseResult {
+	old := r.File.Path
+	rebase := func(id string) string {
+		if id == old {
+			return path
+		}
+		if name, ok := strings.CutPrefix(id, old+":"); ok {
+			return symbolKey(path, name)
+		}
+		return id
+	}
+
+	out := &ParseResult{
+		File:    r.File,
+		Symbols: make([]SymbolNode, len(r.Symbols)),
+		Edges:   make([]Edge, len(r.Edges)),
+	}
+	out.File.Path = path
+	for i, sym := range r.Symbols {
+		sym.FilePath = path
+		out.Symbols[i] = sym
+	}
+	for i, e := range r.Edges {
+		e.SourceID = rebase(e.SourceID)
+		e.TargetID = rebase(e.TargetID)
+		out.Edges[i] = e
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleParseResult() *ParseResult {
+	return &ParseResult{
+		File: FileNode{Path: "a/svc.go", Language: LangGo, LOC: 20, ContentHash: "h1"},
+		Symbols: []SymbolNode{
+			{Name: "Service", Kind: SymbolKindType, Exported: true, FilePath: "a/svc.go", StartLine: 3, EndLine: 5},
+			{Name: "Run", Kind: SymbolKindMethod, Exported: true, FilePath: "a/svc.go", StartLine: 7, EndLine: 9},
+		},
+		Edges: []Edge{
+			{SourceID: "a/svc.go", TargetID: "fmt", Kind: EdgeKindImports},
+			{SourceID: "Service", TargetID: "a/svc.go:Run", Kind: EdgeKindHasMethod},
+		},
+	}
+}
+
+func TestParseCache_RebasesPaths(t *testing.T) {
+	c := NewParseCache()
+	_, ok := c.Get(LangGo, "h1", "b/svc.go")
+	assert.False(t, ok)
+
+	require.NoError(t, c.Put(LangGo, "h1", sampleParseResult()))
+
+	got, ok := c.Get(LangGo, "h1", "b/svc.go")
+	require.True(t, ok)
+	assert.Equal(t, "b/svc.go", got.File.Path)
+	assert.Equal(t, 20, got.File.LOC)
+	for _, sym := range got.Symbols {
+		assert.Equal(t, "b/svc.go", sym.FilePath)
+	}
+	assert.Equal(t, []Edge{
+		{SourceID: "b/svc.go", TargetID: "fmt", Kind: EdgeKindImports},
+		{SourceID: "Service", TargetID: "b/svc.go:Run", Kind: EdgeKindHasMethod},
+	}, got.Edges)
+
+	// A different language with the same hash is a separate entry.
+	_, ok = c.Get(LangPython, "h1", "b/svc.go")
+	assert.False(t, ok)
+}
+
+func TestParseCache_DiskBacking(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewDiskParseCache(dir)
+	require.NoError(t, err)
+	require.NoError(t, c.Put(LangGo, "h1", sampleParseResult()))
+
+	// A fresh cache over the same directory sees the entry.
+	reopened, err := NewDiskParseCache(dir)
+	require.NoError(t, err)
+	got, ok := reopened.Get(LangGo, "h1", "a/svc.go")
+	require.True(t, ok)
+	assert.Equal(t, sampleParseResult(), got)
+}
@@ -1,6 +1,13 @@
 package graph
 
-import "context"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
 
 // ParseResult holds the extracted symbols and edges from a single file.
 type ParseResult struct {
@@ -9,6 +16,32 @@ type ParseResult struct {
 	Edges   []Edge       `json:"edges"` // DEFINES, IMPORTS, CALLS edges
 }
 
+// languageAliases maps the short names ParseLanguage accepts to languages.
+var languageAliases = map[string]Language{
+	"golang": LangGo,
+	"ts":     LangTypeScript,
+	"py":     LangPython,
+	"rs":     LangRust,
+}
+
+// ParseLanguage converts a language name, or an alias such as "ts" or "py",
+// to a Language, ignoring case. Names outside Tier1Languages are an error
+// that lists the supported ones.
+func ParseLanguage(name string) (Language, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if lang, ok := languageAliases[name]; ok {
+		return lang, nil
+	}
+	names := make([]string, len(Tier1Languages))
+	for i, lang := range Tier1Languages {
+		if string(lang) == name {
+			return lang, nil
+		}
+		names[i] = string(lang)
+	}
+	return "", fmt.Errorf("unknown language %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
 // Parser extracts structural information from source files.
 // Implementations: TreeSitterParser (production), StubParser (testing).
 type Parser interface {
@@ -22,3 +55,67 @@ type Parser interface {
 	// Close releases parser resources (Tree-sitter C memory).
 	Close() error
 }
+
+// identifierRe matches the identifiers stableSymbolID masks.
+var identifierRe = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+
+// stableSymbolID returns a location-independent identity for sym: a hash of
+// its kind and the source lines it spans, given as lines, with whitespace
+// collapsed and the symbol's own name, unqualified, masked. Moving a symbol to another file
+// or line, or renaming it, keeps its StableID; changing its signature or body
+// does not.
+func stableSymbolID(sym SymbolNode, lines []string) string {
+	start, end := sym.StartLine-1, sym.EndLine
+	if start < 0 || end > len(lines) || start >= end {
+		return ""
+	}
+	text := strings.Join(strings.Fields(strings.Join(lines[start:end], "\n")), " ")
+	if name := calleeName(sym.Name); name != "" {
+		text = identifierRe.ReplaceAllStringFunc(text, func(word string) string {
+			if word == name {
+				return "_"
+			}
+			return word
+		})
+	}
+	sum := sha256.Sum256([]byte(string(sym.Kind) + "\x00" + text))
+	return hex.EncodeToString(sum[:16])
+}
+
+// MatchMovedSymbols pairs symbols from an earlier build with the symbols of a
+// later one by StableID, returning a map from each moved or renamed symbol's
+// old "filePath:name" ID to its new one. Symbols without a StableID, whose ID
+// is unchanged, or whose StableID is shared by several later symbols are not
+// matched.
+func MatchMovedSymbols(before, after []SymbolNode) map[string]string {
+	byStable := make(map[string]string, len(after))
+	ambiguous := make(map[string]bool)
+	for _, sym := range after {
+		if sym.StableID == "" {
+			continue
+		}
+		if _, ok := byStable[sym.StableID]; ok {
+			ambiguous[sym.StableID] = true
+		}
+		byStable[sym.StableID] = symbolKey(sym.FilePath, sym.Name)
+	}
+
+	moved := make(map[string]string)
+	for _, sym := range before {
+		newID, ok := byStable[sym.StableID]
+		if sym.StableID == "" || !ok || ambiguous[sym.StableID] {
+			continue
+		}
+		if oldID := symbolKey(sym.FilePath, sym.Name); oldID != newID {
+			moved[oldID] = newID
+		}
+	}
+	return moved
+}
+
+// ContentHash returns the hex SHA-256 of source, as stored in
+// FileNode.ContentHash.
+func ContentHash(source []byte) string {
+	sum := sha256.Sum256(source)
+	return hex.EncodeToString(sum[:])
+}
@@ -4,21 +4,55 @@ import (
 	"bufio"
 	"encoding/json"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 )
 
+// NormalizePath converts a repo-relative path to the forward-slash form used
+// by FileNode.Path, symbol IDs, and edge endpoints, whatever the OS path
+// separator. Backslashes are converted even on systems where they are not
+// separators, so paths produced on Windows match those produced elsewhere.
+func NormalizePath(p string) string {
+	return strings.ReplaceAll(filepath.ToSlash(p), `\`, "/")
+}
+
+// normalizePaths applies NormalizePath to each of paths, returning a new
+// slice.
+func normalizePaths(paths []string) []string {
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		out[i] = NormalizePath(p)
+	}
+	return out
+}
+
 // Resolver rewrites raw import specifiers (extracted by tree-sitter) into
 // repo-relative file paths that match FileNode.Path values. It is built once
 // per build_graph call with the set of known file paths and any workspace
-// metadata discovered in the repository root.
+// metadata discovered in the repository root. Repo-relative paths are
+// handled in forward-slash form on every OS; see NormalizePath.
 type Resolver struct {
 	repoRoot     string
 	fileSet      map[string]bool
 	dirIndex     map[string][]string
 	tsWorkspaces map[string]*tsWorkspace
-	goModPath    string
+	goModPath    string     // module declared by go.mod at repoRoot
+	goModules    []goModule // modules declared by go.mod at other roots
+	cleanup      ImportCleanup
+}
+
+// ImportCleanup counts the resolved IMPORTS edges ResolveAll discarded.
+type ImportCleanup struct {
+	Duplicates  int // repeats of an already-kept (source, target) pair
+	SelfImports int // edges whose target resolved to their own source
+}
+
+// goModule is a Go module rooted below the resolver's repoRoot.
+type goModule struct {
+	path string // module path from go.mod
+	dir  string // repo-relative directory containing go.mod
 }
 
 // tsWorkspace holds metadata about a single npm/bun workspace package.
@@ -35,25 +69,61 @@ type tsWorkspace struct {
 // known repo-relative file paths. It scans for workspace metadata
 // (package.json, go.mod) to enable package-aware resolution.
 func NewResolver(repoRoot string, knownFiles []string) *Resolver {
+	return NewMultiRootResolver(repoRoot, []string{repoRoot}, knownFiles)
+}
+
+// NewMultiRootResolver builds a Resolver for several source roots indexed
+// together. Known file paths are relative to base, which must contain every
+// root (see CommonBase). Workspace metadata is read from each root, so an
+// import in one root resolves to files in another when it names that root's
+// Go module or workspace package.
+func NewMultiRootResolver(base string, roots []string, knownFiles []string) *Resolver {
 	r := &Resolver{
-		repoRoot:     repoRoot,
+		repoRoot:     base,
 		fileSet:      make(map[string]bool, len(knownFiles)),
 		dirIndex:     make(map[string][]string),
 		tsWorkspaces: make(map[string]*tsWorkspace),
 	}
 
 	for _, f := range knownFiles {
+		f = NormalizePath(f)
 		r.fileSet[f] = true
-		dir := filepath.Dir(f)
+		dir := path.Dir(f)
 		r.dirIndex[dir] = append(r.dirIndex[dir], f)
 	}
 
-	r.scanTSWorkspaces()
-	r.scanGoMod()
+	for _, root := range roots {
+		r.scanTSWorkspaces(root)
+		r.scanGoMod(root)
+	}
 
 	return r
 }
 
+// CommonBase returns the deepest directory containing every path in roots.
+// A single root is its own base.
+func CommonBase(roots []string) string {
+	if len(roots) == 0 {
+		return ""
+	}
+	base := filepath.Clean(roots[0])
+	for _, root := range roots[1:] {
+		root = filepath.Clean(root)
+		for {
+			rel, err := filepath.Rel(base, root)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				break
+			}
+			parent := filepath.Dir(base)
+			if parent == base {
+				break
+			}
+			base = parent
+		}
+	}
+	return base
+}
+
 // ResolveEdge attempts to resolve a single IMPORTS edge's TargetID from a raw
 // import specifier to a repo-relative file path. Returns the resolved edge and
 // true on success. Non-IMPORTS edges pass through unchanged.
@@ -61,6 +131,7 @@ func (r *Resolver) ResolveEdge(edge Edge, lang Language) (Edge, bool) {
 	if edge.Kind != EdgeKindImports {
 		return edge, true
 	}
+	edge.SourceID = NormalizePath(edge.SourceID)
 
 	var resolved string
 	var ok bool
@@ -87,18 +158,40 @@ func (r *Resolver) ResolveEdge(edge Edge, lang Language) (Edge, bool) {
 }
 
 // ResolveAll resolves a slice of edges, dropping unresolvable IMPORTS edges.
-// Non-IMPORTS edges pass through unchanged.
+// Resolved IMPORTS edges that repeat a (source, target) pair or point back
+// at their source are dropped too and counted in Cleanup. Non-IMPORTS edges
+// pass through unchanged.
 func (r *Resolver) ResolveAll(edges []Edge, lang Language) []Edge {
 	out := make([]Edge, 0, len(edges))
+	seen := make(map[[2]string]bool)
 	for _, e := range edges {
 		resolved, ok := r.ResolveEdge(e, lang)
-		if ok {
-			out = append(out, resolved)
+		if !ok {
+			continue
+		}
+		if resolved.Kind == EdgeKindImports {
+			if resolved.SourceID == resolved.TargetID {
+				r.cleanup.SelfImports++
+				continue
+			}
+			key := [2]string{resolved.SourceID, resolved.TargetID}
+			if seen[key] {
+				r.cleanup.Duplicates++
+				continue
+			}
+			seen[key] = true
 		}
+		out = append(out, resolved)
 	}
 	return out
 }
 
+// Cleanup returns the number of IMPORTS edges dropped as duplicates or
+// self-imports across all ResolveAll calls on r.
+func (r *Resolver) Cleanup() ImportCleanup {
+	return r.cleanup
+}
+
 // --- TypeScript resolution ---
 
 var tsExtensions = []string{".ts", ".tsx", ".js", ".jsx", "/index.ts", "/index.tsx", "/index.js"}
@@ -106,9 +199,9 @@ var tsExtensions = []string{".ts", ".tsx", ".js", ".jsx", "/index.ts", "/index.t
 func (r *Resolver) resolveTS(importPath, sourceFile string) (string, bool) {
 	// Relative imports.
 	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
-		sourceDir := filepath.Dir(sourceFile)
-		base := filepath.Join(sourceDir, importPath)
-		base = filepath.Clean(base)
+		sourceDir := path.Dir(sourceFile)
+		base := path.Join(sourceDir, importPath)
+		base = path.Clean(base)
 		return r.probeFile(base, tsExtensions)
 	}
 
@@ -167,7 +260,7 @@ func (r *Resolver) resolveTSWorkspace(importPath string) (string, bool) {
 	for pattern, template := range ws.wildcardExports {
 		if matched, replacement := matchWildcard(pattern, subpath); matched {
 			target := strings.Replace(template, "*", replacement, 1)
-			resolved := filepath.Clean(filepath.Join(ws.dir, target))
+			resolved := path.Clean(path.Join(ws.dir, target))
 			if r.fileSet[resolved] {
 				return resolved, true
 			}
@@ -179,7 +272,7 @@ func (r *Resolver) resolveTSWorkspace(importPath string) (string, bool) {
 
 	// Fallback: try resolving subpath as a file relative to the workspace dir.
 	relPath := subpath[2:] // strip "./"
-	base := filepath.Join(ws.dir, relPath)
+	base := path.Join(ws.dir, relPath)
 	return r.probeFile(base, tsExtensions)
 }
 
@@ -208,16 +301,32 @@ func matchWildcard(pattern, subpath string) (bool, string) {
 // --- Go resolution ---
 
 func (r *Resolver) resolveGo(importPath string) (string, bool) {
-	if r.goModPath == "" {
-		return "", false
+	modules := r.goModules
+	if r.goModPath != "" {
+		modules = append([]goModule{{path: r.goModPath}}, modules...)
 	}
-	if !strings.HasPrefix(importPath, r.goModPath) {
+
+	// Pick the longest module path that prefixes the import, so nested
+	// modules win over the module that contains them.
+	var mod *goModule
+	for i, m := range modules {
+		if importPath != m.path && !strings.HasPrefix(importPath, m.path+"/") {
+			continue
+		}
+		if mod == nil || len(m.path) > len(mod.path) {
+			mod = &modules[i]
+		}
+	}
+	if mod == nil {
 		return "", false // stdlib or external module
 	}
 
 	// Strip module path to get repo-relative directory.
-	relDir := strings.TrimPrefix(importPath, r.goModPath)
-	relDir = strings.TrimPrefix(relDir, "/")
+	relDir := strings.TrimPrefix(importPath, mod.path)
+	relDir = path.Join(mod.dir, strings.TrimPrefix(relDir, "/"))
+	if relDir == "." {
+		relDir = ""
+	}
 
 	// Find the first .go file in that directory.
 	files := r.dirIndex[relDir]
@@ -258,19 +367,19 @@ func (r *Resolver) resolvePython(importPath, sourceFile string) (string, bool) {
 
 	// Start from source file's directory, go up (dots-1) levels.
 	// One dot = same package (current dir), two dots = parent, etc.
-	baseDir := filepath.Dir(sourceFile)
+	baseDir := path.Dir(sourceFile)
 	for i := 1; i < dots; i++ {
-		baseDir = filepath.Dir(baseDir)
+		baseDir = path.Dir(baseDir)
 	}
 
 	if modulePart == "" {
 		// Bare relative import (just dots) — resolve to __init__.py.
-		return r.probeFile(filepath.Join(baseDir, "__init__"), []string{".py"})
+		return r.probeFile(path.Join(baseDir, "__init__"), []string{".py"})
 	}
 
 	// Replace dots in module name with path separators.
 	relPath := strings.ReplaceAll(modulePart, ".", "/")
-	base := filepath.Join(baseDir, relPath)
+	base := path.Join(baseDir, relPath)
 
 	return r.probeFile(base, []string{".py", "/__init__.py"})
 }
@@ -291,13 +400,13 @@ func (r *Resolver) resolveRust(importPath, sourceFile string) (string, bool) {
 		// Rust source is typically under src/. Try both src/ prefixed
 		// and relative to repo root.
 		candidates := []string{
-			filepath.Join("src", relPath),
+			path.Join("src", relPath),
 			relPath,
 		}
 		// Also check relative to the source file's crate root.
 		// If sourceFile is "some_crate/src/service.rs", crate root is "some_crate/src".
 		if srcDir := findCrateRoot(sourceFile); srcDir != "" {
-			candidates = append(candidates, filepath.Join(srcDir, relPath))
+			candidates = append(candidates, path.Join(srcDir, relPath))
 		}
 
 		for _, base := range candidates {
@@ -310,15 +419,15 @@ func (r *Resolver) resolveRust(importPath, sourceFile string) (string, bool) {
 	case strings.HasPrefix(importPath, "self::"):
 		modulePath := strings.TrimPrefix(importPath, "self::")
 		relPath := strings.ReplaceAll(modulePath, "::", "/")
-		sourceDir := filepath.Dir(sourceFile)
-		base := filepath.Join(sourceDir, relPath)
+		sourceDir := path.Dir(sourceFile)
+		base := path.Join(sourceDir, relPath)
 		return r.probeFile(base, []string{".rs", "/mod.rs"})
 
 	case strings.HasPrefix(importPath, "super::"):
 		modulePath := strings.TrimPrefix(importPath, "super::")
 		relPath := strings.ReplaceAll(modulePath, "::", "/")
-		parentDir := filepath.Dir(filepath.Dir(sourceFile))
-		base := filepath.Join(parentDir, relPath)
+		parentDir := path.Dir(path.Dir(sourceFile))
+		base := path.Join(parentDir, relPath)
 		return r.probeFile(base, []string{".rs", "/mod.rs"})
 
 	default:
@@ -329,12 +438,12 @@ func (r *Resolver) resolveRust(importPath, sourceFile string) (string, bool) {
 // findCrateRoot walks up from a file path to find the nearest "src" directory,
 // which is the conventional Rust crate source root.
 func findCrateRoot(filePath string) string {
-	dir := filepath.Dir(filePath)
+	dir := path.Dir(filePath)
 	for dir != "." && dir != "/" && dir != "" {
-		if filepath.Base(dir) == "src" {
+		if path.Base(dir) == "src" {
 			return dir
 		}
-		dir = filepath.Dir(dir)
+		dir = path.Dir(dir)
 	}
 	return ""
 }
@@ -366,8 +475,8 @@ type packageJSON struct {
 	Exports    json.RawMessage `json:"exports"`
 }
 
-func (r *Resolver) scanTSWorkspaces() {
-	rootPkg := filepath.Join(r.repoRoot, "package.json")
+func (r *Resolver) scanTSWorkspaces(root string) {
+	rootPkg := filepath.Join(root, "package.json")
 	data, err := os.ReadFile(rootPkg)
 	if err != nil {
 		return
@@ -386,7 +495,7 @@ func (r *Resolver) scanTSWorkspaces() {
 
 	// Expand glob patterns to find workspace directories.
 	for _, pattern := range patterns {
-		absPattern := filepath.Join(r.repoRoot, pattern)
+		absPattern := filepath.Join(root, pattern)
 		matches, err := filepath.Glob(absPattern)
 		if err != nil {
 			continue
@@ -440,6 +549,7 @@ func (r *Resolver) loadWorkspacePackage(absDir string) {
 		return
 	}
 
+	relDir = NormalizePath(relDir)
 	ws := &tsWorkspace{
 		dir:             relDir,
 		subpathExports:  make(map[string]string),
@@ -451,8 +561,8 @@ func (r *Resolver) loadWorkspacePackage(absDir string) {
 
 	// Fallback to "main" if no default export found.
 	if ws.mainFile == "" && pkg.Main != "" {
-		candidate := filepath.Join(relDir, pkg.Main)
-		candidate = filepath.Clean(candidate)
+		candidate := path.Join(relDir, pkg.Main)
+		candidate = path.Clean(candidate)
 		if r.fileSet[candidate] {
 			ws.mainFile = candidate
 		} else if resolved, ok := r.probeFile(candidate, tsExtensions); ok {
@@ -463,8 +573,8 @@ func (r *Resolver) loadWorkspacePackage(absDir string) {
 	// Last resort: try index.ts / index.js in the package root or src/.
 	if ws.mainFile == "" {
 		for _, try := range []string{
-			filepath.Join(relDir, "src", "index"),
-			filepath.Join(relDir, "index"),
+			path.Join(relDir, "src", "index"),
+			path.Join(relDir, "index"),
 		} {
 			if resolved, ok := r.probeFile(try, tsExtensions); ok {
 				ws.mainFile = resolved
@@ -484,7 +594,7 @@ func (r *Resolver) parseExports(ws *tsWorkspace, raw json.RawMessage) {
 	// Try as a simple string: "exports": "./src/index.ts"
 	var str string
 	if err := json.Unmarshal(raw, &str); err == nil {
-		resolved := filepath.Clean(filepath.Join(ws.dir, str))
+		resolved := path.Clean(path.Join(ws.dir, str))
 		if r.fileSet[resolved] {
 			ws.mainFile = resolved
 		} else if probed, ok := r.probeFile(resolved, tsExtensions); ok {
@@ -511,7 +621,7 @@ func (r *Resolver) parseExports(ws *tsWorkspace, raw json.RawMessage) {
 			continue
 		}
 
-		resolved := filepath.Clean(filepath.Join(ws.dir, target))
+		resolved := path.Clean(path.Join(ws.dir, target))
 		var finalPath string
 		if r.fileSet[resolved] {
 			finalPath = resolved
@@ -565,8 +675,8 @@ func resolveExportValue(raw json.RawMessage) string {
 	return ""
 }
 
-func (r *Resolver) scanGoMod() {
-	modPath := filepath.Join(r.repoRoot, "go.mod")
+func (r *Resolver) scanGoMod(root string) {
+	modPath := filepath.Join(root, "go.mod")
 	f, err := os.Open(modPath)
 	if err != nil {
 		return
@@ -577,7 +687,13 @@ func (r *Resolver) scanGoMod() {
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if strings.HasPrefix(line, "module ") {
-			r.goModPath = strings.TrimSpace(strings.TrimPrefix(line, "module"))
+			modulePath := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+			dir, err := filepath.Rel(r.repoRoot, root)
+			if err != nil || dir == "." {
+				r.goModPath = modulePath
+				return
+			}
+			r.goModules = append(r.goModules, goModule{path: modulePath, dir: NormalizePath(dir)})
 			return
 		}
 	}
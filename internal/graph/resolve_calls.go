@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CallResolver rewrites raw CALLS callee names (extracted by tree-sitter) into
+// the symbol ID ("filePath:name") of the defining symbol. It runs after the
+// import Resolver so that resolved IMPORTS edges can scope the lookup.
+//
+// Resolution is best-effort and proceeds through scopes in order, stopping at
+// the first scope with exactly one candidate:
+//
+//  1. the calling file
+//  2. the calling file's package directory (Go only)
+//  3. files imported by the calling file (for Go, every file in the imported
+//     package directory)
+//
+// A name defined in more than one file of the winning scope is ambiguous and
+// left unresolved.
+//
+// HAS_METHOD edges are resolved the same way, with the raw owner type name in
+// SourceID looked up from the method's file; only the first two scopes apply
+// since a method must be declared alongside its type.
+type CallResolver struct {
+	defs    map[string][]symbolRef     // callable name → defining symbols
+	imports map[string]map[string]bool // file → imported repo-relative files
+}
+
+// symbolRef locates a symbol by file and stored name. The stored name of a
+// qualified symbol, such as the Python method "Repo.save", differs from the
+// name it is called by.
+type symbolRef struct {
+	file, name string
+}
+
+// NewCallResolver builds a CallResolver from every symbol in the graph and the
+// already-resolved IMPORTS edges. Non-IMPORTS edges are ignored.
+func NewCallResolver(symbols []SymbolNode, importEdges []Edge) *CallResolver {
+	c := &CallResolver{
+		defs:    make(map[string][]symbolRef),
+		imports: make(map[string]map[string]bool),
+	}
+
+	for _, sym := range symbols {
+		name := calleeName(sym.Name)
+		c.defs[name] = append(c.defs[name], symbolRef{file: sym.FilePath, name: sym.Name})
+	}
+	for name, refs := range c.defs {
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].file != refs[j].file {
+				return refs[i].file < refs[j].file
+			}
+			return refs[i].name < refs[j].name
+		})
+		c.defs[name] = slices.Compact(refs)
+	}
+
+	for _, e := range importEdges {
+		if e.Kind != EdgeKindImports {
+			continue
+		}
+		if c.imports[e.SourceID] == nil {
+			c.imports[e.SourceID] = make(map[string]bool)
+		}
+		c.imports[e.SourceID][e.TargetID] = true
+	}
+
+	return c
+}
+
+// ResolveEdge attempts to resolve a single CALLS edge's TargetID from a raw
+// callee name to a symbol ID, or a HAS_METHOD edge's SourceID from a raw type
+// name to a symbol ID. Returns the resolved edge and true on success. Other
+// edges pass through unchanged.
+func (c *CallResolver) ResolveEdge(edge Edge, lang Language) (Edge, bool) {
+	if edge.Kind == EdgeKindHasMethod {
+		return c.resolveOwner(edge, lang)
+	}
+	if edge.Kind != EdgeKindCalls {
+		return edge, true
+	}
+
+	name := calleeName(edge.TargetID)
+	candidates := c.defs[name]
+	if len(candidates) == 0 {
+		return edge, false
+	}
+
+	caller := symbolFile(edge.SourceID)
+	scopes := []func(file string) bool{
+		func(file string) bool { return file == caller },
+	}
+	if lang == LangGo {
+		callerDir := path.Dir(caller)
+		scopes = append(scopes, func(file string) bool {
+			return path.Dir(file) == callerDir
+		})
+	}
+	scopes = append(scopes, func(file string) bool {
+		return c.isImported(caller, file, lang)
+	})
+
+	for _, inScope := range scopes {
+		var match []symbolRef
+		for _, ref := range candidates {
+			if inScope(ref.file) {
+				match = append(match, ref)
+			}
+		}
+		switch len(match) {
+		case 0:
+			continue
+		case 1:
+			edge.TargetID = symbolKey(match[0].file, match[0].name)
+			return edge, true
+		default:
+			return edge, false // ambiguous within the narrowest scope
+		}
+	}
+	return edge, false
+}
+
+// ResolveAll resolves a slice of edges, dropping unresolvable CALLS and
+// HAS_METHOD edges. Other edges pass through unchanged.
+func (c *CallResolver) ResolveAll(edges []Edge, lang Language) []Edge {
+	out := make([]Edge, 0, len(edges))
+	for _, e := range edges {
+		resolved, ok := c.ResolveEdge(e, lang)
+		if ok {
+			out = append(out, resolved)
+		}
+	}
+	return out
+}
+
+// resolveOwner resolves a HAS_METHOD edge's raw owner type name against the
+// method's own file, then (Go only) its package directory.
+func (c *CallResolver) resolveOwner(edge Edge, lang Language) (Edge, bool) {
+	i := strings.LastIndex(edge.TargetID, ":")
+	if i == -1 {
+		return edge, false
+	}
+	methodFile := edge.TargetID[:i]
+	name := edge.SourceID
+
+	var match []string
+	for _, ref := range c.defs[name] {
+		if ref.name != name {
+			continue // a qualified symbol, such as a method, cannot own methods
+		}
+		if ref.file == methodFile {
+			edge.SourceID = symbolKey(ref.file, name)
+			return edge, true
+		}
+		if lang == LangGo && path.Dir(ref.file) == path.Dir(methodFile) {
+			match = append(match, ref.file)
+		}
+	}
+	if len(match) != 1 {
+		return edge, false
+	}
+	edge.SourceID = symbolKey(match[0], name)
+	return edge, true
+}
+
+// isImported reports whether file is reachable from caller through a single
+// IMPORTS edge. Go imports name a package, so any file in the directory of
+// an imported file counts.
+func (c *CallResolver) isImported(caller, file string, lang Language) bool {
+	imported := c.imports[caller]
+	if imported[file] {
+		return true
+	}
+	if lang != LangGo {
+		return false
+	}
+	dir := path.Dir(file)
+	for target := range imported {
+		if path.Dir(target) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// symbolFile returns the file part of a "filePath:name" symbol ID, or id
+// itself when it names a file.
+func symbolFile(id string) string {
+	if i := strings.LastIndex(id, ":"); i > 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// calleeName strips receivers, package qualifiers, and path segments from a
+// raw callee expression: "s.repo.Save" → "Save", "User::new" → "new".
+func calleeName(raw string) string {
+	if i := strings.LastIndex(raw, "::"); i != -1 {
+		raw = raw[i+2:]
+	}
+	if i := strings.LastIndex(raw, "."); i != -1 {
+		raw = raw[i+1:]
+	}
+	return raw
+}
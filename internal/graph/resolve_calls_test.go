@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCallResolver_GoFixture parses the Go fixture, with main.go calling
+// NewUserService, and verifies the call lands on service.go:NewUserService.
+func TestCallResolver_GoFixture(t *testing.T) {
+	p := NewTreeSitterParser()
+	defer p.Close()
+
+	mainSrc := []byte(`package project
+
+import "fmt"
+
+// Run is the application entry point.
+func Run() {
+	svc := NewUserService(nil)
+	fmt.Println("starting application", svc)
+}
+`)
+	sources := map[string][]byte{
+		"main.go":    mainSrc,
+		"model.go":   readFixture(t, "testdata/fixtures/go_project/model.go"),
+		"service.go": readFixture(t, "testdata/fixtures/go_project/service.go"),
+	}
+
+	var symbols []SymbolNode
+	var mainEdges []Edge
+	for path, src := range sources {
+		res, err := p.Parse(context.Background(), path, src, LangGo)
+		require.NoError(t, err)
+		symbols = append(symbols, res.Symbols...)
+		if path == "main.go" {
+			mainEdges = res.Edges
+		}
+	}
+
+	c := NewCallResolver(symbols, nil)
+	calls := findEdgesByKind(c.ResolveAll(mainEdges, LangGo), EdgeKindCalls)
+
+	// fmt.Println has no in-repo definition and is dropped.
+	require.Len(t, calls, 1)
+	assert.Equal(t, "main.go:Run", calls[0].SourceID)
+	assert.Equal(t, "service.go:NewUserService", calls[0].TargetID)
+}
+
+func TestCallResolver_ScopeOrder(t *testing.T) {
+	symbols := []SymbolNode{
+		{Name: "helper", FilePath: "src/a.ts"},
+		{Name: "helper", FilePath: "src/b.ts"},
+		{Name: "format", FilePath: "src/b.ts"},
+		{Name: "format", FilePath: "src/c.ts"},
+	}
+	imports := []Edge{
+		{SourceID: "src/a.ts", TargetID: "src/b.ts", Kind: EdgeKindImports},
+	}
+	c := NewCallResolver(symbols, imports)
+
+	tests := []struct {
+		name   string
+		source string
+		callee string
+		want   string
+		wantOK bool
+	}{
+		{"same file wins over import", "src/a.ts", "helper", "src/a.ts:helper", true},
+		{"import disambiguates", "src/a.ts", "format", "src/b.ts:format", true},
+		{"qualified callee", "src/a.ts", "this.helper", "src/a.ts:helper", true},
+		{"ambiguous without import", "src/d.ts", "format", "", false},
+		{"undefined", "src/a.ts", "missing", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edge := Edge{SourceID: tt.source, TargetID: tt.callee, Kind: EdgeKindCalls}
+			got, ok := c.ResolveEdge(edge, LangTypeScript)
+			assert.Equal(t, tt.wantOK, ok)
+			if ok {
+				assert.Equal(t, tt.want, got.TargetID)
+			}
+		})
+	}
+}
+
+func TestCallResolver_GoPackageImport(t *testing.T) {
+	symbols := []SymbolNode{
+		{Name: "Open", FilePath: "store/db.go"},
+		{Name: "Open", FilePath: "cache/cache.go"},
+	}
+	// The import resolver points Go imports at the first file in the package.
+	imports := []Edge{
+		{SourceID: "cmd/main.go", TargetID: "store/aaa.go", Kind: EdgeKindImports},
+	}
+	c := NewCallResolver(symbols, imports)
+
+	got, ok := c.ResolveEdge(Edge{SourceID: "cmd/main.go", TargetID: "store.Open", Kind: EdgeKindCalls}, LangGo)
+	require.True(t, ok)
+	assert.Equal(t, "store/db.go:Open", got.TargetID)
+}
+
+// TestCallResolver_HasMethod verifies that HAS_METHOD owners resolve to the
+// method's own file first, then to another file in the same Go package, and
+// are dropped when the type is not declared in scope.
+func TestCallResolver_HasMethod(t *testing.T) {
+	symbols := []SymbolNode{
+		{Name: "Cache", Kind: SymbolKindType, FilePath: "cache/cache.go"},
+		{Name: "Get", Kind: SymbolKindMethod, FilePath: "cache/get.go"},
+		{Name: "Entry", Kind: SymbolKindType, FilePath: "cache/get.go"},
+		{Name: "Entry", Kind: SymbolKindType, FilePath: "cache/entry.go"},
+		{Name: "Key", Kind: SymbolKindMethod, FilePath: "cache/get.go"},
+		{Name: "Orphan", Kind: SymbolKindType, FilePath: "other/orphan.go"},
+	}
+	c := NewCallResolver(symbols, nil)
+
+	edges := c.ResolveAll([]Edge{
+		{SourceID: "Cache", TargetID: "cache/get.go:Get", Kind: EdgeKindHasMethod},
+		{SourceID: "Entry", TargetID: "cache/get.go:Key", Kind: EdgeKindHasMethod},
+		{SourceID: "Orphan", TargetID: "cache/get.go:Size", Kind: EdgeKindHasMethod},
+	}, LangGo)
+
+	require.Len(t, edges, 2)
+	assert.Equal(t, "cache/cache.go:Cache", edges[0].SourceID)
+	assert.Equal(t, "cache/get.go:Entry", edges[1].SourceID, "same-file declaration wins")
+}
+
+// TestCallResolver_PythonMethod verifies that a call by method name lands on
+// the class-qualified symbol the Python extractor stores.
+func TestCallResolver_PythonMethod(t *testing.T) {
+	symbols := []SymbolNode{
+		{Name: "Repo", Kind: SymbolKindClass, FilePath: "app/repo.py"},
+		{Name: "Repo.save", Kind: SymbolKindMethod, FilePath: "app/repo.py"},
+	}
+	imports := []Edge{
+		{SourceID: "app/main.py", TargetID: "app/repo.py", Kind: EdgeKindImports},
+	}
+	c := NewCallResolver(symbols, imports)
+
+	got, ok := c.ResolveEdge(Edge{SourceID: "app/main.py", TargetID: "repo.save", Kind: EdgeKindCalls}, LangPython)
+	require.True(t, ok)
+	assert.Equal(t, "app/repo.py:Repo.save", got.TargetID)
+
+	got, ok = c.ResolveEdge(Edge{SourceID: "Repo", TargetID: "app/repo.py:Repo.save", Kind: EdgeKindHasMethod}, LangPython)
+	require.True(t, ok)
+	assert.Equal(t, "app/repo.py:Repo", got.SourceID)
+}
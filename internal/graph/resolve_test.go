@@ -1,6 +1,9 @@
 package graph
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -392,3 +395,139 @@ func TestResolver_NoPackageJSON(t *testing.T) {
 		t.Errorf("TargetID = %q, want %q", got.TargetID, "src/utils.ts")
 	}
 }
+
+func TestMultiRootResolver_CrossRootGoImport(t *testing.T) {
+	base := t.TempDir()
+	for dir, module := range map[string]string{
+		"svc":  "example.com/svc",
+		"libs": "example.com/libs",
+	} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(base, dir, "go.mod"), []byte("module "+module+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewMultiRootResolver(base, []string{filepath.Join(base, "svc"), filepath.Join(base, "libs")}, []string{
+		"svc/main.go",
+		"libs/auth/auth.go",
+	})
+
+	edge := Edge{SourceID: "svc/main.go", TargetID: "example.com/libs/auth", Kind: EdgeKindImports}
+	got, ok := r.ResolveEdge(edge, LangGo)
+	if !ok {
+		t.Fatal("expected import of another root's module to resolve")
+	}
+	if got.TargetID != "libs/auth/auth.go" {
+		t.Errorf("TargetID = %q, want %q", got.TargetID, "libs/auth/auth.go")
+	}
+}
+
+// TestResolver_WindowsSeparators feeds the resolver paths as Windows would
+// produce them and checks that resolved edges use forward slashes and match
+// files stored under forward-slash keys.
+func TestResolver_WindowsSeparators(t *testing.T) {
+	r := NewResolver("/tmp/fake", []string{
+		`web\src\app.ts`,
+		`web\src\lib\util.ts`,
+		`pkg\models\__init__.py`,
+		`pkg\views.py`,
+	})
+
+	tests := []struct {
+		name string
+		edge Edge
+		lang Language
+		want string
+	}{
+		{"ts relative", Edge{SourceID: `web\src\app.ts`, TargetID: "./lib/util", Kind: EdgeKindImports}, LangTypeScript, "web/src/lib/util.ts"},
+		{"python relative", Edge{SourceID: `pkg\views.py`, TargetID: ".models", Kind: EdgeKindImports}, LangPython, "pkg/models/__init__.py"},
+	}
+
+	ctx := context.Background()
+	store := NewMemStore()
+	for _, p := range []string{"web/src/app.ts", "web/src/lib/util.ts", "pkg/models/__init__.py", "pkg/views.py"} {
+		if err := store.AddFile(ctx, FileNode{Path: p}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.ResolveEdge(tt.edge, tt.lang)
+			if !ok {
+				t.Fatal("expected resolution to succeed")
+			}
+			if got.TargetID != tt.want {
+				t.Errorf("TargetID = %q, want %q", got.TargetID, tt.want)
+			}
+			if want := NormalizePath(tt.edge.SourceID); got.SourceID != want {
+				t.Errorf("SourceID = %q, want %q", got.SourceID, want)
+			}
+			for _, id := range []string{got.SourceID, got.TargetID} {
+				if f, err := store.GetFile(ctx, id); err != nil || f == nil {
+					t.Errorf("GetFile(%q) = %v, %v; want the stored file", id, f, err)
+				}
+			}
+		})
+	}
+
+	// Stores accept either separator on the way in and out.
+	if err := store.AddFile(ctx, FileNode{Path: `cmd\main.go`}); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := store.GetFile(ctx, "cmd/main.go"); f == nil || f.Path != "cmd/main.go" {
+		t.Errorf("GetFile(cmd/main.go) = %v, want the file stored as cmd\\main.go", f)
+	}
+}
+
+func TestCommonBase(t *testing.T) {
+	tests := []struct {
+		roots []string
+		want  string
+	}{
+		{[]string{"/src/app"}, "/src/app"},
+		{[]string{"/src/app", "/src/lib"}, "/src"},
+		{[]string{"/src/app", "/src/app/vendor"}, "/src/app"},
+		{[]string{"/src/app", "/src/application"}, "/src"},
+		{[]string{"/a/x", "/b/y"}, "/"},
+	}
+	for _, tt := range tests {
+		if got := CommonBase(tt.roots); got != tt.want {
+			t.Errorf("CommonBase(%v) = %q, want %q", tt.roots, got, tt.want)
+		}
+	}
+}
+
+func TestResolveAll_DropsDuplicateAndSelfImports(t *testing.T) {
+	r := NewResolver("/tmp/fake", []string{
+		"src/index.ts",
+		"src/service.ts",
+	})
+
+	edges := []Edge{
+		{SourceID: "src/index.ts", TargetID: "./service", Kind: EdgeKindImports},
+		{SourceID: "src/index.ts", TargetID: "./service.ts", Kind: EdgeKindImports},
+		{SourceID: "src/index.ts", TargetID: "./index", Kind: EdgeKindImports},
+		{SourceID: "src/service.ts", TargetID: "./index", Kind: EdgeKindImports},
+	}
+
+	got := r.ResolveAll(edges, LangTypeScript)
+	want := []Edge{
+		{SourceID: "src/index.ts", TargetID: "src/service.ts", Kind: EdgeKindImports},
+		{SourceID: "src/service.ts", TargetID: "src/index.ts", Kind: EdgeKindImports},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d edges, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].SourceID != want[i].SourceID || got[i].TargetID != want[i].TargetID {
+			t.Errorf("edge %d = %s -> %s, want %s -> %s", i, got[i].SourceID, got[i].TargetID, want[i].SourceID, want[i].TargetID)
+		}
+	}
+	if c := r.Cleanup(); c != (ImportCleanup{Duplicates: 1, SelfImports: 1}) {
+		t.Errorf("Cleanup() = %+v, want 1 duplicate and 1 self-import", c)
+	}
+}
@@ -21,6 +21,7 @@ const (
 	SymbolKindEnum      SymbolKind = "enum"
 	SymbolKindInterface SymbolKind = "interface"
 	SymbolKindVariable  SymbolKind = "variable"
+	SymbolKindConstant  SymbolKind = "constant"
 	SymbolKindMethod    SymbolKind = "method"
 )
 
@@ -34,6 +35,7 @@ const (
 	EdgeKindInherits   EdgeKind = "INHERITS"
 	EdgeKindImplements EdgeKind = "IMPLEMENTS"
 	EdgeKindBelongs    EdgeKind = "BELONGS"
+	EdgeKindHasMethod  EdgeKind = "HAS_METHOD" // type/class symbol → method symbol
 )
 
 // Language identifies a programming language for parsing.
@@ -57,6 +59,17 @@ type FileNode struct {
 	Path     string   `json:"path"`
 	Language Language `json:"language"`
 	LOC      int      `json:"loc"`
+	SLOC     int      `json:"sloc"` // LOC excluding blank and comment-only lines
+
+	// ContentHash is the hex SHA-256 of the file content when it was indexed.
+	// BuildGraph skips files whose stored hash matches, so an interrupted
+	// build resumes where it left off.
+	ContentHash string `json:"contentHash,omitempty"`
+
+	// Generated marks files produced by a code generator; see
+	// IsGeneratedFile. They never join clusters, and AssessImpact and
+	// GetClusters can leave them out with ExcludeGenerated.
+	Generated bool `json:"generated,omitempty"`
 }
 
 // SymbolNode represents a named symbol (function, class, type, etc.).
@@ -67,6 +80,14 @@ type SymbolNode struct {
 	FilePath  string     `json:"filePath"`
 	StartLine int        `json:"startLine"`
 	EndLine   int        `json:"endLine"`
+	// StableID identifies the symbol by its kind and normalized source, not
+	// its location, so it survives moves and renames between builds. Empty
+	// for symbols from parsers that do not compute it. See stableSymbolID.
+	StableID string `json:"stableId,omitempty"`
+	// Decorators lists the decorators applied to the symbol, in source
+	// order and without the leading "@", e.g. "staticmethod" or
+	// "app.route('/')". Only the Python extractor records them.
+	Decorators []string `json:"decorators,omitempty"`
 }
 
 // ClusterNode represents a group of tightly connected files.
@@ -89,12 +110,32 @@ type GraphStats struct {
 	SymbolCount  int `json:"symbolCount"`
 	ClusterCount int `json:"clusterCount"`
 	EdgeCount    int `json:"edgeCount"`
+	SLOC         int `json:"sloc"` // total source lines across all files
+
+	// EdgesByKind breaks EdgeCount down by edge kind. Kinds with no edges
+	// are omitted.
+	EdgesByKind map[EdgeKind]int `json:"edgesByKind,omitempty"`
 }
 
 // DependencyChain is an ordered sequence of nodes forming a dependency path.
 type DependencyChain struct {
-	Nodes []string `json:"nodes"` // node IDs in order
-	Depth int      `json:"depth"`
+	Nodes []string   `json:"nodes"` // node IDs in order
+	Depth int        `json:"depth"`
+	Hops  []ChainHop `json:"hops,omitempty"` // Hops[i] links Nodes[i] to Nodes[i+1]
+}
+
+// ChainHop describes one step of a DependencyChain: the kind of edge that was
+// followed and how many edges of that kind join the two nodes (for example,
+// the number of call sites).
+type ChainHop struct {
+	Kind   EdgeKind `json:"kind"`
+	Weight int      `json:"weight"`
+}
+
+// neighbor is a node reached in one hop, with the hop that reached it.
+type neighbor struct {
+	id  string
+	hop ChainHop
 }
 
 // ImpactResult describes the blast radius of changing a set of files.
@@ -102,4 +143,27 @@ type ImpactResult struct {
 	DirectlyAffected     []string `json:"directlyAffected"`     // files that import changed files
 	TransitivelyAffected []string `json:"transitivelyAffected"` // full downstream closure
 	RiskScore            float64  `json:"riskScore"`            // 0.0–1.0, based on fan-out
+	// Paths, when requested, maps each affected file to a shortest import
+	// path ending at a changed file: the affected file first, then each file
+	// it imports along the way. AssessImpact implementations leave it nil.
+	Paths map[string][]string `json:"paths,omitempty"`
+	// Classes, when changed symbols are given, says for each affected file
+	// whether it calls one of them or only depends on a changed file.
+	// Breaking lists the files classed ImpactUsesChangedSymbol, sorted, so
+	// reports can show them first. AssessImpact implementations leave both
+	// nil.
+	Classes  map[string]ImpactClass `json:"classes,omitempty"`
+	Breaking []string               `json:"breaking,omitempty"`
 }
+
+// ImpactClass describes how an affected file depends on a change.
+type ImpactClass string
+
+const (
+	// ImpactUsesChangedSymbol marks a file that calls a changed symbol; the
+	// change is likely breaking for it.
+	ImpactUsesChangedSymbol ImpactClass = "uses-changed-symbol"
+	// ImpactImportsOnly marks a file that depends on a changed file without
+	// calling any changed symbol; the change is possibly safe for it.
+	ImpactImportsOnly ImpactClass = "imports-only"
+)
@@ -0,0 +1,34 @@
+package graph
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Reasons a source file is skipped instead of parsed.
+const (
+	SkipTooLarge    = "too-large"    // larger than the configured byte limit
+	SkipBinary      = "binary"       // contains NUL bytes
+	SkipInvalidUTF8 = "invalid-utf8" // not valid UTF-8 text
+)
+
+// DefaultMaxFileBytes is the source file size above which indexing skips a
+// file rather than reading and parsing it.
+const DefaultMaxFileBytes = 2 << 20
+
+// binarySniffLen is how many leading bytes CheckSource searches for a NUL,
+// the same heuristic git uses to tell binary files from text.
+const binarySniffLen = 8000
+
+// CheckSource reports why source should not be handed to a parser: SkipBinary
+// when its first bytes contain a NUL, or SkipInvalidUTF8 when it is not valid
+// UTF-8. It returns "" for text that can be parsed.
+func CheckSource(source []byte) string {
+	if bytes.IndexByte(source[:min(len(source), binarySniffLen)], 0) >= 0 {
+		return SkipBinary
+	}
+	if !utf8.Valid(source) {
+		return SkipInvalidUTF8
+	}
+	return ""
+}
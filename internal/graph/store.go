@@ -2,12 +2,19 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"io"
 )
 
+// ErrQueryUnsupported is returned by RunReadQuery on stores without a query
+// language.
+var ErrQueryUnsupported = errors.New("graph: raw queries are not supported by this store")
+
 // Store is the interface for the code intelligence graph backend.
 // Implementations: KuzuStore (production), MemoryStore (testing).
 // All graph DB access goes through this interface (ADR-006).
+// File paths and node IDs are stored and looked up in NormalizePath form,
+// so callers may pass either path separator.
 type Store interface {
 	io.Closer
 
@@ -19,22 +26,47 @@ type Store interface {
 	AddSymbol(ctx context.Context, node SymbolNode) error
 	AddCluster(ctx context.Context, node ClusterNode) error
 	AddEdge(ctx context.Context, edge Edge) error
+	// SetContentHash records the content hash of an existing file node.
+	// BuildGraph sets it only after the file's symbols and edges are stored,
+	// so a build interrupted in between reindexes the file next time.
+	SetContentHash(ctx context.Context, path, hash string) error
+
+	// RemoveFile deletes a file node, the symbols it defines, and every edge
+	// touching either, so a changed file can be reindexed from scratch.
+	RemoveFile(ctx context.Context, path string) error
+	// ClearClusters deletes all clusters and BELONGS edges.
+	ClearClusters(ctx context.Context) error
 
 	// Read operations.
 	GetFile(ctx context.Context, path string) (*FileNode, error)
 	GetSymbol(ctx context.Context, filePath, name string) (*SymbolNode, error)
 	QuerySymbols(ctx context.Context, query string, limit int) ([]SymbolNode, error)
+	GetMethods(ctx context.Context, filePath, typeName string) ([]SymbolNode, error)
 
-	// Graph traversal.
-	GetDependencies(ctx context.Context, nodeID string, direction Direction, maxDepth int) ([]DependencyChain, error)
-	AssessImpact(ctx context.Context, changedFiles []string) (*ImpactResult, error)
-	GetClusters(ctx context.Context) ([]ClusterNode, error)
+	// Graph traversal. GetDependencies follows edges of the given kinds
+	// (IMPORTS when none are given); node IDs are file paths or symbol IDs
+	// depending on the edge kinds traversed.
+	GetDependencies(ctx context.Context, nodeID string, direction Direction, maxDepth int, kinds ...EdgeKind) ([]DependencyChain, error)
+	// GetDependenciesLimited is GetDependencies that stops after visiting
+	// maxNodes nodes (no cap when maxNodes <= 0), independent of depth, and
+	// reports whether the traversal was truncated.
+	GetDependenciesLimited(ctx context.Context, nodeID string, direction Direction, maxDepth, maxNodes int, kinds ...EdgeKind) ([]DependencyChain, bool, error)
+	AssessImpact(ctx context.Context, changedFiles []string, opts ...QueryOption) (*ImpactResult, error)
+	GetClusters(ctx context.Context, opts ...QueryOption) ([]ClusterNode, error)
 
-	// Edge enumeration.
+	// Enumeration. Files are ordered by path and symbols by file path and
+	// name.
+	GetAllFiles(ctx context.Context) ([]FileNode, error)
+	GetAllSymbols(ctx context.Context) ([]SymbolNode, error)
 	GetAllEdges(ctx context.Context) ([]Edge, error)
 
 	// Stats.
 	Stats(ctx context.Context) (*GraphStats, error)
+
+	// RunReadQuery runs a read-only Cypher query and returns its rows.
+	// Mutating statements are rejected; stores without Cypher support
+	// return ErrQueryUnsupported.
+	RunReadQuery(ctx context.Context, cypher string, params map[string]any) ([][]any, error)
 }
 
 // Direction controls dependency traversal direction.
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	tree_sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
@@ -17,35 +19,55 @@ type extractor interface {
 	Extract(root *tree_sitter.Node, source []byte, filePath string) ([]SymbolNode, []Edge)
 }
 
-// TreeSitterParser implements the Parser interface using tree-sitter grammars.
-// A new tree-sitter parser is created per Parse call, so this type is safe for
-// sequential use but individual Parse calls are not thread-safe.
-type TreeSitterParser struct {
+// grammarRegistry holds the tree-sitter languages and extractors shared by
+// every TreeSitterParser. Both are read-only once built.
+type grammarRegistry struct {
 	languages  map[Language]*tree_sitter.Language
 	extractors map[Language]extractor
 }
 
+var (
+	grammarsOnce sync.Once
+	grammars     *grammarRegistry
+)
+
+// sharedGrammars returns the package-level grammar registry, building it on
+// first use.
+func sharedGrammars() *grammarRegistry {
+	grammarsOnce.Do(func() {
+		grammars = &grammarRegistry{
+			languages: map[Language]*tree_sitter.Language{
+				LangGo:         tree_sitter.NewLanguage(tree_sitter_go.Language()),
+				LangTypeScript: tree_sitter.NewLanguage(tree_sitter_typescript.LanguageTypescript()),
+				LangPython:     tree_sitter.NewLanguage(tree_sitter_python.Language()),
+				LangRust:       tree_sitter.NewLanguage(tree_sitter_rust.Language()),
+			},
+			extractors: map[Language]extractor{
+				LangGo:         &goExtractor{},
+				LangTypeScript: &tsExtractor{},
+				LangPython:     &pyExtractor{},
+				LangRust:       &rsExtractor{},
+			},
+		}
+	})
+	return grammars
+}
+
+// TreeSitterParser implements the Parser interface using tree-sitter grammars.
+// Grammars are shared across instances; each instance owns one tree-sitter
+// parser handle, created on first Parse and reused until Close. Parse calls
+// on one instance are serialized.
+type TreeSitterParser struct {
+	*grammarRegistry
+
+	mu     sync.Mutex
+	parser *tree_sitter.Parser
+}
+
 // NewTreeSitterParser creates a TreeSitterParser with Go, TypeScript, Python,
 // and Rust grammars registered.
 func NewTreeSitterParser() *TreeSitterParser {
-	langs := map[Language]*tree_sitter.Language{
-		LangGo:         tree_sitter.NewLanguage(tree_sitter_go.Language()),
-		LangTypeScript: tree_sitter.NewLanguage(tree_sitter_typescript.LanguageTypescript()),
-		LangPython:     tree_sitter.NewLanguage(tree_sitter_python.Language()),
-		LangRust:       tree_sitter.NewLanguage(tree_sitter_rust.Language()),
-	}
-
-	extractors := map[Language]extractor{
-		LangGo:         &goExtractor{},
-		LangTypeScript: &tsExtractor{},
-		LangPython:     &pyExtractor{},
-		LangRust:       &rsExtractor{},
-	}
-
-	return &TreeSitterParser{
-		languages:  langs,
-		extractors: extractors,
-	}
+	return &TreeSitterParser{grammarRegistry: sharedGrammars()}
 }
 
 // Parse extracts symbols and relationships from a single source file.
@@ -60,14 +82,16 @@ func (p *TreeSitterParser) Parse(_ context.Context, path string, source []byte,
 		return nil, fmt.Errorf("no extractor for language: %s", lang)
 	}
 
-	parser := tree_sitter.NewParser()
-	defer parser.Close()
-
-	if err := parser.SetLanguage(tsLang); err != nil {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.parser == nil {
+		p.parser = tree_sitter.NewParser()
+	}
+	if err := p.parser.SetLanguage(tsLang); err != nil {
 		return nil, fmt.Errorf("set language %s: %w", lang, err)
 	}
 
-	tree := parser.Parse(source, nil)
+	tree := p.parser.Parse(source, nil)
 	if tree == nil {
 		return nil, fmt.Errorf("tree-sitter returned nil tree for %s", path)
 	}
@@ -75,14 +99,22 @@ func (p *TreeSitterParser) Parse(_ context.Context, path string, source []byte,
 
 	root := tree.RootNode()
 	symbols, edges := ext.Extract(root, source, path)
+	lines := strings.Split(string(source), "\n")
+	for i := range symbols {
+		symbols[i].StableID = stableSymbolID(symbols[i], lines)
+	}
 
 	loc := countLOC(source)
+	sloc := countSLOC(root, source)
 
 	return &ParseResult{
 		File: FileNode{
-			Path:     path,
-			Language: lang,
-			LOC:      loc,
+			Path:        path,
+			Language:    lang,
+			LOC:         loc,
+			SLOC:        sloc,
+			ContentHash: ContentHash(source),
+			Generated:   IsGeneratedFile(path, source),
 		},
 		Symbols: symbols,
 		Edges:   edges,
@@ -98,11 +130,38 @@ func (p *TreeSitterParser) SupportedLanguages() []Language {
 	return langs
 }
 
-// Close is a no-op because parsers are created per Parse call.
+// Close releases the parser's tree-sitter handle. The shared grammars stay
+// loaded, and a later Parse creates a new handle.
 func (p *TreeSitterParser) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.parser != nil {
+		p.parser.Close()
+		p.parser = nil
+	}
 	return nil
 }
 
+// enclosingSymbol returns the name of the innermost symbol in symbols whose
+// lines span node, or "" if none does. Extractors walk the tree in preorder,
+// so every symbol enclosing node has been extracted by the time node is
+// visited. CALLS edges run from this symbol; a call outside every symbol,
+// such as a module-level call in Python or TypeScript, has no Symbol to
+// start from and is dropped, since CALLS links Symbol to Symbol.
+func enclosingSymbol(symbols []SymbolNode, node *tree_sitter.Node) string {
+	line := int(node.StartPosition().Row) + 1
+	name, span := "", 0
+	for _, sym := range symbols {
+		if sym.StartLine > line || sym.EndLine < line {
+			continue
+		}
+		if s := sym.EndLine - sym.StartLine; name == "" || s < span {
+			name, span = sym.Name, s
+		}
+	}
+	return name
+}
+
 // countLOC counts the number of lines in source by counting newline bytes
 // and adding one for the final line if the source is non-empty.
 func countLOC(source []byte) int {
@@ -111,3 +170,59 @@ func countLOC(source []byte) int {
 	}
 	return bytes.Count(source, []byte{'\n'}) + 1
 }
+
+// commentNodeKinds lists the tree-sitter node kinds that represent comments
+// across the supported grammars. Go, TypeScript, and Python use "comment";
+// Rust distinguishes line and block comments.
+var commentNodeKinds = map[string]bool{
+	"comment":       true,
+	"line_comment":  true,
+	"block_comment": true,
+}
+
+// countSLOC counts source lines: lines that contain at least one
+// non-whitespace byte outside a comment node. Blank and comment-only lines
+// are excluded.
+func countSLOC(root *tree_sitter.Node, source []byte) int {
+	if len(source) == 0 {
+		return 0
+	}
+
+	inComment := make([]bool, len(source))
+	markComments(root, inComment)
+
+	sloc := 0
+	hasCode := false
+	for i, c := range source {
+		if c == '\n' {
+			if hasCode {
+				sloc++
+			}
+			hasCode = false
+			continue
+		}
+		if !inComment[i] && c != ' ' && c != '\t' && c != '\r' {
+			hasCode = true
+		}
+	}
+	if hasCode {
+		sloc++
+	}
+	return sloc
+}
+
+// markComments sets mask[i] for every byte covered by a comment node under n.
+func markComments(n *tree_sitter.Node, mask []bool) {
+	if commentNodeKinds[n.Kind()] {
+		end := min(int(n.EndByte()), len(mask))
+		for i := int(n.StartByte()); i < end; i++ {
+			mask[i] = true
+		}
+		return
+	}
+	for i := uint(0); i < n.ChildCount(); i++ {
+		if child := n.Child(i); child != nil {
+			markComments(child, mask)
+		}
+	}
+}
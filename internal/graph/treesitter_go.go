@@ -41,19 +41,36 @@ func (e *goExtractor) walk(
 	case "method_declaration":
 		if sym := e.extractMethod(node, source, filePath); sym != nil {
 			*symbols = append(*symbols, *sym)
+			if recv := goReceiverType(node, source); recv != "" {
+				*edges = append(*edges, Edge{
+					SourceID: recv,
+					TargetID: symbolKey(filePath, sym.Name),
+					Kind:     EdgeKindHasMethod,
+				})
+			}
 		}
 
 	case "type_declaration":
 		extracted := e.extractTypeDeclaration(node, source, filePath)
 		*symbols = append(*symbols, extracted...)
 
+	case "const_declaration":
+		if isGoTopLevel(node) {
+			*symbols = append(*symbols, e.extractValueDeclaration(node, source, filePath, SymbolKindConstant)...)
+		}
+
+	case "var_declaration":
+		if isGoTopLevel(node) {
+			*symbols = append(*symbols, e.extractValueDeclaration(node, source, filePath, SymbolKindVariable)...)
+		}
+
 	case "import_spec":
 		if edge := e.extractImport(node, source, filePath); edge != nil {
 			*edges = append(*edges, *edge)
 		}
 
 	case "call_expression":
-		if edge := e.extractCall(node, source, filePath); edge != nil {
+		if edge := e.extractCall(node, source, filePath, *symbols); edge != nil {
 			*edges = append(*edges, *edge)
 		}
 	}
@@ -99,6 +116,36 @@ func (e *goExtractor) extractMethod(node *tree_sitter.Node, source []byte, fileP
 	}
 }
 
+// goReceiverType returns the base type name of a method's receiver, stripping
+// pointers and type parameters: "(s *Cache[K, V])" → "Cache". Returns "" if
+// the receiver cannot be determined.
+func goReceiverType(node *tree_sitter.Node, source []byte) string {
+	recv := node.ChildByFieldName("receiver")
+	if recv == nil {
+		return ""
+	}
+	for i := uint(0); i < recv.NamedChildCount(); i++ {
+		param := recv.NamedChild(i)
+		if param == nil || param.Kind() != "parameter_declaration" {
+			continue
+		}
+		typeNode := param.ChildByFieldName("type")
+		for typeNode != nil {
+			switch typeNode.Kind() {
+			case "type_identifier":
+				return typeNode.Utf8Text(source)
+			case "pointer_type", "parenthesized_type":
+				typeNode = typeNode.NamedChild(0)
+			case "generic_type":
+				typeNode = typeNode.ChildByFieldName("type")
+			default:
+				return ""
+			}
+		}
+	}
+	return ""
+}
+
 func (e *goExtractor) extractTypeDeclaration(node *tree_sitter.Node, source []byte, filePath string) []SymbolNode {
 	var result []SymbolNode
 
@@ -144,6 +191,48 @@ func (e *goExtractor) extractTypeSpec(node *tree_sitter.Node, source []byte, fil
 	}
 }
 
+// extractValueDeclaration extracts one symbol per name declared in a const or
+// var declaration, including grouped "const ( ... )" blocks and multi-name
+// specs such as "var a, b = 1, 2".
+func (e *goExtractor) extractValueDeclaration(
+	node *tree_sitter.Node,
+	source []byte,
+	filePath string,
+	symbolKind SymbolKind,
+) []SymbolNode {
+	var result []SymbolNode
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		if child == nil {
+			continue
+		}
+		switch child.Kind() {
+		case "var_spec_list":
+			result = append(result, e.extractValueDeclaration(child, source, filePath, symbolKind)...)
+		case "const_spec", "var_spec":
+			for j := uint(0); j < child.NamedChildCount(); j++ {
+				nameNode := child.NamedChild(j)
+				if nameNode == nil || nameNode.Kind() != "identifier" {
+					continue
+				}
+				name := nameNode.Utf8Text(source)
+				if name == "_" {
+					continue
+				}
+				result = append(result, SymbolNode{
+					Name:      name,
+					Kind:      symbolKind,
+					Exported:  isGoExported(name),
+					FilePath:  filePath,
+					StartLine: int(child.StartPosition().Row) + 1,
+					EndLine:   int(child.EndPosition().Row) + 1,
+				})
+			}
+		}
+	}
+	return result
+}
+
 func (e *goExtractor) extractImport(node *tree_sitter.Node, source []byte, filePath string) *Edge {
 	pathNode := node.ChildByFieldName("path")
 	if pathNode == nil {
@@ -172,7 +261,7 @@ func (e *goExtractor) extractImport(node *tree_sitter.Node, source []byte, fileP
 	}
 }
 
-func (e *goExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string) *Edge {
+func (e *goExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string, symbols []SymbolNode) *Edge {
 	fnNode := node.ChildByFieldName("function")
 	if fnNode == nil {
 		return nil
@@ -193,13 +282,25 @@ func (e *goExtractor) extractCall(node *tree_sitter.Node, source []byte, filePat
 		return nil
 	}
 
+	caller := enclosingSymbol(symbols, node)
+	if caller == "" {
+		return nil
+	}
+
 	return &Edge{
-		SourceID: filePath,
+		SourceID: symbolKey(filePath, caller),
 		TargetID: callee,
 		Kind:     EdgeKindCalls,
 	}
 }
 
+// isGoTopLevel returns true if the node is a direct child of the source file,
+// i.e. a package-level declaration rather than one inside a function body.
+func isGoTopLevel(node *tree_sitter.Node) bool {
+	parent := node.Parent()
+	return parent != nil && parent.Kind() == "source_file"
+}
+
 // isGoExported returns true if the first rune of name is an uppercase letter.
 func isGoExported(name string) bool {
 	r, _ := utf8.DecodeRuneInString(name)
@@ -32,10 +32,25 @@ func (e *pyExtractor) walk(
 
 	switch kind {
 	case "function_definition":
+		// Covers "async def" too: the grammar marks it with an "async"
+		// token rather than a separate node kind.
 		if isPyTopLevel(node) {
-			if sym := e.extractFunction(node, source, filePath); sym != nil {
+			if sym := e.extractFunction(node, source, filePath, SymbolKindFunction); sym != nil {
 				*symbols = append(*symbols, *sym)
 			}
+		} else if owner := pyMethodOwner(node, source); owner != "" {
+			if sym := e.extractFunction(node, source, filePath, SymbolKindMethod); sym != nil {
+				// Qualify the name so that methods of different classes,
+				// and a method and a function of the same name, stay
+				// distinct symbols: "Repo.save".
+				sym.Name = owner + "." + sym.Name
+				*symbols = append(*symbols, *sym)
+				*edges = append(*edges, Edge{
+					SourceID: owner,
+					TargetID: symbolKey(filePath, sym.Name),
+					Kind:     EdgeKindHasMethod,
+				})
+			}
 		}
 
 	case "class_definition":
@@ -45,6 +60,11 @@ func (e *pyExtractor) walk(
 			}
 		}
 
+	case "expression_statement":
+		if isPyTopLevel(node) {
+			*symbols = append(*symbols, e.extractAssignment(node, source, filePath)...)
+		}
+
 	case "decorated_definition":
 		// The actual function_definition or class_definition is a child; we
 		// handle it when we recurse. Skip the decorated_definition itself.
@@ -59,7 +79,7 @@ func (e *pyExtractor) walk(
 		}
 
 	case "call":
-		if edge := e.extractCall(node, source, filePath); edge != nil {
+		if edge := e.extractCall(node, source, filePath, *symbols); edge != nil {
 			*edges = append(*edges, *edge)
 		}
 	}
@@ -73,19 +93,22 @@ func (e *pyExtractor) walk(
 	}
 }
 
-func (e *pyExtractor) extractFunction(node *tree_sitter.Node, source []byte, filePath string) *SymbolNode {
+// extractFunction extracts a function or method definition as a symbol of
+// the given kind, with the decorators applied to it.
+func (e *pyExtractor) extractFunction(node *tree_sitter.Node, source []byte, filePath string, kind SymbolKind) *SymbolNode {
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
 		return nil
 	}
 	name := nameNode.Utf8Text(source)
 	return &SymbolNode{
-		Name:      name,
-		Kind:      SymbolKindFunction,
-		Exported:  isPyExported(name),
-		FilePath:  filePath,
-		StartLine: int(node.StartPosition().Row) + 1,
-		EndLine:   int(node.EndPosition().Row) + 1,
+		Name:       name,
+		Kind:       kind,
+		Exported:   isPyExported(name),
+		FilePath:   filePath,
+		StartLine:  int(node.StartPosition().Row) + 1,
+		EndLine:    int(node.EndPosition().Row) + 1,
+		Decorators: pyDecorators(node, source),
 	}
 }
 
@@ -96,15 +119,63 @@ func (e *pyExtractor) extractClass(node *tree_sitter.Node, source []byte, filePa
 	}
 	name := nameNode.Utf8Text(source)
 	return &SymbolNode{
-		Name:      name,
-		Kind:      SymbolKindClass,
-		Exported:  isPyExported(name),
-		FilePath:  filePath,
-		StartLine: int(node.StartPosition().Row) + 1,
-		EndLine:   int(node.EndPosition().Row) + 1,
+		Name:       name,
+		Kind:       SymbolKindClass,
+		Exported:   isPyExported(name),
+		FilePath:   filePath,
+		StartLine:  int(node.StartPosition().Row) + 1,
+		EndLine:    int(node.EndPosition().Row) + 1,
+		Decorators: pyDecorators(node, source),
 	}
 }
 
+// extractAssignment extracts module-level assignment targets. ALL_CAPS names
+// follow the Python convention for constants; everything else is a variable.
+func (e *pyExtractor) extractAssignment(node *tree_sitter.Node, source []byte, filePath string) []SymbolNode {
+	assign := node.NamedChild(0)
+	if assign == nil || assign.Kind() != "assignment" {
+		return nil
+	}
+	left := assign.ChildByFieldName("left")
+	if left == nil {
+		return nil
+	}
+
+	var targets []*tree_sitter.Node
+	switch left.Kind() {
+	case "identifier":
+		targets = append(targets, left)
+	case "pattern_list", "tuple_pattern":
+		for i := uint(0); i < left.NamedChildCount(); i++ {
+			if child := left.NamedChild(i); child != nil && child.Kind() == "identifier" {
+				targets = append(targets, child)
+			}
+		}
+	}
+
+	var result []SymbolNode
+	for _, target := range targets {
+		name := target.Utf8Text(source)
+		if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") {
+			// Dunder names such as __all__ are module metadata, not symbols.
+			continue
+		}
+		symbolKind := SymbolKindVariable
+		if isPyConstantName(name) {
+			symbolKind = SymbolKindConstant
+		}
+		result = append(result, SymbolNode{
+			Name:      name,
+			Kind:      symbolKind,
+			Exported:  isPyExported(name),
+			FilePath:  filePath,
+			StartLine: int(node.StartPosition().Row) + 1,
+			EndLine:   int(node.EndPosition().Row) + 1,
+		})
+	}
+	return result
+}
+
 func (e *pyExtractor) extractImport(node *tree_sitter.Node, source []byte, filePath string) []Edge {
 	var edges []Edge
 	// import_statement children: "import" keyword then dotted_name(s).
@@ -155,7 +226,7 @@ func (e *pyExtractor) extractFromImport(node *tree_sitter.Node, source []byte, f
 	}
 }
 
-func (e *pyExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string) *Edge {
+func (e *pyExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string, symbols []SymbolNode) *Edge {
 	fnNode := node.ChildByFieldName("function")
 	if fnNode == nil {
 		return nil
@@ -175,8 +246,13 @@ func (e *pyExtractor) extractCall(node *tree_sitter.Node, source []byte, filePat
 		return nil
 	}
 
+	caller := enclosingSymbol(symbols, node)
+	if caller == "" {
+		return nil
+	}
+
 	return &Edge{
-		SourceID: filePath,
+		SourceID: symbolKey(filePath, caller),
 		TargetID: callee,
 		Kind:     EdgeKindCalls,
 	}
@@ -200,6 +276,55 @@ func isPyTopLevel(node *tree_sitter.Node) bool {
 	return false
 }
 
+// pyMethodOwner returns the name of the class a function definition is a
+// method of, or "" if it is not defined directly in the body of a top-level
+// class. Functions nested inside other functions are not methods.
+func pyMethodOwner(node *tree_sitter.Node, source []byte) string {
+	parent := node.Parent()
+	if parent != nil && parent.Kind() == "decorated_definition" {
+		parent = parent.Parent()
+	}
+	if parent == nil || parent.Kind() != "block" {
+		return ""
+	}
+	class := parent.Parent()
+	if class == nil || class.Kind() != "class_definition" || !isPyTopLevel(class) {
+		return ""
+	}
+	nameNode := class.ChildByFieldName("name")
+	if nameNode == nil {
+		return ""
+	}
+	return nameNode.Utf8Text(source)
+}
+
+// pyDecorators returns the decorators of a function or class definition, in
+// source order and without the leading "@", or nil if it has none.
+func pyDecorators(node *tree_sitter.Node, source []byte) []string {
+	parent := node.Parent()
+	if parent == nil || parent.Kind() != "decorated_definition" {
+		return nil
+	}
+	var decorators []string
+	for i := uint(0); i < parent.NamedChildCount(); i++ {
+		child := parent.NamedChild(i)
+		if child == nil || child.Kind() != "decorator" {
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimPrefix(child.Utf8Text(source), "@"))
+		if text != "" {
+			decorators = append(decorators, text)
+		}
+	}
+	return decorators
+}
+
+// isPyConstantName returns true if name is ALL_CAPS: it contains at least one
+// letter and no lowercase letters.
+func isPyConstantName(name string) bool {
+	return strings.ToUpper(name) == name && strings.ToLower(name) != name
+}
+
 // isPyExported returns true if the name does not start with an underscore.
 func isPyExported(name string) bool {
 	return !strings.HasPrefix(name, "_")
@@ -54,6 +54,25 @@ func (e *rsExtractor) walk(
 			*symbols = append(*symbols, *sym)
 		}
 
+	case "const_item":
+		if !isRustLocal(node) {
+			if sym := e.extractNamedSymbol(node, source, filePath, SymbolKindConstant); sym != nil {
+				// Associated consts are qualified by their type or trait,
+				// so that Config::DEFAULT and Limits::DEFAULT stay apart.
+				if owner := rustOwner(node, source); owner != "" {
+					sym.Name = owner + "." + sym.Name
+				}
+				*symbols = append(*symbols, *sym)
+			}
+		}
+
+	case "static_item":
+		if !isRustLocal(node) {
+			if sym := e.extractNamedSymbol(node, source, filePath, SymbolKindVariable); sym != nil {
+				*symbols = append(*symbols, *sym)
+			}
+		}
+
 	case "impl_item":
 		e.extractImpl(node, source, filePath, symbols, edges)
 
@@ -63,7 +82,7 @@ func (e *rsExtractor) walk(
 		}
 
 	case "call_expression":
-		if edge := e.extractCall(node, source, filePath); edge != nil {
+		if edge := e.extractCall(node, source, filePath, *symbols); edge != nil {
 			*edges = append(*edges, *edge)
 		}
 	}
@@ -99,8 +118,9 @@ func (e *rsExtractor) extractNamedSymbol(
 	}
 }
 
-// extractImpl processes an impl_item: extracts methods inside, and detects
-// trait implementations to produce EdgeKindImplements edges.
+// extractImpl processes an impl_item: extracts methods inside, links them to
+// the implementing type with EdgeKindHasMethod edges, and detects trait
+// implementations to produce EdgeKindImplements edges.
 func (e *rsExtractor) extractImpl(
 	node *tree_sitter.Node,
 	source []byte,
@@ -148,9 +168,34 @@ func (e *rsExtractor) extractImpl(
 			StartLine: int(child.StartPosition().Row) + 1,
 			EndLine:   int(child.EndPosition().Row) + 1,
 		})
+		if owner := rustTypeName(typeNode, source); owner != "" {
+			*edges = append(*edges, Edge{
+				SourceID: owner,
+				TargetID: symbolKey(filePath, name),
+				Kind:     EdgeKindHasMethod,
+			})
+		}
 	}
 }
 
+// rustTypeName returns the bare name of an impl's self type, stripping type
+// arguments and path qualifiers: "UserService<R>" → "UserService".
+func rustTypeName(node *tree_sitter.Node, source []byte) string {
+	for node != nil {
+		switch node.Kind() {
+		case "type_identifier":
+			return node.Utf8Text(source)
+		case "generic_type":
+			node = node.ChildByFieldName("type")
+		case "scoped_type_identifier":
+			node = node.ChildByFieldName("name")
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
 func (e *rsExtractor) extractUse(node *tree_sitter.Node, source []byte, filePath string) *Edge {
 	// The use_declaration's argument is typically a scoped_identifier, use_wildcard,
 	// or use_list. We extract the full text as the import path.
@@ -180,7 +225,7 @@ func (e *rsExtractor) extractUse(node *tree_sitter.Node, source []byte, filePath
 	}
 }
 
-func (e *rsExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string) *Edge {
+func (e *rsExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string, symbols []SymbolNode) *Edge {
 	fnNode := node.ChildByFieldName("function")
 	if fnNode == nil {
 		return nil
@@ -202,13 +247,47 @@ func (e *rsExtractor) extractCall(node *tree_sitter.Node, source []byte, filePat
 		return nil
 	}
 
+	caller := enclosingSymbol(symbols, node)
+	if caller == "" {
+		return nil
+	}
+
 	return &Edge{
-		SourceID: filePath,
+		SourceID: symbolKey(filePath, caller),
 		TargetID: callee,
 		Kind:     EdgeKindCalls,
 	}
 }
 
+// rustOwner returns the bare name of the impl's self type or the trait whose
+// body declares node, or "" if node is not an associated item.
+func rustOwner(node *tree_sitter.Node, source []byte) string {
+	parent := node.Parent()
+	if parent == nil || parent.Kind() != "declaration_list" {
+		return ""
+	}
+	owner := parent.Parent()
+	if owner == nil {
+		return ""
+	}
+	switch owner.Kind() {
+	case "impl_item":
+		return rustTypeName(owner.ChildByFieldName("type"), source)
+	case "trait_item":
+		if nameNode := owner.ChildByFieldName("name"); nameNode != nil {
+			return nameNode.Utf8Text(source)
+		}
+	}
+	return ""
+}
+
+// isRustLocal returns true if the node is declared inside a block, such as a
+// const or static item local to a function body.
+func isRustLocal(node *tree_sitter.Node) bool {
+	parent := node.Parent()
+	return parent != nil && parent.Kind() == "block"
+}
+
 // isRustPub checks if a node has a visibility_modifier child with "pub" text.
 func isRustPub(node *tree_sitter.Node) bool {
 	if node.ChildCount() == 0 {
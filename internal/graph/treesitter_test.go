@@ -2,8 +2,10 @@ package graph
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -159,6 +161,14 @@ func TestTreeSitterParser_Go(t *testing.T) {
 		}
 		assert.True(t, found, "should have import edge for fmt")
 
+		// Member edges from the receiver type to each method.
+		members := findEdgesByKind(res.Edges, EdgeKindHasMethod)
+		require.Len(t, members, 2)
+		assert.Equal(t, "UserService", members[0].SourceID)
+		assert.Equal(t, "service.go:GetUser", members[0].TargetID)
+		assert.Equal(t, "UserService", members[1].SourceID)
+		assert.Equal(t, "service.go:CreateUser", members[1].TargetID)
+
 		// At least one call edge
 		calls := findEdgesByKind(res.Edges, EdgeKindCalls)
 		assert.GreaterOrEqual(t, len(calls), 1, "should have at least 1 call edge")
@@ -192,6 +202,218 @@ func TestTreeSitterParser_Go(t *testing.T) {
 		calls := findEdgesByKind(res.Edges, EdgeKindCalls)
 		assert.GreaterOrEqual(t, len(calls), 1, "should have at least 1 call edge")
 	})
+
+	t.Run("package-level const and var", func(t *testing.T) {
+		src := []byte(`package config
+
+const MaxRetries = 3
+
+const (
+	defaultHost, defaultPort = "localhost", 8080
+)
+
+var (
+	Verbose bool
+)
+
+func load() {
+	const local = 1
+	var scratch int
+	_ = scratch
+}
+`)
+		res, err := p.Parse(ctx, "config.go", src, LangGo)
+		require.NoError(t, err)
+
+		maxRetries := findSymbol(res.Symbols, "MaxRetries")
+		require.NotNil(t, maxRetries, "MaxRetries symbol should exist")
+		assert.Equal(t, SymbolKindConstant, maxRetries.Kind)
+		assert.True(t, maxRetries.Exported)
+		assert.Equal(t, 3, maxRetries.StartLine)
+
+		port := findSymbol(res.Symbols, "defaultPort")
+		require.NotNil(t, port, "defaultPort symbol should exist")
+		assert.Equal(t, SymbolKindConstant, port.Kind)
+		assert.False(t, port.Exported)
+		assert.NotNil(t, findSymbol(res.Symbols, "defaultHost"))
+
+		verbose := findSymbol(res.Symbols, "Verbose")
+		require.NotNil(t, verbose, "Verbose symbol should exist")
+		assert.Equal(t, SymbolKindVariable, verbose.Kind)
+
+		assert.Nil(t, findSymbol(res.Symbols, "local"), "function-local const should not be indexed")
+		assert.Nil(t, findSymbol(res.Symbols, "scratch"), "function-local var should not be indexed")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestTreeSitterParser_ConstantsAndVariables
+// ---------------------------------------------------------------------------
+
+func TestTreeSitterParser_ConstantsAndVariables(t *testing.T) {
+	p := NewTreeSitterParser()
+	defer p.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		lang Language
+		src  string
+		want map[string]SymbolKind
+	}{
+		{
+			name: "python",
+			lang: LangPython,
+			src:  "MAX_SIZE = 10\nregistry = {}\n\ndef f():\n    inner = 1\n",
+			want: map[string]SymbolKind{"MAX_SIZE": SymbolKindConstant, "registry": SymbolKindVariable},
+		},
+		{
+			name: "typescript",
+			lang: LangTypeScript,
+			src:  "export const LIMIT = 5;\nlet counter = 0;\nconst handler = () => 1;\n",
+			want: map[string]SymbolKind{"LIMIT": SymbolKindConstant, "counter": SymbolKindVariable, "handler": SymbolKindFunction},
+		},
+		{
+			name: "rust",
+			lang: LangRust,
+			src: "pub const MAX: u32 = 3;\nstatic mut COUNT: u32 = 0;\nfn f() { const LOCAL: u8 = 1; }\n" +
+				"impl<T> Config<T> { pub const DEFAULT: u32 = 1; }\ntrait Limits { const DEFAULT: usize; }\n" +
+				"mod inner { pub const DEPTH: u8 = 2; }\n",
+			want: map[string]SymbolKind{
+				"MAX":            SymbolKindConstant,
+				"COUNT":          SymbolKindVariable,
+				"Config.DEFAULT": SymbolKindConstant,
+				"Limits.DEFAULT": SymbolKindConstant,
+				"DEPTH":          SymbolKindConstant,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := p.Parse(ctx, "file", []byte(tt.src), tt.lang)
+			require.NoError(t, err)
+
+			got := make(map[string]SymbolKind)
+			for _, sym := range res.Symbols {
+				if sym.Kind == SymbolKindConstant || sym.Kind == SymbolKindVariable || sym.Kind == SymbolKindFunction {
+					got[sym.Name] = sym.Kind
+				}
+			}
+			delete(got, "f")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTreeSitterParser_StableIDs(t *testing.T) {
+	p := NewTreeSitterParser()
+	defer p.Close()
+	ctx := context.Background()
+
+	stableID := func(path, src, name string) string {
+		t.Helper()
+		res, err := p.Parse(ctx, path, []byte(src), LangGo)
+		require.NoError(t, err)
+		sym := findSymbol(res.Symbols, name)
+		require.NotNil(t, sym, "symbol %s", name)
+		return sym.StableID
+	}
+
+	original := stableID("a.go", "package p\n\nfunc Double(n int) int {\n\treturn n * 2\n}\n", "Double")
+	require.NotEmpty(t, original)
+
+	moved := stableID("b.go", "package p\n\nimport \"fmt\"\n\nfunc Other() {}\n\nfunc Double(n int)   int {\n    return n * 2\n}\n", "Double")
+	assert.Equal(t, original, moved, "moving and reformatting keeps the StableID")
+
+	renamed := stableID("a.go", "package p\n\nfunc Twice(n int) int {\n\treturn n * 2\n}\n", "Twice")
+	assert.Equal(t, original, renamed, "renaming keeps the StableID")
+
+	edited := stableID("a.go", "package p\n\nfunc Double(n int) int {\n\treturn n * 3\n}\n", "Double")
+	assert.NotEqual(t, original, edited, "changing the body changes the StableID")
+
+	// Qualified names are masked by their unqualified part.
+	method := func(name string) string {
+		t.Helper()
+		src := "class Repo:\n    def " + name + "(self):\n        return 1\n"
+		res, err := p.Parse(ctx, "repo.py", []byte(src), LangPython)
+		require.NoError(t, err)
+		sym := findSymbol(res.Symbols, "Repo."+name)
+		require.NotNil(t, sym, "symbol Repo.%s", name)
+		return sym.StableID
+	}
+	assert.Equal(t, method("save"), method("store"), "renaming a method keeps the StableID")
+}
+
+// TestTreeSitterParser_CallSources verifies that CALLS edges start at the
+// innermost enclosing symbol, and that calls outside every symbol are dropped.
+func TestTreeSitterParser_CallSources(t *testing.T) {
+	p := NewTreeSitterParser()
+	defer p.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		path string
+		lang Language
+		src  string
+		want map[string]string // callee → source
+		// dropped lists calls made outside every symbol, which have no
+		// Symbol to start a CALLS edge from.
+		dropped []string
+	}{
+		{
+			name: "go", path: "a.go", lang: LangGo,
+			src: "package a\n\nfunc Run() {\n\tgo func() {\n\t\thelper()\n\t}()\n}\n\n" +
+				"func (s *S) Do() {\n\ts.step()\n}\n",
+			want: map[string]string{"helper": "a.go:Run", "s.step": "a.go:Do"},
+		},
+		{
+			name: "python", path: "a.py", lang: LangPython,
+			src: "setup()\n\ndef run():\n    helper()\n\n" +
+				"class Repo:\n    def save(self):\n        self.flush()\n",
+			want:    map[string]string{"helper": "a.py:run", "self.flush": "a.py:Repo.save"},
+			dropped: []string{"setup"},
+		},
+		{
+			name: "typescript", path: "a.ts", lang: LangTypeScript,
+			src:     "init();\n\nfunction run() {\n  helper();\n}\n",
+			want:    map[string]string{"helper": "a.ts:run"},
+			dropped: []string{"init"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := p.Parse(ctx, tt.path, []byte(tt.src), tt.lang)
+			require.NoError(t, err)
+			got := make(map[string]string)
+			for _, e := range findEdgesByKind(res.Edges, EdgeKindCalls) {
+				got[e.TargetID] = e.SourceID
+			}
+			assert.Equal(t, tt.want, got)
+			for _, callee := range tt.dropped {
+				assert.NotContains(t, got, callee, "module-level call %q should be dropped", callee)
+			}
+		})
+	}
+}
+
+func TestMatchMovedSymbols(t *testing.T) {
+	before := []SymbolNode{
+		{Name: "Helper", FilePath: "a.go", StableID: "s1"},
+		{Name: "Kept", FilePath: "a.go", StableID: "s2"},
+		{Name: "Dup", FilePath: "a.go", StableID: "s3"},
+		{Name: "Legacy", FilePath: "a.go"},
+	}
+	after := []SymbolNode{
+		{Name: "Helper", FilePath: "b.go", StableID: "s1"},
+		{Name: "Kept", FilePath: "a.go", StableID: "s2"},
+		{Name: "Dup", FilePath: "b.go", StableID: "s3"},
+		{Name: "DupCopy", FilePath: "c.go", StableID: "s3"},
+		{Name: "Legacy", FilePath: "b.go"},
+	}
+
+	assert.Equal(t, map[string]string{"a.go:Helper": "b.go:Helper"}, MatchMovedSymbols(before, after))
 }
 
 // ---------------------------------------------------------------------------
@@ -361,6 +583,63 @@ func TestTreeSitterParser_Python(t *testing.T) {
 		assert.GreaterOrEqual(t, len(calls), 1, "should have at least 1 call edge")
 	})
 
+	t.Run("decorated.py", func(t *testing.T) {
+		src := readFixture(t, "testdata/fixtures/py_project/decorated.py")
+		res, err := p.Parse(ctx, "decorated.py", src, LangPython)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+
+		balance := findSymbol(res.Symbols, "Account.balance")
+		require.NotNil(t, balance, "balance property should exist")
+		assert.Equal(t, SymbolKindMethod, balance.Kind)
+		assert.Equal(t, []string{"property"}, balance.Decorators)
+
+		currency := findSymbol(res.Symbols, "Account.currency")
+		require.NotNil(t, currency, "currency static method should exist")
+		assert.Equal(t, SymbolKindMethod, currency.Kind)
+		assert.Equal(t, []string{"staticmethod", "cached"}, currency.Decorators)
+
+		refresh := findSymbol(res.Symbols, "Account.refresh")
+		require.NotNil(t, refresh, "async method should exist")
+		assert.Equal(t, SymbolKindMethod, refresh.Kind)
+		assert.Empty(t, refresh.Decorators)
+
+		fetch := findSymbol(res.Symbols, "fetch_account")
+		require.NotNil(t, fetch, "async function should exist")
+		assert.Equal(t, SymbolKindFunction, fetch.Kind)
+
+		// Functions nested in other functions are neither functions nor methods.
+		assert.Nil(t, findSymbol(res.Symbols, "wrapper"))
+
+		var owners []string
+		for _, e := range findEdgesByKind(res.Edges, EdgeKindHasMethod) {
+			assert.Equal(t, "Account", e.SourceID)
+			owners = append(owners, e.TargetID)
+		}
+		assert.ElementsMatch(t, []string{
+			"decorated.py:Account.__init__", "decorated.py:Account.balance",
+			"decorated.py:Account.currency", "decorated.py:Account.refresh",
+		}, owners)
+	})
+
+	t.Run("methods are qualified by their class", func(t *testing.T) {
+		src := []byte("class A:\n    def __init__(self):\n        pass\n\n" +
+			"class B:\n    def __init__(self):\n        pass\n\n    def save(self):\n        pass\n\n" +
+			"def save():\n    pass\n")
+		res, err := p.Parse(ctx, "m.py", src, LangPython)
+		require.NoError(t, err)
+
+		kinds := make(map[string]SymbolKind)
+		for _, sym := range res.Symbols {
+			kinds[sym.Name] = sym.Kind
+		}
+		assert.Equal(t, map[string]SymbolKind{
+			"A": SymbolKindClass, "A.__init__": SymbolKindMethod,
+			"B": SymbolKindClass, "B.__init__": SymbolKindMethod, "B.save": SymbolKindMethod,
+			"save": SymbolKindFunction,
+		}, kinds)
+	})
+
 	t.Run("__init__.py", func(t *testing.T) {
 		src := readFixture(t, "testdata/fixtures/py_project/__init__.py")
 		res, err := p.Parse(ctx, "__init__.py", src, LangPython)
@@ -449,6 +728,14 @@ func TestTreeSitterParser_Rust(t *testing.T) {
 		assert.Equal(t, SymbolKindMethod, createUser.Kind)
 		assert.True(t, createUser.Exported)
 
+		// Member edges strip the impl's type arguments: UserService<R>.
+		members := findEdgesByKind(res.Edges, EdgeKindHasMethod)
+		require.Len(t, members, 3)
+		for _, e := range members {
+			assert.Equal(t, "UserService", e.SourceID)
+		}
+		assert.Equal(t, "service.rs:get_user", members[1].TargetID)
+
 		// Import edge from use declaration
 		imports := findEdgesByKind(res.Edges, EdgeKindImports)
 		require.GreaterOrEqual(t, len(imports), 1, "should have at least 1 import edge")
@@ -516,6 +803,44 @@ func TestTreeSitterParser_EmptyFile(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestTreeSitterParser_SLOC
+// ---------------------------------------------------------------------------
+
+func TestTreeSitterParser_SLOC(t *testing.T) {
+	p := NewTreeSitterParser()
+	defer p.Close()
+	ctx := context.Background()
+
+	t.Run("go fixture", func(t *testing.T) {
+		// service.go: 31 lines, 5 blank, 4 doc-comment lines. LOC also
+		// counts the empty line after the trailing newline.
+		src := readFixture(t, "testdata/fixtures/go_project/service.go")
+		res, err := p.Parse(ctx, "service.go", src, LangGo)
+		require.NoError(t, err)
+		assert.Equal(t, 32, res.File.LOC)
+		assert.Equal(t, 22, res.File.SLOC)
+	})
+
+	tests := []struct {
+		lang Language
+		src  string
+		want int
+	}{
+		{LangGo, "package x\n\n/* block\n   comment */\nvar a = 1 // trailing\n", 2},
+		{LangTypeScript, "// lead\nconst a = 1;\n\n/** doc */\nexport const b = a;\n", 2},
+		{LangPython, "# comment\nx = 1\n\n    # indented comment\ny = x  # trailing\n", 2},
+		{LangRust, "//! crate doc\nfn main() {\n    /* inner */\n}\n", 2},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			res, err := p.Parse(ctx, "snippet", []byte(tt.src), tt.lang)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res.File.SLOC)
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestTreeSitterParser_Close
 // ---------------------------------------------------------------------------
@@ -529,3 +854,36 @@ func TestTreeSitterParser_Close(t *testing.T) {
 	err = p.Close()
 	assert.NoError(t, err, "second Close should also not return an error")
 }
+
+// ---------------------------------------------------------------------------
+// TestTreeSitterParser_SharedGrammars
+// ---------------------------------------------------------------------------
+
+func TestTreeSitterParser_SharedGrammars(t *testing.T) {
+	sources := map[Language]string{
+		LangGo:         "package main\n\nfunc Run() {}\n",
+		LangTypeScript: "export function run(): void {}\n",
+		LangPython:     "def run():\n    pass\n",
+		LangRust:       "pub fn run() {}\n",
+	}
+	want := map[Language]string{LangGo: "Run", LangTypeScript: "run", LangPython: "run", LangRust: "run"}
+
+	sharedGrammars() // exclude the one-time grammar load from the timing
+	start := time.Now()
+	parsers := make([]*TreeSitterParser, 100)
+	for i := range parsers {
+		parsers[i] = NewTreeSitterParser()
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond, "constructing 100 parsers should not reload grammars")
+
+	ctx := context.Background()
+	for i, p := range parsers {
+		assert.Same(t, parsers[0].grammarRegistry, p.grammarRegistry)
+		for lang, src := range sources {
+			res, err := p.Parse(ctx, fmt.Sprintf("f%d.%s", i, lang), []byte(src), lang)
+			require.NoError(t, err)
+			require.NotNil(t, findSymbol(res.Symbols, want[lang]), "parser %d, %s", i, lang)
+		}
+		require.NoError(t, p.Close())
+	}
+}
@@ -59,6 +59,9 @@ func (e *tsExtractor) walk(
 	case "lexical_declaration":
 		extracted := e.extractArrowFunctions(node, source, filePath)
 		*symbols = append(*symbols, extracted...)
+		if isTSTopLevel(node) {
+			*symbols = append(*symbols, e.extractBindings(node, source, filePath)...)
+		}
 
 	case "import_statement":
 		if edge := e.extractImport(node, source, filePath); edge != nil {
@@ -66,7 +69,7 @@ func (e *tsExtractor) walk(
 		}
 
 	case "call_expression":
-		if edge := e.extractCall(node, source, filePath); edge != nil {
+		if edge := e.extractCall(node, source, filePath, *symbols); edge != nil {
 			*edges = append(*edges, *edge)
 		}
 	}
@@ -142,6 +145,43 @@ func (e *tsExtractor) extractArrowFunctions(node *tree_sitter.Node, source []byt
 	return result
 }
 
+// extractBindings extracts the non-function bindings of a lexical_declaration:
+// "const" names become constants and "let" names become variables. Arrow
+// functions are handled by extractArrowFunctions, and destructuring patterns
+// are skipped.
+func (e *tsExtractor) extractBindings(node *tree_sitter.Node, source []byte, filePath string) []SymbolNode {
+	symbolKind := SymbolKindVariable
+	if kindNode := node.ChildByFieldName("kind"); kindNode != nil && kindNode.Kind() == "const" {
+		symbolKind = SymbolKindConstant
+	}
+	exported := isTSExported(node)
+
+	var result []SymbolNode
+	for i := uint(0); i < node.ChildCount(); i++ {
+		child := node.Child(i)
+		if child == nil || child.Kind() != "variable_declarator" {
+			continue
+		}
+		if valueNode := child.ChildByFieldName("value"); valueNode != nil && valueNode.Kind() == "arrow_function" {
+			continue
+		}
+		nameNode := child.ChildByFieldName("name")
+		if nameNode == nil || nameNode.Kind() != "identifier" {
+			continue
+		}
+
+		result = append(result, SymbolNode{
+			Name:      nameNode.Utf8Text(source),
+			Kind:      symbolKind,
+			Exported:  exported,
+			FilePath:  filePath,
+			StartLine: int(child.StartPosition().Row) + 1,
+			EndLine:   int(child.EndPosition().Row) + 1,
+		})
+	}
+	return result
+}
+
 func (e *tsExtractor) extractImport(node *tree_sitter.Node, source []byte, filePath string) *Edge {
 	sourceNode := node.ChildByFieldName("source")
 	if sourceNode == nil {
@@ -170,7 +210,7 @@ func (e *tsExtractor) extractImport(node *tree_sitter.Node, source []byte, fileP
 	}
 }
 
-func (e *tsExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string) *Edge {
+func (e *tsExtractor) extractCall(node *tree_sitter.Node, source []byte, filePath string, symbols []SymbolNode) *Edge {
 	fnNode := node.ChildByFieldName("function")
 	if fnNode == nil {
 		return nil
@@ -190,13 +230,28 @@ func (e *tsExtractor) extractCall(node *tree_sitter.Node, source []byte, filePat
 		return nil
 	}
 
+	caller := enclosingSymbol(symbols, node)
+	if caller == "" {
+		return nil
+	}
+
 	return &Edge{
-		SourceID: filePath,
+		SourceID: symbolKey(filePath, caller),
 		TargetID: callee,
 		Kind:     EdgeKindCalls,
 	}
 }
 
+// isTSTopLevel returns true if the node is declared at module scope, either
+// directly or through an export_statement.
+func isTSTopLevel(node *tree_sitter.Node) bool {
+	parent := node.Parent()
+	if parent != nil && parent.Kind() == "export_statement" {
+		parent = parent.Parent()
+	}
+	return parent != nil && parent.Kind() == "program"
+}
+
 // isTSExported checks if a node is exported by looking at whether its parent
 // is an export_statement.
 func isTSExported(node *tree_sitter.Node) bool {
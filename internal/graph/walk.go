@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WalkOptions configures WalkTree.
+type WalkOptions struct {
+	// FollowSymlinks descends into symlinked directories. Each real
+	// directory is visited at most once, so symlink cycles terminate.
+	FollowSymlinks bool
+}
+
+// WalkTree walks the tree rooted at root like filepath.WalkDir, calling fn
+// for each file and directory. Symlinks to directories are only descended
+// when opts.FollowSymlinks is set, and never into a directory already
+// visited. Those not descended, including a real directory first reached
+// through a symlink, are returned as skipped rather than passed to fn.
+// Symlinks to files are passed to fn as-is.
+func WalkTree(root string, opts WalkOptions, fn fs.WalkDirFunc) (skipped []string, err error) {
+	w := &treeWalker{opts: opts, fn: fn, visited: make(map[string]bool)}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		w.visit(root)
+		err = w.walk(root, fs.FileInfoToDirEntry(info))
+	}
+	if errors.Is(err, filepath.SkipDir) || errors.Is(err, filepath.SkipAll) {
+		err = nil
+	}
+	return w.skipped, err
+}
+
+// treeWalker holds the state of a single WalkTree call.
+type treeWalker struct {
+	opts    WalkOptions
+	fn      fs.WalkDirFunc
+	visited map[string]bool // real paths of directories already walked
+	skipped []string
+}
+
+// visit records dir's real path and reports whether it was new.
+func (w *treeWalker) visit(dir string) bool {
+	if !w.opts.FollowSymlinks {
+		return true // without symlinks, every directory is reached once
+	}
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		real = dir
+	}
+	if w.visited[real] {
+		return false
+	}
+	w.visited[real] = true
+	return true
+}
+
+// walk mirrors filepath.WalkDir's recursion, including its SkipDir handling.
+func (w *treeWalker) walk(path string, d fs.DirEntry) error {
+	if err := w.fn(path, d, nil); err != nil || !d.IsDir() {
+		if err == filepath.SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if err = w.fn(path, d, err); err != nil {
+			if err == filepath.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if info, err := os.Stat(child); err == nil && info.IsDir() {
+				if !w.opts.FollowSymlinks || !w.visit(child) {
+					w.skipped = append(w.skipped, child)
+					continue
+				}
+				entry = fs.FileInfoToDirEntry(info)
+			}
+		} else if entry.IsDir() && !w.visit(child) {
+			w.skipped = append(w.skipped, child)
+			continue
+		}
+
+		if err := w.walk(child, entry); err != nil {
+			if err == filepath.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
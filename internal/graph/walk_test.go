@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkTree_Symlinks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "a.go"), []byte("package a\n"), 0o644))
+	other := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(other, "b.go"), []byte("package b\n"), 0o644))
+
+	loop := filepath.Join(dir, "src", "loop")
+	require.NoError(t, os.Symlink(dir, loop))
+	ext := filepath.Join(dir, "ext")
+	require.NoError(t, os.Symlink(other, ext))
+
+	walk := func(opts WalkOptions) ([]string, []string) {
+		var files []string
+		skipped, err := WalkTree(dir, opts, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				rel, _ := filepath.Rel(dir, path)
+				files = append(files, rel)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		sort.Strings(files)
+		sort.Strings(skipped)
+		return files, skipped
+	}
+
+	t.Run("not following skips every symlinked directory", func(t *testing.T) {
+		files, skipped := walk(WalkOptions{})
+		assert.Equal(t, []string{filepath.Join("src", "a.go")}, files)
+		assert.Equal(t, []string{ext, loop}, skipped)
+	})
+
+	t.Run("following descends once and reports the loop", func(t *testing.T) {
+		files, skipped := walk(WalkOptions{FollowSymlinks: true})
+		assert.Equal(t, []string{filepath.Join("ext", "b.go"), filepath.Join("src", "a.go")}, files)
+		assert.Equal(t, []string{loop}, skipped)
+	})
+}
@@ -8,23 +8,63 @@ import "github.com/onedusk/pd/internal/graph"
 
 // BuildGraphInput is the input for the build_graph MCP tool.
 type BuildGraphInput struct {
-	RepoPath    string   `json:"repoPath" jsonschema:"the absolute path to the repository to index"`
-	Languages   []string `json:"languages,omitempty" jsonschema:"languages to index (default: tier-1). Values: go, typescript, python, rust"`
-	ExcludeDirs []string `json:"excludeDirs,omitempty" jsonschema:"directories to exclude from indexing (e.g. vendor, node_modules)"`
+	RepoPath       string   `json:"repoPath,omitempty" jsonschema:"the absolute path to the repository to index"`
+	RepoPaths      []string `json:"repoPaths,omitempty" jsonschema:"additional absolute root directories to index together with repoPath; file paths are recorded relative to the roots' common parent directory"`
+	Languages      []string `json:"languages,omitempty" jsonschema:"languages to index (default: tier-1). Values: go, typescript, python, rust"`
+	ExcludeDirs    []string `json:"excludeDirs,omitempty" jsonschema:"directories to exclude from indexing (e.g. vendor, node_modules)"`
+	MaxFiles       int      `json:"maxFiles,omitempty" jsonschema:"abort if more than this many source files are parsed (default: 50000)"`
+	MaxSymbols     int      `json:"maxSymbols,omitempty" jsonschema:"abort if more than this many symbols are extracted (default: 1000000)"`
+	FollowSymlinks bool     `json:"followSymlinks,omitempty" jsonschema:"descend into symlinked directories, visiting each real directory once (default: false)"`
+	MaxFileBytes   int64    `json:"maxFileBytes,omitempty" jsonschema:"skip source files larger than this many bytes (default: 2097152)"`
+	Include        []string `json:"include,omitempty" jsonschema:"index only files under these subtrees, given as paths or path.Match globs relative to the indexed root (e.g. services/payments or services/*/api); imports into the rest of the tree still resolve and are reported as externalImports"`
+	// A pointer, since a weight of 0 is meaningful.
+	CrossLanguageWeight *float64 `json:"crossLanguageWeight,omitempty" jsonschema:"weight from 0 to 1 of imports between files of different languages when clustering; below 0.5 they do not join clusters (default: 0.25)"`
 }
 
+// Default graph-size limits applied when BuildGraphInput leaves them unset.
+const (
+	DefaultMaxFiles   = 50000
+	DefaultMaxSymbols = 1000000
+)
+
 // BuildGraphOutput is the result of the build_graph MCP tool.
 type BuildGraphOutput struct {
-	Stats graph.GraphStats `json:"stats"`
+	Stats           graph.GraphStats `json:"stats"`
+	SkippedSymlinks []string         `json:"skippedSymlinks,omitempty"` // symlinked directories not descended
+	SkippedFiles    []SkippedFile    `json:"skippedFiles,omitempty"`    // source files not parsed
+	ExternalImports []ExternalImport `json:"externalImports,omitempty"` // imports leaving the Include scope
+	RemovedFiles    []string         `json:"removedFiles,omitempty"`    // indexed files no longer on disk or outside Include
+}
+
+// ExternalImport is an import from an indexed file that resolved to a file
+// outside BuildGraphInput.Include. The target is not indexed, so the import
+// is reported here instead of stored as an IMPORTS edge.
+type ExternalImport struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SkippedFile is a source file BuildGraph left out of the graph without
+// parsing it.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"` // graph.SkipTooLarge, graph.SkipBinary, or graph.SkipInvalidUTF8
 }
 
 // QuerySymbolsInput is the input for the query_symbols MCP tool.
 type QuerySymbolsInput struct {
 	Query string `json:"query" jsonschema:"search query for symbol names (substring match)"`
-	Kind  string `json:"kind,omitempty" jsonschema:"filter by symbol kind: function, class, type, enum, interface, variable, method"`
+	Kind  string `json:"kind,omitempty" jsonschema:"filter by symbol kind: function, class, type, enum, interface, variable, constant, method"`
 	Limit int    `json:"limit,omitempty" jsonschema:"maximum number of results (default: 20)"`
+	Mode  string `json:"mode,omitempty" jsonschema:"substring (default) matches the query inside names; tokens splits the query and names into lowercase camelCase/snake_case words and matches names containing every query word, so 'user service' finds UserService and user_service"`
 }
 
+// Symbol query modes for QuerySymbolsInput.Mode.
+const (
+	QueryModeSubstring = "substring"
+	QueryModeTokens    = "tokens"
+)
+
 // QuerySymbolsOutput is the result of the query_symbols MCP tool.
 type QuerySymbolsOutput struct {
 	Symbols []graph.SymbolNode `json:"symbols"`
@@ -33,19 +73,42 @@ type QuerySymbolsOutput struct {
 
 // GetDependenciesInput is the input for the get_dependencies MCP tool.
 type GetDependenciesInput struct {
-	NodeID    string `json:"nodeId" jsonschema:"file path or qualified symbol name"`
-	Direction string `json:"direction,omitempty" jsonschema:"upstream (what it depends on) or downstream (what depends on it). Default: downstream"`
-	MaxDepth  int    `json:"maxDepth,omitempty" jsonschema:"maximum traversal depth (default: 5)"`
+	NodeID     string           `json:"nodeId" jsonschema:"file path (repo-relative, or absolute under the indexed root), symbol ID (filePath:name), or bare symbol name; symbols resolve to their file when following IMPORTS"`
+	Direction  string           `json:"direction,omitempty" jsonschema:"upstream (what it depends on) or downstream (what depends on it). Default: downstream"`
+	MaxDepth   int              `json:"maxDepth,omitempty" jsonschema:"maximum traversal depth (default: 5)"`
+	MaxNodes   int              `json:"maxNodes,omitempty" jsonschema:"stop after visiting this many nodes, regardless of depth (default: 1000)"`
+	EdgeKinds  []graph.EdgeKind `json:"edgeKinds,omitempty" jsonschema:"relationship types to follow (default: IMPORTS). IMPORTS links files; CALLS, INHERITS, IMPLEMENTS, and HAS_METHOD link symbols (filePath:name); DEFINES links a file to its symbols"`
+	LeavesOnly bool             `json:"leavesOnly,omitempty" jsonschema:"return only the terminal nodes reached (those with no edge to another node in the traversal) and their depth, instead of every chain"`
+}
+
+// DependencyLeaf is a terminal node of a get_dependencies traversal: one with
+// no followed edge to another node the traversal reached.
+type DependencyLeaf struct {
+	Node  string `json:"node"`
+	Depth int    `json:"depth"`
 }
 
 // GetDependenciesOutput is the result of the get_dependencies MCP tool.
 type GetDependenciesOutput struct {
+	// Chains is empty when leavesOnly is set.
 	Chains []graph.DependencyChain `json:"chains"`
+	// Leaves holds the traversal's terminal nodes, ordered by depth then
+	// node ID, when leavesOnly is set.
+	Leaves []DependencyLeaf `json:"leaves,omitempty"`
+	// Truncated is set when the traversal stopped at maxNodes before
+	// visiting every reachable node.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // AssessImpactInput is the input for the assess_impact MCP tool.
 type AssessImpactInput struct {
-	ChangedFiles []string `json:"changedFiles" jsonschema:"list of file paths that will be modified"`
+	ChangedFiles []string `json:"changedFiles" jsonschema:"list of file paths that will be modified, repo-relative or absolute under the indexed root"`
+	IncludePaths bool     `json:"includePaths,omitempty" jsonschema:"also report, for each affected file, a shortest import path to a changed file"`
+	// ChangedSymbols names the symbols being changed, as "filePath:name" IDs
+	// or bare names. When set, affected files are classified by whether they
+	// call one of them.
+	ChangedSymbols   []string `json:"changedSymbols,omitempty" jsonschema:"symbols that will be modified (filePath:name or bare name); classifies affected files as uses-changed-symbol or imports-only"`
+	ExcludeGenerated bool     `json:"excludeGenerated,omitempty" jsonschema:"leave generated files (*.pb.go, DO NOT EDIT headers, ...) out of the affected lists and risk score"`
 }
 
 // AssessImpactOutput is the result of the assess_impact MCP tool.
@@ -54,13 +117,62 @@ type AssessImpactOutput struct {
 }
 
 // GetClustersInput is the input for the get_clusters MCP tool.
-type GetClustersInput struct{}
+type GetClustersInput struct {
+	ExcludeGenerated bool `json:"excludeGenerated,omitempty" jsonschema:"leave generated files out of cluster members, omitting clusters left empty"`
+}
 
 // GetClustersOutput is the result of the get_clusters MCP tool.
 type GetClustersOutput struct {
 	Clusters []graph.ClusterNode `json:"clusters"`
 }
 
+// RecomputeClustersInput is the input for the recompute_clusters MCP tool.
+type RecomputeClustersInput struct{}
+
+// RecomputeClustersOutput is the result of the recompute_clusters MCP tool.
+type RecomputeClustersOutput struct {
+	Clusters []graph.ClusterNode `json:"clusters"`
+}
+
+// ReindexInput is the input for the reindex MCP tool.
+type ReindexInput struct {
+	Languages   []string `json:"languages,omitempty" jsonschema:"languages to index (default: those of the last build_graph, else tier-1). Values: go, typescript, python, rust"`
+	ExcludeDirs []string `json:"excludeDirs,omitempty" jsonschema:"directories to exclude from indexing (default: those of the last build_graph)"`
+}
+
+// ReindexOutput is the result of the reindex MCP tool.
+type ReindexOutput struct {
+	Before graph.GraphStats `json:"before"`
+	After  graph.GraphStats `json:"after"`
+	// Incremental is set when the graph already held files, so only new
+	// and changed files were parsed.
+	Incremental bool     `json:"incremental"`
+	Removed     []string `json:"removed,omitempty"` // indexed files no longer on disk
+}
+
+// GetTypeMethodsInput is the input for the get_type_methods MCP tool.
+type GetTypeMethodsInput struct {
+	TypeName string `json:"typeName" jsonschema:"name of the type, struct, or class whose methods to list"`
+	FilePath string `json:"filePath,omitempty" jsonschema:"file declaring the type, repo-relative or absolute under the indexed root; required only when the name is declared in more than one file"`
+}
+
+// GetTypeMethodsOutput is the result of the get_type_methods MCP tool.
+type GetTypeMethodsOutput struct {
+	Type    graph.SymbolNode   `json:"type"`
+	Methods []graph.SymbolNode `json:"methods"`
+}
+
+// GraphQueryInput is the input for the graph_query MCP tool.
+type GraphQueryInput struct {
+	Query  string         `json:"query" jsonschema:"read-only Cypher query starting with MATCH, OPTIONAL MATCH, WITH, UNWIND, or RETURN"`
+	Params map[string]any `json:"params,omitempty" jsonschema:"query parameters referenced as $name"`
+}
+
+// GraphQueryOutput is the result of the graph_query MCP tool.
+type GraphQueryOutput struct {
+	Rows [][]any `json:"rows"`
+}
+
 // GenerateDiagramInput is the input for the generate_diagram MCP tool.
 type GenerateDiagramInput struct{}
 
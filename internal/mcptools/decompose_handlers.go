@@ -145,6 +145,8 @@ func (s *DecomposeService) ListDecompositions(
 
 // WriteStage validates, merges, and writes stage content generated by Claude.
 // It uses the orchestrator's MergePlan and CheckCoherence to ensure consistency.
+// When input.Content holds a complete stage document it is written as-is and
+// its level-2 sections are checked for coherence instead.
 func (s *DecomposeService) WriteStage(
 	_ context.Context,
 	_ *mcp.CallToolRequest,
@@ -156,44 +158,64 @@ func (s *DecomposeService) WriteStage(
 			Message: fmt.Sprintf("stage must be 0-4, got %d", input.Stage),
 		}, fmt.Errorf("invalid stage number: %d", input.Stage)
 	}
-	if len(input.Sections) == 0 {
+	if len(input.Sections) == 0 && strings.TrimSpace(input.Content) == "" {
 		return nil, WriteStageOutput{
 			Status:  "failed",
-			Message: "at least one section is required",
+			Message: "either content or at least one section is required",
 		}, fmt.Errorf("no sections provided")
 	}
 
 	stage := orchestrator.Stage(input.Stage)
 
 	// Convert input sections to orchestrator.Section for merge + coherence.
-	sections := make([]orchestrator.Section, len(input.Sections))
-	for i, sec := range input.Sections {
-		sections[i] = orchestrator.Section{
-			Name:    sec.Name,
-			Content: sec.Content,
-			Agent:   "claude",
+	var sections []orchestrator.Section
+	if len(input.Sections) > 0 {
+		sections = make([]orchestrator.Section, len(input.Sections))
+		for i, sec := range input.Sections {
+			sections[i] = orchestrator.Section{
+				Name:    sec.Name,
+				Content: sec.Content,
+				Agent:   "claude",
+			}
 		}
+	} else {
+		sections = orchestrator.SplitStageSections(input.Content, "claude")
 	}
 
-	// Get the merge plan for this stage.
-	plan := orchestrator.MergePlanForStage(stage)
-
-	// Run coherence check.
-	issues, _ := orchestrator.CheckCoherence(sections)
+	// Run coherence check, noting when the stage was too large for the
+	// full one.
 	var issueStrs []string
-	for _, iss := range issues {
-		issueStrs = append(issueStrs, iss.Description)
+	if !s.cfg.SkipCoherence {
+		issues, reduced, err := orchestrator.CheckCoherenceLimit(sections, s.cfg.CoherenceMaxSections)
+		if err != nil {
+			issueStrs = append(issueStrs, fmt.Sprintf("coherence check failed: %v", err))
+		}
+		for _, iss := range issues {
+			issueStrs = append(issueStrs, iss.Description)
+		}
+		if reduced {
+			issueStrs = append(issueStrs, fmt.Sprintf(
+				"stage has %d sections; ran a reduced coherence check that reports each conflicting dependency once",
+				len(sections)))
+		}
 	}
 
-	// Merge sections according to plan order.
-	merger := orchestrator.NewMerger(plan)
-	merged, err := merger.Merge(sections)
-	if err != nil {
-		return nil, WriteStageOutput{
-			Status:          "failed",
-			Message:         fmt.Sprintf("merge failed: %v", err),
-			CoherenceIssues: issueStrs,
-		}, nil
+	merged := input.Content
+	if len(input.Sections) > 0 {
+		// Merge sections according to the stage's plan order.
+		merger := orchestrator.NewMerger(orchestrator.MergePlanForStage(stage))
+		var err error
+		merged, err = merger.Merge(sections)
+		if err != nil {
+			return nil, WriteStageOutput{
+				Status:          "failed",
+				Message:         fmt.Sprintf("merge failed: %v", err),
+				CoherenceIssues: issueStrs,
+			}, nil
+		}
+		for _, iss := range merger.Warnings() {
+			issueStrs = append(issueStrs, iss.Description)
+		}
 	}
 
 	// Determine output path.
@@ -201,23 +223,24 @@ func (s *DecomposeService) WriteStage(
 	if name == "" {
 		name = s.cfg.Name
 	}
-	outputDir := filepath.Join(s.cfg.ProjectRoot, "docs", "decompose", name)
+	cfg := s.cfg
+	cfg.Name = name
+	cfg.OutputDir = filepath.Join(s.cfg.ProjectRoot, "docs", "decompose", name)
 	if stage == orchestrator.StageDevelopmentStandards {
-		outputDir = filepath.Join(s.cfg.ProjectRoot, "docs", "decompose")
+		// Stage 0 is shared by all decompositions and always lives flat at
+		// the docs/decompose root.
+		cfg.OutputDir = filepath.Join(s.cfg.ProjectRoot, "docs", "decompose")
+		cfg.LayoutMode = orchestrator.LayoutFlat
 	}
-	outPath := filepath.Join(outputDir, fmt.Sprintf("stage-%d-%s.md", int(stage), stage.String()))
 
-	// Create directory and write file.
-	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
-		return nil, WriteStageOutput{
-			Status:  "failed",
-			Message: fmt.Sprintf("mkdir: %v", err),
-		}, nil
-	}
-	if err := os.WriteFile(outPath, []byte(merged), 0o644); err != nil {
+	// Write through the pipeline's layout so nested mode and its index
+	// are honored.
+	outPath, err := orchestrator.WriteStageFile(cfg, stage, merged)
+	if err != nil {
 		return nil, WriteStageOutput{
-			Status:  "failed",
-			Message: fmt.Sprintf("write: %v", err),
+			Status:          "failed",
+			Message:         err.Error(),
+			CoherenceIssues: issueStrs,
 		}, nil
 	}
 
@@ -62,7 +62,8 @@ type SectionInput struct {
 type WriteStageInput struct {
 	Name     string         `json:"name" jsonschema:"decomposition name (kebab-case)"`
 	Stage    int            `json:"stage" jsonschema:"pipeline stage (0-4)"`
-	Sections []SectionInput `json:"sections" jsonschema:"ordered list of sections with name and content"`
+	Sections []SectionInput `json:"sections,omitempty" jsonschema:"ordered list of sections with name and content"`
+	Content  string         `json:"content,omitempty" jsonschema:"complete stage markdown, written as-is (alternative to sections)"`
 }
 
 // WriteStageOutput is the result of the write_stage MCP tool.
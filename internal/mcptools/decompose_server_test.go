@@ -111,6 +111,75 @@ func TestDecomposeService_GetStatus_WithStages(t *testing.T) {
 	assert.Equal(t, 2, out.NextStage)
 }
 
+func TestDecomposeService_WriteStage_Content(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := orchestrator.Config{Name: "myproject", ProjectRoot: tmpDir}
+	svc := NewDecomposeService(newMockOrchestrator(), cfg)
+
+	content := "# Design Pack\n\n## Assumptions\n\nBuilt on Go 1.22.\n\n## Data Model\n\nRequires Go 1.21 for generics.\n"
+	_, out, err := svc.WriteStage(context.Background(), nil, WriteStageInput{
+		Name:    "myproject",
+		Stage:   1,
+		Content: content,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "completed", out.Status)
+
+	want := filepath.Join(tmpDir, "docs", "decompose", "myproject", "stage-1-design-pack.md")
+	assert.Equal(t, []string{want}, out.FilesWritten)
+	data, err := os.ReadFile(want)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+
+	require.Len(t, out.CoherenceIssues, 1)
+	assert.Contains(t, out.CoherenceIssues[0], `dependency "go" has conflicting versions`)
+
+	t.Run("coherent content reports no issues", func(t *testing.T) {
+		_, out, err := svc.WriteStage(context.Background(), nil, WriteStageInput{
+			Name:    "myproject",
+			Stage:   2,
+			Content: "## Types\n\nGo 1.22 throughout.\n",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "completed", out.Status)
+		assert.Empty(t, out.CoherenceIssues)
+	})
+
+	t.Run("large stages note the reduced coherence check", func(t *testing.T) {
+		small := NewDecomposeService(newMockOrchestrator(), orchestrator.Config{
+			Name: "myproject", ProjectRoot: tmpDir, CoherenceMaxSections: 1,
+		})
+		_, out, err := small.WriteStage(context.Background(), nil, WriteStageInput{
+			Name:    "myproject",
+			Stage:   1,
+			Content: content,
+		})
+		require.NoError(t, err)
+		require.Len(t, out.CoherenceIssues, 2)
+		assert.Contains(t, out.CoherenceIssues[0], `"go"`)
+		assert.Contains(t, out.CoherenceIssues[1], "ran a reduced coherence check")
+	})
+
+	t.Run("skipCoherence disables the check", func(t *testing.T) {
+		skip := NewDecomposeService(newMockOrchestrator(), orchestrator.Config{
+			Name: "myproject", ProjectRoot: tmpDir, SkipCoherence: true,
+		})
+		_, out, err := skip.WriteStage(context.Background(), nil, WriteStageInput{
+			Name:    "myproject",
+			Stage:   1,
+			Content: content,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, out.CoherenceIssues)
+	})
+
+	t.Run("missing content and sections fails", func(t *testing.T) {
+		_, out, err := svc.WriteStage(context.Background(), nil, WriteStageInput{Name: "myproject", Stage: 1})
+		require.Error(t, err)
+		assert.Equal(t, "failed", out.Status)
+	})
+}
+
 func TestDecomposeService_ListDecompositions_Empty(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfg := orchestrator.Config{ProjectRoot: tmpDir}
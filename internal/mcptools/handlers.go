@@ -2,11 +2,15 @@ package mcptools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/onedusk/pd/internal/export"
 	"github.com/onedusk/pd/internal/graph"
@@ -18,6 +22,13 @@ type CodeIntelService struct {
 	store       graph.Store
 	parser      graph.Parser
 	projectRoot string // used for persisting the graph to disk
+	allowQuery  bool   // expose the graph_query tool; see SetAllowGraphQuery
+	parseCache  *graph.ParseCache
+
+	mu        sync.Mutex        // guards lastBuild, indexBase, and moved
+	lastBuild *BuildGraphInput  // options of the last successful BuildGraph, reused by Reindex
+	indexBase string            // directory stored file paths are relative to; see storedPath
+	moved     map[string]string // symbol ID from an earlier build → its current ID; see recordMoved
 }
 
 // NewCodeIntelService creates a CodeIntelService with the given store and parser.
@@ -25,11 +36,24 @@ func NewCodeIntelService(store graph.Store, parser graph.Parser) *CodeIntelServi
 	return &CodeIntelService{store: store, parser: parser}
 }
 
-// SetProjectRoot sets the project root used for graph persistence.
+// SetProjectRoot sets the project root used for graph persistence and
+// indexed by Reindex.
 func (s *CodeIntelService) SetProjectRoot(root string) {
 	s.projectRoot = root
 }
 
+// SetAllowGraphQuery opts in to the graph_query tool, which runs read-only
+// Cypher against the graph. It must be set before the MCP server is created.
+func (s *CodeIntelService) SetAllowGraphQuery(allow bool) {
+	s.allowQuery = allow
+}
+
+// SetParseCache makes BuildGraph reuse parse results for file contents it has
+// already parsed, keyed by language and content hash. Nil disables caching.
+func (s *CodeIntelService) SetParseCache(cache *graph.ParseCache) {
+	s.parseCache = cache
+}
+
 // extToLanguage maps file extensions to graph.Language.
 var extToLanguage = map[string]graph.Language{
 	".go":  graph.LangGo,
@@ -39,6 +63,25 @@ var extToLanguage = map[string]graph.Language{
 	".rs":  graph.LangRust,
 }
 
+// GraphLimitError is returned by BuildGraph when the repository exceeds the
+// configured maxFiles or maxSymbols limit.
+type GraphLimitError struct {
+	Limit string // "maxFiles" or "maxSymbols"
+	Max   int
+	Dir   string // directory being indexed when the limit tripped
+}
+
+func (e *GraphLimitError) Error() string {
+	what := "source files"
+	if e.Limit == "maxSymbols" {
+		what = "symbols"
+	}
+	return fmt.Sprintf(
+		"graph too large: more than %d %s (%s limit reached while indexing %s); "+
+			"add generated or third-party directories (e.g. node_modules, vendor, dist) to excludeDirs, or raise %s",
+		e.Max, what, e.Limit, e.Dir, e.Limit)
+}
+
 // BuildGraph walks a repository, parses source files, populates the graph store,
 // and runs clustering. Returns graph statistics.
 func (s *CodeIntelService) BuildGraph(
@@ -46,16 +89,9 @@ func (s *CodeIntelService) BuildGraph(
 	_ *mcp.CallToolRequest,
 	input BuildGraphInput,
 ) (*mcp.CallToolResult, BuildGraphOutput, error) {
-	if input.RepoPath == "" {
-		return nil, BuildGraphOutput{}, fmt.Errorf("repoPath is required")
-	}
-
-	info, err := os.Stat(input.RepoPath)
+	roots, base, err := indexRoots(input)
 	if err != nil {
-		return nil, BuildGraphOutput{}, fmt.Errorf("cannot access repoPath: %w", err)
-	}
-	if !info.IsDir() {
-		return nil, BuildGraphOutput{}, fmt.Errorf("repoPath is not a directory: %s", input.RepoPath)
+		return nil, BuildGraphOutput{}, err
 	}
 
 	// Build allowed language set.
@@ -76,19 +112,52 @@ func (s *CodeIntelService) BuildGraph(
 		excludeSet[d] = true
 	}
 
+	inScope, err := includeMatcher(input.Include)
+	if err != nil {
+		return nil, BuildGraphOutput{}, err
+	}
+
+	maxFiles := input.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+	maxSymbols := input.MaxSymbols
+	if maxSymbols <= 0 {
+		maxSymbols = DefaultMaxSymbols
+	}
+	maxFileBytes := input.MaxFileBytes
+	if maxFileBytes <= 0 {
+		maxFileBytes = graph.DefaultMaxFileBytes
+	}
+	var clusterOpts []graph.ClusterOption
+	if w := input.CrossLanguageWeight; w != nil {
+		if *w < 0 || *w > 1 {
+			return nil, BuildGraphOutput{}, fmt.Errorf("crossLanguageWeight %v is not between 0 and 1", *w)
+		}
+		clusterOpts = append(clusterOpts, graph.WithCrossLanguageWeight(*w))
+	}
+
 	if err := s.store.InitSchema(ctx); err != nil {
 		return nil, BuildGraphOutput{}, fmt.Errorf("init schema: %w", err)
 	}
 
-	// Pass 1: parse all files, collecting results.
+	// Pass 1: parse new and changed files, collecting results. A file whose
+	// content hash matches its stored FileNode was indexed by an earlier,
+	// possibly interrupted, build and is not parsed again.
 	type parseEntry struct {
 		result *graph.ParseResult
 		lang   graph.Language
 	}
 	var entries []parseEntry
+	var unchanged []graph.FileNode
+	var changed []string
+	var skippedFiles []SkippedFile
+	var contextPaths []string // source files outside the Include scope
+	symbolCount := 0
+	seen := make(map[string]bool) // guards against overlapping roots
 
 	fmt.Fprintf(os.Stderr, "Scanning files...\n")
-	walkErr := filepath.WalkDir(input.RepoPath, func(path string, d fs.DirEntry, err error) error {
+	walk := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil // skip inaccessible paths
 		}
@@ -106,34 +175,155 @@ func (s *CodeIntelService) BuildGraph(
 			return nil
 		}
 
+		relPath, err := filepath.Rel(base, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = graph.NormalizePath(relPath)
+		if seen[relPath] {
+			return nil
+		}
+		seen[relPath] = true
+
+		// Files outside the scope are not read, but their paths let
+		// imports from the scope resolve into them.
+		if !inScope(relPath) {
+			contextPaths = append(contextPaths, relPath)
+			return nil
+		}
+
+		if len(entries)+len(unchanged) >= maxFiles {
+			return &GraphLimitError{Limit: "maxFiles", Max: maxFiles, Dir: filepath.Dir(path)}
+		}
+
+		// Check the size before reading so a huge file is never loaded.
+		if info, err := d.Info(); err == nil && info.Size() > maxFileBytes {
+			skippedFiles = append(skippedFiles, SkippedFile{Path: relPath, Reason: graph.SkipTooLarge})
+			return nil
+		}
 		source, err := os.ReadFile(path)
 		if err != nil {
 			return nil // skip unreadable files
 		}
+		if reason := graph.CheckSource(source); reason != "" {
+			skippedFiles = append(skippedFiles, SkippedFile{Path: relPath, Reason: reason})
+			return nil
+		}
 
-		relPath, err := filepath.Rel(input.RepoPath, path)
+		hash := graph.ContentHash(source)
+		existing, err := s.store.GetFile(ctx, relPath)
 		if err != nil {
-			relPath = path
+			return fmt.Errorf("get file %s: %w", relPath, err)
+		}
+		if existing != nil && existing.ContentHash == hash {
+			unchanged = append(unchanged, *existing)
+			return nil
 		}
 
-		result, err := s.parser.Parse(ctx, relPath, source, lang)
+		result, err := s.parse(ctx, relPath, source, lang, hash)
 		if err != nil {
 			return nil // skip unparseable files
 		}
 
+		symbolCount += len(result.Symbols)
+		if symbolCount > maxSymbols {
+			return &GraphLimitError{Limit: "maxSymbols", Max: maxSymbols, Dir: filepath.Dir(path)}
+		}
+
+		if existing != nil {
+			changed = append(changed, relPath)
+		}
 		entries = append(entries, parseEntry{result: result, lang: lang})
 		return nil
-	})
+	}
+	var walkErr error
+	var skippedLinks []string
+	for _, root := range roots {
+		skipped, err := graph.WalkTree(root, graph.WalkOptions{FollowSymlinks: input.FollowSymlinks}, walk)
+		skippedLinks = append(skippedLinks, skipped...)
+		if walkErr = err; walkErr != nil {
+			break
+		}
+	}
+	var limitErr *GraphLimitError
+	if errors.As(walkErr, &limitErr) {
+		return nil, BuildGraphOutput{}, limitErr
+	}
 	if walkErr != nil {
 		return nil, BuildGraphOutput{}, fmt.Errorf("walk: %w", walkErr)
 	}
-	fmt.Fprintf(os.Stderr, "Parsed %d files\n", len(entries))
+	fmt.Fprintf(os.Stderr, "Parsed %d files (%d unchanged)\n", len(entries), len(unchanged))
+	for _, link := range skippedLinks {
+		fmt.Fprintf(os.Stderr, "Skipped symlinked directory %s\n", link)
+	}
+	for _, f := range skippedFiles {
+		fmt.Fprintf(os.Stderr, "Skipped %s (%s)\n", f.Path, f.Reason)
+	}
+
+	// Files deleted from disk since an earlier build, and with Include
+	// files outside the scope, are pruned too, unless that build indexed a
+	// different directory: stored paths are relative to it, so none of them
+	// would be found under base.
+	var deleted []string
+	outOfScope := make(map[string]bool)
+	if prev := s.lastIndexBase(); prev == "" || sameDir(prev, base) {
+		if deleted, err = s.staleFiles(ctx, base, inScope, outOfScope); err != nil {
+			return nil, BuildGraphOutput{}, err
+		}
+		if len(deleted) > 0 {
+			fmt.Fprintf(os.Stderr, "Removing %d deleted or out-of-scope files\n", len(deleted))
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Not removing deleted files: the graph was last built from %s\n", prev)
+	}
+
+	// Drop the stale copies of changed and deleted files, remembering the
+	// edges that unchanged files point into them so they can be restored
+	// below, following symbols that moved to another file.
+	inbound, targets, err := removeChangedFiles(ctx, s.store, append(changed, deleted...))
+	if err != nil {
+		return nil, BuildGraphOutput{}, err
+	}
+	// Imports of unchanged files into pruned out-of-scope files now leave
+	// the scope and are reported like those of the files parsed below.
+	var external []ExternalImport
+	for _, e := range inbound {
+		if e.Kind == graph.EdgeKindImports && outOfScope[e.TargetID] {
+			external = append(external, ExternalImport{From: e.SourceID, To: e.TargetID})
+		}
+	}
+
+	// Start from what earlier builds already indexed, so new and changed
+	// files resolve their imports and calls against the whole repository.
+	files := append([]graph.FileNode(nil), unchanged...)
+	knownPaths := make([]string, 0, len(unchanged)+len(entries))
+	for _, f := range unchanged {
+		knownPaths = append(knownPaths, f.Path)
+	}
+	var allSymbols []graph.SymbolNode
+	var importEdges []graph.Edge
+	if len(unchanged) > 0 {
+		if allSymbols, err = s.store.QuerySymbols(ctx, "", maxSymbols); err != nil {
+			return nil, BuildGraphOutput{}, fmt.Errorf("query symbols: %w", err)
+		}
+		edges, err := s.store.GetAllEdges(ctx)
+		if err != nil {
+			return nil, BuildGraphOutput{}, fmt.Errorf("get edges: %w", err)
+		}
+		for _, e := range edges {
+			if e.Kind == graph.EdgeKindImports {
+				importEdges = append(importEdges, e)
+			}
+		}
+	}
 
 	// Pass 2: store all files first (needed for KuzuDB MATCH on IMPORTS edges).
-	var files []graph.FileNode
-	knownPaths := make([]string, 0, len(entries))
+	// Their content hashes are recorded only once everything else is stored,
+	// so that a build interrupted before then reparses them.
 	for i, e := range entries {
-		if err := s.store.AddFile(ctx, e.result.File); err != nil {
+		node := e.result.File
+		node.ContentHash = ""
+		if err := s.store.AddFile(ctx, node); err != nil {
 			return nil, BuildGraphOutput{}, fmt.Errorf("add file %s: %w", e.result.File.Path, err)
 		}
 		files = append(files, e.result.File)
@@ -143,18 +333,53 @@ func (s *CodeIntelService) BuildGraph(
 		}
 	}
 
-	// Build resolver to rewrite raw import specifiers into repo-relative paths.
-	resolver := graph.NewResolver(input.RepoPath, knownPaths)
+	// Build resolver to rewrite raw import specifiers into repo-relative
+	// paths. With several roots, imports may resolve across them.
+	indexed := make(map[string]bool, len(knownPaths))
+	for _, p := range knownPaths {
+		indexed[p] = true
+	}
+	resolver := graph.NewMultiRootResolver(base, roots, append(knownPaths, contextPaths...))
+
+	// Resolve imports first so the call resolver can scope lookups by them.
+	// Imports that resolve outside the Include scope are reported rather
+	// than stored, since their targets have no File node.
+	resolvedByEntry := make([][]graph.Edge, len(entries))
+	for i, e := range entries {
+		allSymbols = append(allSymbols, e.result.Symbols...)
+		resolved := resolver.ResolveAll(e.result.Edges, e.lang)
+		kept := resolved[:0]
+		for _, edge := range resolved {
+			if edge.Kind == graph.EdgeKindImports && !indexed[edge.TargetID] {
+				external = append(external, ExternalImport{From: edge.SourceID, To: edge.TargetID})
+				continue
+			}
+			kept = append(kept, edge)
+		}
+		resolvedByEntry[i] = kept
+		importEdges = append(importEdges, kept...)
+	}
+	if c := resolver.Cleanup(); c.Duplicates > 0 || c.SelfImports > 0 {
+		fmt.Fprintf(os.Stderr, "Dropped %d duplicate and %d self-referencing imports\n", c.Duplicates, c.SelfImports)
+	}
+	callResolver := graph.NewCallResolver(allSymbols, importEdges)
 
-	// Store symbols and resolved edges.
+	// Store symbols, then resolved edges: Kuzu only links existing nodes,
+	// so every symbol must be in place before a CALLS edge into another
+	// file is added.
 	edgeCount := 0
+	reindexed := make(map[string]bool)
 	for _, e := range entries {
+		reindexed[e.result.File.Path] = true
 		for _, sym := range e.result.Symbols {
 			if err := s.store.AddSymbol(ctx, sym); err != nil {
 				return nil, BuildGraphOutput{}, fmt.Errorf("add symbol %s: %w", sym.Name, err)
 			}
+			reindexed[sym.FilePath+":"+sym.Name] = true
 		}
-		resolved := resolver.ResolveAll(e.result.Edges, e.lang)
+	}
+	for i, e := range entries {
+		resolved := callResolver.ResolveAll(resolvedByEntry[i], e.lang)
 		for _, edge := range resolved {
 			if err := s.store.AddEdge(ctx, edge); err != nil {
 				return nil, BuildGraphOutput{}, fmt.Errorf("add edge %s->%s: %w", edge.SourceID, edge.TargetID, err)
@@ -162,11 +387,35 @@ func (s *CodeIntelService) BuildGraph(
 			edgeCount++
 		}
 	}
-	fmt.Fprintf(os.Stderr, "Resolved %d import edges\n", edgeCount)
+
+	// Restore edges from unchanged files whose targets survived the reindex,
+	// following symbols that moved or were renamed to their new IDs.
+	var parsedSymbols []graph.SymbolNode
+	for _, e := range entries {
+		parsedSymbols = append(parsedSymbols, e.result.Symbols...)
+	}
+	moved := graph.MatchMovedSymbols(targets, parsedSymbols)
+	for _, edge := range inbound {
+		if newID, ok := moved[edge.TargetID]; ok && !reindexed[edge.TargetID] {
+			edge.TargetID = newID
+		}
+		if !reindexed[edge.TargetID] {
+			continue
+		}
+		if err := s.store.AddEdge(ctx, edge); err != nil {
+			return nil, BuildGraphOutput{}, fmt.Errorf("add edge %s->%s: %w", edge.SourceID, edge.TargetID, err)
+		}
+	}
+	for _, e := range entries {
+		if err := s.store.SetContentHash(ctx, e.result.File.Path, e.result.File.ContentHash); err != nil {
+			return nil, BuildGraphOutput{}, fmt.Errorf("set content hash %s: %w", e.result.File.Path, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Resolved %d edges\n", edgeCount)
 
 	// Run clustering on the indexed files.
 	fmt.Fprintf(os.Stderr, "Clustering...\n")
-	if _, err := graph.ComputeClusters(ctx, s.store, files); err != nil {
+	if _, err := graph.ComputeClusters(ctx, s.store, files, clusterOpts...); err != nil {
 		return nil, BuildGraphOutput{}, fmt.Errorf("compute clusters: %w", err)
 	}
 
@@ -183,7 +432,240 @@ func (s *CodeIntelService) BuildGraph(
 		}
 	}
 
-	return nil, BuildGraphOutput{Stats: *stats}, nil
+	s.recordMoved(moved, reindexed)
+	last := input
+	s.mu.Lock()
+	s.lastBuild = &last
+	s.indexBase = base
+	s.mu.Unlock()
+	return nil, BuildGraphOutput{
+		Stats:           *stats,
+		SkippedSymlinks: skippedLinks,
+		SkippedFiles:    skippedFiles,
+		ExternalImports: external,
+		RemovedFiles:    deleted,
+	}, nil
+}
+
+// lastIndexBase returns the directory the last successful BuildGraph
+// indexed, or "" before the first build.
+func (s *CodeIntelService) lastIndexBase() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.indexBase
+}
+
+// recordMoved folds the symbols a build moved or renamed into the history
+// AssessImpact follows, so that an ID from any earlier build maps to the
+// symbol's current ID. IDs the build defines anew are dropped from it.
+func (s *CodeIntelService) recordMoved(moved map[string]string, reindexed map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.moved == nil {
+		s.moved = make(map[string]string)
+	}
+	for old, cur := range s.moved {
+		if next, ok := moved[cur]; ok {
+			s.moved[old] = next
+		}
+	}
+	for old, cur := range moved {
+		s.moved[old] = cur
+	}
+	for old, cur := range s.moved {
+		if old == cur || reindexed[old] {
+			delete(s.moved, old)
+		}
+	}
+}
+
+// followMoved returns the current ID of a symbol that has moved or been
+// renamed since id was valid, or id itself.
+func (s *CodeIntelService) followMoved(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur, ok := s.moved[id]; ok {
+		return cur
+	}
+	return id
+}
+
+// sameDir reports whether a and b name the same directory once made
+// absolute.
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// staleFiles returns the indexed files that no longer exist under base or
+// lie outside the inScope matcher, adding the latter to outOfScope.
+func (s *CodeIntelService) staleFiles(ctx context.Context, base string, inScope func(string) bool, outOfScope map[string]bool) ([]string, error) {
+	files, err := s.store.GetAllFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	var stale []string
+	for _, f := range files {
+		if !inScope(f.Path) {
+			outOfScope[f.Path] = true
+			stale = append(stale, f.Path)
+			continue
+		}
+		_, err := os.Stat(filepath.Join(base, filepath.FromSlash(f.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			stale = append(stale, f.Path)
+		}
+	}
+	return stale, nil
+}
+
+// includeMatcher returns a function reporting whether a repo-relative file
+// path lies under one of the include patterns: a pattern matches a file when
+// path.Match matches it against the file's path or any parent directory. No
+// patterns include everything.
+func includeMatcher(patterns []string) (func(string) bool, error) {
+	var cleaned []string
+	for _, p := range patterns {
+		p = path.Clean(graph.NormalizePath(strings.TrimSpace(p)))
+		if p == "." || p == "" {
+			return func(string) bool { return true }, nil
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("include pattern %q: %w", p, err)
+		}
+		cleaned = append(cleaned, p)
+	}
+	if len(cleaned) == 0 {
+		return func(string) bool { return true }, nil
+	}
+	return func(file string) bool {
+		for dir := file; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			for _, p := range cleaned {
+				if ok, _ := path.Match(p, dir); ok {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
+
+// indexRoots returns the directories BuildGraph walks, RepoPath followed by
+// RepoPaths, and the base directory file paths are recorded relative to. A
+// single root is its own base; several roots use their common parent, so
+// files from sibling roots keep distinct paths.
+func indexRoots(input BuildGraphInput) ([]string, string, error) {
+	var roots []string
+	if input.RepoPath != "" {
+		roots = append(roots, input.RepoPath)
+	}
+	roots = append(roots, input.RepoPaths...)
+	if len(roots) == 0 {
+		return nil, "", fmt.Errorf("repoPath is required")
+	}
+
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, "", fmt.Errorf("cannot access repoPath: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, "", fmt.Errorf("repoPath is not a directory: %s", root)
+		}
+	}
+	if len(roots) == 1 {
+		return roots, roots[0], nil
+	}
+
+	for i, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return nil, "", fmt.Errorf("resolve repoPath %s: %w", root, err)
+		}
+		roots[i] = abs
+	}
+	return roots, graph.CommonBase(roots), nil
+}
+
+// parse parses source, consulting the parse cache first when one is set and
+// populating it after a miss. The result's ContentHash is set to hash.
+// Cached results are keyed by content alone, so Generated, which also
+// depends on the file name, is recomputed for path.
+func (s *CodeIntelService) parse(ctx context.Context, path string, source []byte, lang graph.Language, hash string) (*graph.ParseResult, error) {
+	if s.parseCache != nil {
+		if result, ok := s.parseCache.Get(lang, hash, path); ok {
+			result.File.Generated = graph.IsGeneratedFile(path, source)
+			return result, nil
+		}
+	}
+	result, err := s.parser.Parse(ctx, path, source, lang)
+	if err != nil {
+		return nil, err
+	}
+	result.File.ContentHash = hash
+	if s.parseCache != nil {
+		if err := s.parseCache.Put(lang, hash, result); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+	return result, nil
+}
+
+// removeChangedFiles removes each changed file from store and returns the
+// edges from other files into it, which RemoveFile drops along with the file,
+// and the symbols those edges target. BELONGS edges are not returned;
+// clustering rebuilds them.
+func removeChangedFiles(ctx context.Context, store graph.Store, paths []string) ([]graph.Edge, []graph.SymbolNode, error) {
+	if len(paths) == 0 {
+		return nil, nil, nil
+	}
+	changed := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		changed[p] = true
+	}
+	// ownerFile maps a file path or "filePath:name" symbol ID to its file.
+	ownerFile := func(id string) string {
+		file, _, _ := strings.Cut(id, ":")
+		return file
+	}
+
+	edges, err := store.GetAllEdges(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get edges: %w", err)
+	}
+	var inbound []graph.Edge
+	var targets []graph.SymbolNode
+	seen := make(map[string]bool)
+	for _, e := range edges {
+		if e.Kind == graph.EdgeKindBelongs || !changed[ownerFile(e.TargetID)] || changed[ownerFile(e.SourceID)] {
+			continue
+		}
+		inbound = append(inbound, e)
+
+		file, name, isSymbol := strings.Cut(e.TargetID, ":")
+		if !isSymbol || seen[e.TargetID] {
+			continue
+		}
+		seen[e.TargetID] = true
+		sym, err := store.GetSymbol(ctx, file, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get symbol %s: %w", e.TargetID, err)
+		}
+		if sym != nil {
+			targets = append(targets, *sym)
+		}
+	}
+
+	for _, p := range paths {
+		if err := store.RemoveFile(ctx, p); err != nil {
+			return nil, nil, fmt.Errorf("remove file %s: %w", p, err)
+		}
+	}
+	return inbound, targets, nil
 }
 
 // persistGraph copies graph data from the in-memory store to a file-based
@@ -255,7 +737,16 @@ func (s *CodeIntelService) QuerySymbols(
 		limit = 20
 	}
 
-	symbols, err := s.store.QuerySymbols(ctx, input.Query, limit)
+	var symbols []graph.SymbolNode
+	var err error
+	switch strings.ToLower(input.Mode) {
+	case "", QueryModeSubstring:
+		symbols, err = s.store.QuerySymbols(ctx, input.Query, limit)
+	case QueryModeTokens:
+		symbols, err = s.queryTokens(ctx, input.Query, limit)
+	default:
+		return nil, QuerySymbolsOutput{}, fmt.Errorf("unknown mode %q (want %s or %s)", input.Mode, QueryModeSubstring, QueryModeTokens)
+	}
 	if err != nil {
 		return nil, QuerySymbolsOutput{}, fmt.Errorf("query symbols: %w", err)
 	}
@@ -278,6 +769,57 @@ func (s *CodeIntelService) QuerySymbols(
 	}, nil
 }
 
+// queryTokens returns up to limit symbols whose name, split with
+// graph.IdentifierTokens, contains every word of query. Names with fewer
+// words beyond the query's rank first, so an exact match such as UserService
+// precedes UserServiceImpl.
+func (s *CodeIntelService) queryTokens(ctx context.Context, query string, limit int) ([]graph.SymbolNode, error) {
+	want := graph.IdentifierTokens(query)
+	if len(want) == 0 {
+		return nil, nil
+	}
+	all, err := s.store.GetAllSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		sym   graph.SymbolNode
+		extra int
+	}
+	var matches []match
+	for _, sym := range all {
+		have := make(map[string]bool)
+		tokens := graph.IdentifierTokens(sym.Name)
+		for _, t := range tokens {
+			have[t] = true
+		}
+		ok := true
+		for _, w := range want {
+			if !have[w] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matches = append(matches, match{sym: sym, extra: len(tokens) - len(want)})
+		}
+	}
+	// GetAllSymbols orders by file path and name; a stable sort keeps that
+	// order among names of equal rank.
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].extra < matches[j].extra })
+
+	symbols := make([]graph.SymbolNode, 0, min(len(matches), limit))
+	for _, m := range matches[:min(len(matches), limit)] {
+		symbols = append(symbols, m.sym)
+	}
+	return symbols, nil
+}
+
+// defaultMaxDependencyNodes caps get_dependencies traversals when the caller
+// sets no maxNodes, so a densely connected graph cannot stall the server.
+const defaultMaxDependencyNodes = 1000
+
 // GetDependencies traverses the dependency graph from a given node.
 func (s *CodeIntelService) GetDependencies(
 	ctx context.Context,
@@ -297,13 +839,209 @@ func (s *CodeIntelService) GetDependencies(
 	if maxDepth <= 0 {
 		maxDepth = 5
 	}
+	maxNodes := input.MaxNodes
+	if maxNodes <= 0 {
+		maxNodes = defaultMaxDependencyNodes
+	}
+
+	kinds, err := parseEdgeKinds(input.EdgeKinds)
+	if err != nil {
+		return nil, GetDependenciesOutput{}, err
+	}
+
+	nodeID, err := s.resolveNodeID(ctx, input.NodeID, fileLevelKinds(kinds))
+	if err != nil {
+		return nil, GetDependenciesOutput{}, err
+	}
 
-	chains, err := s.store.GetDependencies(ctx, input.NodeID, direction, maxDepth)
+	chains, truncated, err := s.store.GetDependenciesLimited(ctx, nodeID, direction, maxDepth, maxNodes, kinds...)
 	if err != nil {
 		return nil, GetDependenciesOutput{}, fmt.Errorf("get dependencies: %w", err)
 	}
+	if input.LeavesOnly {
+		leaves, err := s.dependencyLeaves(ctx, nodeID, chains, direction, kinds)
+		if err != nil {
+			return nil, GetDependenciesOutput{}, fmt.Errorf("get dependencies: %w", err)
+		}
+		return nil, GetDependenciesOutput{Leaves: leaves, Truncated: truncated}, nil
+	}
+
+	return nil, GetDependenciesOutput{Chains: chains, Truncated: truncated}, nil
+}
+
+// dependencyLeaves returns the nodes reached by chains from root that have no
+// edge of kinds, in direction, to root or another reached node. Nodes that
+// continue a chain are never leaves; the remaining chain ends are checked one
+// hop out, since a breadth-first traversal records only the first path to
+// each node.
+func (s *CodeIntelService) dependencyLeaves(ctx context.Context, root string, chains []graph.DependencyChain, direction graph.Direction, kinds []graph.EdgeKind) ([]DependencyLeaf, error) {
+	reached := map[string]bool{root: true}
+	interior := make(map[string]bool)
+	for _, c := range chains {
+		for i, n := range c.Nodes {
+			reached[n] = true
+			if i < len(c.Nodes)-1 {
+				interior[n] = true
+			}
+		}
+	}
+
+	leaves := []DependencyLeaf{}
+	for _, c := range chains {
+		end := c.Nodes[len(c.Nodes)-1]
+		if interior[end] {
+			continue
+		}
+		next, _, err := s.store.GetDependenciesLimited(ctx, end, direction, 1, 0, kinds...)
+		if err != nil {
+			return nil, err
+		}
+		terminal := true
+		for _, n := range next {
+			if reached[n.Nodes[len(n.Nodes)-1]] {
+				terminal = false
+				break
+			}
+		}
+		if terminal {
+			leaves = append(leaves, DependencyLeaf{Node: end, Depth: c.Depth})
+		}
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		if leaves[i].Depth != leaves[j].Depth {
+			return leaves[i].Depth < leaves[j].Depth
+		}
+		return leaves[i].Node < leaves[j].Node
+	})
+	return leaves, nil
+}
+
+// fileLevelKinds reports whether traversing kinds starts from a file node:
+// IMPORTS and DEFINES edges leave files, the other kinds leave symbols.
+func fileLevelKinds(kinds []graph.EdgeKind) bool {
+	for _, k := range kinds {
+		if k != graph.EdgeKindImports && k != graph.EdgeKindDefines {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveNodeID maps a get_dependencies nodeId to the graph node to start
+// from. A file path is used as is. A "filePath:name" symbol ID, or a bare
+// symbol name that matches exactly one symbol, resolves to the symbol's file
+// when fileLevel is set and to the symbol ID otherwise. An ambiguous name is
+// an error listing the candidates; an unknown one is passed through.
+func (s *CodeIntelService) resolveNodeID(ctx context.Context, nodeID string, fileLevel bool) (string, error) {
+	nodeID = s.storedNodeID(nodeID)
+	file, err := s.store.GetFile(ctx, nodeID)
+	if err != nil {
+		return "", fmt.Errorf("get file: %w", err)
+	}
+	if file != nil {
+		return nodeID, nil
+	}
+
+	resolve := func(sym graph.SymbolNode) string {
+		if fileLevel {
+			return sym.FilePath
+		}
+		return sym.FilePath + ":" + sym.Name
+	}
+
+	if i := strings.LastIndex(nodeID, ":"); i > 0 {
+		sym, err := s.store.GetSymbol(ctx, nodeID[:i], nodeID[i+1:])
+		if err != nil {
+			return "", fmt.Errorf("get symbol: %w", err)
+		}
+		if sym != nil {
+			return resolve(*sym), nil
+		}
+		return nodeID, nil
+	}
+
+	candidates, err := s.store.QuerySymbols(ctx, nodeID, 1000)
+	if err != nil {
+		return "", fmt.Errorf("query symbols: %w", err)
+	}
+	var resolved, matches []string
+	seen := make(map[string]bool)
+	for _, sym := range candidates {
+		if sym.Name != nodeID {
+			continue
+		}
+		matches = append(matches, sym.FilePath+":"+sym.Name)
+		if id := resolve(sym); !seen[id] {
+			seen[id] = true
+			resolved = append(resolved, id)
+		}
+	}
+	switch len(resolved) {
+	case 0:
+		return nodeID, nil
+	case 1:
+		return resolved[0], nil
+	default:
+		sort.Strings(matches)
+		return "", fmt.Errorf("nodeId %q matches multiple symbols (%s); pass a file path or filePath:name", nodeID, strings.Join(matches, ", "))
+	}
+}
 
-	return nil, GetDependenciesOutput{Chains: chains}, nil
+// storedPath converts a file path from a tool input to the form file nodes
+// are stored under. An absolute path inside the directory the last BuildGraph
+// indexed (the project root before any build) becomes relative to it; other
+// paths are only cleaned, so repo-relative and absolute inputs name the same
+// file.
+func (s *CodeIntelService) storedPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if filepath.IsAbs(path) {
+		base := s.lastIndexBase()
+		if base == "" {
+			base = s.projectRoot
+		}
+		if base != "" {
+			base, _ = filepath.Abs(base)
+			if rel, err := filepath.Rel(base, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return graph.NormalizePath(rel)
+			}
+		}
+	}
+	return graph.NormalizePath(filepath.Clean(path))
+}
+
+// storedNodeID is storedPath for a node ID that may be a "filePath:name"
+// symbol ID.
+func (s *CodeIntelService) storedNodeID(id string) string {
+	if i := strings.LastIndex(id, ":"); i > 0 && filepath.IsAbs(id[:i]) {
+		return s.storedPath(id[:i]) + id[i:]
+	}
+	return s.storedPath(id)
+}
+
+// traversableEdgeKinds are the edge kinds get_dependencies can follow.
+var traversableEdgeKinds = map[graph.EdgeKind]bool{
+	graph.EdgeKindImports:    true,
+	graph.EdgeKindCalls:      true,
+	graph.EdgeKindInherits:   true,
+	graph.EdgeKindImplements: true,
+	graph.EdgeKindHasMethod:  true,
+	graph.EdgeKindDefines:    true,
+}
+
+// parseEdgeKinds normalizes edge kinds to upper case and rejects unknown
+// ones. An empty input yields nil, which the store treats as IMPORTS.
+func parseEdgeKinds(in []graph.EdgeKind) ([]graph.EdgeKind, error) {
+	var kinds []graph.EdgeKind
+	for _, k := range in {
+		kind := graph.EdgeKind(strings.ToUpper(string(k)))
+		if !traversableEdgeKinds[kind] {
+			return nil, fmt.Errorf("unsupported edge kind %q", k)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
 }
 
 // AssessImpact computes the blast radius of modifying a set of files.
@@ -315,22 +1053,137 @@ func (s *CodeIntelService) AssessImpact(
 	if len(input.ChangedFiles) == 0 {
 		return nil, AssessImpactOutput{}, fmt.Errorf("changedFiles is required")
 	}
+	// Normalize copies so the caller's slices are left untouched. Symbols
+	// named by an ID from before a move or rename follow it to their current
+	// ID.
+	changedFiles := make([]string, len(input.ChangedFiles))
+	for i, f := range input.ChangedFiles {
+		changedFiles[i] = s.storedPath(f)
+	}
+	changedSymbols := make([]string, len(input.ChangedSymbols))
+	for i, sym := range input.ChangedSymbols {
+		changedSymbols[i] = s.followMoved(s.storedNodeID(sym))
+	}
 
-	impact, err := s.store.AssessImpact(ctx, input.ChangedFiles)
+	impact, err := s.store.AssessImpact(ctx, changedFiles, queryOptions(input.ExcludeGenerated)...)
 	if err != nil {
 		return nil, AssessImpactOutput{}, fmt.Errorf("assess impact: %w", err)
 	}
 
+	if input.IncludePaths {
+		if impact.Paths, err = s.impactPaths(ctx, changedFiles, impact.TransitivelyAffected); err != nil {
+			return nil, AssessImpactOutput{}, err
+		}
+	}
+
+	if len(changedSymbols) > 0 {
+		if err := s.classifyImpact(ctx, changedFiles, changedSymbols, impact); err != nil {
+			return nil, AssessImpactOutput{}, err
+		}
+	}
+
 	return nil, AssessImpactOutput{Impact: *impact}, nil
 }
 
+// classifyImpact fills impact.Classes and impact.Breaking. A file with a
+// CALLS edge into one of changedSymbols uses a changed symbol; any other
+// affected file only imports. Callers outside the import closure, such as
+// same-package callers in Go, are classified too.
+func (s *CodeIntelService) classifyImpact(ctx context.Context, changedFiles, changedSymbols []string, impact *graph.ImpactResult) error {
+	changed := make(map[string]bool, len(changedFiles))
+	for _, f := range changedFiles {
+		changed[f] = true
+	}
+
+	classes := make(map[string]graph.ImpactClass)
+	for _, sym := range changedSymbols {
+		id, err := s.resolveNodeID(ctx, sym, false)
+		if err != nil {
+			return fmt.Errorf("resolve changed symbol %s: %w", sym, err)
+		}
+		chains, err := s.store.GetDependencies(ctx, id, graph.DirectionUpstream, 1, graph.EdgeKindCalls)
+		if err != nil {
+			return fmt.Errorf("get callers of %s: %w", id, err)
+		}
+		for _, chain := range chains {
+			// CALLS edges start at the calling symbol; classify its file.
+			caller := chain.Nodes[len(chain.Nodes)-1]
+			if i := strings.LastIndex(caller, ":"); i > 0 {
+				caller = caller[:i]
+			}
+			if !changed[caller] {
+				classes[caller] = graph.ImpactUsesChangedSymbol
+			}
+		}
+	}
+
+	var breaking []string
+	for f := range classes {
+		breaking = append(breaking, f)
+	}
+	sort.Strings(breaking)
+
+	for _, f := range impact.TransitivelyAffected {
+		if _, ok := classes[f]; !ok {
+			classes[f] = graph.ImpactImportsOnly
+		}
+	}
+	impact.Classes = classes
+	impact.Breaking = breaking
+	return nil
+}
+
+// impactPaths finds, for each affected file, a shortest import path to one of
+// the changed files. It walks IMPORTS chains upstream, toward importers, from
+// every changed file and keeps the shortest chain reaching each affected file, breaking ties
+// by path text so the result is deterministic. Paths are returned in import
+// order: the affected file first and the changed file last.
+func (s *CodeIntelService) impactPaths(ctx context.Context, changedFiles, affected []string) (map[string][]string, error) {
+	want := make(map[string]bool, len(affected))
+	for _, f := range affected {
+		want[f] = true
+	}
+
+	paths := make(map[string][]string, len(affected))
+	for _, changed := range changedFiles {
+		// No shortest path can be longer than the affected set.
+		chains, err := s.store.GetDependencies(ctx, changed, graph.DirectionUpstream, len(affected)+1, graph.EdgeKindImports)
+		if err != nil {
+			return nil, fmt.Errorf("get dependencies of %s: %w", changed, err)
+		}
+		for _, chain := range chains {
+			file := chain.Nodes[len(chain.Nodes)-1]
+			if !want[file] {
+				continue
+			}
+			path := make([]string, len(chain.Nodes))
+			for i, n := range chain.Nodes {
+				path[len(path)-1-i] = n
+			}
+			if prev, ok := paths[file]; !ok || shorterPath(path, prev) {
+				paths[file] = path
+			}
+		}
+	}
+	return paths, nil
+}
+
+// shorterPath reports whether a should be preferred over b: fewer hops, or
+// equal hops and lexically smaller.
+func shorterPath(a, b []string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return strings.Join(a, "\x00") < strings.Join(b, "\x00")
+}
+
 // GetClusters returns all file clusters in the graph.
 func (s *CodeIntelService) GetClusters(
 	ctx context.Context,
 	_ *mcp.CallToolRequest,
-	_ GetClustersInput,
+	input GetClustersInput,
 ) (*mcp.CallToolResult, GetClustersOutput, error) {
-	clusters, err := s.store.GetClusters(ctx)
+	clusters, err := s.store.GetClusters(ctx, queryOptions(input.ExcludeGenerated)...)
 	if err != nil {
 		return nil, GetClustersOutput{}, fmt.Errorf("get clusters: %w", err)
 	}
@@ -338,6 +1191,194 @@ func (s *CodeIntelService) GetClusters(
 	return nil, GetClustersOutput{Clusters: clusters}, nil
 }
 
+// queryOptions converts tool input flags to store query options.
+func queryOptions(excludeGenerated bool) []graph.QueryOption {
+	if excludeGenerated {
+		return []graph.QueryOption{graph.ExcludeGenerated()}
+	}
+	return nil
+}
+
+// RecomputeClusters reruns cluster detection over the current graph and
+// replaces the stored clusters, without reparsing any files.
+func (s *CodeIntelService) RecomputeClusters(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	_ RecomputeClustersInput,
+) (*mcp.CallToolResult, RecomputeClustersOutput, error) {
+	clusters, err := graph.RecomputeClusters(ctx, s.store)
+	if err != nil {
+		return nil, RecomputeClustersOutput{}, fmt.Errorf("recompute clusters: %w", err)
+	}
+
+	return nil, RecomputeClustersOutput{Clusters: clusters}, nil
+}
+
+// Reindex rebuilds the graph from the project root set by SetProjectRoot.
+// BuildGraph parses only new and changed files and removes files no longer
+// on disk, so reindexing an existing graph is incremental. The
+// languages, excluded directories, and limits of the last BuildGraph are
+// reused unless the input overrides them.
+func (s *CodeIntelService) Reindex(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input ReindexInput,
+) (*mcp.CallToolResult, ReindexOutput, error) {
+	if s.projectRoot == "" {
+		return nil, ReindexOutput{}, fmt.Errorf("reindex: no project root configured; use build_graph with a repoPath")
+	}
+	if err := s.store.InitSchema(ctx); err != nil {
+		return nil, ReindexOutput{}, fmt.Errorf("init schema: %w", err)
+	}
+	before, err := s.store.Stats(ctx)
+	if err != nil {
+		return nil, ReindexOutput{}, fmt.Errorf("stats: %w", err)
+	}
+
+	var build BuildGraphInput
+	s.mu.Lock()
+	if s.lastBuild != nil {
+		build = *s.lastBuild
+	}
+	s.mu.Unlock()
+	build.RepoPath, build.RepoPaths = s.projectRoot, nil
+	if len(input.Languages) > 0 {
+		build.Languages = input.Languages
+	}
+	if len(input.ExcludeDirs) > 0 {
+		build.ExcludeDirs = input.ExcludeDirs
+	}
+
+	_, out, err := s.BuildGraph(ctx, nil, build)
+	if err != nil {
+		return nil, ReindexOutput{}, err
+	}
+
+	return nil, ReindexOutput{
+		Before:      *before,
+		After:       out.Stats,
+		Incremental: before.FileCount > 0,
+		Removed:     out.RemovedFiles,
+	}, nil
+}
+
+// GraphQuery runs a read-only Cypher query. The service's own store is used
+// when it supports Cypher; otherwise the query runs against the graph
+// persisted under .decompose/graph by the last build_graph.
+func (s *CodeIntelService) GraphQuery(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input GraphQueryInput,
+) (*mcp.CallToolResult, GraphQueryOutput, error) {
+	if !s.allowQuery {
+		return nil, GraphQueryOutput{}, fmt.Errorf("graph_query is disabled; start the server with --allow-graph-query")
+	}
+	if input.Query == "" {
+		return nil, GraphQueryOutput{}, fmt.Errorf("query is required")
+	}
+
+	rows, err := s.store.RunReadQuery(ctx, input.Query, input.Params)
+	if errors.Is(err, graph.ErrQueryUnsupported) && s.projectRoot != "" {
+		rows, err = queryPersistedGraph(ctx, filepath.Join(s.projectRoot, ".decompose", "graph"), input)
+	}
+	if err != nil {
+		return nil, GraphQueryOutput{}, fmt.Errorf("graph query: %w", err)
+	}
+	if rows == nil {
+		rows = [][]any{}
+	}
+	return nil, GraphQueryOutput{Rows: rows}, nil
+}
+
+// queryPersistedGraph runs a read-only query against the file-based graph at
+// path.
+func queryPersistedGraph(ctx context.Context, path string, input GraphQueryInput) ([][]any, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("no persisted graph at %s; run build_graph first", path)
+	}
+	store, err := graph.NewKuzuFileStore(path)
+	if err != nil {
+		return nil, fmt.Errorf("open graph: %w", err)
+	}
+	defer store.Close()
+	return store.RunReadQuery(ctx, input.Query, input.Params)
+}
+
+// GetTypeMethods lists the methods linked to a type by HAS_METHOD edges.
+func (s *CodeIntelService) GetTypeMethods(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input GetTypeMethodsInput,
+) (*mcp.CallToolResult, GetTypeMethodsOutput, error) {
+	if input.TypeName == "" {
+		return nil, GetTypeMethodsOutput{}, fmt.Errorf("typeName is required")
+	}
+
+	owner, err := s.findType(ctx, input.TypeName, s.storedPath(input.FilePath))
+	if err != nil {
+		return nil, GetTypeMethodsOutput{}, err
+	}
+
+	methods, err := s.store.GetMethods(ctx, owner.FilePath, owner.Name)
+	if err != nil {
+		return nil, GetTypeMethodsOutput{}, fmt.Errorf("get methods: %w", err)
+	}
+	if methods == nil {
+		methods = []graph.SymbolNode{}
+	}
+
+	return nil, GetTypeMethodsOutput{Type: *owner, Methods: methods}, nil
+}
+
+// findType looks up the type symbol named name, restricted to filePath when
+// set. It fails if no symbol or more than one symbol matches.
+func (s *CodeIntelService) findType(ctx context.Context, name, filePath string) (*graph.SymbolNode, error) {
+	if filePath != "" {
+		sym, err := s.store.GetSymbol(ctx, filePath, name)
+		if err != nil {
+			return nil, fmt.Errorf("get symbol: %w", err)
+		}
+		if sym == nil {
+			return nil, fmt.Errorf("type %q not found in %s", name, filePath)
+		}
+		return sym, nil
+	}
+
+	candidates, err := s.store.QuerySymbols(ctx, name, 1000)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols: %w", err)
+	}
+	var matches []graph.SymbolNode
+	for _, sym := range candidates {
+		if sym.Name != name || !isTypeLikeKind(sym.Kind) {
+			continue
+		}
+		matches = append(matches, sym)
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("type %q not found", name)
+	case 1:
+		return &matches[0], nil
+	default:
+		files := make([]string, len(matches))
+		for i, m := range matches {
+			files[i] = m.FilePath
+		}
+		sort.Strings(files)
+		return nil, fmt.Errorf("type %q is declared in multiple files (%s); set filePath", name, strings.Join(files, ", "))
+	}
+}
+
+// isTypeLikeKind reports whether symbols of kind k can own methods.
+func isTypeLikeKind(k graph.SymbolKind) bool {
+	switch k {
+	case graph.SymbolKindFunction, graph.SymbolKindMethod, graph.SymbolKindVariable, graph.SymbolKindConstant:
+		return false
+	}
+	return true
+}
+
 // GenerateDiagram produces a Mermaid dependency diagram from the graph.
 func (s *CodeIntelService) GenerateDiagram(
 	ctx context.Context,
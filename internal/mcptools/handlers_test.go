@@ -4,8 +4,10 @@ package mcptools
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/onedusk/pd/internal/graph"
@@ -188,6 +190,46 @@ func TestBuildGraph(t *testing.T) {
 		assert.Contains(t, err.Error(), "repoPath is required")
 	})
 
+	t.Run("maxFiles limit aborts with descriptive error", func(t *testing.T) {
+		store := newTestStore(t)
+		parser := graph.NewTreeSitterParser()
+		defer parser.Close()
+
+		svc := NewCodeIntelService(store, parser)
+		ctx := context.Background()
+
+		_, _, err := svc.BuildGraph(ctx, nil, BuildGraphInput{
+			RepoPath:  fixtureAbsPath(t),
+			Languages: []string{"go"},
+			MaxFiles:  2,
+		})
+		require.Error(t, err)
+
+		var limitErr *GraphLimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, "maxFiles", limitErr.Limit)
+		assert.Equal(t, 2, limitErr.Max)
+		assert.Contains(t, err.Error(), "excludeDirs")
+	})
+
+	t.Run("maxSymbols limit aborts with descriptive error", func(t *testing.T) {
+		store := newTestStore(t)
+		parser := graph.NewTreeSitterParser()
+		defer parser.Close()
+
+		svc := NewCodeIntelService(store, parser)
+		ctx := context.Background()
+
+		_, _, err := svc.BuildGraph(ctx, nil, BuildGraphInput{
+			RepoPath:   fixtureAbsPath(t),
+			Languages:  []string{"go"},
+			MaxSymbols: 1,
+		})
+		var limitErr *GraphLimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, "maxSymbols", limitErr.Limit)
+	})
+
 	t.Run("empty languages defaults to tier-1", func(t *testing.T) {
 		store := newTestStore(t)
 		parser := graph.NewTreeSitterParser()
@@ -205,6 +247,355 @@ func TestBuildGraph(t *testing.T) {
 		require.NoError(t, err)
 		assert.Greater(t, out.Stats.FileCount, 0, "should index files with default tier-1 languages")
 	})
+
+	t.Run("rebuild on a persistent store reparses only changed files", func(t *testing.T) {
+		repo := t.TempDir()
+		for _, name := range []string{"main.go", "model.go", "service.go"} {
+			data, err := os.ReadFile(filepath.Join(fixtureAbsPath(t), name))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(repo, name), data, 0o644))
+		}
+		dbPath := filepath.Join(t.TempDir(), "graph")
+		parser := &countingParser{Parser: graph.NewTreeSitterParser()}
+		defer parser.Close()
+		ctx := context.Background()
+
+		// build opens the store afresh each time, as a restarted process would.
+		build := func() graph.GraphStats {
+			t.Helper()
+			store, err := graph.NewKuzuFileStore(dbPath)
+			require.NoError(t, err)
+			defer store.Close()
+			parser.calls = 0
+			_, out, err := NewCodeIntelService(store, parser).BuildGraph(ctx, nil, BuildGraphInput{
+				RepoPath:  repo,
+				Languages: []string{"go"},
+			})
+			require.NoError(t, err)
+			return out.Stats
+		}
+
+		first := build()
+		assert.Equal(t, 3, parser.calls)
+
+		second := build()
+		assert.Equal(t, 0, parser.calls, "unchanged repo should not be reparsed")
+		assert.Equal(t, first, second)
+
+		model := filepath.Join(repo, "model.go")
+		data, err := os.ReadFile(model)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(model, append(data, "\n// trailing comment\n"...), 0o644))
+
+		third := build()
+		assert.Equal(t, 1, parser.calls, "only the changed file should be reparsed")
+		assert.Equal(t, first.SymbolCount, third.SymbolCount)
+		assert.Equal(t, first.EdgeCount, third.EdgeCount)
+
+		require.NoError(t, os.WriteFile(model, append(data, "\nfunc Extra() {}\n"...), 0o644))
+		fourth := build()
+		assert.Equal(t, 1, parser.calls)
+		assert.Equal(t, first.SymbolCount+1, fourth.SymbolCount)
+	})
+
+	t.Run("rebuild reparses a file left without its hash and prunes deleted files", func(t *testing.T) {
+		repo := t.TempDir()
+		require.NoError(t, os.CopyFS(repo, os.DirFS(fixtureAbsPath(t))))
+		store := newTestStore(t)
+		parser := &countingParser{Parser: graph.NewTreeSitterParser()}
+		defer parser.Close()
+		svc := NewCodeIntelService(store, parser)
+		ctx := context.Background()
+		input := BuildGraphInput{RepoPath: repo, Languages: []string{"go"}}
+
+		_, first, err := svc.BuildGraph(ctx, nil, input)
+		require.NoError(t, err)
+		f, err := store.GetFile(ctx, "service.go")
+		require.NoError(t, err)
+		require.NotNil(t, f)
+		assert.NotEmpty(t, f.ContentHash, "the hash is recorded once the file is indexed")
+
+		// A build interrupted after storing the file node, but before its
+		// symbols and edges, leaves the hash unset.
+		require.NoError(t, store.SetContentHash(ctx, "service.go", ""))
+		parser.calls = 0
+		_, second, err := svc.BuildGraph(ctx, nil, input)
+		require.NoError(t, err)
+		assert.Equal(t, 1, parser.calls, "the half-indexed file should be reparsed")
+		assert.Equal(t, first.Stats, second.Stats)
+
+		require.NoError(t, os.Remove(filepath.Join(repo, "model.go")))
+		_, third, err := svc.BuildGraph(ctx, nil, input)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"model.go"}, third.RemovedFiles)
+		assert.Equal(t, first.Stats.FileCount-1, third.Stats.FileCount)
+		f, err = store.GetFile(ctx, "model.go")
+		require.NoError(t, err)
+		assert.Nil(t, f)
+	})
+
+	t.Run("identical contents are parsed once with a parse cache", func(t *testing.T) {
+		repo := t.TempDir()
+		data, err := os.ReadFile(filepath.Join(fixtureAbsPath(t), "service.go"))
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, "vendor_copy"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "service.go"), data, 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "vendor_copy", "service.go"), data, 0o644))
+
+		store := newTestStore(t)
+		parser := &countingParser{Parser: graph.NewTreeSitterParser()}
+		defer parser.Close()
+		svc := NewCodeIntelService(store, parser)
+		svc.SetParseCache(graph.NewParseCache())
+		ctx := context.Background()
+
+		_, out, err := svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: repo, Languages: []string{"go"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, parser.calls, "the second copy should be served from the cache")
+		assert.Equal(t, 2, out.Stats.FileCount)
+
+		original, err := store.GetSymbol(ctx, "service.go", "GetUser")
+		require.NoError(t, err)
+		require.NotNil(t, original)
+		copied, err := store.GetSymbol(ctx, filepath.Join("vendor_copy", "service.go"), "GetUser")
+		require.NoError(t, err)
+		require.NotNil(t, copied)
+		assert.Equal(t, filepath.Join("vendor_copy", "service.go"), copied.FilePath)
+		copied.FilePath = original.FilePath
+		assert.Equal(t, *original, *copied)
+	})
+
+	t.Run("moved symbol keeps its StableID and inbound calls", func(t *testing.T) {
+		repo := t.TempDir()
+		write := func(name, content string) {
+			t.Helper()
+			require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644))
+		}
+		helper := "func Helper(n int) int {\n\treturn n * 2\n}\n"
+		write("a.go", "package app\n\n"+helper+"\nfunc Other() {}\n")
+		write("b.go", "package app\n\nfunc Unrelated() {}\n")
+		write("c.go", "package app\n\nfunc Main() int {\n\treturn Helper(1)\n}\n")
+
+		store := newTestStore(t)
+		svc := NewCodeIntelService(store, graph.NewTreeSitterParser())
+		ctx := context.Background()
+		build := func() {
+			t.Helper()
+			_, _, err := svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: repo, Languages: []string{"go"}})
+			require.NoError(t, err)
+		}
+
+		build()
+		before, err := store.GetSymbol(ctx, "a.go", "Helper")
+		require.NoError(t, err)
+		require.NotNil(t, before)
+		require.NotEmpty(t, before.StableID)
+
+		// Move Helper from a.go to b.go; c.go, which calls it, is unchanged.
+		write("a.go", "package app\n\nfunc Other() {}\n")
+		write("b.go", "package app\n\nfunc Unrelated() {}\n\n"+helper)
+		build()
+
+		after, err := store.GetSymbol(ctx, "b.go", "Helper")
+		require.NoError(t, err)
+		require.NotNil(t, after)
+		assert.Equal(t, before.StableID, after.StableID)
+
+		edges, err := store.GetAllEdges(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, edges, graph.Edge{SourceID: "c.go:Main", TargetID: "b.go:Helper", Kind: graph.EdgeKindCalls})
+		assert.NotContains(t, edges, graph.Edge{SourceID: "c.go:Main", TargetID: "a.go:Helper", Kind: graph.EdgeKindCalls})
+
+		// AssessImpact follows the symbol's old ID to its new one.
+		_, impact, err := svc.AssessImpact(ctx, nil, AssessImpactInput{
+			ChangedFiles:   []string{"b.go"},
+			ChangedSymbols: []string{"a.go:Helper"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c.go"}, impact.Impact.Breaking)
+	})
+
+	t.Run("multiple roots keep distinct paths and resolve across roots", func(t *testing.T) {
+		base := t.TempDir()
+		files := map[string]string{
+			"app/go.mod":    "module example.com/app\n",
+			"app/main.go":   "package main\n\nimport \"example.com/lib/util\"\n\nfunc main() { util.Run() }\n",
+			"lib/go.mod":    "module example.com/lib\n",
+			"lib/main.go":   "package main\n\nfunc main() {}\n",
+			"lib/util/u.go": "package util\n\nfunc Run() {}\n",
+			"other/skip.go": "package other\n",
+		}
+		for name, content := range files {
+			path := filepath.Join(base, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		}
+
+		store := newTestStore(t)
+		svc := NewCodeIntelService(store, graph.NewTreeSitterParser())
+		ctx := context.Background()
+
+		_, out, err := svc.BuildGraph(ctx, nil, BuildGraphInput{
+			RepoPath:  filepath.Join(base, "app"),
+			RepoPaths: []string{filepath.Join(base, "lib")},
+			Languages: []string{"go"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, out.Stats.FileCount, "only the requested roots are indexed")
+
+		for _, path := range []string{"app/main.go", "lib/main.go", "lib/util/u.go"} {
+			f, err := store.GetFile(ctx, filepath.FromSlash(path))
+			require.NoError(t, err)
+			assert.NotNil(t, f, "expected %s to be indexed", path)
+		}
+
+		edges, err := store.GetAllEdges(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, edges, graph.Edge{
+			SourceID: filepath.FromSlash("app/main.go"),
+			TargetID: filepath.FromSlash("lib/util/u.go"),
+			Kind:     graph.EdgeKindImports,
+		})
+	})
+
+	t.Run("binary and oversized files are skipped with a reason", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"ok.go":     "package p\n\nfunc OK() {}\n",
+			"blob.go":   "package p\x00\x01\x02",
+			"latin1.go": "package p // caf\xe9\n",
+			"huge.go":   "package p\n\n// " + strings.Repeat("x", 4096) + "\n",
+		}
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+		}
+
+		parser := &countingParser{Parser: graph.NewTreeSitterParser()}
+		svc := NewCodeIntelService(newTestStore(t), parser)
+		_, out, err := svc.BuildGraph(context.Background(), nil, BuildGraphInput{
+			RepoPath:     dir,
+			MaxFileBytes: 1024,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, out.Stats.FileCount)
+		assert.Equal(t, 1, parser.calls, "skipped files never reach the parser")
+		assert.ElementsMatch(t, []SkippedFile{
+			{Path: "blob.go", Reason: graph.SkipBinary},
+			{Path: "huge.go", Reason: graph.SkipTooLarge},
+			{Path: "latin1.go", Reason: graph.SkipInvalidUTF8},
+		}, out.SkippedFiles)
+	})
+
+	t.Run("crossLanguageWeight outside 0 to 1 is rejected", func(t *testing.T) {
+		svc := NewCodeIntelService(newTestStore(t), graph.NewTreeSitterParser())
+		for _, w := range []float64{-0.5, 1.5} {
+			_, _, err := svc.BuildGraph(context.Background(), nil, BuildGraphInput{
+				RepoPath:            fixtureAbsPath(t),
+				CrossLanguageWeight: &w,
+			})
+			assert.ErrorContains(t, err, "crossLanguageWeight")
+		}
+
+		w := 0.0
+		_, _, err := svc.BuildGraph(context.Background(), nil, BuildGraphInput{
+			RepoPath:            fixtureAbsPath(t),
+			CrossLanguageWeight: &w,
+		})
+		assert.NoError(t, err, "a weight of 0 ignores cross-language imports")
+	})
+
+	t.Run("include limits indexing to a subtree and reports imports leaving it", func(t *testing.T) {
+		repo := t.TempDir()
+		files := map[string]string{
+			"go.mod": "module example.com/mono\n",
+			"services/payments/pay.go": "package payments\n\nimport (\n\t\"example.com/mono/lib/log\"\n\t\"example.com/mono/services/payments/ledger\"\n)\n\n" +
+				"func Pay() { log.Info(); ledger.Post() }\n",
+			"services/payments/ledger/ledger.go": "package ledger\n\nfunc Post() {}\n",
+			"services/orders/orders.go":          "package orders\n\nfunc Order() {}\n",
+			"lib/log/log.go":                     "package log\n\nfunc Info() {}\n",
+		}
+		for name, content := range files {
+			path := filepath.Join(repo, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		}
+
+		store := newTestStore(t)
+		svc := NewCodeIntelService(store, graph.NewTreeSitterParser())
+		ctx := context.Background()
+		_, out, err := svc.BuildGraph(ctx, nil, BuildGraphInput{
+			RepoPath: repo,
+			Include:  []string{"services/payments/"},
+		})
+		require.NoError(t, err)
+
+		indexed, err := store.GetAllFiles(ctx)
+		require.NoError(t, err)
+		var paths []string
+		for _, f := range indexed {
+			paths = append(paths, f.Path)
+		}
+		assert.Equal(t, []string{"services/payments/ledger/ledger.go", "services/payments/pay.go"}, paths)
+
+		edges, err := store.GetAllEdges(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, edges, graph.Edge{
+			SourceID: "services/payments/pay.go",
+			TargetID: "services/payments/ledger/ledger.go",
+			Kind:     graph.EdgeKindImports,
+		})
+		assert.Equal(t, []ExternalImport{{From: "services/payments/pay.go", To: "lib/log/log.go"}}, out.ExternalImports)
+
+		_, _, err = svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: repo, Include: []string{"services/["}})
+		assert.Error(t, err, "malformed include pattern")
+	})
+
+	t.Run("scoped rebuild on top of a full build prunes files outside the scope", func(t *testing.T) {
+		repo := t.TempDir()
+		files := map[string]string{
+			"go.mod":                    "module example.com/mono\n",
+			"services/payments/pay.go":  "package payments\n\nimport \"example.com/mono/lib/log\"\n\nfunc Pay() { log.Info() }\n",
+			"services/orders/orders.go": "package orders\n\nimport \"example.com/mono/lib/log\"\n\nfunc Order() { log.Info() }\n",
+			"lib/log/log.go":            "package log\n\nfunc Info() {}\n",
+		}
+		for name, content := range files {
+			path := filepath.Join(repo, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		}
+
+		store := newTestStore(t)
+		svc := NewCodeIntelService(store, graph.NewTreeSitterParser())
+		ctx := context.Background()
+		_, full, err := svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: repo})
+		require.NoError(t, err)
+		assert.Equal(t, 3, full.Stats.FileCount)
+
+		_, scoped, err := svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: repo, Include: []string{"services/payments"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, scoped.Stats.FileCount)
+		assert.ElementsMatch(t, []string{"lib/log/log.go", "services/orders/orders.go"}, scoped.RemovedFiles)
+		assert.Equal(t, []ExternalImport{{From: "services/payments/pay.go", To: "lib/log/log.go"}}, scoped.ExternalImports)
+
+		edges, err := store.GetAllEdges(ctx)
+		require.NoError(t, err)
+		for _, e := range edges {
+			assert.NotContains(t, e.SourceID, "orders", "edges of pruned files must go")
+			assert.NotContains(t, e.TargetID, "lib/log", "edges into pruned files must go")
+		}
+	})
+}
+
+// countingParser wraps a graph.Parser and counts Parse calls.
+type countingParser struct {
+	graph.Parser
+	calls int
+}
+
+func (p *countingParser) Parse(ctx context.Context, path string, source []byte, lang graph.Language) (*graph.ParseResult, error) {
+	p.calls++
+	return p.Parser.Parse(ctx, path, source, lang)
 }
 
 // ---------------------------------------------------------------------------
@@ -212,6 +603,48 @@ func TestBuildGraph(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestQuerySymbols(t *testing.T) {
+	t.Run("tokens mode matches across naming conventions", func(t *testing.T) {
+		store := newTestStore(t)
+		ctx := context.Background()
+		files := []graph.FileNode{
+			{Path: "svc/user.go", Language: graph.LangGo},
+			{Path: "web/user.ts", Language: graph.LangTypeScript},
+			{Path: "api/user.py", Language: graph.LangPython},
+		}
+		for _, f := range files {
+			require.NoError(t, store.AddFile(ctx, f))
+		}
+		symbols := []graph.SymbolNode{
+			{Name: "UserService", Kind: graph.SymbolKindType, FilePath: "svc/user.go"},
+			{Name: "UserServiceImpl", Kind: graph.SymbolKindClass, FilePath: "web/user.ts"},
+			{Name: "user_service", Kind: graph.SymbolKindVariable, FilePath: "api/user.py"},
+			{Name: "UserRepository", Kind: graph.SymbolKindType, FilePath: "svc/user.go"},
+			{Name: "serviceUser", Kind: graph.SymbolKindFunction, FilePath: "web/user.ts"},
+			{Name: "users", Kind: graph.SymbolKindVariable, FilePath: "api/user.py"},
+		}
+		for _, sym := range symbols {
+			require.NoError(t, store.AddSymbol(ctx, sym))
+		}
+		svc := NewCodeIntelService(store, nil)
+
+		_, out, err := svc.QuerySymbols(ctx, nil, QuerySymbolsInput{Query: "user service", Mode: QueryModeTokens})
+		require.NoError(t, err)
+		names := make([]string, len(out.Symbols))
+		for i, s := range out.Symbols {
+			names[i] = s.Name
+		}
+		assert.ElementsMatch(t, []string{"UserService", "user_service", "serviceUser", "UserServiceImpl"}, names)
+		assert.Equal(t, "UserServiceImpl", names[len(names)-1], "names with extra words rank last")
+
+		// The default substring mode finds none of them.
+		_, out, err = svc.QuerySymbols(ctx, nil, QuerySymbolsInput{Query: "user service"})
+		require.NoError(t, err)
+		assert.Empty(t, out.Symbols)
+
+		_, _, err = svc.QuerySymbols(ctx, nil, QuerySymbolsInput{Query: "user", Mode: "fuzzy"})
+		assert.Error(t, err)
+	})
+
 	t.Run("substring match returns matching symbols", func(t *testing.T) {
 		store := newTestStore(t)
 		seedSymbols(t, store)
@@ -255,6 +688,24 @@ func TestQuerySymbols(t *testing.T) {
 		assert.GreaterOrEqual(t, out.Total, 1, "should match at least User or UserService as type")
 	})
 
+	t.Run("kind filter selects constants", func(t *testing.T) {
+		store := newTestStore(t)
+		seedSymbols(t, store)
+		ctx := context.Background()
+		require.NoError(t, store.AddSymbol(ctx, graph.SymbolNode{
+			Name: "MaxUsers", Kind: graph.SymbolKindConstant, Exported: true, FilePath: "pkg/model.go", StartLine: 1, EndLine: 1,
+		}))
+		svc := NewCodeIntelService(store, nil)
+
+		_, out, err := svc.QuerySymbols(ctx, nil, QuerySymbolsInput{
+			Query: "User",
+			Kind:  "constant",
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, out.Total)
+		assert.Equal(t, "MaxUsers", out.Symbols[0].Name)
+	})
+
 	t.Run("limit is respected", func(t *testing.T) {
 		store := newTestStore(t)
 		seedSymbols(t, store)
@@ -377,6 +828,55 @@ func TestGetDependencies(t *testing.T) {
 			"depth=1 from A should NOT reach C")
 	})
 
+	t.Run("maxNodes=1 truncates traversal", func(t *testing.T) {
+		store := newTestStore(t)
+		seedLinearChain(t, store) // A -> B -> C
+		svc := NewCodeIntelService(store, nil)
+		ctx := context.Background()
+
+		_, out, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+			NodeID:   "A.go",
+			MaxNodes: 1,
+		})
+		require.NoError(t, err)
+		assert.True(t, out.Truncated)
+		assert.True(t, containsNode(out.Chains, "B.go"))
+		assert.False(t, containsNode(out.Chains, "C.go"))
+	})
+
+	t.Run("leavesOnly from A returns only C", func(t *testing.T) {
+		store := newTestStore(t)
+		seedLinearChain(t, store) // A -> B -> C
+		svc := NewCodeIntelService(store, nil)
+		ctx := context.Background()
+
+		_, out, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+			NodeID:     "A.go",
+			LeavesOnly: true,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, out.Chains)
+		assert.Equal(t, []DependencyLeaf{{Node: "C.go", Depth: 2}}, out.Leaves)
+	})
+
+	t.Run("leavesOnly skips nodes with edges back into the traversal", func(t *testing.T) {
+		store := newTestStore(t)
+		seedLinearChain(t, store) // A -> B -> C
+		ctx := context.Background()
+		// D is reached from A directly and also imports B, so only C is terminal.
+		require.NoError(t, store.AddFile(ctx, graph.FileNode{Path: "D.go", Language: graph.LangGo}))
+		require.NoError(t, store.AddEdge(ctx, graph.Edge{SourceID: "A.go", TargetID: "D.go", Kind: graph.EdgeKindImports}))
+		require.NoError(t, store.AddEdge(ctx, graph.Edge{SourceID: "D.go", TargetID: "B.go", Kind: graph.EdgeKindImports}))
+		svc := NewCodeIntelService(store, nil)
+
+		_, out, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+			NodeID:     "A.go",
+			LeavesOnly: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []DependencyLeaf{{Node: "C.go", Depth: 2}}, out.Leaves)
+	})
+
 	t.Run("empty nodeId returns error", func(t *testing.T) {
 		store := newTestStore(t)
 		svc := NewCodeIntelService(store, nil)
@@ -401,6 +901,134 @@ func TestGetDependencies(t *testing.T) {
 		require.NoError(t, err)
 		assert.Empty(t, out.Chains, "non-existent node should have no dependencies")
 	})
+
+	t.Run("CALLS edge kind follows symbol call chains", func(t *testing.T) {
+		store := newTestStore(t)
+		seedCallChain(t, store) // A -> B -> C imports, main -> run -> helper calls
+		svc := NewCodeIntelService(store, nil)
+		ctx := context.Background()
+
+		_, out, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+			NodeID:    "A.go:main",
+			EdgeKinds: []graph.EdgeKind{"calls"},
+		})
+		require.NoError(t, err)
+		assert.True(t, containsNode(out.Chains, "B.go:run"),
+			"CALLS from main should reach run")
+		assert.True(t, containsNode(out.Chains, "C.go:helper"),
+			"CALLS from main should reach helper (transitively through run)")
+		assert.False(t, containsNode(out.Chains, "B.go"),
+			"CALLS traversal should not follow file imports")
+	})
+
+	t.Run("default edge kind still follows imports only", func(t *testing.T) {
+		store := newTestStore(t)
+		seedCallChain(t, store)
+		svc := NewCodeIntelService(store, nil)
+		ctx := context.Background()
+
+		_, out, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+			NodeID: "A.go",
+		})
+		require.NoError(t, err)
+		assert.True(t, containsNode(out.Chains, "C.go"),
+			"default traversal from A should reach C through imports")
+		assert.False(t, containsNode(out.Chains, "B.go:run"),
+			"default traversal should not follow CALLS edges")
+	})
+
+	t.Run("unknown edge kind returns error", func(t *testing.T) {
+		store := newTestStore(t)
+		svc := NewCodeIntelService(store, nil)
+		ctx := context.Background()
+
+		_, _, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+			NodeID:    "A.go",
+			EdgeKinds: []graph.EdgeKind{"FRIENDS_WITH"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "FRIENDS_WITH")
+	})
+
+	t.Run("bare symbol name resolves to its file", func(t *testing.T) {
+		store := newTestStore(t)
+		seedLinearChain(t, store) // A -> B -> C
+		ctx := context.Background()
+		require.NoError(t, store.AddSymbol(ctx, graph.SymbolNode{Name: "Start", Kind: graph.SymbolKindFunction, FilePath: "A.go"}))
+		svc := NewCodeIntelService(store, nil)
+
+		_, byName, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{NodeID: "Start"})
+		require.NoError(t, err)
+		_, bySymbolID, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{NodeID: "A.go:Start"})
+		require.NoError(t, err)
+		_, byFile, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{NodeID: "A.go"})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, byFile.Chains)
+		assert.Equal(t, byFile.Chains, byName.Chains)
+		assert.Equal(t, byFile.Chains, bySymbolID.Chains)
+	})
+
+	t.Run("bare symbol name keeps the symbol for symbol-level edges", func(t *testing.T) {
+		store := newTestStore(t)
+		ctx := context.Background()
+		for _, sym := range []graph.SymbolNode{
+			{Name: "main", Kind: graph.SymbolKindFunction, FilePath: "A.go"},
+			{Name: "run", Kind: graph.SymbolKindFunction, FilePath: "B.go"},
+		} {
+			require.NoError(t, store.AddSymbol(ctx, sym))
+		}
+		require.NoError(t, store.AddEdge(ctx, graph.Edge{SourceID: "A.go:main", TargetID: "B.go:run", Kind: graph.EdgeKindCalls}))
+		svc := NewCodeIntelService(store, nil)
+
+		_, out, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+			NodeID:    "main",
+			EdgeKinds: []graph.EdgeKind{graph.EdgeKindCalls},
+		})
+		require.NoError(t, err)
+		assert.True(t, containsNode(out.Chains, "B.go:run"))
+	})
+
+	t.Run("ambiguous symbol name lists candidates", func(t *testing.T) {
+		store := newTestStore(t)
+		seedLinearChain(t, store)
+		ctx := context.Background()
+		for _, path := range []string{"B.go", "A.go"} {
+			require.NoError(t, store.AddSymbol(ctx, graph.SymbolNode{Name: "init", Kind: graph.SymbolKindFunction, FilePath: path}))
+		}
+		svc := NewCodeIntelService(store, nil)
+
+		_, _, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{NodeID: "init"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "A.go:init, B.go:init")
+	})
+}
+
+// seedCallChain extends seedLinearChain with one symbol per file and a
+// main -> run -> helper CALLS chain, plus a file-to-symbol DEFINES edge so
+// the graph mixes file and symbol relationships.
+func seedCallChain(t *testing.T, store *graph.MemStore) {
+	t.Helper()
+	seedLinearChain(t, store)
+	ctx := context.Background()
+
+	symbols := []graph.SymbolNode{
+		{Name: "main", Kind: graph.SymbolKindFunction, FilePath: "A.go", StartLine: 1, EndLine: 5},
+		{Name: "run", Kind: graph.SymbolKindFunction, FilePath: "B.go", StartLine: 1, EndLine: 5},
+		{Name: "helper", Kind: graph.SymbolKindFunction, FilePath: "C.go", StartLine: 1, EndLine: 5},
+	}
+	for _, s := range symbols {
+		require.NoError(t, store.AddSymbol(ctx, s))
+	}
+
+	edges := []graph.Edge{
+		{SourceID: "A.go", TargetID: "A.go:main", Kind: graph.EdgeKindDefines},
+		{SourceID: "A.go:main", TargetID: "B.go:run", Kind: graph.EdgeKindCalls},
+		{SourceID: "B.go:run", TargetID: "C.go:helper", Kind: graph.EdgeKindCalls},
+	}
+	for _, e := range edges {
+		require.NoError(t, store.AddEdge(ctx, e))
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -498,6 +1126,219 @@ func TestAssessImpact(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "changedFiles is required")
 	})
+	t.Run("includePaths reports shortest import paths", func(t *testing.T) {
+		// Diamond: A->B, A->C, B->D, C->D. Changing D affects B and C
+		// directly and A through either of them.
+		store := newTestStore(t)
+		seedDiamondGraph(t, store)
+		svc := NewCodeIntelService(store, nil)
+		ctx := context.Background()
+
+		_, out, err := svc.AssessImpact(ctx, nil, AssessImpactInput{
+			ChangedFiles: []string{"D.go"},
+			IncludePaths: true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string][]string{
+			"B.go": {"B.go", "D.go"},
+			"C.go": {"C.go", "D.go"},
+			"A.go": {"A.go", "B.go", "D.go"},
+		}, out.Impact.Paths)
+	})
+
+	t.Run("changedSymbols classifies callers as breaking", func(t *testing.T) {
+		// B and C both import D, but only B.go:Load calls the changed
+		// D.go:Parse.
+		store := newTestStore(t)
+		seedDiamondGraph(t, store)
+		ctx := context.Background()
+		require.NoError(t, store.AddSymbol(ctx, graph.SymbolNode{Name: "Parse", Kind: graph.SymbolKindFunction, Exported: true, FilePath: "D.go"}))
+		require.NoError(t, store.AddSymbol(ctx, graph.SymbolNode{Name: "Load", Kind: graph.SymbolKindFunction, Exported: true, FilePath: "B.go"}))
+		require.NoError(t, store.AddEdge(ctx, graph.Edge{SourceID: "B.go:Load", TargetID: "D.go:Parse", Kind: graph.EdgeKindCalls}))
+		svc := NewCodeIntelService(store, nil)
+
+		_, out, err := svc.AssessImpact(ctx, nil, AssessImpactInput{
+			ChangedFiles:   []string{"D.go"},
+			ChangedSymbols: []string{"Parse"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]graph.ImpactClass{
+			"B.go": graph.ImpactUsesChangedSymbol,
+			"C.go": graph.ImpactImportsOnly,
+			"A.go": graph.ImpactImportsOnly,
+		}, out.Impact.Classes)
+		assert.Equal(t, []string{"B.go"}, out.Impact.Breaking)
+	})
+
+	t.Run("changedSymbols classifies callers stored in Kuzu", func(t *testing.T) {
+		// Kuzu stores CALLS only between symbols, so this checks that built
+		// call edges survive the store.
+		repo := t.TempDir()
+		files := map[string]string{
+			"parse.go":  "package app\n\nfunc Parse() {}\n",
+			"loader.go": "package app\n\nfunc Load() {\n\tParse()\n}\n",
+			"other.go":  "package app\n\nfunc Other() {}\n",
+		}
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644))
+		}
+		store, err := graph.NewKuzuStore()
+		require.NoError(t, err)
+		defer store.Close()
+		parser := graph.NewTreeSitterParser()
+		defer parser.Close()
+		svc := NewCodeIntelService(store, parser)
+		ctx := context.Background()
+
+		_, _, err = svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: repo, Languages: []string{"go"}})
+		require.NoError(t, err)
+
+		edges, err := store.GetAllEdges(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, edges, graph.Edge{SourceID: "loader.go:Load", TargetID: "parse.go:Parse", Kind: graph.EdgeKindCalls})
+
+		_, out, err := svc.AssessImpact(ctx, nil, AssessImpactInput{
+			ChangedFiles:   []string{"parse.go"},
+			ChangedSymbols: []string{"parse.go:Parse"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"loader.go"}, out.Impact.Breaking)
+	})
+
+	t.Run("absolute paths are normalized without touching the input", func(t *testing.T) {
+		store := newTestStore(t)
+		seedDiamondGraph(t, store)
+		ctx := context.Background()
+		require.NoError(t, store.AddSymbol(ctx, graph.SymbolNode{Name: "Parse", Kind: graph.SymbolKindFunction, Exported: true, FilePath: "D.go"}))
+		require.NoError(t, store.AddSymbol(ctx, graph.SymbolNode{Name: "Load", Kind: graph.SymbolKindFunction, Exported: true, FilePath: "B.go"}))
+		require.NoError(t, store.AddEdge(ctx, graph.Edge{SourceID: "B.go:Load", TargetID: "D.go:Parse", Kind: graph.EdgeKindCalls}))
+		root := t.TempDir()
+		svc := NewCodeIntelService(store, nil)
+		svc.SetProjectRoot(root)
+
+		files := []string{filepath.Join(root, "D.go")}
+		symbols := []string{filepath.Join(root, "D.go") + ":Parse"}
+		_, out, err := svc.AssessImpact(ctx, nil, AssessImpactInput{ChangedFiles: files, ChangedSymbols: symbols})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"B.go"}, out.Impact.Breaking)
+		assert.Equal(t, filepath.Join(root, "D.go"), files[0], "the caller's slice must not be rewritten")
+		assert.Equal(t, filepath.Join(root, "D.go")+":Parse", symbols[0])
+	})
+
+	t.Run("paths are omitted unless requested", func(t *testing.T) {
+		store := newTestStore(t)
+		seedDiamondGraph(t, store)
+		svc := NewCodeIntelService(store, nil)
+
+		_, out, err := svc.AssessImpact(context.Background(), nil, AssessImpactInput{ChangedFiles: []string{"D.go"}})
+		require.NoError(t, err)
+		assert.Nil(t, out.Impact.Paths)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestGetTypeMethods
+// ---------------------------------------------------------------------------
+
+func TestGetTypeMethods(t *testing.T) {
+	store := newTestStore(t)
+	parser := graph.NewTreeSitterParser()
+	defer parser.Close()
+
+	svc := NewCodeIntelService(store, parser)
+	ctx := context.Background()
+
+	_, _, err := svc.BuildGraph(ctx, nil, BuildGraphInput{
+		RepoPath:  fixtureAbsPath(t),
+		Languages: []string{"go"},
+	})
+	require.NoError(t, err)
+
+	t.Run("lists methods linked by member edges", func(t *testing.T) {
+		_, out, err := svc.GetTypeMethods(ctx, nil, GetTypeMethodsInput{TypeName: "UserService"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "service.go", out.Type.FilePath)
+		names := make([]string, len(out.Methods))
+		for i, m := range out.Methods {
+			names[i] = m.Name
+		}
+		assert.Equal(t, []string{"GetUser", "CreateUser"}, names)
+	})
+
+	t.Run("type without methods", func(t *testing.T) {
+		_, out, err := svc.GetTypeMethods(ctx, nil, GetTypeMethodsInput{TypeName: "User", FilePath: "model.go"})
+		require.NoError(t, err)
+		assert.Empty(t, out.Methods)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, _, err := svc.GetTypeMethods(ctx, nil, GetTypeMethodsInput{TypeName: "Nope"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestAbsoluteAndRelativePathInputs(t *testing.T) {
+	svc := NewCodeIntelService(newTestStore(t), graph.NewTreeSitterParser())
+	ctx := context.Background()
+	root := fixtureAbsPath(t)
+	_, _, err := svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: root, Languages: []string{"go"}})
+	require.NoError(t, err)
+
+	_, want, err := svc.AssessImpact(ctx, nil, AssessImpactInput{ChangedFiles: []string{"model.go"}})
+	require.NoError(t, err)
+
+	for name, file := range map[string]string{
+		"relative": "service.go",
+		"dotted":   "./service.go",
+		"absolute": filepath.Join(root, "service.go"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, methods, err := svc.GetTypeMethods(ctx, nil, GetTypeMethodsInput{TypeName: "UserService", FilePath: file})
+			require.NoError(t, err)
+			assert.Equal(t, "service.go", methods.Type.FilePath)
+			assert.Len(t, methods.Methods, 2)
+
+			_, deps, err := svc.GetDependencies(ctx, nil, GetDependenciesInput{
+				NodeID:    file + ":UserService",
+				EdgeKinds: []graph.EdgeKind{graph.EdgeKindHasMethod},
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, deps.Chains)
+			assert.Equal(t, "service.go:UserService", deps.Chains[0].Nodes[0])
+
+			model := filepath.Join(filepath.Dir(file), "model.go")
+			_, impact, err := svc.AssessImpact(ctx, nil, AssessImpactInput{ChangedFiles: []string{model}})
+			require.NoError(t, err)
+			assert.Equal(t, want, impact)
+		})
+	}
+
+	assert.Equal(t, "/elsewhere/service.go", svc.storedPath("/elsewhere/service.go"), "paths outside the root are kept")
+}
+
+// ---------------------------------------------------------------------------
+// TestGraphQuery
+// ---------------------------------------------------------------------------
+
+func TestGraphQuery(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		svc := NewCodeIntelService(newTestStore(t), nil)
+		_, _, err := svc.GraphQuery(context.Background(), nil, GraphQueryInput{Query: "MATCH (f:File) RETURN f.path"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--allow-graph-query")
+	})
+
+	t.Run("MemStore without a persisted graph is unsupported", func(t *testing.T) {
+		svc := NewCodeIntelService(newTestStore(t), nil)
+		svc.SetAllowGraphQuery(true)
+		_, _, err := svc.GraphQuery(context.Background(), nil, GraphQueryInput{Query: "MATCH (f:File) RETURN f.path"})
+		require.ErrorIs(t, err, graph.ErrQueryUnsupported)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -587,3 +1428,116 @@ func TestGetClusters(t *testing.T) {
 		assert.Empty(t, out.Clusters, "empty store should return no clusters")
 	})
 }
+
+// ---------------------------------------------------------------------------
+// RecomputeClusters tests
+// ---------------------------------------------------------------------------
+
+func TestRecomputeClusters(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	for _, p := range []string{"pkg/a/x.go", "pkg/a/y.go", "pkg/b/z.go", "other.go"} {
+		require.NoError(t, store.AddFile(ctx, graph.FileNode{Path: p, Language: graph.LangGo, LOC: 10}))
+	}
+	for _, e := range []graph.Edge{
+		{SourceID: "pkg/a/x.go", TargetID: "pkg/a/y.go", Kind: graph.EdgeKindImports},
+		{SourceID: "pkg/a/y.go", TargetID: "pkg/b/z.go", Kind: graph.EdgeKindImports},
+	} {
+		require.NoError(t, store.AddEdge(ctx, e))
+	}
+
+	svc := NewCodeIntelService(store, nil)
+
+	_, before, err := svc.GetClusters(ctx, nil, GetClustersInput{})
+	require.NoError(t, err)
+	require.Empty(t, before.Clusters, "store should start without clusters")
+
+	_, out, err := svc.RecomputeClusters(ctx, nil, RecomputeClustersInput{})
+	require.NoError(t, err)
+	require.Len(t, out.Clusters, 1)
+	assert.ElementsMatch(t, []string{"pkg/a/x.go", "pkg/a/y.go", "pkg/b/z.go"}, out.Clusters[0].Members)
+
+	_, after, err := svc.GetClusters(ctx, nil, GetClustersInput{})
+	require.NoError(t, err)
+	require.Len(t, after.Clusters, 1, "recomputed clusters should be persisted")
+	assert.ElementsMatch(t, out.Clusters[0].Members, after.Clusters[0].Members)
+
+	// Recomputing again replaces rather than duplicates clusters.
+	_, _, err = svc.RecomputeClusters(ctx, nil, RecomputeClustersInput{})
+	require.NoError(t, err)
+	_, after, err = svc.GetClusters(ctx, nil, GetClustersInput{})
+	require.NoError(t, err)
+	assert.Len(t, after.Clusters, 1)
+}
+
+// ---------------------------------------------------------------------------
+// Reindex tests
+// ---------------------------------------------------------------------------
+
+func TestReindex(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	require.NoError(t, os.CopyFS(root, os.DirFS(fixtureAbsPath(t))))
+
+	svc := NewCodeIntelService(newTestStore(t), graph.NewTreeSitterParser())
+	svc.SetProjectRoot(root)
+
+	_, first, err := svc.Reindex(ctx, nil, ReindexInput{Languages: []string{"go"}})
+	require.NoError(t, err)
+	assert.False(t, first.Incremental, "an empty graph is built in full")
+	assert.Zero(t, first.Before.FileCount)
+	assert.Equal(t, 3, first.After.FileCount)
+
+	// Change the project on disk: add a file and delete another.
+	extra := "package main\n\nfunc Audit() {}\n\nfunc Purge() {}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "audit.go"), []byte(extra), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(root, "model.go")))
+
+	_, second, err := svc.Reindex(ctx, nil, ReindexInput{})
+	require.NoError(t, err)
+	assert.True(t, second.Incremental)
+	assert.Equal(t, first.After, second.Before)
+	assert.Equal(t, []string{"model.go"}, second.Removed)
+	assert.Equal(t, 3, second.After.FileCount)
+
+	files, err := svc.store.GetAllFiles(ctx)
+	require.NoError(t, err)
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{"audit.go", "main.go", "service.go"}, paths)
+
+	_, out, err := svc.QuerySymbols(ctx, nil, QuerySymbolsInput{Query: "Purge"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, out.Total, "symbols of the new file should be indexed")
+}
+
+func TestReindex_DifferentRootKeepsFiles(t *testing.T) {
+	ctx := context.Background()
+	built := t.TempDir()
+	require.NoError(t, os.CopyFS(built, os.DirFS(fixtureAbsPath(t))))
+	other := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(other, "audit.go"), []byte("package main\n\nfunc Audit() {}\n"), 0o644))
+
+	svc := NewCodeIntelService(newTestStore(t), graph.NewTreeSitterParser())
+	_, _, err := svc.BuildGraph(ctx, nil, BuildGraphInput{RepoPath: built, Languages: []string{"go"}})
+	require.NoError(t, err)
+
+	// The stored paths are relative to the built directory, where they all
+	// still exist; none may be pruned for being missing under other.
+	svc.SetProjectRoot(other)
+	_, out, err := svc.Reindex(ctx, nil, ReindexInput{})
+	require.NoError(t, err)
+	assert.Empty(t, out.Removed)
+	assert.Equal(t, 4, out.After.FileCount)
+}
+
+func TestReindex_NoProjectRoot(t *testing.T) {
+	svc := NewCodeIntelService(newTestStore(t), graph.NewTreeSitterParser())
+
+	_, _, err := svc.Reindex(context.Background(), nil, ReindexInput{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "project root")
+}
@@ -0,0 +1,86 @@
+package mcptools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Harness connects an in-process MCP client to a server, such as one from
+// NewUnifiedMCPServer, over in-memory transports. It lets tests call tools by
+// name without stdio plumbing; see CallTool.
+type Harness struct {
+	server *mcp.ServerSession
+	client *mcp.ClientSession
+}
+
+// NewHarness connects a client to server. Callers must Close the harness.
+func NewHarness(ctx context.Context, server *mcp.Server) (*Harness, error) {
+	st, ct := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		return nil, fmt.Errorf("harness: connect server: %w", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "decompose-harness", Version: version}, nil)
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		ss.Close()
+		return nil, fmt.Errorf("harness: connect client: %w", err)
+	}
+	return &Harness{server: ss, client: cs}, nil
+}
+
+// Tools returns the names of the tools the server registers.
+func (h *Harness) Tools(ctx context.Context) ([]string, error) {
+	result, err := h.client.ListTools(ctx, &mcp.ListToolsParams{})
+	if err != nil {
+		return nil, fmt.Errorf("harness: list tools: %w", err)
+	}
+	names := make([]string, len(result.Tools))
+	for i, tool := range result.Tools {
+		names[i] = tool.Name
+	}
+	return names, nil
+}
+
+// Close disconnects the client and the server session.
+func (h *Harness) Close() error {
+	return errors.Join(h.client.Close(), h.server.Wait())
+}
+
+// CallTool calls the tool name with params, typically the tool's input
+// struct, and decodes its structured output into Out. A tool that reports an
+// error returns it as a Go error carrying the tool's message.
+func CallTool[Out any](ctx context.Context, h *Harness, name string, params any) (Out, error) {
+	var out Out
+	result, err := h.client.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: params})
+	if err != nil {
+		return out, fmt.Errorf("harness: call %s: %w", name, err)
+	}
+	if result.IsError {
+		return out, fmt.Errorf("harness: %s: %s", name, resultText(result))
+	}
+	raw, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return out, fmt.Errorf("harness: %s: encode output: %w", name, err)
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("harness: %s: decode output: %w", name, err)
+	}
+	return out, nil
+}
+
+// resultText joins the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, c := range result.Content {
+		if tc, ok := c.(*mcp.TextContent); ok {
+			parts = append(parts, tc.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,62 @@
+package mcptools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onedusk/pd/internal/graph"
+	"github.com/onedusk/pd/internal/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUnifiedHarness serves the unified server over a Harness, with a code
+// intelligence service backed by store, and closes it when the test ends.
+func newUnifiedHarness(t *testing.T, store graph.Store) *Harness {
+	t.Helper()
+	cfg := orchestrator.Config{Name: "test", ProjectRoot: t.TempDir()}
+	server := NewUnifiedMCPServer(newMockOrchestrator(), cfg, NewCodeIntelService(store, nil))
+
+	h, err := NewHarness(context.Background(), server)
+	require.NoError(t, err)
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+func TestHarness_QuerySymbols(t *testing.T) {
+	ctx := context.Background()
+	store := graph.NewMemStore()
+	require.NoError(t, store.AddFile(ctx, graph.FileNode{Path: "svc.go", Language: graph.LangGo}))
+	for _, sym := range []graph.SymbolNode{
+		{Name: "NewServer", Kind: graph.SymbolKindFunction, Exported: true, FilePath: "svc.go", StartLine: 3, EndLine: 9},
+		{Name: "Server", Kind: graph.SymbolKindType, Exported: true, FilePath: "svc.go", StartLine: 11, EndLine: 14},
+		{Name: "helper", Kind: graph.SymbolKindFunction, FilePath: "svc.go", StartLine: 16, EndLine: 18},
+	} {
+		require.NoError(t, store.AddSymbol(ctx, sym))
+	}
+	h := newUnifiedHarness(t, store)
+
+	out, err := CallTool[QuerySymbolsOutput](ctx, h, "query_symbols", QuerySymbolsInput{Query: "Server", Kind: "type"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, out.Total)
+	require.Len(t, out.Symbols, 1)
+	assert.Equal(t, "Server", out.Symbols[0].Name)
+	assert.Equal(t, 11, out.Symbols[0].StartLine)
+}
+
+func TestHarness_ListsTools(t *testing.T) {
+	h := newUnifiedHarness(t, graph.NewMemStore())
+
+	names, err := h.Tools(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, names, "query_symbols")
+	assert.Contains(t, names, "run_stage")
+}
+
+func TestHarness_ToolErrors(t *testing.T) {
+	h := newUnifiedHarness(t, graph.NewMemStore())
+
+	_, err := CallTool[QuerySymbolsOutput](context.Background(), h, "no_such_tool", struct{}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no_such_tool")
+}
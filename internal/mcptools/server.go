@@ -10,7 +10,8 @@ import (
 // version is set by the linker at build time.
 var version = "dev"
 
-// NewCodeIntelMCPServer creates an MCP server with all 5 code intelligence tools registered.
+// NewCodeIntelMCPServer creates an MCP server with all 7 code intelligence tools
+// registered, plus graph_query when the service has opted in to it.
 func NewCodeIntelMCPServer(svc *CodeIntelService) *mcp.Server {
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "decompose-codeintel",
@@ -19,22 +20,22 @@ func NewCodeIntelMCPServer(svc *CodeIntelService) *mcp.Server {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "build_graph",
-		Description: "Index a repository and build the code intelligence graph. Walks the file tree, parses source files using tree-sitter, extracts symbols and dependencies, and computes file clusters.",
+		Description: "Index a repository and build the code intelligence graph. Walks the file tree, parses source files using tree-sitter, extracts symbols and dependencies, and computes file clusters. Set include to index only some subtrees.",
 	}, svc.BuildGraph)
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "query_symbols",
-		Description: "Search for symbols (functions, classes, types, etc.) by name substring match. Optionally filter by symbol kind and limit results.",
+		Description: "Search for symbols (functions, classes, types, etc.) by name substring match, or with mode tokens by camelCase/snake_case words so naming conventions do not hide results. Optionally filter by symbol kind and limit results.",
 	}, svc.QuerySymbols)
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_dependencies",
-		Description: "Traverse the dependency graph upstream or downstream from a file or symbol. Returns dependency chains up to the specified depth.",
+		Description: "Traverse the dependency graph upstream or downstream from a file or symbol. Follows IMPORTS edges by default; set edgeKinds (e.g. CALLS, INHERITS) to trace other relationships. Returns dependency chains up to the specified depth, stopping after maxNodes visited nodes and setting truncated when the cap is hit. With leavesOnly, returns just the terminal nodes reached and their depth.",
 	}, svc.GetDependencies)
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "assess_impact",
-		Description: "Compute the blast radius of modifying a set of files. Returns directly and transitively affected files with a risk score.",
+		Description: "Compute the blast radius of modifying a set of files. Returns directly and transitively affected files with a risk score; with changedSymbols, also flags files that call a changed symbol (likely breaking) versus those that only import (possibly safe).",
 	}, svc.AssessImpact)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -42,6 +43,28 @@ func NewCodeIntelMCPServer(svc *CodeIntelService) *mcp.Server {
 		Description: "Return all file clusters discovered during graph building. Clusters are groups of tightly connected files with cohesion scores.",
 	}, svc.GetClusters)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "recompute_clusters",
+		Description: "Recompute file clusters from the current graph without reparsing files, replacing the stored clusters. Use after edges change outside build_graph.",
+	}, svc.RecomputeClusters)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "reindex",
+		Description: "Rebuild the code intelligence graph from the project root without specifying a path. Only new and changed files are parsed and deleted files are dropped; reuses the options of the last build_graph. Returns graph statistics before and after.",
+	}, svc.Reindex)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_type_methods",
+		Description: "List the methods of a type, struct, or class. Methods are linked to their type by receiver (Go), impl block (Rust), or class body (Python) when the graph is built; TypeScript class methods are not extracted.",
+	}, svc.GetTypeMethods)
+
+	if svc.allowQuery {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "graph_query",
+			Description: "Run a read-only Cypher query against the code graph (File, Symbol, and Cluster nodes; DEFINES, IMPORTS, CALLS, INHERITS_FROM, IMPLEMENTS, BELONGS_TO, and HAS_METHOD relationships). Mutating statements are rejected.",
+		}, svc.GraphQuery)
+	}
+
 	return server
 }
 
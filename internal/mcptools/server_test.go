@@ -23,7 +23,13 @@ func setupServerClient(t *testing.T) (*mcp.ClientSession, *CodeIntelService) {
 	store := graph.NewMemStore()
 	parser := graph.NewTreeSitterParser()
 	svc := NewCodeIntelService(store, parser)
-	server := NewCodeIntelMCPServer(svc)
+	return connectClient(t, NewCodeIntelMCPServer(svc)), svc
+}
+
+// connectClient connects a client session to server over in-memory
+// transports and closes it when the test ends.
+func connectClient(t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
 
 	st, ct := mcp.NewInMemoryTransports()
 
@@ -44,10 +50,10 @@ func setupServerClient(t *testing.T) (*mcp.ClientSession, *CodeIntelService) {
 		session.Close()
 	})
 
-	return session, svc
+	return session
 }
 
-// TestMCPListTools verifies that the MCP server exposes exactly 5 tools with
+// TestMCPListTools verifies that the MCP server exposes exactly 8 tools with
 // the expected names.
 func TestMCPListTools(t *testing.T) {
 	session, _ := setupServerClient(t)
@@ -56,7 +62,7 @@ func TestMCPListTools(t *testing.T) {
 	result, err := session.ListTools(ctx, &mcp.ListToolsParams{})
 	require.NoError(t, err)
 
-	require.Len(t, result.Tools, 5, "expected 5 registered tools")
+	require.Len(t, result.Tools, 8, "expected 8 registered tools")
 
 	names := make([]string, len(result.Tools))
 	for i, tool := range result.Tools {
@@ -69,7 +75,10 @@ func TestMCPListTools(t *testing.T) {
 		"build_graph",
 		"get_clusters",
 		"get_dependencies",
+		"get_type_methods",
 		"query_symbols",
+		"recompute_clusters",
+		"reindex",
 	}
 	assert.Equal(t, expected, names)
 }
@@ -185,3 +194,54 @@ func TestMCPCallUnknownTool(t *testing.T) {
 	require.NotNil(t, result)
 	assert.True(t, result.IsError, "calling an unknown tool should set IsError")
 }
+
+// TestMCPGraphQuery verifies that graph_query is only registered after
+// opting in, returns rows for a read query, and rejects a write query.
+func TestMCPGraphQuery(t *testing.T) {
+	ctx := context.Background()
+	store, err := graph.NewKuzuStore()
+	require.NoError(t, err)
+	defer store.Close()
+	parser := graph.NewTreeSitterParser()
+	defer parser.Close()
+	svc := NewCodeIntelService(store, parser)
+
+	tools, err := connectClient(t, NewCodeIntelMCPServer(svc)).ListTools(ctx, &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	for _, tool := range tools.Tools {
+		assert.NotEqual(t, "graph_query", tool.Name, "graph_query must be opt-in")
+	}
+
+	svc.SetAllowGraphQuery(true)
+	session := connectClient(t, NewCodeIntelMCPServer(svc))
+
+	buildResult, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "build_graph",
+		Arguments: BuildGraphInput{RepoPath: fixtureAbsPath(t), Languages: []string{"go"}},
+	})
+	require.NoError(t, err)
+	require.False(t, buildResult.IsError, "build_graph should succeed")
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "graph_query",
+		Arguments: GraphQueryInput{
+			Query:  "MATCH (s:Symbol) WHERE s.name = $name RETURN s.file_path",
+			Params: map[string]any{"name": "Run"},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "read query should succeed")
+
+	raw, err := json.Marshal(result.StructuredContent)
+	require.NoError(t, err)
+	var output GraphQueryOutput
+	require.NoError(t, json.Unmarshal(raw, &output))
+	assert.Equal(t, [][]any{{"main.go"}}, output.Rows)
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "graph_query",
+		Arguments: GraphQueryInput{Query: "MATCH (s:Symbol) DELETE s"},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError, "write query should be rejected")
+}
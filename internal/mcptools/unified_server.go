@@ -10,8 +10,10 @@ import (
 // NewUnifiedMCPServer creates a single MCP server that registers all tools:
 // 3 decompose tools (run_stage, get_status, list_decompositions),
 // 2 hybrid tools (write_stage, get_stage_context),
-// and 5 code intelligence tools (build_graph, query_symbols, get_dependencies,
-// assess_impact, get_clusters).
+// and 8 code intelligence tools (build_graph, query_symbols, get_dependencies,
+// assess_impact, get_clusters, recompute_clusters, get_type_methods,
+// generate_diagram), plus graph_query when the code intelligence service has
+// opted in to it.
 func NewUnifiedMCPServer(pipeline orchestrator.Orchestrator, cfg orchestrator.Config, codeintel *CodeIntelService) *mcp.Server {
 	decomposeSvc := NewDecomposeService(pipeline, cfg)
 	if codeintel != nil {
@@ -44,7 +46,7 @@ func NewUnifiedMCPServer(pipeline orchestrator.Orchestrator, cfg orchestrator.Co
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "write_stage",
-		Description: "Validate, merge, and write stage content generated by Claude. Accepts named sections, runs coherence checking, merges in template order, and writes the output file. Use this instead of manually writing stage files.",
+		Description: "Validate, merge, and write stage content generated by Claude. Accepts named sections (merged in template order) or a complete content document (written as-is), runs coherence checking, and writes the output file. Returns the written path and any coherence issues. Use this instead of manually writing stage files.",
 	}, decomposeSvc.WriteStage)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -69,22 +71,22 @@ func NewUnifiedMCPServer(pipeline orchestrator.Orchestrator, cfg orchestrator.Co
 	if codeintel != nil {
 		mcp.AddTool(server, &mcp.Tool{
 			Name:        "build_graph",
-			Description: "Index a repository and build the code intelligence graph. Walks the file tree, parses source files using tree-sitter, extracts symbols and dependencies, and computes file clusters.",
+			Description: "Index a repository and build the code intelligence graph. Walks the file tree, parses source files using tree-sitter, extracts symbols and dependencies, and computes file clusters. Set include to index only some subtrees.",
 		}, codeintel.BuildGraph)
 
 		mcp.AddTool(server, &mcp.Tool{
 			Name:        "query_symbols",
-			Description: "Search for symbols (functions, classes, types, etc.) by name substring match. Optionally filter by symbol kind and limit results.",
+			Description: "Search for symbols (functions, classes, types, etc.) by name substring match, or with mode tokens by camelCase/snake_case words so naming conventions do not hide results. Optionally filter by symbol kind and limit results.",
 		}, codeintel.QuerySymbols)
 
 		mcp.AddTool(server, &mcp.Tool{
 			Name:        "get_dependencies",
-			Description: "Traverse the dependency graph upstream or downstream from a file or symbol. Returns dependency chains up to the specified depth.",
+			Description: "Traverse the dependency graph upstream or downstream from a file or symbol. Follows IMPORTS edges by default; set edgeKinds (e.g. CALLS, INHERITS) to trace other relationships. Returns dependency chains up to the specified depth, stopping after maxNodes visited nodes and setting truncated when the cap is hit. With leavesOnly, returns just the terminal nodes reached and their depth.",
 		}, codeintel.GetDependencies)
 
 		mcp.AddTool(server, &mcp.Tool{
 			Name:        "assess_impact",
-			Description: "Compute the blast radius of modifying a set of files. Returns directly and transitively affected files with a risk score.",
+			Description: "Compute the blast radius of modifying a set of files. Returns directly and transitively affected files with a risk score; with changedSymbols, also flags files that call a changed symbol (likely breaking) versus those that only import (possibly safe).",
 		}, codeintel.AssessImpact)
 
 		mcp.AddTool(server, &mcp.Tool{
@@ -92,10 +94,32 @@ func NewUnifiedMCPServer(pipeline orchestrator.Orchestrator, cfg orchestrator.Co
 			Description: "Return all file clusters discovered during graph building. Clusters are groups of tightly connected files with cohesion scores.",
 		}, codeintel.GetClusters)
 
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "recompute_clusters",
+			Description: "Recompute file clusters from the current graph without reparsing files, replacing the stored clusters. Use after edges change outside build_graph.",
+		}, codeintel.RecomputeClusters)
+
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "reindex",
+			Description: "Rebuild the code intelligence graph from the project root without specifying a path. Only new and changed files are parsed and deleted files are dropped; reuses the options of the last build_graph. Returns graph statistics before and after.",
+		}, codeintel.Reindex)
+
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "get_type_methods",
+			Description: "List the methods of a type, struct, or class. Methods are linked to their type by receiver (Go), impl block (Rust), or class body (Python) when the graph is built; TypeScript class methods are not extracted.",
+		}, codeintel.GetTypeMethods)
+
 		mcp.AddTool(server, &mcp.Tool{
 			Name:        "generate_diagram",
 			Description: "Generate a Mermaid dependency diagram from the code graph. Clusters become subgraphs, imports become arrows.",
 		}, codeintel.GenerateDiagram)
+
+		if codeintel.allowQuery {
+			mcp.AddTool(server, &mcp.Tool{
+				Name:        "graph_query",
+				Description: "Run a read-only Cypher query against the code graph (File, Symbol, and Cluster nodes; DEFINES, IMPORTS, CALLS, INHERITS_FROM, IMPLEMENTS, BELONGS_TO, and HAS_METHOD relationships). Mutating statements are rejected.",
+			}, codeintel.GraphQuery)
+		}
 	}
 
 	return server
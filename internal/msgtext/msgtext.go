@@ -0,0 +1,161 @@
+// Package msgtext extracts text, paths, and node names from the free-form
+// A2A messages that agents receive.
+//
+// Messages are split into tokens on whitespace. A token that starts with a
+// double quote, single quote, or backtick runs to the matching closing quote,
+// so quoted paths may contain spaces. Surrounding quotes, brackets, and
+// separators (,;:) are trimmed from each token, as is trailing sentence
+// punctuation (.!?). Keyword filters compare tokens case-insensitively.
+package msgtext
+
+import (
+	"strings"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// Text concatenates the text parts of msg, one per line.
+func Text(msg a2a.Message) string {
+	var parts []string
+	for _, p := range msg.Parts {
+		if p.Text != "" {
+			parts = append(parts, p.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Tokens returns the cleaned tokens of text, in order, dropping any that
+// match one of keywords.
+func Tokens(text string, keywords ...string) []string {
+	var tokens []string
+	for _, raw := range split(text) {
+		tok := clean(raw)
+		if tok == "" || isKeyword(tok, keywords) {
+			continue
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+// FirstToken returns the first token of text that is not one of keywords,
+// or "" if there is none.
+func FirstToken(text string, keywords ...string) string {
+	if tokens := Tokens(text, keywords...); len(tokens) > 0 {
+		return tokens[0]
+	}
+	return ""
+}
+
+// IsAbsPath reports whether tok is an absolute path. A "//" prefix marks a
+// comment or protocol-relative URL, not a path.
+func IsAbsPath(tok string) bool {
+	return len(tok) > 1 && strings.HasPrefix(tok, "/") && !strings.HasPrefix(tok, "//")
+}
+
+// IsPathLike reports whether tok looks like a file path, symbol ID, or
+// qualified name: it contains a "/" or ".", is not a URL, and is not made
+// of dots alone.
+func IsPathLike(tok string) bool {
+	if !strings.ContainsAny(tok, "/.") || strings.Trim(tok, ".") == "" {
+		return false
+	}
+	return !strings.Contains(tok, "://")
+}
+
+// AbsPath returns the absolute path named in text, or "" if there is none.
+// A line that begins with an absolute path takes precedence over paths
+// mentioned mid-sentence; otherwise the first absolute path wins.
+func AbsPath(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if tok := FirstToken(line); IsAbsPath(tok) {
+			return tok
+		}
+	}
+	for _, tok := range Tokens(text) {
+		if IsAbsPath(tok) {
+			return tok
+		}
+	}
+	return ""
+}
+
+// AbsPaths returns every absolute path in text, in order and without
+// duplicates.
+func AbsPaths(text string) []string {
+	return dedupe(Tokens(text), IsAbsPath)
+}
+
+// Paths returns every path-like token in text (see IsPathLike), in order
+// and without duplicates, skipping keywords.
+func Paths(text string, keywords ...string) []string {
+	return dedupe(Tokens(text, keywords...), IsPathLike)
+}
+
+// dedupe returns the tokens that satisfy keep, dropping repeats.
+func dedupe(tokens []string, keep func(string) bool) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, tok := range tokens {
+		if keep(tok) && !seen[tok] {
+			seen[tok] = true
+			out = append(out, tok)
+		}
+	}
+	return out
+}
+
+// split breaks text into raw tokens on whitespace, keeping a quoted run
+// together when a token opens with a quote that is closed later in text.
+func split(text string) []string {
+	var tokens []string
+	for i := 0; i < len(text); {
+		if isSpace(text[i]) {
+			i++
+			continue
+		}
+		start := i
+		if q := text[i]; q == '"' || q == '\'' || q == '`' {
+			if end := strings.IndexByte(text[i+1:], q); end >= 0 {
+				i += end + 2
+				// Keep trailing punctuation attached, as in `"/a b",`.
+				for i < len(text) && !isSpace(text[i]) {
+					i++
+				}
+				tokens = append(tokens, text[start:i])
+				continue
+			}
+		}
+		for i < len(text) && !isSpace(text[i]) {
+			i++
+		}
+		tokens = append(tokens, text[start:i])
+	}
+	return tokens
+}
+
+// clean trims quotes, brackets, and separators from both ends of tok and
+// sentence punctuation from its end.
+func clean(tok string) string {
+	for {
+		trimmed := strings.TrimRight(strings.Trim(tok, "`\"',;:()[]"), ".!?")
+		if trimmed == tok {
+			return tok
+		}
+		tok = trimmed
+	}
+}
+
+func isKeyword(tok string, keywords []string) bool {
+	for _, kw := range keywords {
+		if strings.EqualFold(tok, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
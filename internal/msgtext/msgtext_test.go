@@ -0,0 +1,97 @@
+package msgtext
+
+import (
+	"testing"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	msg := a2a.Message{Parts: []a2a.Part{
+		a2a.TextPart("explore-codebase"),
+		{Data: []byte(`{"k":"v"}`)},
+		a2a.TextPart("/tmp/project"),
+	}}
+	assert.Equal(t, "explore-codebase\n/tmp/project", Text(msg))
+	assert.Empty(t, Text(a2a.Message{}))
+}
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		keywords []string
+		want     []string
+	}{
+		{"whitespace", "a  b\tc\nd", nil, []string{"a", "b", "c", "d"}},
+		{"punctuation", "Check `pkg/a.go`, (b.go); [c.go]: done.", nil, []string{"Check", "pkg/a.go", "b.go", "c.go", "done"}},
+		{"double-quoted spaces", `open "/tmp/my project" now`, nil, []string{"open", "/tmp/my project", "now"}},
+		{"single-quoted spaces", "open '/tmp/my project'.", nil, []string{"open", "/tmp/my project"}},
+		{"backtick-quoted spaces", "see `a b/c.go`", nil, []string{"see", "a b/c.go"}},
+		{"unclosed quote", `say "hello world`, nil, []string{"say", "hello", "world"}},
+		{"apostrophe", "don't touch it's file.go", nil, []string{"don't", "touch", "it's", "file.go"}},
+		{"keywords", "Analyze UPSTREAM deps of x.go", []string{"analyze", "upstream"}, []string{"deps", "of", "x.go"}},
+		{"dots only", "wait ... ok", nil, []string{"wait", "ok"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Tokens(tt.text, tt.keywords...))
+		})
+	}
+}
+
+func TestAbsPath(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"own line", "explore-codebase\n/home/u/repo\n", "/home/u/repo"},
+		{"same line as skill", "explore-codebase /home/u/repo", "/home/u/repo"},
+		{"mid sentence", "Please build the graph for /srv/app, thanks.", "/srv/app"},
+		{"line start wins", "Compare with /a/b\n/c/d", "/c/d"},
+		{"quoted", `index "/tmp/my repo" please`, "/tmp/my repo"},
+		{"comment is not a path", "// generated\nbuild /x/y", "/x/y"},
+		{"bare slash", "a / b", ""},
+		{"url", "see https://example.com/x", ""},
+		{"relative only", "look at internal/graph", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AbsPath(tt.text))
+		})
+	}
+}
+
+func TestAbsPaths(t *testing.T) {
+	text := "explore-codebase /repo/api and '/repo/web app'\n/repo/api again, /repo/cli."
+	assert.Equal(t, []string{"/repo/api", "/repo/web app", "/repo/cli"}, AbsPaths(text))
+	assert.Empty(t, AbsPaths("no paths here"))
+}
+
+func TestPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		keywords []string
+		want     []string
+	}{
+		{"mixed", "assess-impact internal/graph/store.go and `cmd/main.go`.", []string{"assess-impact"}, []string{"internal/graph/store.go", "cmd/main.go"}},
+		{"absolute and relative", "/abs/x.go rel/y.go", nil, []string{"/abs/x.go", "rel/y.go"}},
+		{"symbol IDs", "graph/store.go:Store pkg.Type", nil, []string{"graph/store.go:Store", "pkg.Type"}},
+		{"skips urls and prose", "See https://x.io/a for details. Done.", nil, nil},
+		{"dedupes", "a.go b.go a.go", nil, []string{"a.go", "b.go"}},
+		{"keyword case", "Assess.Impact a.go", []string{"assess.impact"}, []string{"a.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Paths(tt.text, tt.keywords...))
+		})
+	}
+}
+
+func TestFirstToken(t *testing.T) {
+	assert.Equal(t, "repo", FirstToken("explore-codebase\n\nrepo here", "explore-codebase"))
+	assert.Empty(t, FirstToken("  explore-codebase ", "EXPLORE-CODEBASE"))
+}
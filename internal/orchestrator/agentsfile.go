@@ -0,0 +1,127 @@
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AgentEndpoint is an A2A agent endpoint listed in an --agents-file, with
+// optional credentials sent on every request to it.
+type AgentEndpoint struct {
+	URL string `json:"url"`
+	// Token, if set, is sent as "Authorization: Bearer <token>".
+	Token string `json:"token,omitempty"`
+	// Headers are extra HTTP headers sent to this endpoint.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// LoadAgentsFile reads agent endpoints from path. Two formats are accepted:
+//
+//   - a JSON array whose elements are URL strings or AgentEndpoint objects;
+//   - one URL per line, ignoring blank lines and "#" comments. A "#" starts a
+//     comment only at the start of a line or after whitespace, so URL
+//     fragments such as "http://host/#frag" are kept.
+func LoadAgentsFile(path string) ([]AgentEndpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load agents file: %w", err)
+	}
+
+	var endpoints []AgentEndpoint
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("[")) {
+		endpoints, err = parseAgentsJSON(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("load agents file %s: %w", path, err)
+		}
+	} else {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(stripComment(line)); line != "" {
+				endpoints = append(endpoints, AgentEndpoint{URL: line})
+			}
+		}
+	}
+
+	for i, ep := range endpoints {
+		if ep.URL == "" {
+			return nil, fmt.Errorf("load agents file %s: entry %d has no url", path, i+1)
+		}
+	}
+	return endpoints, nil
+}
+
+// stripComment removes a "#" comment from line. The "#" must start the line
+// or follow whitespace.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseAgentsJSON decodes a JSON array of URL strings and endpoint objects.
+func parseAgentsJSON(data []byte) ([]AgentEndpoint, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	endpoints := make([]AgentEndpoint, 0, len(raw))
+	for i, elem := range raw {
+		var url string
+		if err := json.Unmarshal(elem, &url); err == nil {
+			endpoints = append(endpoints, AgentEndpoint{URL: url})
+			continue
+		}
+		var ep AgentEndpoint
+		if err := json.Unmarshal(elem, &ep); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, nil
+}
+
+// MergeAgentEndpoints appends the endpoints in extra to urls, skipping URLs
+// already present, and returns the combined URL list.
+func MergeAgentEndpoints(urls []string, extra []AgentEndpoint) []string {
+	seen := make(map[string]bool, len(urls)+len(extra))
+	merged := make([]string, 0, len(urls)+len(extra))
+	for _, u := range urls {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			merged = append(merged, u)
+		}
+	}
+	for _, ep := range extra {
+		if !seen[ep.URL] {
+			seen[ep.URL] = true
+			merged = append(merged, ep.URL)
+		}
+	}
+	return merged
+}
+
+// EndpointHeaders returns the HTTP headers each endpoint's credentials call
+// for, keyed by URL, for a2a.WithEndpointHeaders. Endpoints without
+// credentials are omitted.
+func EndpointHeaders(endpoints []AgentEndpoint) map[string]map[string]string {
+	headers := make(map[string]map[string]string)
+	for _, ep := range endpoints {
+		if ep.Token == "" && len(ep.Headers) == 0 {
+			continue
+		}
+		h := make(map[string]string, len(ep.Headers)+1)
+		for k, v := range ep.Headers {
+			h[k] = v
+		}
+		if ep.Token != "" {
+			h["Authorization"] = "Bearer " + ep.Token
+		}
+		headers[ep.URL] = h
+	}
+	return headers
+}
@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAgentsFile_LineFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.txt")
+	content := "# research and planning\nhttp://localhost:9100\n\n  http://localhost:9101  # planning\nhttp://localhost:9102/#/agents\t# fragment\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	endpoints, err := LoadAgentsFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []AgentEndpoint{
+		{URL: "http://localhost:9100"},
+		{URL: "http://localhost:9101"},
+		{URL: "http://localhost:9102/#/agents"},
+	}, endpoints)
+}
+
+func TestLoadAgentsFile_JSONFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	content := `[
+		"http://localhost:9100",
+		{"url": "https://agents.example.com/planning", "token": "abc", "headers": {"X-Team": "core"}}
+	]`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	endpoints, err := LoadAgentsFile(path)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, "http://localhost:9100", endpoints[0].URL)
+	assert.Equal(t, "abc", endpoints[1].Token)
+
+	assert.Equal(t, map[string]map[string]string{
+		"https://agents.example.com/planning": {"Authorization": "Bearer abc", "X-Team": "core"},
+	}, EndpointHeaders(endpoints))
+}
+
+func TestLoadAgentsFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadAgentsFile(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+
+	noURL := filepath.Join(dir, "nourl.json")
+	require.NoError(t, os.WriteFile(noURL, []byte(`[{"token": "abc"}]`), 0o644))
+	_, err = LoadAgentsFile(noURL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "entry 1 has no url")
+}
+
+func TestMergeAgentEndpoints(t *testing.T) {
+	merged := MergeAgentEndpoints(
+		[]string{"http://a", "http://b", ""},
+		[]AgentEndpoint{{URL: "http://b"}, {URL: "http://c"}},
+	)
+	assert.Equal(t, []string{"http://a", "http://b", "http://c"}, merged)
+}
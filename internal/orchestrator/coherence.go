@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -13,12 +14,74 @@ var codeBlockRe = regexp.MustCompile("(?s)```.*?```")
 // "PostgreSQL 16", or any word followed by an optional 'v' and a version number.
 var depVersionRe = regexp.MustCompile(`(?i)\b([A-Za-z][A-Za-z0-9_.-]*)\s+v?(\d+\.\d+(?:\.\d+)?(?:\.x)?)\b`)
 
+// SplitStageSections splits a complete stage document into Sections at its
+// level-2 headings, naming each by its kebab-case heading so the result can
+// be passed to CheckCoherence. Text before the first heading forms a
+// "preamble" section when it is not blank. Headings inside fenced code
+// blocks are part of the block, not section breaks.
+func SplitStageSections(content, agent string) []Section {
+	var sections []Section
+	name := "preamble"
+	var body strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(body.String()); text != "" || name != "preamble" {
+			sections = append(sections, Section{Name: name, Content: text, Agent: agent})
+		}
+		body.Reset()
+	}
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if heading, ok := strings.CutPrefix(line, "## "); ok && !inFence {
+			flush()
+			name = kebabCase(heading)
+			continue
+		}
+		body.WriteString(line)
+		body.WriteString("\n")
+	}
+	flush()
+	return sections
+}
+
+// DefaultCoherenceMaxSections is the section count above which
+// CheckCoherenceLimit switches to its reduced check.
+const DefaultCoherenceMaxSections = 200
+
+// maxListedVersions caps the versions named in a reduced-check issue.
+const maxListedVersions = 5
+
 // CheckCoherence performs a lightweight cross-section consistency scan.
 // It extracts dependency mentions with version numbers from each section,
 // builds a map of dependency name to version to section, and flags any
 // dependency that appears with different versions across sections.
 // Content inside fenced code blocks is excluded to avoid false positives.
 func CheckCoherence(sections []Section) ([]CoherenceIssue, error) {
+	return pairwiseIssues(collectDepVersions(sections)), nil
+}
+
+// CheckCoherenceLimit runs CheckCoherence when there are at most maxSections
+// sections (DefaultCoherenceMaxSections when maxSections is zero). Larger
+// stages get a reduced check that reports one issue per conflicting
+// dependency instead of one per pair of versions, whose count grows
+// quadratically when many sections mention a dependency; reduced reports
+// which check ran.
+func CheckCoherenceLimit(sections []Section, maxSections int) (issues []CoherenceIssue, reduced bool, err error) {
+	if maxSections <= 0 {
+		maxSections = DefaultCoherenceMaxSections
+	}
+	depVersions := collectDepVersions(sections)
+	if len(sections) <= maxSections {
+		return pairwiseIssues(depVersions), false, nil
+	}
+	return bucketedIssues(depVersions), true, nil
+}
+
+// collectDepVersions maps each normalized dependency name mentioned outside
+// code blocks to its versions, and each version to the sections naming it.
+func collectDepVersions(sections []Section) map[string]map[string][]string {
 	// depVersions maps normalized dependency name -> version -> list of section names.
 	depVersions := make(map[string]map[string][]string)
 
@@ -49,7 +112,12 @@ func CheckCoherence(sections []Section) ([]CoherenceIssue, error) {
 			depVersions[name][version] = append(depVersions[name][version], sec.Name)
 		}
 	}
+	return depVersions
+}
 
+// pairwiseIssues reports every pair of conflicting versions of each
+// dependency.
+func pairwiseIssues(depVersions map[string]map[string][]string) []CoherenceIssue {
 	// Find dependencies with conflicting versions across sections.
 	var issues []CoherenceIssue
 	for dep, versions := range depVersions {
@@ -86,5 +154,46 @@ func CheckCoherence(sections []Section) ([]CoherenceIssue, error) {
 		}
 	}
 
-	return issues, nil
+	return issues
+}
+
+// bucketedIssues reports each dependency with conflicting versions once,
+// naming its most widely used versions and the first section of each.
+func bucketedIssues(depVersions map[string]map[string][]string) []CoherenceIssue {
+	var issues []CoherenceIssue
+	for dep, versions := range depVersions {
+		if len(versions) <= 1 {
+			continue
+		}
+		ordered := make([]string, 0, len(versions))
+		for v := range versions {
+			ordered = append(ordered, v)
+		}
+		sort.Slice(ordered, func(i, j int) bool {
+			a, b := versions[ordered[i]], versions[ordered[j]]
+			if len(a) != len(b) {
+				return len(a) > len(b)
+			}
+			return ordered[i] < ordered[j]
+		})
+
+		var listed []string
+		for _, v := range ordered[:min(len(ordered), maxListedVersions)] {
+			where := versions[v][0]
+			if n := len(versions[v]) - 1; n > 0 {
+				where = fmt.Sprintf("%s and %d more", where, n)
+			}
+			listed = append(listed, fmt.Sprintf("%s (in %s)", v, where))
+		}
+		if extra := len(ordered) - maxListedVersions; extra > 0 {
+			listed = append(listed, fmt.Sprintf("%d more versions", extra))
+		}
+		issues = append(issues, CoherenceIssue{
+			SectionA: versions[ordered[0]][0],
+			SectionB: versions[ordered[1]][0],
+			Description: fmt.Sprintf("dependency %q has %d conflicting versions: %s",
+				dep, len(ordered), strings.Join(listed, ", ")),
+		})
+	}
+	return issues
 }
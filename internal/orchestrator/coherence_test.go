@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -68,3 +70,77 @@ func TestCheckCoherence_SameTechSameVersion_DifferentSections(t *testing.T) {
 	assert.Empty(t, issues,
 		"same technology with the same version across different sections should produce no issues")
 }
+
+func TestSplitStageSections(t *testing.T) {
+	content := "# Design Pack\n\nIntro.\n\n## Assumptions\n\nGo 1.22.\n\n## Data Model (v2)\n\n"
+
+	sections := SplitStageSections(content, "claude")
+	require.Len(t, sections, 3)
+	assert.Equal(t, Section{Name: "preamble", Content: "# Design Pack\n\nIntro.", Agent: "claude"}, sections[0])
+	assert.Equal(t, Section{Name: "assumptions", Content: "Go 1.22.", Agent: "claude"}, sections[1])
+	assert.Equal(t, Section{Name: "data-model-v2", Content: "", Agent: "claude"}, sections[2])
+
+	assert.Empty(t, SplitStageSections("  \n", "claude"))
+
+	fenced := "## Setup\n\n```sh\n## not a heading\necho ok\n```\n\n## Usage\n\nRun it."
+	sections = SplitStageSections(fenced, "claude")
+	require.Len(t, sections, 2)
+	assert.Equal(t, "setup", sections[0].Name)
+	assert.Equal(t, "```sh\n## not a heading\necho ok\n```", sections[0].Content)
+	assert.Equal(t, "usage", sections[1].Name)
+}
+
+// versionSprawl returns n sections that each pin a different Widget version,
+// plus two that disagree on Go.
+func versionSprawl(n int) []Section {
+	sections := []Section{
+		{Name: "toolchain", Content: "Build with Go 1.22."},
+		{Name: "ci", Content: "CI installs Go 1.21."},
+	}
+	for i := range n {
+		sections = append(sections, Section{
+			Name:    fmt.Sprintf("part-%d", i),
+			Content: fmt.Sprintf("This part targets Widget %d.%d.", i/100, i%100),
+		})
+	}
+	return sections
+}
+
+func TestCheckCoherenceLimit_SmallStageRunsFullCheck(t *testing.T) {
+	sections := versionSprawl(10)
+
+	issues, reduced, err := CheckCoherenceLimit(sections, 0)
+	require.NoError(t, err)
+	assert.False(t, reduced)
+	full, err := CheckCoherence(sections)
+	require.NoError(t, err)
+	assert.Len(t, issues, len(full))
+	assert.Len(t, issues, 1+10*9/2)
+}
+
+func TestCheckCoherenceLimit_LargeStageRunsReducedCheck(t *testing.T) {
+	sections := versionSprawl(1000)
+
+	full, err := CheckCoherence(sections)
+	require.NoError(t, err)
+	assert.Len(t, full, 1+1000*999/2)
+
+	issues, reduced, err := CheckCoherenceLimit(sections, 0)
+	require.NoError(t, err)
+	assert.True(t, reduced)
+
+	// The reduced path is cheaper on the same input: one issue per
+	// conflicting dependency, where the full path grows with the square
+	// of the number of versions.
+	require.Len(t, issues, 2)
+	assert.Less(t, len(issues)*100, len(full))
+	byDep := make(map[string]CoherenceIssue)
+	for _, issue := range issues {
+		byDep[strings.Fields(issue.Description)[1]] = issue
+	}
+	goIssue := byDep[`"go"`]
+	assert.ElementsMatch(t, []string{"toolchain", "ci"}, []string{goIssue.SectionA, goIssue.SectionB})
+	assert.Equal(t, `dependency "go" has 2 conflicting versions: 1.21 (in ci), 1.22 (in toolchain)`, goIssue.Description)
+	assert.Contains(t, byDep[`"widget"`].Description, "1000 conflicting versions")
+	assert.Contains(t, byDep[`"widget"`].Description, "995 more versions")
+}
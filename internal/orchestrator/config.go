@@ -1,5 +1,7 @@
 package orchestrator
 
+import "fmt"
+
 // CapabilityLevel describes the detected runtime capabilities.
 // Determines which execution mode the orchestrator uses.
 type CapabilityLevel int
@@ -33,6 +35,32 @@ func (c CapabilityLevel) String() string {
 	}
 }
 
+// LayoutMode controls how stage output files are arranged in OutputDir.
+type LayoutMode string
+
+const (
+	// LayoutFlat writes every stage file directly into OutputDir as
+	// stage-{N}-{name}.md. This is the default.
+	LayoutFlat LayoutMode = "flat"
+
+	// LayoutNested writes each stage into its own stage-{N}/ subdirectory
+	// and maintains an index.md at the OutputDir root.
+	LayoutNested LayoutMode = "nested"
+)
+
+// ParseLayoutMode converts a string to a LayoutMode. An empty string maps to
+// LayoutFlat.
+func ParseLayoutMode(s string) (LayoutMode, error) {
+	switch LayoutMode(s) {
+	case "", LayoutFlat:
+		return LayoutFlat, nil
+	case LayoutNested:
+		return LayoutNested, nil
+	default:
+		return "", fmt.Errorf("unknown layout mode %q (want flat or nested)", s)
+	}
+}
+
 // Config holds runtime configuration for a decomposition run.
 type Config struct {
 	// Name is the decomposition name (kebab-case).
@@ -58,7 +86,9 @@ type Config struct {
 	// InputFile is the path to a high-level input file that seeds Stage 1.
 	InputFile string
 
-	// InputContent is the inline content that seeds Stage 1 (alternative to InputFile).
+	// InputContent is the inline content that seeds Stage 1 (alternative to
+	// InputFile). The design-pack stage includes it in its agent context; see
+	// LoadSeedInput for building it from --input or --input-dir.
 	InputContent string
 
 	// SingleAgent forces single-agent mode regardless of available capabilities.
@@ -67,6 +97,86 @@ type Config struct {
 	// SkipVerification disables the post-stage verification step.
 	SkipVerification bool
 
+	// SkipCoherence disables the cross-section coherence check.
+	SkipCoherence bool
+
+	// CoherenceMaxSections is the section count above which a stage gets
+	// the reduced coherence check; see CheckCoherenceLimit. Zero uses
+	// DefaultCoherenceMaxSections.
+	CoherenceMaxSections int
+
 	// Verbose enables agent-level progress output.
 	Verbose bool
+
+	// LayoutMode selects flat or nested stage output files. The zero value
+	// behaves as LayoutFlat.
+	LayoutMode LayoutMode
+
+	// MaxSectionsPerStage caps the number of agent tasks created for one
+	// stage. Plans with more sections have adjacent sections combined into
+	// shared prompts. Zero means no cap.
+	MaxSectionsPerStage int
+
+	// ParallelAgents caps the number of agent calls in flight across the
+	// whole pipeline, including concurrent stages, independently of how
+	// many sections a stage fans out to. Zero means no cap.
+	ParallelAgents int
+
+	// FailFast cancels a stage's remaining agent calls as soon as one fails
+	// with a non-retryable error; see WithFailFast. By default every call
+	// runs to completion.
+	FailFast bool
+
+	// KeepGoing makes RunPipeline continue past a failed stage instead of
+	// stopping: stages that require it, directly or through another skipped
+	// stage, are skipped, the rest still run, and a *PipelineError listing
+	// every failure is returned alongside the successful results.
+	KeepGoing bool
+
+	// AgentRetries is how many times a failed agent call is resent when
+	// RetryClassifier deems the failure retryable, with a backoff starting
+	// at DefaultAgentRetryBackoff. Zero disables retries.
+	AgentRetries int
+
+	// RetryClassifier decides which agent failures AgentRetries resends and
+	// which end a FailFast stage. Nil uses IsRetryable.
+	RetryClassifier RetryClassifier
+
+	// ProgressBuffer is the size of each progress subscriber's buffer. Zero uses
+	// DefaultProgressBuffer. When the buffer is full, events are dropped and
+	// counted rather than blocking the pipeline; see Pipeline.DroppedEvents.
+	ProgressBuffer int
+
+	// Stages replaces the built-in five-stage pipeline with a custom list of
+	// stage definitions; see StageDefinition. Empty uses the built-in stages.
+	Stages []StageDefinition
+
+	// PostProcessors rewrite each stage's merged content, in order, before
+	// it is written; see GofmtPostProcessor. A failing processor aborts the
+	// stage.
+	PostProcessors []PostProcessor
+
+	// Prerequisites overrides, per stage, the earlier stages that must have
+	// output before the stage runs; see DefaultPrerequisites. The listed
+	// stages are required, and only their output goes into the stage's
+	// agent context. Stages not in the map keep their defaults.
+	Prerequisites map[Stage][]Stage
+
+	// GoValidation, when set, compiles the Go code blocks in the Stage 2
+	// output after it is written and reports compile errors through
+	// progress; see GoValidation. Nil disables the check.
+	GoValidation *GoValidation
+
+	// MergeOnly rebuilds each stage from the sections cached by the last
+	// agent run instead of calling agents: the cached sections are merged,
+	// coherence-checked, post-processed, and written as in a normal run.
+	// Every agent run caches its sections beside the stage file; see
+	// SectionCachePath. A stage without a cache fails.
+	MergeOnly bool
+
+	// MaxContextBytes caps the size of the prior-stage context given to
+	// agents. Sections from earlier stages are trimmed first, down to their
+	// headings, so the most recent stage's content survives longest. Zero
+	// means no cap.
+	MaxContextBytes int
 }
@@ -0,0 +1,149 @@
+package orchestrator
+
+import (
+	"sort"
+	"strings"
+)
+
+// contextTruncatedMarker ends a section body trimmed by fitContextBudget.
+const contextTruncatedMarker = "[... truncated to fit the context budget]"
+
+// contextEntry is one prior-stage section in an agent context message:
+// heading is written verbatim and followed by body and a blank line.
+type contextEntry struct {
+	stage   Stage
+	heading string
+	body    string
+}
+
+// size returns the number of bytes the entry adds to the message.
+func (e contextEntry) size() int {
+	return len(e.heading) + len(e.body) + len("\n\n")
+}
+
+// fitContextBudget trims entries so that together they take at most budget
+// bytes. Every entry keeps its heading and the markdown headings of its body
+// (see trimSectionBody); the rest of the budget is shared out from the latest
+// stage back to the earliest, so earlier stages are the first to lose body
+// text. If even the headings do not fit, entries from the earliest stages are
+// dropped. Entry order is preserved.
+func fitContextBudget(entries []contextEntry, budget int) []contextEntry {
+	total := 0
+	for _, e := range entries {
+		total += e.size()
+	}
+	if total <= budget {
+		return entries
+	}
+
+	// Priority order: latest stage first, then section order.
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return entries[order[a]].stage > entries[order[b]].stage
+	})
+
+	// floor is the least an entry can take: its heading and body outline.
+	floor := make([]int, len(entries))
+	reserved := 0
+	for i, e := range entries {
+		floor[i] = len(e.heading) + len("\n\n") + min(len(e.body), headingsSize(e.body))
+		reserved += floor[i]
+	}
+
+	// Drop the lowest-priority entries until the floors fit.
+	keep := make([]bool, len(entries))
+	for i := range keep {
+		keep[i] = true
+	}
+	for n := len(order) - 1; n >= 0 && reserved > budget; n-- {
+		keep[order[n]] = false
+		reserved -= floor[order[n]]
+	}
+
+	spare := budget - reserved
+	trimmed := make([]contextEntry, len(entries))
+	copy(trimmed, entries)
+	for _, i := range order {
+		if !keep[i] {
+			continue
+		}
+		outline := floor[i] - len(entries[i].heading) - len("\n\n")
+		trimmed[i].body = trimSectionBody(entries[i].body, outline+spare)
+		spare -= max(len(trimmed[i].body)-outline, 0)
+	}
+
+	var out []contextEntry
+	for i, e := range trimmed {
+		if keep[i] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// headingsSize returns the number of bytes taken by the markdown heading
+// lines in body.
+func headingsSize(body string) int {
+	n := 0
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if isMarkdownHeading(line) {
+			n += len(line)
+		}
+	}
+	return n
+}
+
+// trimSectionBody shortens body to at most limit bytes. It keeps every
+// markdown heading so the section's outline survives, fills the remaining
+// room with the body's opening lines, and ends with a truncation marker.
+// When not even the headings fit, as many leading headings as fit are kept.
+func trimSectionBody(body string, limit int) string {
+	if len(body) <= limit {
+		return body
+	}
+	lines := strings.SplitAfter(body, "\n")
+
+	// Reserve room for the headings, the marker, and a newline before it.
+	room := limit - headingsSize(body) - len(contextTruncatedMarker) - 1
+
+	var b strings.Builder
+	if room < 0 {
+		// Headings only, for as many as fit.
+		for _, line := range lines {
+			if !isMarkdownHeading(line) {
+				continue
+			}
+			if b.Len()+len(line) > limit {
+				break
+			}
+			b.WriteString(line)
+		}
+		return b.String()
+	}
+
+	text := true // still copying the body's opening lines
+	for _, line := range lines {
+		switch {
+		case isMarkdownHeading(line):
+			b.WriteString(line)
+		case text && len(line) <= room:
+			b.WriteString(line)
+			room -= len(line)
+		default:
+			text = false
+		}
+	}
+	if s := b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(contextTruncatedMarker)
+	return b.String()
+}
+
+// isMarkdownHeading reports whether line is an ATX markdown heading.
+func isMarkdownHeading(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), "#")
+}
@@ -0,0 +1,67 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildContextMessage_MaxContextBytes(t *testing.T) {
+	long := func(heading string) string {
+		return "## " + heading + "\n\n" + strings.Repeat("Lorem ipsum dolor sit amet.\n", 40)
+	}
+	inputs := []StageResult{
+		{Stage: StageDesignPack, Sections: []Section{
+			{Name: "platform", Content: long("Platform")},
+			{Name: "data-model", Content: long("Data Model")},
+		}},
+		{Stage: StageImplementationSkeletons, Sections: []Section{
+			{Name: "interface-contracts", Content: long("Interfaces")},
+		}},
+		{Stage: StageTaskIndex, Sections: []Section{
+			{Name: "milestones", Content: "## Milestones\n\nM1: Scaffolding\nM2: API\n"},
+		}},
+	}
+
+	full := buildContextMessage(Config{}, StageTaskSpecifications, inputs)
+	const budget = 1200
+	require.Greater(t, len(full), budget)
+
+	msg := buildContextMessage(Config{MaxContextBytes: budget}, StageTaskSpecifications, inputs)
+	assert.LessOrEqual(t, len(msg), budget)
+
+	// The latest stage's section is kept whole.
+	assert.Contains(t, msg, "### task-index / milestones\n\n## Milestones\n\nM1: Scaffolding\nM2: API\n")
+
+	// Earlier sections keep their headings but lose body text.
+	for _, heading := range []string{"## Platform", "## Data Model", "## Interfaces"} {
+		assert.Contains(t, msg, heading)
+	}
+	assert.Contains(t, msg, contextTruncatedMarker)
+	assert.Less(t, strings.Count(msg, "Lorem ipsum"), strings.Count(full, "Lorem ipsum"))
+
+	// Stage 2 is more recent than Stage 1, so it keeps more of its body.
+	stage1 := msg[strings.Index(msg, "### design-pack / platform"):strings.Index(msg, "### implementation-skeletons")]
+	stage2 := msg[strings.Index(msg, "### implementation-skeletons"):strings.Index(msg, "### task-index")]
+	assert.Greater(t, strings.Count(stage2, "Lorem ipsum"), strings.Count(stage1, "Lorem ipsum"))
+
+	// A budget large enough for everything leaves the message unchanged.
+	assert.Equal(t, full, buildContextMessage(Config{MaxContextBytes: len(full)}, StageTaskSpecifications, inputs))
+}
+
+func TestTrimSectionBody(t *testing.T) {
+	body := "## A\n\n" + strings.Repeat("alpha line\n", 10) + "\n## B\n\n" + strings.Repeat("beta line\n", 10)
+
+	assert.Equal(t, body, trimSectionBody(body, len(body)))
+
+	got := trimSectionBody(body, 80)
+	assert.LessOrEqual(t, len(got), 80)
+	assert.Equal(t, "## A\n\nalpha line\nalpha line\n## B\n"+contextTruncatedMarker, got)
+
+	// Too small for the marker: headings only, as many as fit.
+	assert.Equal(t, "## A\n## B\n", trimSectionBody(body, 20))
+	assert.Equal(t, "## A\n", trimSectionBody(body, 6))
+	assert.Empty(t, trimSectionBody(body, 2))
+}
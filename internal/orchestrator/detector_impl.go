@@ -19,18 +19,53 @@ type DefaultDetector struct {
 	client       a2a.Client
 	singleAgent  bool
 	probeTimeout time.Duration
-	portRange    [2]int // [start, end] inclusive
+	portRange    [2]int        // [start, end] inclusive
+	maxAttempts  int           // probes per endpoint before giving up
+	backoff      time.Duration // delay before the second probe, doubled after each failure
+}
+
+// Default probe retry settings of a DefaultDetector; see WithProbeRetry.
+const (
+	DefaultProbeAttempts = 3
+	DefaultProbeBackoff  = 100 * time.Millisecond
+)
+
+// DetectorOption configures a DefaultDetector.
+type DetectorOption func(*DefaultDetector)
+
+// WithProbeRetry sets how many times each endpoint is probed before it is
+// considered unreachable, and the delay before the first retry, so that an
+// agent that is still starting up is not missed. The delay doubles after
+// each further failure. attempts below 1 are treated as 1.
+//
+// Every port in the range without an agent uses up all its attempts, so
+// retries delay every Detect by the sum of the backoffs; one attempt turns
+// retries off.
+func WithProbeRetry(attempts int, backoff time.Duration) DetectorOption {
+	return func(d *DefaultDetector) {
+		d.maxAttempts = max(attempts, 1)
+		d.backoff = backoff
+	}
 }
 
 // NewDefaultDetector creates a DefaultDetector. If singleAgent is true,
-// Detect always returns CapBasic without probing.
-func NewDefaultDetector(client a2a.Client, singleAgent bool) *DefaultDetector {
-	return &DefaultDetector{
+// Detect always returns CapBasic without probing. By default each endpoint
+// is probed up to DefaultProbeAttempts times, starting with a
+// DefaultProbeBackoff delay, so an agent that is still starting up is not
+// missed.
+func NewDefaultDetector(client a2a.Client, singleAgent bool, opts ...DetectorOption) *DefaultDetector {
+	d := &DefaultDetector{
 		client:       client,
 		singleAgent:  singleAgent,
 		probeTimeout: 500 * time.Millisecond,
 		portRange:    [2]int{9100, 9110},
+		maxAttempts:  DefaultProbeAttempts,
+		backoff:      DefaultProbeBackoff,
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
+	return d
 }
 
 // Detect probes for A2A agents, MCP tools, and code intelligence. It returns
@@ -82,7 +117,7 @@ func (d *DefaultDetector) probeAgents(ctx context.Context) []string {
 		wg.Add(1)
 		go func(ep string) {
 			defer wg.Done()
-			if d.probeAgent(ctx, ep) {
+			if d.probeAgentWithRetry(ctx, ep) {
 				mu.Lock()
 				agents = append(agents, ep)
 				mu.Unlock()
@@ -94,6 +129,26 @@ func (d *DefaultDetector) probeAgents(ctx context.Context) []string {
 	return agents
 }
 
+// probeAgentWithRetry probes endpoint up to d.maxAttempts times, sleeping
+// with exponential backoff between attempts. It stops early when ctx is done.
+func (d *DefaultDetector) probeAgentWithRetry(ctx context.Context, endpoint string) bool {
+	delay := d.backoff
+	for attempt := 1; ; attempt++ {
+		if d.probeAgent(ctx, endpoint) {
+			return true
+		}
+		if attempt >= d.maxAttempts {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
 // probeAgent attempts to discover an A2A agent at the given endpoint.
 // Returns true if the agent responds with a valid card within the timeout.
 func (d *DefaultDetector) probeAgent(ctx context.Context, endpoint string) (ok bool) {
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -133,3 +134,78 @@ func TestDetector_MixedReachability(t *testing.T) {
 	assert.Len(t, agents, 1)
 	assert.Contains(t, agents[0], strconv.Itoa(port1))
 }
+
+func TestDetector_RetriesTransientFailure(t *testing.T) {
+	var calls atomic.Int32
+	cards := mockAgentCardHandler()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "starting up", http.StatusServiceUnavailable)
+			return
+		}
+		cards.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+	port := serverPort(t, ts)
+
+	client := a2a.NewHTTPClient()
+	d := NewDefaultDetector(client, false, WithProbeRetry(3, 10*time.Millisecond))
+	d.portRange = [2]int{port, port}
+	d.probeTimeout = 2 * time.Second
+
+	level, agents, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, CapA2AMCP, level)
+	require.Len(t, agents, 1)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestDetector_RetriesByDefault(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	port := serverPort(t, ts)
+
+	client := a2a.NewHTTPClient()
+	d := NewDefaultDetector(client, false)
+	d.portRange = [2]int{port, port}
+	d.probeTimeout = 2 * time.Second
+
+	_, agents, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, agents)
+	assert.Equal(t, int32(DefaultProbeAttempts), calls.Load())
+
+	// One attempt turns retries off.
+	calls.Store(0)
+	d = NewDefaultDetector(client, false, WithProbeRetry(1, 0))
+	d.portRange = [2]int{port, port}
+	d.probeTimeout = 2 * time.Second
+	_, _, err = d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDetector_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	port := serverPort(t, ts)
+
+	client := a2a.NewHTTPClient()
+	d := NewDefaultDetector(client, false, WithProbeRetry(3, 10*time.Millisecond))
+	d.portRange = [2]int{port, port}
+	d.probeTimeout = 2 * time.Second
+
+	level, agents, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, CapMCPOnly, level)
+	assert.Empty(t, agents)
+	assert.Equal(t, int32(3), calls.Load())
+}
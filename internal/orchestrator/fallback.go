@@ -21,10 +21,13 @@ func NewFallbackExecutor(level CapabilityLevel) *FallbackExecutor {
 	return &FallbackExecutor{level: level}
 }
 
-// Execute runs the fallback path for a single stage.
+// Execute runs the fallback path for the stage inferred from inputs.
 func (f *FallbackExecutor) Execute(ctx context.Context, cfg Config, inputs []StageResult) (*StageResult, error) {
-	stage := inferStageFromInputs(inputs)
+	return f.ExecuteStage(ctx, cfg, inferStageFromInputs(inputs, cfg.LastStage()), inputs)
+}
 
+// ExecuteStage runs the fallback path for the given stage.
+func (f *FallbackExecutor) ExecuteStage(ctx context.Context, cfg Config, stage Stage, inputs []StageResult) (*StageResult, error) {
 	switch f.level {
 	case CapBasic:
 		return f.executeTemplate(ctx, cfg, stage, inputs)
@@ -37,11 +40,14 @@ func (f *FallbackExecutor) Execute(ctx context.Context, cfg Config, inputs []Sta
 
 // executeTemplate produces a template file with TODO markers for manual completion.
 func (f *FallbackExecutor) executeTemplate(_ context.Context, cfg Config, stage Stage, _ []StageResult) (*StageResult, error) {
-	plan := MergePlanForStage(stage)
+	plan, err := mergePlanFor(cfg, stage)
+	if err != nil {
+		return nil, fmt.Errorf("fallback template: %w", err)
+	}
 	sections := make([]Section, 0, len(plan.SectionOrder))
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# Stage %d: %s\n\n", int(stage), stage.String()))
+	sb.WriteString(fmt.Sprintf("# Stage %d: %s\n\n", int(stage), cfg.StageName(stage)))
 	sb.WriteString("> Generated in basic mode. Fill in each section below.\n\n")
 
 	for _, name := range plan.SectionOrder {
@@ -55,9 +61,9 @@ func (f *FallbackExecutor) executeTemplate(_ context.Context, cfg Config, stage
 		})
 	}
 
-	outPath := stageOutputPath(cfg, stage)
-	if err := writeOutputFile(outPath, sb.String()); err != nil {
-		return nil, fmt.Errorf("fallback template: write output for stage %d (%s): %w", stage, stage, err)
+	outPath, err := writeStageOutput(cfg, stage, sb.String())
+	if err != nil {
+		return nil, fmt.Errorf("fallback template: write output for stage %d (%s): %w", stage, cfg.StageName(stage), err)
 	}
 
 	return &StageResult{
@@ -71,12 +77,15 @@ func (f *FallbackExecutor) executeTemplate(_ context.Context, cfg Config, stage
 // Without agents, each section is generated with available context and a note
 // about MCP tool availability.
 func (f *FallbackExecutor) executeMCPOnly(_ context.Context, cfg Config, stage Stage, inputs []StageResult) (*StageResult, error) {
-	plan := MergePlanForStage(stage)
-	contextText := buildContextMessage(stage, inputs)
+	plan, err := mergePlanFor(cfg, stage)
+	if err != nil {
+		return nil, fmt.Errorf("fallback mcp-only: %w", err)
+	}
+	contextText := buildContextMessage(cfg, stage, inputs)
 	sections := make([]Section, 0, len(plan.SectionOrder))
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# Stage %d: %s\n\n", int(stage), stage.String()))
+	sb.WriteString(fmt.Sprintf("# Stage %d: %s\n\n", int(stage), cfg.StageName(stage)))
 	sb.WriteString("> Generated in MCP-only mode (single agent, sequential execution).\n\n")
 
 	if contextText != "" {
@@ -96,9 +105,9 @@ func (f *FallbackExecutor) executeMCPOnly(_ context.Context, cfg Config, stage S
 		})
 	}
 
-	outPath := stageOutputPath(cfg, stage)
-	if err := writeOutputFile(outPath, sb.String()); err != nil {
-		return nil, fmt.Errorf("fallback mcp-only: write output for stage %d (%s): %w", stage, stage, err)
+	outPath, err := writeStageOutput(cfg, stage, sb.String())
+	if err != nil {
+		return nil, fmt.Errorf("fallback mcp-only: write output for stage %d (%s): %w", stage, cfg.StageName(stage), err)
 	}
 
 	return &StageResult{
@@ -109,16 +118,18 @@ func (f *FallbackExecutor) executeMCPOnly(_ context.Context, cfg Config, stage S
 }
 
 // inferStageFromInputs determines which stage is being executed based on the
-// inputs that have already been produced.
-func inferStageFromInputs(inputs []StageResult) Stage {
+// inputs that have already been produced. The stage is the smallest stage
+// index up to last not present in the inputs.
+func inferStageFromInputs(inputs []StageResult, last Stage) Stage {
 	present := make(map[Stage]bool, len(inputs))
 	for _, r := range inputs {
 		present[r.Stage] = true
 	}
-	for s := StageDevelopmentStandards; s <= StageTaskSpecifications; s++ {
+	for s := StageDevelopmentStandards; s <= last; s++ {
 		if !present[s] {
 			return s
 		}
 	}
-	return StageTaskSpecifications
+	// All stages present: default to the last one.
+	return last
 }
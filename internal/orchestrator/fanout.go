@@ -2,7 +2,10 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/onedusk/pd/internal/a2a"
 	"golang.org/x/sync/errgroup"
@@ -18,6 +21,14 @@ type AgentTask struct {
 
 	// Section identifies which section of the stage this task produces.
 	Section string
+
+	// Sections lists the plan sections covered by a combined task, in order.
+	// Nil for a task that produces the single section named by Section.
+	Sections []string
+
+	// AcceptedOutputModes lists the media types the agent may answer with,
+	// in order of preference; see Config.OutputModes. Nil accepts any.
+	AcceptedOutputModes []string
 }
 
 // AgentResult holds the outcome of a single AgentTask after fan-out.
@@ -25,6 +36,9 @@ type AgentResult struct {
 	// Section identifies which section of the stage this result belongs to.
 	Section string
 
+	// Sections is copied from the AgentTask; see AgentTask.Sections.
+	Sections []string
+
 	// Artifacts are the outputs produced by the agent on success.
 	Artifacts []a2a.Artifact
 
@@ -35,33 +49,198 @@ type AgentResult struct {
 	Task *a2a.Task
 }
 
+// AgentBudget caps the number of agent calls in flight at once. A single
+// budget can be shared by several FanOuts (or several concurrent Runs of one
+// FanOut) so that parallel stages together never exceed an agent fleet's
+// capacity. A nil *AgentBudget imposes no limit.
+type AgentBudget struct {
+	slots chan struct{}
+}
+
+// NewAgentBudget returns a budget allowing at most n concurrent agent calls.
+// It returns nil (no limit) when n <= 0.
+func NewAgentBudget(n int) *AgentBudget {
+	if n <= 0 {
+		return nil
+	}
+	return &AgentBudget{slots: make(chan struct{}, n)}
+}
+
+// acquire blocks until a slot is free or ctx is done.
+func (b *AgentBudget) acquire(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire.
+func (b *AgentBudget) release() {
+	if b == nil {
+		return
+	}
+	<-b.slots
+}
+
+// NonRetryableError marks an agent failure that resending the same task
+// cannot fix. Clients may wrap errors in it; see IsRetryable.
+type NonRetryableError struct {
+	Err error
+}
+
+func (e *NonRetryableError) Error() string { return e.Err.Error() }
+
+func (e *NonRetryableError) Unwrap() error { return e.Err }
+
+// RetryClassifier reports whether a failed agent call may succeed if sent
+// again. FanOut uses it to decide which failures to retry and which abort a
+// fail-fast run; IsRetryable is the default.
+type RetryClassifier func(error) bool
+
+// retryableRPCCodes are the JSON-RPC error codes IsRetryable treats as
+// transient: an internal error may be a momentary agent-side failure, while
+// every other code rejects the request itself or names a missing task.
+var retryableRPCCodes = map[int]bool{
+	a2a.ErrCodeInternal: true,
+}
+
+// IsRetryable is the default RetryClassifier. Errors wrapping a
+// NonRetryableError are not retryable, nor are canceled contexts, JSON-RPC
+// errors other than an internal error, or HTTP responses below 500. Deadline
+// expiries, HTTP 5xx responses, and connection and other transport failures
+// are retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var nonRetryable *NonRetryableError
+	if errors.As(err, &nonRetryable) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var rpcErr *a2a.RPCError
+	if errors.As(err, &rpcErr) {
+		return retryableRPCCodes[rpcErr.Code]
+	}
+	var statusErr *a2a.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	return true
+}
+
 // FanOut dispatches AgentTasks to remote A2A agents in parallel and collects
-// their results. If any agent fails, the derived context is canceled so that
-// remaining in-flight calls are abandoned promptly.
+// their results. By default every task runs to completion even when a
+// sibling fails; with WithFailFast, a non-retryable failure cancels the
+// remaining in-flight calls. With WithRetry, retryable failures are resent.
 type FanOut struct {
-	client     a2a.Client
-	onProgress func(ProgressEvent)
-	mu         sync.Mutex // guards nothing at struct level; kept for future use
+	client      a2a.Client
+	onProgress  func(ProgressEvent)
+	budget      *AgentBudget
+	failFast    bool
+	retryable   RetryClassifier
+	maxAttempts int           // SendMessage calls per task, including the first
+	backoff     time.Duration // delay before the second attempt, doubled after each failure
+	mu          sync.Mutex    // guards nothing at struct level; kept for future use
+}
+
+// FanOutOption configures a FanOut.
+type FanOutOption func(*FanOut)
+
+// WithAgentBudget limits the FanOut's concurrent SendMessage calls to the
+// shared budget b. Tasks waiting for a slot stay pending.
+func WithAgentBudget(b *AgentBudget) FanOutOption {
+	return func(f *FanOut) {
+		f.budget = b
+	}
+}
+
+// WithRetryClassifier replaces IsRetryable as the FanOut's test of which
+// failures WithRetry resends and which abort a WithFailFast run. A nil
+// classifier keeps IsRetryable.
+func WithRetryClassifier(c RetryClassifier) FanOutOption {
+	return func(f *FanOut) {
+		if c != nil {
+			f.retryable = c
+		}
+	}
+}
+
+// DefaultAgentRetryBackoff is the delay before the first retry of a failed
+// agent call when Config.AgentRetries is set.
+const DefaultAgentRetryBackoff = 500 * time.Millisecond
+
+// WithRetry sends each task up to attempts times while its failures are
+// retryable (see WithRetryClassifier), waiting backoff before the first
+// retry and doubling the delay after each further failure. attempts below 1
+// are treated as 1, which disables retries.
+func WithRetry(attempts int, backoff time.Duration) FanOutOption {
+	return func(f *FanOut) {
+		f.maxAttempts = max(attempts, 1)
+		f.backoff = backoff
+	}
+}
+
+// WithFailFast makes a non-retryable task failure (see WithRetryClassifier) cancel
+// the context shared by the other tasks of the same Run, so that a stage
+// that cannot succeed stops promptly instead of waiting for its siblings.
+func WithFailFast() FanOutOption {
+	return func(f *FanOut) {
+		f.failFast = true
+	}
 }
 
 // NewFanOut creates a FanOut that dispatches tasks via client.
 // onProgress is called synchronously from each goroutine; it may be nil.
-func NewFanOut(client a2a.Client, onProgress func(ProgressEvent)) *FanOut {
-	return &FanOut{
-		client:     client,
-		onProgress: onProgress,
+func NewFanOut(client a2a.Client, onProgress func(ProgressEvent), opts ...FanOutOption) *FanOut {
+	f := &FanOut{
+		client:      client,
+		onProgress:  onProgress,
+		retryable:   IsRetryable,
+		maxAttempts: 1,
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
-// Run dispatches every task in parallel, emitting progress events for each.
-// It uses errgroup.WithContext so that the first agent failure cancels the
-// derived context, causing remaining SendMessage calls to return early.
+// Run dispatches every task in parallel, emitting progress events for each,
+// and waits for all of them. With WithFailFast, the first non-retryable
+// failure cancels the context shared by the tasks, causing the remaining
+// SendMessage calls to return early.
 //
 // All collected AgentResults are returned regardless of whether an error
-// occurred. The returned error is the first non-nil error from the errgroup.
+// occurred. The returned error is the failure that canceled the run, if
+// any, and otherwise the first task error.
 func (f *FanOut) Run(ctx context.Context, stage Stage, tasks []AgentTask) ([]AgentResult, error) {
 	results := make([]AgentResult, len(tasks))
-	g, gctx := errgroup.WithContext(ctx)
+	gctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var g errgroup.Group
+
+	var (
+		abortOnce sync.Once
+		abortErr  error
+	)
+	abort := func(err error) {
+		if f.failFast && !f.retryable(err) {
+			abortOnce.Do(func() {
+				abortErr = err
+				cancel()
+			})
+		}
+	}
 
 	for i, task := range tasks {
 		f.emit(ProgressEvent{
@@ -71,6 +250,16 @@ func (f *FanOut) Run(ctx context.Context, stage Stage, tasks []AgentTask) ([]Age
 		})
 
 		g.Go(func() error {
+			if err := f.budget.acquire(gctx); err != nil {
+				results[i] = AgentResult{
+					Section:  task.Section,
+					Sections: task.Sections,
+					Err:      err,
+				}
+				return err
+			}
+			defer f.budget.release()
+
 			f.emit(ProgressEvent{
 				Stage:   stage,
 				Section: task.Section,
@@ -78,15 +267,19 @@ func (f *FanOut) Run(ctx context.Context, stage Stage, tasks []AgentTask) ([]Age
 			})
 
 			req := a2a.SendMessageRequest{
-				Message:       task.Message,
-				Configuration: &a2a.SendMessageConfig{Blocking: true},
+				Message: task.Message,
+				Configuration: &a2a.SendMessageConfig{
+					AcceptedOutputModes: task.AcceptedOutputModes,
+					Blocking:            true,
+				},
 			}
 
-			t, err := f.client.SendMessage(gctx, task.AgentEndpoint, req)
+			t, err := f.send(gctx, stage, task, req)
 			if err != nil {
 				results[i] = AgentResult{
-					Section: task.Section,
-					Err:     err,
+					Section:  task.Section,
+					Sections: task.Sections,
+					Err:      err,
 				}
 				f.emit(ProgressEvent{
 					Stage:   stage,
@@ -94,11 +287,13 @@ func (f *FanOut) Run(ctx context.Context, stage Stage, tasks []AgentTask) ([]Age
 					Status:  ProgressFailed,
 					Message: err.Error(),
 				})
-				return err // triggers context cancellation for other goroutines
+				abort(err)
+				return err
 			}
 
 			results[i] = AgentResult{
 				Section:   task.Section,
+				Sections:  task.Sections,
 				Artifacts: t.Artifacts,
 				Task:      t,
 			}
@@ -112,9 +307,36 @@ func (f *FanOut) Run(ctx context.Context, stage Stage, tasks []AgentTask) ([]Age
 	}
 
 	err := g.Wait()
+	if abortErr != nil {
+		return results, abortErr
+	}
 	return results, err
 }
 
+// send sends req to the task's agent, retrying retryable failures as
+// configured by WithRetry. It stops early when ctx is done.
+func (f *FanOut) send(ctx context.Context, stage Stage, task AgentTask, req a2a.SendMessageRequest) (*a2a.Task, error) {
+	delay := f.backoff
+	for attempt := 1; ; attempt++ {
+		t, err := f.client.SendMessage(ctx, task.AgentEndpoint, req)
+		if err == nil || attempt >= f.maxAttempts || !f.retryable(err) {
+			return t, err
+		}
+		f.emit(ProgressEvent{
+			Stage:   stage,
+			Section: task.Section,
+			Status:  ProgressWorking,
+			Message: fmt.Sprintf("retrying (attempt %d of %d) after: %v", attempt+1, f.maxAttempts, err),
+		})
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
 // emit sends a progress event if a callback is registered.
 func (f *FanOut) emit(ev ProgressEvent) {
 	if f.onProgress != nil {
@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -281,3 +282,221 @@ func TestFanOut_ProgressEventsEmitted(t *testing.T) {
 		assert.True(t, statuses[ProgressComplete], "missing Complete event for section %q", task.Section)
 	}
 }
+
+func TestFanOut_AgentBudgetAcrossConcurrentStages(t *testing.T) {
+	const budget = 2
+	var inFlight, peak, calls atomic.Int32
+	client := &mockClient{
+		sendMessage: func(ctx context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			calls.Add(1)
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return completedTask(req.Message.MessageID, "section"), nil
+		},
+	}
+
+	p := NewPipeline(Config{ParallelAgents: budget, Capability: CapA2AMCP}, client)
+	defer p.Close()
+
+	// Two stages fan out at the same time through the pipeline's FanOut.
+	var wg sync.WaitGroup
+	for _, stage := range []Stage{StageDesignPack, StageImplementationSkeletons} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := p.fanout.Run(context.Background(), stage, makeTasks(5))
+			assert.NoError(t, err)
+			assert.Len(t, results, 5)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(10), calls.Load())
+	assert.LessOrEqual(t, peak.Load(), int32(budget), "simultaneous SendMessage calls exceeded the budget")
+	assert.Equal(t, int32(budget), peak.Load(), "budget should be fully used")
+}
+
+func TestFanOut_AgentBudgetCanceledWhileWaiting(t *testing.T) {
+	release := make(chan struct{})
+	client := &mockClient{
+		sendMessage: func(ctx context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			<-release
+			return completedTask(req.Message.MessageID, "section"), nil
+		},
+	}
+	fo := NewFanOut(client, nil, WithAgentBudget(NewAgentBudget(1)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := fo.Run(ctx, StageDesignPack, makeTasks(2))
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	close(release)
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
+
+// failFastClient fails "api-contracts" immediately with err and blocks every
+// other task until its context is canceled or release is closed. canceled
+// counts the tasks that observed cancellation.
+func failFastClient(err error, release <-chan struct{}, canceled *atomic.Int32) *mockClient {
+	return &mockClient{
+		sendMessage: func(ctx context.Context, endpoint string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			if req.Message.MessageID == "msg-api-contracts" {
+				return nil, err
+			}
+			select {
+			case <-ctx.Done():
+				canceled.Add(1)
+				return nil, ctx.Err()
+			case <-release:
+				section := req.Message.Parts[0].Text
+				return completedTask("t-"+section, section), nil
+			}
+		},
+	}
+}
+
+func TestFanOut_FailFastCancelsSiblings(t *testing.T) {
+	hardErr := &a2a.RPCError{Method: "message/send", Code: a2a.ErrCodeInvalidParams, Message: "bad message"}
+	var canceled atomic.Int32
+	fanout := NewFanOut(failFastClient(hardErr, nil, &canceled), nil, WithFailFast())
+
+	start := time.Now()
+	results, err := fanout.Run(context.Background(), StageDesignPack, makeTasks(3))
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	assert.ErrorIs(t, err, hardErr, "the hard failure is returned, not a sibling's cancellation")
+	assert.Equal(t, int32(2), canceled.Load(), "slow siblings observe cancellation")
+	require.Len(t, results, 3)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+	assert.ErrorIs(t, results[1].Err, hardErr)
+	assert.ErrorIs(t, results[2].Err, context.Canceled)
+}
+
+func TestFanOut_WaitsForAllByDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		opts []FanOutOption
+	}{
+		{"non-retryable failure without FailFast", &NonRetryableError{Err: errors.New("unknown skill")}, nil},
+		{"retryable failure with FailFast", errors.New("agent timeout"), []FanOutOption{WithFailFast()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			var canceled atomic.Int32
+			fanout := NewFanOut(failFastClient(tt.err, release, &canceled), nil, tt.opts...)
+
+			done := make(chan struct{})
+			var results []AgentResult
+			var err error
+			go func() {
+				results, err = fanout.Run(context.Background(), StageDesignPack, makeTasks(3))
+				close(done)
+			}()
+			select {
+			case <-done:
+				t.Fatal("Run returned before the slow siblings finished")
+			case <-time.After(50 * time.Millisecond):
+			}
+			close(release)
+			<-done
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Zero(t, canceled.Load())
+			assert.NoError(t, results[0].Err)
+			assert.NoError(t, results[2].Err)
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.True(t, IsRetryable(errors.New("connection reset")))
+	assert.True(t, IsRetryable(&a2a.RPCError{Code: a2a.ErrCodeInternal}))
+	assert.False(t, IsRetryable(&a2a.RPCError{Code: a2a.ErrCodeMethodNotFound}))
+	assert.False(t, IsRetryable(fmt.Errorf("send: %w", &NonRetryableError{Err: errors.New("x")})))
+	assert.False(t, IsRetryable(&a2a.RPCError{Code: a2a.ErrCodeTaskNotFound}))
+	assert.True(t, IsRetryable(&a2a.StatusError{Op: "message/send", StatusCode: 503}))
+	assert.False(t, IsRetryable(&a2a.StatusError{Op: "message/send", StatusCode: 404}))
+	assert.True(t, IsRetryable(fmt.Errorf("send: %w", context.DeadlineExceeded)))
+	assert.False(t, IsRetryable(context.Canceled))
+}
+
+func TestFanOut_RetryClassifier(t *testing.T) {
+	// The agent answers with an application-specific "overloaded" error
+	// before succeeding.
+	const codeOverloaded = -32050
+	newClient := func(calls *atomic.Int32) *mockClient {
+		return &mockClient{
+			sendMessage: func(ctx context.Context, endpoint string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+				if calls.Add(1) == 1 {
+					return nil, &a2a.RPCError{Method: "message/send", Code: codeOverloaded, Message: "overloaded"}
+				}
+				return completedTask("t1", "platform-baseline"), nil
+			},
+		}
+	}
+
+	t.Run("custom classifier retries the designated code", func(t *testing.T) {
+		var calls atomic.Int32
+		classify := func(err error) bool {
+			var rpcErr *a2a.RPCError
+			if errors.As(err, &rpcErr) && rpcErr.Code == codeOverloaded {
+				return true
+			}
+			return IsRetryable(err)
+		}
+		fo := NewFanOut(newClient(&calls), nil, WithRetry(3, time.Millisecond), WithRetryClassifier(classify))
+
+		results, err := fo.Run(context.Background(), StageDesignPack, makeTasks(1))
+		require.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("default classifier does not retry it", func(t *testing.T) {
+		var calls atomic.Int32
+		fo := NewFanOut(newClient(&calls), nil, WithRetry(3, time.Millisecond))
+
+		results, err := fo.Run(context.Background(), StageDesignPack, makeTasks(1))
+		require.Error(t, err)
+		var rpcErr *a2a.RPCError
+		require.ErrorAs(t, results[0].Err, &rpcErr)
+		assert.Equal(t, codeOverloaded, rpcErr.Code)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("retries stop after the configured attempts", func(t *testing.T) {
+		var calls atomic.Int32
+		client := &mockClient{
+			sendMessage: func(ctx context.Context, endpoint string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+				calls.Add(1)
+				return nil, &a2a.StatusError{Op: "message/send", StatusCode: 502}
+			},
+		}
+		fo := NewFanOut(client, nil, WithRetry(3, time.Millisecond))
+
+		_, err := fo.Run(context.Background(), StageDesignPack, makeTasks(1))
+		require.Error(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+}
@@ -0,0 +1,165 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GoBuildFunc runs "go build ./..." in dir and returns the combined
+// toolchain output. A non-nil error means the build failed.
+type GoBuildFunc func(ctx context.Context, dir string) ([]byte, error)
+
+// RunGoBuild is the default GoBuildFunc. It invokes the go toolchain on PATH.
+func RunGoBuild(ctx context.Context, dir string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", "build", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	return cmd.CombinedOutput()
+}
+
+// GoValidation configures compile checking of the Go code blocks in the
+// Stage 2 (implementation skeletons) output; see ValidateGoBlocks.
+type GoValidation struct {
+	// FailStage makes a compile failure fail the stage. Otherwise the
+	// errors are only reported through progress.
+	FailStage bool
+
+	// Build runs the go toolchain. Nil uses RunGoBuild.
+	Build GoBuildFunc
+}
+
+// GoCompileError reports Go code blocks that failed to compile. Output is
+// the toolchain output, with file names such as "store/block_03.go" naming
+// the package and the 1-based position of the block in the stage output.
+type GoCompileError struct {
+	Output string
+}
+
+func (e *GoCompileError) Error() string {
+	return "generated Go does not compile:\n" + e.Output
+}
+
+// ValidateGoBlocks compiles the ```go fenced code blocks in content. Each
+// block is written to a temporary module as its own file, in a directory
+// named after its package clause, and the module is built with build.
+// Blocks without a package clause are skipped, as they are snippets rather
+// than files. The module has no dependencies, so blocks may import only the
+// standard library and each other as "skeleton/<package>". It returns a
+// *GoCompileError when the build fails, and nil when it succeeds or there
+// is nothing to build.
+func ValidateGoBlocks(ctx context.Context, content string, build GoBuildFunc) error {
+	if build == nil {
+		build = RunGoBuild
+	}
+
+	dir, err := os.MkdirTemp("", "decompose-validate-*")
+	if err != nil {
+		return fmt.Errorf("validate go: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	written := 0
+	for i, block := range goCodeBlocks(content) {
+		pkg := goPackageName(block)
+		if pkg == "" {
+			continue
+		}
+		path := filepath.Join(dir, pkg, fmt.Sprintf("block_%02d.go", i+1))
+		if err := writeOutputFile(path, block); err != nil {
+			return fmt.Errorf("validate go: %w", err)
+		}
+		written++
+	}
+	if written == 0 {
+		return nil
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module skeleton\n\ngo 1.21\n"), 0o644); err != nil {
+		return fmt.Errorf("validate go: %w", err)
+	}
+
+	out, err := build(ctx, dir)
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if len(out) == 0 && !errors.As(err, &exitErr) {
+		// The toolchain could not be run at all.
+		return fmt.Errorf("validate go: %w", err)
+	}
+	return &GoCompileError{Output: strings.TrimSpace(strings.ReplaceAll(string(out), dir+string(filepath.Separator), ""))}
+}
+
+// goCodeBlocks returns the bodies of the ```go fenced code blocks in
+// content, in order. An unterminated block runs to the end of content.
+func goCodeBlocks(content string) []string {
+	var blocks []string
+	lines := strings.SplitAfter(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "```go" {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "```" {
+			end++
+		}
+		blocks = append(blocks, strings.Join(lines[i+1:end], ""))
+		i = end
+	}
+	return blocks
+}
+
+// goPackageName returns the name in src's package clause, or "" if src has
+// none.
+func goPackageName(src string) string {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly)
+	if err != nil || f.Name == nil {
+		return ""
+	}
+	return f.Name.Name
+}
+
+// validateGoOutput runs cfg.GoValidation against content when stage is the
+// implementation skeletons stage, reporting the result through progress.
+// The returned error is non-nil only when the code does not compile and
+// FailStage is set; a toolchain that cannot be run is logged as a warning.
+func (p *Pipeline) validateGoOutput(ctx context.Context, cfg Config, stage Stage, content string) error {
+	if cfg.GoValidation == nil || stage != StageImplementationSkeletons {
+		return nil
+	}
+	p.progress.Emit(ProgressEvent{
+		Stage:   stage,
+		Section: "go-build",
+		Status:  ProgressVerifying,
+	})
+
+	err := ValidateGoBlocks(ctx, content, cfg.GoValidation.Build)
+	var compileErr *GoCompileError
+	switch {
+	case err == nil:
+		p.progress.Emit(ProgressEvent{Stage: stage, Section: "go-build", Status: ProgressComplete})
+		return nil
+	case errors.As(err, &compileErr):
+		p.progress.Emit(ProgressEvent{
+			Stage:   stage,
+			Section: "go-build",
+			Status:  ProgressFailed,
+			Message: compileErr.Output,
+		})
+		if cfg.GoValidation.FailStage {
+			return err
+		}
+		return nil
+	default:
+		log.Printf("WARNING: could not validate Go for stage %d (%s): %v", stage, cfg.StageName(stage), err)
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const brokenSkeleton = "## Data Model\n\n" +
+	"```go\npackage store\n\nfunc Get() int {\n\treturn \"not an int\"\n}\n```\n\n" +
+	"A snippet without a package clause is skipped:\n\n" +
+	"```go\nx := 1\n```\n"
+
+// fakeGoBuild returns a GoBuildFunc that reports a compile error for every
+// file containing `return "` and records the files it was asked to build.
+func fakeGoBuild(files *[]string) GoBuildFunc {
+	return func(_ context.Context, dir string) ([]byte, error) {
+		var out []string
+		err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".go" {
+				return err
+			}
+			rel, _ := filepath.Rel(dir, path)
+			*files = append(*files, rel)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(string(data), `return "`) {
+				out = append(out, filepath.Join(dir, rel)+":4:9: cannot use \"not an int\" (untyped string constant) as int value in return statement")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(out) > 0 {
+			return []byte(strings.Join(out, "\n")), errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+}
+
+func TestValidateGoBlocks_ReportsCompileError(t *testing.T) {
+	var files []string
+	err := ValidateGoBlocks(context.Background(), brokenSkeleton, fakeGoBuild(&files))
+
+	var compileErr *GoCompileError
+	require.ErrorAs(t, err, &compileErr)
+	assert.Equal(t, []string{filepath.Join("store", "block_01.go")}, files)
+	assert.Contains(t, compileErr.Output, "store/block_01.go:4:9: cannot use")
+	assert.NotContains(t, compileErr.Output, os.TempDir(), "temp dir should be stripped from the output")
+}
+
+func TestValidateGoBlocks_NothingToBuild(t *testing.T) {
+	build := func(context.Context, string) ([]byte, error) {
+		t.Fatal("build should not run without package-level blocks")
+		return nil, nil
+	}
+	assert.NoError(t, ValidateGoBlocks(context.Background(), "```go\nx := 1\n```\n", build))
+}
+
+func TestValidateGoBlocks_GoToolchain(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	ctx := context.Background()
+
+	err := ValidateGoBlocks(ctx, brokenSkeleton, nil)
+	var compileErr *GoCompileError
+	require.ErrorAs(t, err, &compileErr)
+	assert.Contains(t, compileErr.Output, "block_01.go")
+
+	fixed := strings.Replace(brokenSkeleton, `return "not an int"`, "return 1", 1)
+	assert.NoError(t, ValidateGoBlocks(ctx, fixed, nil))
+}
+
+func TestPipeline_GoValidation(t *testing.T) {
+	client := &mockClient{
+		sendMessage: func(_ context.Context, _ string, _ a2a.SendMessageRequest) (*a2a.Task, error) {
+			return &a2a.Task{
+				ID:     "t",
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+				Artifacts: []a2a.Artifact{
+					{ArtifactID: "art", Parts: []a2a.Part{a2a.TextPart(brokenSkeleton)}},
+				},
+			}, nil
+		},
+	}
+
+	run := func(t *testing.T, failStage bool) ([]ProgressEvent, error) {
+		var files []string
+		cfg := Config{
+			Name:             "validate",
+			OutputDir:        t.TempDir(),
+			Capability:       CapA2AMCP,
+			AgentEndpoints:   []string{"http://a"},
+			SkipVerification: true,
+			GoValidation:     &GoValidation{FailStage: failStage, Build: fakeGoBuild(&files)},
+		}
+		p := NewPipeline(cfg, client)
+		_, err := p.ExecuteStage(context.Background(), cfg, StageImplementationSkeletons, nil)
+		p.Close()
+
+		var events []ProgressEvent
+		for ev := range p.Progress() {
+			if ev.Section == "go-build" {
+				events = append(events, ev)
+			}
+		}
+		return events, err
+	}
+
+	t.Run("reports through progress", func(t *testing.T) {
+		events, err := run(t, false)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, ProgressVerifying, events[0].Status)
+		assert.Equal(t, ProgressFailed, events[1].Status)
+		assert.Contains(t, events[1].Message, "cannot use \"not an int\"")
+	})
+
+	t.Run("fails the stage when configured", func(t *testing.T) {
+		_, err := run(t, true)
+		var compileErr *GoCompileError
+		require.ErrorAs(t, err, &compileErr)
+		assert.Contains(t, err.Error(), "pipeline: validate stage 2")
+	})
+}
@@ -57,7 +57,8 @@ func NewImplementPipeline(
 	}
 }
 
-// Progress returns a channel that emits progress events.
+// Progress subscribes to progress events. Each call returns an independent
+// channel; see ProgressReporter.Subscribe.
 func (ip *ImplementPipeline) Progress() <-chan ProgressEvent {
 	return ip.progress.Subscribe()
 }
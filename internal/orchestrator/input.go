@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// seedExtensions are the file types read from an input directory.
+var seedExtensions = map[string]bool{".md": true, ".markdown": true, ".txt": true}
+
+// LoadSeedInput returns the content that seeds Stage 1, read from a single
+// input file or concatenated from an input directory. Setting both is an
+// error; setting neither returns "".
+func LoadSeedInput(inputFile, inputDir string) (string, error) {
+	switch {
+	case inputFile != "" && inputDir != "":
+		return "", fmt.Errorf("--input and --input-dir are mutually exclusive")
+	case inputFile != "":
+		data, err := os.ReadFile(inputFile)
+		if err != nil {
+			return "", fmt.Errorf("read input file: %w", err)
+		}
+		return string(data), nil
+	case inputDir != "":
+		return ConcatInputDir(inputDir)
+	}
+	return "", nil
+}
+
+// ConcatInputDir concatenates the markdown and text files directly inside dir
+// in filename order. Each file is preceded by a "## <filename>" header so the
+// combined seed keeps track of where each part came from.
+func ConcatInputDir(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("read input dir: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && seedExtensions[strings.ToLower(filepath.Ext(e.Name()))] {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("input dir %s has no .md, .markdown, or .txt files", dir)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "", fmt.Errorf("read input file: %w", err)
+		}
+		parts = append(parts, fmt.Sprintf("## %s\n\n%s", name, strings.TrimSpace(string(data))))
+	}
+	return strings.Join(parts, "\n\n") + "\n", nil
+}
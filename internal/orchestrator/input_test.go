@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcatInputDir_FilenameOrderWithHeaders(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"02-api.md":      "# API\n\nEndpoints.",
+		"01-overview.md": "# Overview\n\nThe idea.\n",
+		"03-data.md":     "# Data\n\nTables.",
+		"diagram.png":    "not text",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "drafts.md"), 0o755))
+
+	got, err := ConcatInputDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "## 01-overview.md\n\n# Overview\n\nThe idea.\n\n"+
+		"## 02-api.md\n\n# API\n\nEndpoints.\n\n"+
+		"## 03-data.md\n\n# Data\n\nTables.\n", got)
+}
+
+func TestConcatInputDir_NoMatchingFiles(t *testing.T) {
+	_, err := ConcatInputDir(t.TempDir())
+	require.Error(t, err)
+}
+
+func TestLoadSeedInput(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "idea.md")
+	require.NoError(t, os.WriteFile(file, []byte("An idea."), 0o644))
+
+	got, err := LoadSeedInput(file, "")
+	require.NoError(t, err)
+	assert.Equal(t, "An idea.", got)
+
+	got, err = LoadSeedInput("", "")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = LoadSeedInput(file, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mutually exclusive")
+}
+
+func TestBuildContextMessage_SeedsDesignPackOnly(t *testing.T) {
+	cfg := Config{InputContent: "Build a todo app."}
+
+	msg := buildContextMessage(cfg, StageDesignPack, nil)
+	assert.Contains(t, msg, "## High-level input\n\nBuild a todo app.")
+
+	assert.Empty(t, buildContextMessage(cfg, StageImplementationSkeletons, nil))
+}
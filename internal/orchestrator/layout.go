@@ -0,0 +1,126 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stageIndexFileName is the index written at the OutputDir root in nested
+// layout mode.
+const stageIndexFileName = "index.md"
+
+// StageFilePath returns the output file path for a stage under dir using the
+// given layout:
+//
+//	flat:   <dir>/stage-{N}-{name}.md
+//	nested: <dir>/stage-{N}/{name}.md
+func StageFilePath(dir string, stage Stage, layout LayoutMode) string {
+	return stageFilePathNamed(dir, stage, stage.String(), layout)
+}
+
+// stageFilePathNamed is StageFilePath with an explicit stage name, used for
+// custom pipelines.
+func stageFilePathNamed(dir string, stage Stage, name string, layout LayoutMode) string {
+	if layout == LayoutNested {
+		return filepath.Join(dir, fmt.Sprintf("stage-%d", int(stage)), name+".md")
+	}
+	return filepath.Join(dir, fmt.Sprintf("stage-%d-%s.md", int(stage), name))
+}
+
+// stageIndexEntry describes one stage row in the generated index.
+type stageIndexEntry struct {
+	Stage    Stage
+	Name     string
+	RelPath  string // path relative to the index file, slash-separated
+	Complete bool
+}
+
+// collectStageIndex stats the output file of every stage and returns one
+// entry per stage in pipeline order.
+func collectStageIndex(cfg Config) []stageIndexEntry {
+	entries := make([]stageIndexEntry, 0, int(cfg.LastStage())+1)
+	for s := StageDevelopmentStandards; s <= cfg.LastStage(); s++ {
+		p := stageOutputPath(cfg, s)
+		rel, err := filepath.Rel(cfg.OutputDir, p)
+		if err != nil {
+			rel = p
+		}
+		_, statErr := os.Stat(p)
+		complete := statErr == nil
+		if !complete && s == StageTaskSpecifications && len(cfg.Stages) == 0 {
+			// A fanned-out Stage 4 writes tasks_mNN.md files instead.
+			if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(p), "tasks_m*.md")); len(matches) > 0 {
+				complete = true
+				if rel, err = filepath.Rel(cfg.OutputDir, matches[0]); err != nil {
+					rel = matches[0]
+				}
+			}
+		}
+		entries = append(entries, stageIndexEntry{
+			Stage:    s,
+			Name:     cfg.StageName(s),
+			RelPath:  filepath.ToSlash(rel),
+			Complete: complete,
+		})
+	}
+	return entries
+}
+
+// renderStageIndex formats the stage index as markdown. Only completed
+// stages are linked; pending stages are listed without a link.
+func renderStageIndex(name string, entries []stageIndexEntry) string {
+	var b strings.Builder
+	if name != "" {
+		fmt.Fprintf(&b, "# %s\n\n", name)
+	} else {
+		b.WriteString("# Decomposition\n\n")
+	}
+	b.WriteString("| Stage | Name | Status |\n")
+	b.WriteString("|:-----:|------|--------|\n")
+	for _, e := range entries {
+		label := e.Name
+		status := "pending"
+		if e.Complete {
+			label = fmt.Sprintf("[%s](%s)", e.Name, e.RelPath)
+			status = "complete"
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s |\n", int(e.Stage), label, status)
+	}
+	return b.String()
+}
+
+// writeStageIndex regenerates index.md at the root of cfg.OutputDir.
+func writeStageIndex(cfg Config) error {
+	path := filepath.Join(cfg.OutputDir, stageIndexFileName)
+	return writeOutputFile(path, renderStageIndex(cfg.Name, collectStageIndex(cfg)))
+}
+
+// writeStageOutput writes the merged content of a stage to its layout-specific
+// path and, in nested mode, refreshes the stage index. Content is passed
+// through cfg.PostProcessors first. It returns the path of the written stage
+// file.
+func writeStageOutput(cfg Config, stage Stage, content string) (string, error) {
+	content, err := applyPostProcessors(cfg.PostProcessors, stage, content)
+	if err != nil {
+		return "", err
+	}
+	outPath := stageOutputPath(cfg, stage)
+	if err := writeOutputFile(outPath, content); err != nil {
+		return "", err
+	}
+	if cfg.LayoutMode == LayoutNested {
+		if err := writeStageIndex(cfg); err != nil {
+			return "", fmt.Errorf("update index: %w", err)
+		}
+	}
+	return outPath, nil
+}
+
+// WriteStageFile writes content as the output of stage under cfg.OutputDir,
+// following the same layout and index handling as the pipeline. It is used
+// by callers that author stage content outside the pipeline.
+func WriteStageFile(cfg Config, stage Stage, content string) (string, error) {
+	return writeStageOutput(cfg, stage, content)
+}
@@ -0,0 +1,90 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLayoutMode(t *testing.T) {
+	for in, want := range map[string]LayoutMode{
+		"":       LayoutFlat,
+		"flat":   LayoutFlat,
+		"nested": LayoutNested,
+	} {
+		got, err := ParseLayoutMode(in)
+		require.NoError(t, err, "input %q", in)
+		assert.Equal(t, want, got, "input %q", in)
+	}
+
+	_, err := ParseLayoutMode("tree")
+	assert.Error(t, err)
+}
+
+func TestStageFilePath(t *testing.T) {
+	assert.Equal(t,
+		filepath.Join("out", "stage-1-design-pack.md"),
+		StageFilePath("out", StageDesignPack, LayoutFlat))
+	assert.Equal(t,
+		filepath.Join("out", "stage-1-design-pack.md"),
+		StageFilePath("out", StageDesignPack, ""))
+	assert.Equal(t,
+		filepath.Join("out", "stage-1", "design-pack.md"),
+		StageFilePath("out", StageDesignPack, LayoutNested))
+}
+
+func TestRenderStageIndex(t *testing.T) {
+	out := renderStageIndex("my-feature", []stageIndexEntry{
+		{Stage: StageDevelopmentStandards, Name: "development-standards", RelPath: "stage-0/development-standards.md", Complete: true},
+		{Stage: StageDesignPack, Name: "design-pack", RelPath: "stage-1/design-pack.md"},
+	})
+
+	assert.Contains(t, out, "# my-feature")
+	assert.Contains(t, out, "| 0 | [development-standards](stage-0/development-standards.md) | complete |")
+	assert.Contains(t, out, "| 1 | design-pack | pending |")
+}
+
+// TestPipeline_NestedLayout runs stages 0-1 in basic mode with the nested
+// layout and verifies that each stage lands in its own subdirectory and that
+// index.md lists both stages as complete.
+func TestPipeline_NestedLayout(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{
+		Name:       "nested-run",
+		OutputDir:  dir,
+		Capability: CapBasic,
+		LayoutMode: LayoutNested,
+	}
+
+	pipeline := NewPipeline(cfg, stubClient(t))
+	defer pipeline.Close()
+
+	results, err := pipeline.RunPipeline(
+		context.Background(),
+		StageDevelopmentStandards,
+		StageDesignPack,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, filepath.Join(dir, "stage-0", "development-standards.md"), results[0].FilePaths[0])
+	assert.Equal(t, filepath.Join(dir, "stage-1", "design-pack.md"), results[1].FilePaths[0])
+	for _, res := range results {
+		_, err := os.Stat(res.FilePaths[0])
+		assert.NoError(t, err)
+	}
+
+	// No flat-layout files should be written.
+	_, err = os.Stat(filepath.Join(dir, "stage-0-development-standards.md"))
+	assert.True(t, os.IsNotExist(err))
+
+	index, err := os.ReadFile(filepath.Join(dir, stageIndexFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "[development-standards](stage-0/development-standards.md) | complete")
+	assert.Contains(t, string(index), "[design-pack](stage-1/design-pack.md) | complete")
+	assert.Contains(t, string(index), "| 2 | implementation-skeletons | pending |")
+}
@@ -6,12 +6,44 @@ type MergeStrategy string
 const (
 	// MergeConcatenate joins sections in template order.
 	MergeConcatenate MergeStrategy = "concatenate"
+
+	// MergeDedup joins sections in template order like MergeConcatenate,
+	// then drops boilerplate repeated across sections: a heading block
+	// (heading plus body) identical to an earlier one, a bare heading that
+	// repeats the previous heading at its level, and a fenced code block
+	// identical to an earlier one.
+	MergeDedup MergeStrategy = "dedup"
+)
+
+// ConflictPolicy decides what a merge does with sections that share a name,
+// as retried or duplicated agent tasks can produce.
+type ConflictPolicy string
+
+const (
+	// ConflictReject fails the merge on duplicate section names. It is the
+	// zero value.
+	ConflictReject ConflictPolicy = ""
+
+	// ConflictFirstWins keeps the first section with a name.
+	ConflictFirstWins ConflictPolicy = "first-wins"
+
+	// ConflictLastWins keeps the last section with a name.
+	ConflictLastWins ConflictPolicy = "last-wins"
+
+	// ConflictLongestWins keeps the section with the longest content; ties
+	// go to the earliest.
+	ConflictLongestWins ConflictPolicy = "longest-wins"
+
+	// ConflictConcat joins the contents of same-named sections, in order,
+	// into one section.
+	ConflictConcat ConflictPolicy = "concat"
 )
 
 // MergePlan describes how to combine sections from parallel agents.
 type MergePlan struct {
 	Strategy     MergeStrategy
 	SectionOrder []string // section names in template order
+	Conflicts    ConflictPolicy
 }
 
 // CoherenceIssue is a contradiction found during post-merge validation.
@@ -32,7 +32,8 @@ var (
 
 // Merger combines parallel agent outputs according to a MergePlan.
 type Merger struct {
-	plan MergePlan
+	plan     MergePlan
+	warnings []CoherenceIssue
 }
 
 // NewMerger creates a Merger with the given merge plan.
@@ -41,24 +42,40 @@ func NewMerger(plan MergePlan) *Merger {
 }
 
 // Merge combines sections according to the merge plan's section order.
-// It validates that every section in the plan has a corresponding Section,
-// checks for duplicate section names, sorts by plan order, and appends
-// any extra sections not in the plan at the end. Sections are concatenated
-// with "\n\n---\n\n" separators.
+// It resolves duplicate section names by the plan's ConflictPolicy,
+// validates that every section in the plan has a corresponding Section,
+// sorts by plan order, and appends any extra sections not in the plan at
+// the end. Sections are concatenated with "\n\n---\n\n" separators; under
+// MergeDedup, repeated boilerplate is removed first and sections left empty
+// are dropped. A section name repeated in the plan is merged once. Each
+// duplicate it resolves or skips is reported by Warnings.
 func (m *Merger) Merge(sections []Section) (string, error) {
-	// Check for duplicate section names.
-	seen := make(map[string]int, len(sections))
-	for _, sec := range sections {
-		seen[sec.Name]++
+	m.warnings = nil
+	switch m.plan.Strategy {
+	case "", MergeConcatenate, MergeDedup:
+	default:
+		return "", fmt.Errorf("merge: unknown strategy %q", m.plan.Strategy)
 	}
-	var duplicates []string
-	for name, count := range seen {
-		if count > 1 {
-			duplicates = append(duplicates, fmt.Sprintf("%q (x%d)", name, count))
-		}
+
+	sections, warnings, err := resolveConflicts(sections, m.plan.Conflicts)
+	if err != nil {
+		return "", err
 	}
-	if len(duplicates) > 0 {
-		return "", fmt.Errorf("merge: duplicate section names: %s", strings.Join(duplicates, ", "))
+	m.warnings = warnings
+
+	sectionOrder := make([]string, 0, len(m.plan.SectionOrder))
+	inOrder := make(map[string]bool, len(m.plan.SectionOrder))
+	for _, name := range m.plan.SectionOrder {
+		if inOrder[name] {
+			m.warnings = append(m.warnings, CoherenceIssue{
+				SectionA:    name,
+				SectionB:    name,
+				Description: fmt.Sprintf("section %q appears more than once in the merge plan; merged once", name),
+			})
+			continue
+		}
+		inOrder[name] = true
+		sectionOrder = append(sectionOrder, name)
 	}
 
 	// Build a lookup from section name to Section.
@@ -69,7 +86,7 @@ func (m *Merger) Merge(sections []Section) (string, error) {
 
 	// Validate that every section name in the plan has a corresponding Section.
 	var missing []string
-	for _, name := range m.plan.SectionOrder {
+	for _, name := range sectionOrder {
 		if _, ok := byName[name]; !ok {
 			missing = append(missing, name)
 		}
@@ -79,22 +96,216 @@ func (m *Merger) Merge(sections []Section) (string, error) {
 	}
 
 	// Build the ordered set: plan-ordered sections first, then extras.
-	planned := make(map[string]bool, len(m.plan.SectionOrder))
-	for _, name := range m.plan.SectionOrder {
-		planned[name] = true
-	}
-
 	ordered := make([]string, 0, len(sections))
-	for _, name := range m.plan.SectionOrder {
+	for _, name := range sectionOrder {
 		ordered = append(ordered, byName[name].Content)
 	}
 
 	// Append extra sections not in the plan, preserving input order.
 	for _, sec := range sections {
-		if !planned[sec.Name] {
+		if !inOrder[sec.Name] {
 			ordered = append(ordered, sec.Content)
 		}
 	}
 
+	if m.plan.Strategy == MergeDedup {
+		ordered = dedupSections(ordered)
+	}
+
 	return strings.Join(ordered, "\n\n---\n\n"), nil
 }
+
+// Warnings returns the duplicate sections the last Merge resolved by its
+// ConflictPolicy or skipped in the plan, as coherence issues.
+func (m *Merger) Warnings() []CoherenceIssue {
+	return m.warnings
+}
+
+// resolveConflicts collapses sections sharing a name into one according to
+// policy, returning a warning for each collapsed name. The surviving section
+// takes the position of the first section with its name.
+func resolveConflicts(sections []Section, policy ConflictPolicy) ([]Section, []CoherenceIssue, error) {
+	switch policy {
+	case ConflictReject, ConflictFirstWins, ConflictLastWins, ConflictLongestWins, ConflictConcat:
+	default:
+		return nil, nil, fmt.Errorf("merge: unknown conflict policy %q", policy)
+	}
+
+	groups := make(map[string][]Section, len(sections))
+	var names []string
+	for _, sec := range sections {
+		if _, ok := groups[sec.Name]; !ok {
+			names = append(names, sec.Name)
+		}
+		groups[sec.Name] = append(groups[sec.Name], sec)
+	}
+	if len(names) == len(sections) {
+		return sections, nil, nil
+	}
+
+	if policy == ConflictReject {
+		var duplicates []string
+		for _, name := range names {
+			if count := len(groups[name]); count > 1 {
+				duplicates = append(duplicates, fmt.Sprintf("%q (x%d)", name, count))
+			}
+		}
+		return nil, nil, fmt.Errorf("merge: duplicate section names: %s", strings.Join(duplicates, ", "))
+	}
+
+	resolved := make([]Section, 0, len(names))
+	var warnings []CoherenceIssue
+	for _, name := range names {
+		group := groups[name]
+		if len(group) > 1 {
+			warnings = append(warnings, CoherenceIssue{
+				SectionA:    name,
+				SectionB:    name,
+				Description: fmt.Sprintf("section %q was produced %d times; resolved by %s policy", name, len(group), policy),
+			})
+		}
+		keep := group[0]
+		switch policy {
+		case ConflictLastWins:
+			keep = group[len(group)-1]
+		case ConflictLongestWins:
+			for _, sec := range group[1:] {
+				if len(sec.Content) > len(keep.Content) {
+					keep = sec
+				}
+			}
+		case ConflictConcat:
+			contents := make([]string, len(group))
+			for i, sec := range group {
+				contents[i] = sec.Content
+			}
+			keep.Content = strings.Join(contents, "\n\n")
+		}
+		resolved = append(resolved, keep)
+	}
+	return resolved, warnings, nil
+}
+
+// mdBlock is a run of markdown lines: an optional heading line and the body
+// up to the next heading outside a code fence.
+type mdBlock struct {
+	heading string // "" for text before a section's first heading
+	lines   []string
+}
+
+// dedupSections removes repeated boilerplate from section contents in order:
+// heading blocks identical to an earlier block, bare headings identical to
+// the previous heading at the same level, and fenced code blocks identical
+// to an earlier fence. Sections left blank are dropped.
+func dedupSections(contents []string) []string {
+	seenBlocks := make(map[string]bool)
+	seenFences := make(map[string]bool)
+	lastAtLevel := make(map[int]string) // heading level -> last heading kept
+
+	var out []string
+	for _, content := range contents {
+		var kept []string
+		for _, b := range splitMarkdownBlocks(content) {
+			if b.heading == "" {
+				kept = append(kept, dropSeenFences(b.lines, seenFences)...)
+				continue
+			}
+			key := strings.TrimSpace(strings.Join(b.lines, "\n"))
+			if seenBlocks[key] {
+				continue
+			}
+			// A heading whose body is empty, or only repeated fences, is
+			// dropped when it repeats the previous heading at its level.
+			heading := strings.TrimSpace(b.heading)
+			level := headingLevel(heading)
+			lines := dropSeenFences(b.lines, seenFences)
+			if strings.TrimSpace(strings.Join(lines[1:], "\n")) == "" && lastAtLevel[level] == heading {
+				continue
+			}
+			if strings.TrimSpace(strings.Join(b.lines[1:], "\n")) != "" {
+				seenBlocks[key] = true
+			}
+			lastAtLevel[level] = heading
+			for l := range lastAtLevel {
+				if l > level {
+					delete(lastAtLevel, l)
+				}
+			}
+			kept = append(kept, lines...)
+		}
+		if text := strings.TrimSpace(strings.Join(kept, "\n")); text != "" {
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+// splitMarkdownBlocks splits content at ATX headings that are not inside a
+// fenced code block.
+func splitMarkdownBlocks(content string) []mdBlock {
+	var blocks []mdBlock
+	cur := mdBlock{}
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if isFence(line) {
+			inFence = !inFence
+		}
+		if !inFence && headingLevel(line) > 0 {
+			if len(cur.lines) > 0 {
+				blocks = append(blocks, cur)
+			}
+			cur = mdBlock{heading: line}
+		}
+		cur.lines = append(cur.lines, line)
+	}
+	if len(cur.lines) > 0 {
+		blocks = append(blocks, cur)
+	}
+	return blocks
+}
+
+// dropSeenFences returns lines without fenced code blocks already recorded
+// in seen, recording the fences it keeps.
+func dropSeenFences(lines []string, seen map[string]bool) []string {
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		if !isFence(lines[i]) {
+			out = append(out, lines[i])
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !isFence(lines[end]) {
+			end++
+		}
+		if end == len(lines) {
+			// Unterminated fence: keep the rest as-is.
+			return append(out, lines[i:]...)
+		}
+		key := strings.Join(lines[i:end+1], "\n")
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, lines[i:end+1]...)
+		}
+		i = end
+	}
+	return out
+}
+
+// isFence reports whether line opens or closes a fenced code block.
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// headingLevel returns the ATX heading level of line (1-6), or 0 if line is
+// not a heading.
+func headingLevel(line string) int {
+	n := 0
+	for n < len(line) && line[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || (n < len(line) && line[n] != ' ') {
+		return 0
+	}
+	return n
+}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -82,6 +83,78 @@ func TestMerge_DuplicateSectionName_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), "alpha")
 }
 
+func TestMerge_ConflictPolicies(t *testing.T) {
+	sections := []Section{
+		{Name: "Overview", Content: "first overview, longer", Agent: "agent-1"},
+		{Name: "Details", Content: "details", Agent: "agent-2"},
+		{Name: "Overview", Content: "second overview", Agent: "agent-3"},
+	}
+
+	tests := map[ConflictPolicy]string{
+		ConflictFirstWins:   "first overview, longer\n\n---\n\ndetails",
+		ConflictLastWins:    "second overview\n\n---\n\ndetails",
+		ConflictLongestWins: "first overview, longer\n\n---\n\ndetails",
+		ConflictConcat:      "first overview, longer\n\nsecond overview\n\n---\n\ndetails",
+	}
+	for policy, want := range tests {
+		t.Run(string(policy), func(t *testing.T) {
+			m := NewMerger(MergePlan{
+				Strategy:     MergeConcatenate,
+				SectionOrder: []string{"Overview", "Details"},
+				Conflicts:    policy,
+			})
+			got, err := m.Merge(sections)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	_, err := NewMerger(MergePlan{Conflicts: "newest"}).Merge(sections)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown conflict policy")
+}
+
+func TestMerge_DuplicateSectionWarns(t *testing.T) {
+	m := NewMerger(MergePlan{
+		Strategy:     MergeConcatenate,
+		SectionOrder: []string{"alpha", "beta"},
+		Conflicts:    ConflictFirstWins,
+	})
+	got, err := m.Merge([]Section{
+		{Name: "alpha", Content: "AAA", Agent: "agent-1"},
+		{Name: "beta", Content: "BBB", Agent: "agent-2"},
+		{Name: "alpha", Content: "AAA-dup", Agent: "agent-3"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "AAA\n\n---\n\nBBB", got)
+
+	warnings := m.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "alpha", warnings[0].SectionA)
+	assert.Contains(t, warnings[0].Description, "produced 2 times")
+	assert.Contains(t, warnings[0].Description, string(ConflictFirstWins))
+
+	// A later merge without duplicates clears the warnings.
+	_, err = m.Merge([]Section{{Name: "alpha", Content: "A"}, {Name: "beta", Content: "B"}})
+	require.NoError(t, err)
+	assert.Empty(t, m.Warnings())
+}
+
+func TestMerge_DuplicatePlanEntryMergedOnce(t *testing.T) {
+	m := NewMerger(MergePlan{
+		Strategy:     MergeConcatenate,
+		SectionOrder: []string{"alpha", "beta", "alpha"},
+	})
+	got, err := m.Merge([]Section{
+		{Name: "alpha", Content: "AAA"},
+		{Name: "beta", Content: "BBB"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "AAA\n\n---\n\nBBB", got)
+	require.Len(t, m.Warnings(), 1)
+	assert.Contains(t, m.Warnings()[0].Description, "more than once in the merge plan")
+}
+
 func TestMerge_ExtraSection_AppendedAtEnd(t *testing.T) {
 	plan := MergePlan{
 		Strategy:     MergeConcatenate,
@@ -119,3 +192,49 @@ func TestMerge_EmptySectionContent_Included(t *testing.T) {
 	assert.Equal(t, "AAA\n\n---\n\n\n\n---\n\nCCC", got,
 		"empty section content should still appear between separators")
 }
+
+func TestMerge_Dedup_IdenticalHeadingBlock(t *testing.T) {
+	plan := MergePlan{Strategy: MergeDedup, SectionOrder: []string{"alpha", "beta"}}
+	overview := "## Overview\n\nShared project summary."
+
+	got, err := NewMerger(plan).Merge([]Section{
+		{Name: "alpha", Content: overview + "\n\n## Alpha\n\nAAA"},
+		{Name: "beta", Content: overview + "\n\n## Beta\n\nBBB"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(got, "## Overview"))
+	assert.Equal(t, overview+"\n\n## Alpha\n\nAAA\n\n---\n\n## Beta\n\nBBB", got)
+}
+
+func TestMerge_Dedup_CodeFencesAndRepeatedHeadings(t *testing.T) {
+	plan := MergePlan{Strategy: MergeDedup, SectionOrder: []string{"alpha", "beta", "gamma"}}
+	imports := "```go\nimport \"fmt\"\n```"
+
+	got, err := NewMerger(plan).Merge([]Section{
+		{Name: "alpha", Content: "# Design\n\n## Types\n\n" + imports + "\n\ntype A struct{}"},
+		{Name: "beta", Content: "# Design\n\n## Funcs\n\n" + imports + "\n\nfunc B() {}"},
+		// Entirely boilerplate: dropped along with its separator.
+		{Name: "gamma", Content: "# Design\n\n" + imports},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(got, "# Design\n"), "repeated top-level heading should be dropped")
+	assert.Equal(t, 1, strings.Count(got, imports), "identical fences should be dropped")
+	assert.Equal(t, 1, strings.Count(got, "---"))
+	assert.Less(t, strings.Index(got, "## Types"), strings.Index(got, "## Funcs"), "order should be preserved")
+}
+
+func TestMerge_Concatenate_KeepsDuplicates(t *testing.T) {
+	plan := MergePlan{Strategy: MergeConcatenate, SectionOrder: []string{"alpha", "beta"}}
+	got, err := NewMerger(plan).Merge([]Section{
+		{Name: "alpha", Content: "## Overview\n\nSame."},
+		{Name: "beta", Content: "## Overview\n\nSame."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(got, "## Overview"))
+}
+
+func TestMerge_UnknownStrategy_Error(t *testing.T) {
+	_, err := NewMerger(MergePlan{Strategy: "zip"}).Merge(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "zip")
+}
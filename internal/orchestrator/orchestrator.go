@@ -1,6 +1,9 @@
 package orchestrator
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Stage identifies a pipeline stage (0–4).
 type Stage int
@@ -33,13 +36,14 @@ type StageResult struct {
 	FilePaths          []string             // output files written
 	Sections           []Section
 	VerificationReport *VerificationReport  `json:"verificationReport,omitempty"`
+	CoherenceIssues    []CoherenceIssue     `json:"coherenceIssues,omitempty"` // cross-section conflicts; see CheckCoherence
 }
 
 // Section is a named chunk of stage output produced by one agent.
 type Section struct {
-	Name    string // section identifier (e.g., "platform-baseline")
-	Content string // markdown content
-	Agent   string // which agent produced this section
+	Name    string `json:"name"`            // section identifier (e.g., "platform-baseline")
+	Content string `json:"content"`         // markdown content
+	Agent   string `json:"agent,omitempty"` // which agent produced this section
 }
 
 // ProgressEvent is emitted to the user during pipeline execution.
@@ -48,6 +52,7 @@ type ProgressEvent struct {
 	Section string
 	Status  ProgressStatus
 	Message string
+	Time    time.Time // when the event was emitted; set by ProgressReporter.Emit if zero
 }
 
 // ProgressStatus is the state of a section within a stage.
@@ -1,12 +1,17 @@
 package orchestrator
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/onedusk/pd/internal/a2a"
 )
@@ -27,14 +32,34 @@ type Pipeline struct {
 	router   *Router
 	progress *ProgressReporter
 	fanout   *FanOut
+
+	stateMu sync.Mutex
+	state   PipelineState // overall state; see State
 }
 
 // NewPipeline creates a Pipeline wired with a Router, ProgressReporter, and
-// FanOut. The pipeline registers itself as the StageExecutor for all five
-// stages.
+// FanOut. The pipeline registers itself as the StageExecutor for every stage
+// of the configured pipeline (the built-in five unless cfg.Stages is set).
 func NewPipeline(cfg Config, client a2a.Client) *Pipeline {
-	progress := NewProgressReporter()
-	fanout := NewFanOut(client, progress.Emit)
+	// Agent cards are discovered once, whether by detection, Warmup, or the
+	// first stage routed to an agent.
+	if _, ok := client.(*a2a.CardCache); !ok {
+		client = a2a.NewCardCache(client)
+	}
+	var progressOpts []ProgressOption
+	if cfg.ProgressBuffer > 0 {
+		progressOpts = append(progressOpts, WithProgressBuffer(cfg.ProgressBuffer))
+	}
+	progress := NewProgressReporter(progressOpts...)
+	fanoutOpts := []FanOutOption{WithAgentBudget(NewAgentBudget(cfg.ParallelAgents))}
+	if cfg.FailFast {
+		fanoutOpts = append(fanoutOpts, WithFailFast())
+	}
+	if cfg.AgentRetries > 0 {
+		fanoutOpts = append(fanoutOpts, WithRetry(cfg.AgentRetries+1, DefaultAgentRetryBackoff))
+	}
+	fanoutOpts = append(fanoutOpts, WithRetryClassifier(cfg.RetryClassifier))
+	fanout := NewFanOut(client, progress.Emit, fanoutOpts...)
 	router := NewRouter(cfg)
 
 	p := &Pipeline{
@@ -43,10 +68,11 @@ func NewPipeline(cfg Config, client a2a.Client) *Pipeline {
 		router:   router,
 		progress: progress,
 		fanout:   fanout,
+		state:    PipelineState{Phase: PipelineIdle},
 	}
 
 	// Register this pipeline as the executor for every stage.
-	for stage := StageDevelopmentStandards; stage <= StageTaskSpecifications; stage++ {
+	for stage := StageDevelopmentStandards; stage <= cfg.LastStage(); stage++ {
 		router.RegisterExecutor(stage, p)
 	}
 
@@ -59,11 +85,132 @@ func NewPipeline(cfg Config, client a2a.Client) *Pipeline {
 
 // RunStage executes a single pipeline stage. It emits a stage header via the
 // progress reporter and delegates to the router, which calls back into
-// Pipeline.Execute.
+// Pipeline.Execute. The pipeline state moves to running and then to
+// completed or failed.
 func (p *Pipeline) RunStage(ctx context.Context, stage Stage) (*StageResult, error) {
+	p.setState(PipelineRunning, stage, nil)
+	result, err := p.routeStage(ctx, stage)
+	if err != nil {
+		p.setState(PipelineFailed, stage, err)
+		return nil, err
+	}
+	p.setState(PipelineCompleted, stage, nil)
+	return result, nil
+}
+
+// RunPipeline executes stages from..to inclusive, routing each in turn as
+// RunStage does. It stops at the first stage that fails or whose
+// verification finds critical issues, unless Config.KeepGoing is set: then
+// the failure is recorded, stages that require the failed one are skipped,
+// and the run continues, returning a *PipelineError if anything failed. The
+// pipeline state tracks the running stage and ends completed at to or failed
+// at the (first) stage that failed.
+func (p *Pipeline) RunPipeline(ctx context.Context, from, to Stage) ([]StageResult, error) {
+	if from > to {
+		return nil, fmt.Errorf("router: invalid range: from (%d) > to (%d)", from, to)
+	}
+	p.setState(PipelineRunning, from, nil)
+
+	var results []StageResult
+	var failures []StageFailure
+	failed := make(map[Stage]bool)
+	fail := func(stage Stage, err error) {
+		failed[stage] = true
+		failures = append(failures, StageFailure{Stage: stage, Name: p.cfg.StageName(stage), Err: err})
+	}
+	for stage := from; stage <= to; stage++ {
+		if p.cfg.KeepGoing {
+			if pre, ok := p.failedPrerequisite(stage, failed); ok {
+				fail(stage, fmt.Errorf("skipped: prerequisite stage %d (%s) failed", pre, p.cfg.StageName(pre)))
+				continue
+			}
+		}
+
+		result, err := p.routeStage(ctx, stage)
+		if err != nil {
+			if p.cfg.KeepGoing {
+				fail(stage, err)
+				if ctx.Err() != nil {
+					break
+				}
+				continue
+			}
+			err = fmt.Errorf("router: stage %d (%s) failed: %w", stage, p.cfg.StageName(stage), err)
+			p.setState(PipelineFailed, stage, err)
+			return results, err
+		}
+		results = append(results, *result)
+
+		// Block pipeline progression if verification found critical issues.
+		if result.VerificationReport != nil && result.VerificationReport.HasCritical() {
+			if p.cfg.KeepGoing {
+				fail(stage, ErrVerificationFailed)
+				continue
+			}
+			err := fmt.Errorf("router: stage %d (%s) %w", stage, p.cfg.StageName(stage), ErrVerificationFailed)
+			p.setState(PipelineFailed, stage, err)
+			return results, err
+		}
+	}
+
+	if len(failures) > 0 {
+		err := &PipelineError{Failures: failures}
+		p.setState(PipelineFailed, failures[0].Stage, err)
+		return results, err
+	}
+	p.setState(PipelineCompleted, to, nil)
+	return results, nil
+}
+
+// failedPrerequisite returns the first required prerequisite of stage that
+// is in failed, if any.
+func (p *Pipeline) failedPrerequisite(stage Stage, failed map[Stage]bool) (Stage, bool) {
+	for _, rule := range p.cfg.prerequisiteRules(stage) {
+		if rule.required && failed[rule.stage] {
+			return rule.stage, true
+		}
+	}
+	return 0, false
+}
+
+// StageFailure records a stage that failed, or was skipped because a stage
+// it requires failed, during a KeepGoing run.
+type StageFailure struct {
+	Stage Stage
+	Name  string
+	Err   error
+}
+
+// PipelineError is returned by RunPipeline with Config.KeepGoing when any
+// stage failed. It lists every failure in stage order and unwraps to their
+// errors, so errors.Is and errors.As see through it.
+type PipelineError struct {
+	Failures []StageFailure
+}
+
+func (e *PipelineError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "router: %d stage(s) failed", len(e.Failures))
+	for _, f := range e.Failures {
+		fmt.Fprintf(&b, "\n  stage %d (%s): %v", f.Stage, f.Name, f.Err)
+	}
+	return b.String()
+}
+
+func (e *PipelineError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// routeStage routes one stage through the router, bracketing it with a
+// stage header event and a stage-level complete or failed event.
+func (p *Pipeline) routeStage(ctx context.Context, stage Stage) (*StageResult, error) {
 	p.progress.Emit(ProgressEvent{
 		Stage:   stage,
-		Section: FormatStageHeader(p.cfg.Name, stage),
+		Section: formatStageHeader(p.cfg.Name, stage, p.cfg.StageName(stage)),
 		Status:  ProgressWorking,
 	})
 
@@ -71,7 +218,7 @@ func (p *Pipeline) RunStage(ctx context.Context, stage Stage) (*StageResult, err
 	if err != nil {
 		p.progress.Emit(ProgressEvent{
 			Stage:   stage,
-			Section: stage.String(),
+			Section: p.cfg.StageName(stage),
 			Status:  ProgressFailed,
 			Message: err.Error(),
 		})
@@ -80,23 +227,24 @@ func (p *Pipeline) RunStage(ctx context.Context, stage Stage) (*StageResult, err
 
 	p.progress.Emit(ProgressEvent{
 		Stage:   stage,
-		Section: stage.String(),
+		Section: p.cfg.StageName(stage),
 		Status:  ProgressComplete,
 	})
-
 	return result, nil
 }
 
-// RunPipeline executes stages from..to inclusive by delegating to the router.
-func (p *Pipeline) RunPipeline(ctx context.Context, from, to Stage) ([]StageResult, error) {
-	return p.router.RouteRange(ctx, from, to)
-}
-
-// Progress returns a channel that emits progress events.
+// Progress subscribes to progress events. Each call returns an independent
+// channel; see ProgressReporter.Subscribe.
 func (p *Pipeline) Progress() <-chan ProgressEvent {
 	return p.progress.Subscribe()
 }
 
+// DroppedEvents returns the number of progress events dropped because no
+// consumer drained its progress channel fast enough.
+func (p *Pipeline) DroppedEvents() uint64 {
+	return p.progress.DroppedEvents()
+}
+
 // Close shuts down the progress reporter. Callers should invoke this when the
 // pipeline is no longer needed.
 func (p *Pipeline) Close() {
@@ -107,26 +255,34 @@ func (p *Pipeline) Close() {
 // StageExecutor interface
 // ---------------------------------------------------------------------------
 
-// Execute is the StageExecutor callback invoked by the Router. It selects
-// between fan-out (full/a2a) and fallback (basic/mcp-only) execution modes
-// based on the configuration capability level.
+// Execute is the StageExecutor callback. It infers the current stage from
+// the inputs and delegates to ExecuteStage.
 func (p *Pipeline) Execute(ctx context.Context, cfg Config, inputs []StageResult) (*StageResult, error) {
-	// Determine the current stage from the router's perspective.
-	stage := p.inferStage(inputs)
+	return p.ExecuteStage(ctx, cfg, inferStageFromInputs(inputs, cfg.LastStage()), inputs)
+}
 
+// ExecuteStage runs the given stage, selecting between fan-out (full/a2a) and
+// fallback (basic/mcp-only) execution modes based on the configuration
+// capability level, or re-merging cached sections when cfg.MergeOnly is set.
+// The Router calls this directly with the routed stage.
+func (p *Pipeline) ExecuteStage(ctx context.Context, cfg Config, stage Stage, inputs []StageResult) (*StageResult, error) {
+	p.setState(PipelineRunning, stage, nil)
+	if cfg.MergeOnly {
+		return p.executeMergeOnly(ctx, cfg, stage, inputs)
+	}
 	switch cfg.Capability {
 	case CapFull, CapA2AMCP:
 		if cfg.SingleAgent {
 			fb := NewFallbackExecutor(CapBasic)
-			return fb.Execute(ctx, cfg, inputs)
+			return fb.ExecuteStage(ctx, cfg, stage, inputs)
 		}
 		return p.executeFullMode(ctx, cfg, stage, inputs)
 	case CapMCPOnly:
 		fb := NewFallbackExecutor(CapMCPOnly)
-		return fb.Execute(ctx, cfg, inputs)
+		return fb.ExecuteStage(ctx, cfg, stage, inputs)
 	default:
 		fb := NewFallbackExecutor(CapBasic)
-		return fb.Execute(ctx, cfg, inputs)
+		return fb.ExecuteStage(ctx, cfg, stage, inputs)
 	}
 }
 
@@ -135,85 +291,156 @@ func (p *Pipeline) Execute(ctx context.Context, cfg Config, inputs []StageResult
 // ---------------------------------------------------------------------------
 
 func (p *Pipeline) executeFullMode(ctx context.Context, cfg Config, stage Stage, inputs []StageResult) (*StageResult, error) {
-	plan := MergePlanForStage(stage)
+	// The built-in Stage 4 fans out one task per Stage 3 milestone when the
+	// milestone list can be parsed.
+	if stage == StageTaskSpecifications && len(cfg.Stages) == 0 {
+		if milestones, err := ParseMilestones(stageContent(inputs, StageTaskIndex)); err == nil {
+			return p.executeTaskSpecFanOut(ctx, cfg, milestones, inputs)
+		}
+	}
 
-	// Build the context message from predecessor inputs.
-	contextText := buildContextMessage(stage, inputs)
+	name := cfg.StageName(stage)
+	plan, err := mergePlanFor(cfg, stage)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: %w", err)
+	}
 
-	// Assign sections to agents via round-robin.
-	tasks := assignSectionsToAgents(plan, cfg.AgentEndpoints, stage, contextText)
+	// Build the context message from predecessor inputs.
+	contextText := buildContextMessage(cfg, stage, inputs)
+
+	// Assign sections to agents via round-robin, combining sections when the
+	// plan exceeds the per-stage task cap.
+	tasks := assignSectionsToAgents(cfg, plan, stage, contextText)
+	if cfg.MaxSectionsPerStage > 0 && len(plan.SectionOrder) > cfg.MaxSectionsPerStage {
+		log.Printf("WARNING: stage %d (%s) has %d sections; combining into %d agent tasks (max sections per stage)",
+			stage, name, len(plan.SectionOrder), len(tasks))
+	}
 
-	// Fan out to agents.
+	// Fan out to agents, asking each only for output it offers.
+	p.narrowOutputModes(ctx, tasks)
 	agentResults, err := p.fanout.Run(ctx, stage, tasks)
 	if err != nil {
-		return nil, fmt.Errorf("pipeline: fan-out for stage %d (%s) failed: %w", stage, stage, err)
+		return nil, fmt.Errorf("pipeline: fan-out for stage %d (%s) failed: %w", stage, name, err)
 	}
 
-	// Convert AgentResults to Sections.
+	// Convert AgentResults to Sections, caching them for merge-only reruns.
 	sections := agentResultsToSections(agentResults)
+	if err := saveSections(cfg, stage, sections); err != nil {
+		log.Printf("WARNING: stage %d (%s): %v", stage, name, err)
+	}
+
+	return p.completeStage(ctx, cfg, stage, plan, sections, inputs)
+}
+
+// executeMergeOnly rebuilds a stage from the sections cached by its last
+// agent run, without calling agents. The built-in Stage 4 is rewritten as
+// per-milestone files under the same condition executeFullMode uses.
+func (p *Pipeline) executeMergeOnly(ctx context.Context, cfg Config, stage Stage, inputs []StageResult) (*StageResult, error) {
+	name := cfg.StageName(stage)
+	sections, err := loadSections(cfg, stage)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: merge-only stage %d (%s): %w", stage, name, err)
+	}
+
+	if stage == StageTaskSpecifications && len(cfg.Stages) == 0 {
+		if _, err := ParseMilestones(stageContent(inputs, StageTaskIndex)); err == nil {
+			return p.writeTaskSpecs(cfg, sections, inputs)
+		}
+	}
+
+	plan, err := mergePlanFor(cfg, stage)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: %w", err)
+	}
+	return p.completeStage(ctx, cfg, stage, plan, sections, inputs)
+}
+
+// completeStage merges a stage's sections according to plan, checks their
+// coherence, writes and validates the stage file, and verifies it.
+func (p *Pipeline) completeStage(ctx context.Context, cfg Config, stage Stage, plan MergePlan, sections []Section, inputs []StageResult) (*StageResult, error) {
+	name := cfg.StageName(stage)
 
 	// Merge sections according to the plan.
 	merger := NewMerger(plan)
 	merged, err := merger.Merge(sections)
 	if err != nil {
-		return nil, fmt.Errorf("pipeline: merge for stage %d (%s) failed: %w", stage, stage, err)
+		return nil, fmt.Errorf("pipeline: merge for stage %d (%s) failed: %w", stage, name, err)
 	}
 
 	// Check coherence (log issues, do not block).
-	issues, cohErr := CheckCoherence(sections)
-	if cohErr != nil {
-		log.Printf("WARNING: coherence check error for stage %d (%s): %v", stage, stage, cohErr)
+	var issues []CoherenceIssue
+	if !cfg.SkipCoherence {
+		var reduced bool
+		var cohErr error
+		issues, reduced, cohErr = CheckCoherenceLimit(sections, cfg.CoherenceMaxSections)
+		if cohErr != nil {
+			log.Printf("WARNING: coherence check error for stage %d (%s): %v", stage, name, cohErr)
+		}
+		if reduced {
+			log.Printf("WARNING: stage %d (%s) has %d sections; ran a reduced coherence check that reports each conflicting dependency once", stage, name, len(sections))
+		}
 	}
+	// Duplicate sections the merge resolved are reported with the
+	// coherence issues, whether or not the coherence check ran.
+	issues = append(issues, merger.Warnings()...)
 	for _, issue := range issues {
-		log.Printf("WARNING: coherence issue in stage %d (%s): %s", stage, stage, issue.Description)
+		log.Printf("WARNING: coherence issue in stage %d (%s): %s", stage, name, issue.Description)
 	}
 
 	// Write output file.
-	outPath := stageOutputPath(cfg, stage)
-	if err := writeOutputFile(outPath, merged); err != nil {
-		return nil, fmt.Errorf("pipeline: write output for stage %d (%s): %w", stage, stage, err)
+	outPath, err := writeStageOutput(cfg, stage, merged)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: write output for stage %d (%s): %w", stage, name, err)
+	}
+	if err := p.validateGoOutput(ctx, cfg, stage, merged); err != nil {
+		return nil, fmt.Errorf("pipeline: validate stage %d (%s): %w", stage, name, err)
 	}
 
 	result := &StageResult{
-		Stage:     stage,
-		FilePaths: []string{outPath},
-		Sections:  sections,
+		Stage:           stage,
+		FilePaths:       []string{outPath},
+		Sections:        sections,
+		CoherenceIssues: issues,
 	}
-
-	// Verify the stage output with "fresh eyes".
 	if !cfg.SkipVerification {
-		p.progress.Emit(ProgressEvent{
-			Stage:   stage,
-			Section: "verification",
-			Status:  ProgressVerifying,
-		})
+		p.verifyStageResult(result, merged, inputs, outPath+".verification.md")
+	}
+	return result, nil
+}
 
-		report := p.verifyStageOutput(stage, merged, inputs)
-		result.VerificationReport = report
+// verifyStageResult verifies the stage output with "fresh eyes", records the
+// report on result, and writes it to reportPath when verification fails.
+func (p *Pipeline) verifyStageResult(result *StageResult, content string, inputs []StageResult, reportPath string) {
+	stage := result.Stage
+	p.progress.Emit(ProgressEvent{
+		Stage:   stage,
+		Section: "verification",
+		Status:  ProgressVerifying,
+	})
 
-		if !report.Passed {
-			// Write verification report alongside stage output.
-			reportPath := outPath + ".verification.md"
-			if writeErr := writeOutputFile(reportPath, report.Markdown()); writeErr != nil {
-				log.Printf("WARNING: failed to write verification report: %v", writeErr)
-			} else {
-				result.FilePaths = append(result.FilePaths, reportPath)
-			}
+	report := p.verifyStageOutput(stage, content, inputs)
+	result.VerificationReport = report
+	if report.Passed {
+		return
+	}
 
-			for _, f := range report.Findings {
-				if f.Severity == SeverityCritical {
-					p.progress.Emit(ProgressEvent{
-						Stage:   stage,
-						Section: "verification",
-						Status:  ProgressFailed,
-						Message: f.Description,
-					})
-				}
-			}
-		}
+	// Write verification report alongside stage output.
+	if writeErr := writeOutputFile(reportPath, report.Markdown()); writeErr != nil {
+		log.Printf("WARNING: failed to write verification report: %v", writeErr)
+	} else {
+		result.FilePaths = append(result.FilePaths, reportPath)
 	}
 
-	return result, nil
+	for _, f := range report.Findings {
+		if f.Severity == SeverityCritical {
+			p.progress.Emit(ProgressEvent{
+				Stage:   stage,
+				Section: "verification",
+				Status:  ProgressFailed,
+				Message: f.Description,
+			})
+		}
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -231,23 +458,6 @@ func (p *Pipeline) verifyStageOutput(stage Stage, content string, priorInputs []
 // Helpers
 // ---------------------------------------------------------------------------
 
-// inferStage determines which stage is being executed based on the inputs
-// that have already been produced. The stage is the smallest stage index
-// not present in the inputs.
-func (p *Pipeline) inferStage(inputs []StageResult) Stage {
-	present := make(map[Stage]bool, len(inputs))
-	for _, r := range inputs {
-		present[r.Stage] = true
-	}
-	for s := StageDevelopmentStandards; s <= StageTaskSpecifications; s++ {
-		if !present[s] {
-			return s
-		}
-	}
-	// All stages present: default to the last one.
-	return StageTaskSpecifications
-}
-
 // MergePlanForStage returns the MergePlan for the given stage. Stages without
 // a multi-section plan return a single-section plan using the stage name.
 func MergePlanForStage(stage Stage) MergePlan {
@@ -267,32 +477,56 @@ func MergePlanForStage(stage Stage) MergePlan {
 	}
 }
 
-// stageOutputPath returns the output file path for a stage:
-// <OutputDir>/stage-{N}-{name}.md
+// stageOutputPath returns the output file path for a stage according to the
+// configured layout mode. See StageFilePath.
 func stageOutputPath(cfg Config, stage Stage) string {
-	return filepath.Join(cfg.OutputDir, fmt.Sprintf("stage-%d-%s.md", int(stage), stage.String()))
+	return stageFilePathNamed(cfg.OutputDir, stage, cfg.StageName(stage), cfg.LayoutMode)
 }
 
 // assignSectionsToAgents creates AgentTasks by round-robin assignment of
-// merge plan sections to the available agent endpoints.
-func assignSectionsToAgents(plan MergePlan, endpoints []string, stage Stage, contextText string) []AgentTask {
+// merge plan sections to cfg.AgentEndpoints. When cfg.MaxSectionsPerStage is
+// positive and the plan has more sections than that, adjacent sections are
+// combined so that at most that many tasks are created; a combined task asks
+// the agent to delimit each section with a sectionMarker line.
+func assignSectionsToAgents(cfg Config, plan MergePlan, stage Stage, contextText string) []AgentTask {
+	endpoints := cfg.AgentEndpoints
 	if len(endpoints) == 0 {
 		return nil
 	}
 
-	tasks := make([]AgentTask, 0, len(plan.SectionOrder))
-	for i, section := range plan.SectionOrder {
+	stageName := cfg.StageName(stage)
+	groups := groupSections(plan.SectionOrder, cfg.MaxSectionsPerStage)
+	tasks := make([]AgentTask, 0, len(groups))
+	for i, group := range groups {
 		endpoint := endpoints[i%len(endpoints)]
 
-		prompt := fmt.Sprintf("Generate the %q section for stage %d (%s).\n\n%s",
-			section, int(stage), stage.String(), contextText)
+		var prompt string
+		var covered []string
+		if len(group) == 1 {
+			prompt = fmt.Sprintf("Generate the %q section for stage %d (%s).\n\n%s",
+				group[0], int(stage), stageName, contextText)
+		} else {
+			covered = group
+			var markers strings.Builder
+			for _, name := range group {
+				fmt.Fprintf(&markers, "%s\n", sectionMarker(name))
+			}
+			prompt = fmt.Sprintf("Generate the %s sections for stage %d (%s). "+
+				"Start each section with its marker line, exactly as written:\n\n%s\n%s",
+				quoteList(group), int(stage), stageName, markers.String(), contextText)
+		}
+
+		meta, _ := json.Marshal(taskMetadata{Stage: int(stage), Section: strings.Join(group, "+")})
 
 		tasks = append(tasks, AgentTask{
-			AgentEndpoint: endpoint,
-			Section:       section,
+			AgentEndpoint:       endpoint,
+			Section:             strings.Join(group, "+"),
+			Sections:            covered,
+			AcceptedOutputModes: cfg.OutputModes(stage),
 			Message: a2a.Message{
-				Role:  a2a.RoleUser,
-				Parts: []a2a.Part{a2a.TextPart(prompt)},
+				Role:     a2a.RoleUser,
+				Parts:    []a2a.Part{a2a.TextPart(prompt)},
+				Metadata: meta,
 			},
 		})
 	}
@@ -300,20 +534,131 @@ func assignSectionsToAgents(plan MergePlan, endpoints []string, stage Stage, con
 	return tasks
 }
 
+// groupSections splits sections into at most max contiguous groups of
+// near-equal size, preserving plan order. A max <= 0, or one no smaller than
+// len(sections), yields one group per section.
+func groupSections(sections []string, max int) [][]string {
+	n := len(sections)
+	if max <= 0 || max >= n {
+		groups := make([][]string, n)
+		for i, s := range sections {
+			groups[i] = []string{s}
+		}
+		return groups
+	}
+
+	groups := make([][]string, 0, max)
+	start := 0
+	for i := 0; i < max; i++ {
+		size := n / max
+		if i < n%max {
+			size++
+		}
+		groups = append(groups, sections[start:start+size])
+		start += size
+	}
+	return groups
+}
+
+// sectionMarker is the delimiter line an agent writes before each section of
+// a combined task.
+func sectionMarker(name string) string {
+	return fmt.Sprintf("<!-- section: %s -->", name)
+}
+
+// quoteList formats names as a comma-separated list of quoted strings.
+func quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// splitCombinedContent splits the output of a combined task into one Section
+// per covered name using the sectionMarker lines. Content before the first
+// marker, or the whole output when no markers are present, is attributed to
+// the first section; sections without a marker get empty content.
+func splitCombinedContent(content string, names []string, agent string) []Section {
+	bodies := make(map[string]*strings.Builder, len(names))
+	for _, n := range names {
+		bodies[n] = &strings.Builder{}
+	}
+	markers := make(map[string]string, len(names))
+	for _, n := range names {
+		markers[sectionMarker(n)] = n
+	}
+
+	current := names[0]
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if name, ok := markers[strings.TrimSpace(line)]; ok {
+			current = name
+			continue
+		}
+		bodies[current].WriteString(line)
+	}
+
+	sections := make([]Section, 0, len(names))
+	for _, n := range names {
+		sections = append(sections, Section{
+			Name:    n,
+			Content: strings.TrimSpace(bodies[n].String()),
+			Agent:   agent,
+		})
+	}
+	return sections
+}
+
 // buildContextMessage constructs a prompt preamble from predecessor stage
-// outputs so that downstream agents have full context.
-func buildContextMessage(stage Stage, inputs []StageResult) string {
-	if len(inputs) == 0 {
+// outputs so that downstream agents have full context. When cfg.Prerequisites
+// lists the stage, only those predecessors are included. The design-pack
+// stage is also given the high-level input in cfg.InputContent, if any. When
+// cfg.MaxContextBytes is set, section bodies are trimmed to fit it; see
+// fitContextBudget.
+func buildContextMessage(cfg Config, stage Stage, inputs []StageResult) string {
+	if pres, ok := cfg.Prerequisites[stage]; ok {
+		var kept []StageResult
+		for _, input := range inputs {
+			if slices.Contains(pres, input.Stage) {
+				kept = append(kept, input)
+			}
+		}
+		inputs = kept
+	}
+
+	seed := ""
+	if stage == StageDesignPack {
+		seed = strings.TrimSpace(cfg.InputContent)
+	}
+	if len(inputs) == 0 && seed == "" {
 		return ""
 	}
 
 	var b strings.Builder
+	if seed != "" {
+		fmt.Fprintf(&b, "## High-level input\n\n%s\n\n", seed)
+	}
+	if len(inputs) == 0 {
+		return b.String()
+	}
 	b.WriteString("## Context from prior stages\n\n")
+
+	var entries []contextEntry
 	for _, input := range inputs {
 		for _, sec := range input.Sections {
-			fmt.Fprintf(&b, "### %s / %s\n\n%s\n\n", input.Stage.String(), sec.Name, sec.Content)
+			entries = append(entries, contextEntry{
+				stage:   input.Stage,
+				heading: fmt.Sprintf("### %s / %s\n\n", cfg.StageName(input.Stage), sec.Name),
+				body:    sec.Content,
+			})
 		}
 	}
+	if cfg.MaxContextBytes > 0 {
+		entries = fitContextBudget(entries, cfg.MaxContextBytes-b.Len())
+	}
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%s%s\n\n", e.heading, e.body)
+	}
 	return b.String()
 }
 
@@ -326,6 +671,10 @@ func agentResultsToSections(results []AgentResult) []Section {
 			continue
 		}
 		content := extractTextFromArtifacts(r.Artifacts)
+		if len(r.Sections) > 1 {
+			sections = append(sections, splitCombinedContent(content, r.Sections, agentFromTask(r.Task))...)
+			continue
+		}
 		sections = append(sections, Section{
 			Name:    r.Section,
 			Content: content,
@@ -335,19 +684,39 @@ func agentResultsToSections(results []AgentResult) []Section {
 	return sections
 }
 
-// extractTextFromArtifacts concatenates text parts from all artifacts.
+// extractTextFromArtifacts concatenates the text and data parts of all
+// artifacts. Data parts, returned by agents honoring an application/json
+// output mode, become fenced json blocks so they merge as markdown. URL
+// parts, which agents return for outputs too large to inline, become links
+// to the stored content.
 func extractTextFromArtifacts(artifacts []a2a.Artifact) string {
 	var parts []string
 	for _, art := range artifacts {
 		for _, p := range art.Parts {
-			if p.Text != "" {
+			switch {
+			case p.Text != "":
 				parts = append(parts, p.Text)
+			case len(p.Data) > 0:
+				parts = append(parts, "```json\n"+indentJSON(p.Data)+"\n```")
+			case p.URL != "":
+				label := cmp.Or(p.Filename, art.Name, "artifact")
+				parts = append(parts, fmt.Sprintf("[%s](%s)", label, p.URL))
 			}
 		}
 	}
 	return strings.Join(parts, "\n\n")
 }
 
+// indentJSON pretty-prints data, or returns it unchanged if it is not valid
+// JSON.
+func indentJSON(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
+
 // agentFromTask returns the agent name from a completed task, or "unknown".
 func agentFromTask(t *a2a.Task) string {
 	if t == nil {
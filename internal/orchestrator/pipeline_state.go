@@ -0,0 +1,38 @@
+package orchestrator
+
+// PipelinePhase is the overall lifecycle phase of a Pipeline.
+type PipelinePhase string
+
+const (
+	PipelineIdle      PipelinePhase = "idle"
+	PipelineRunning   PipelinePhase = "running"
+	PipelineFailed    PipelinePhase = "failed"
+	PipelineCompleted PipelinePhase = "completed"
+)
+
+// PipelineState is a snapshot of a Pipeline's overall state, suitable for a
+// status bar. Stage is the stage running, the stage that failed, or the last
+// stage completed; it is zero while idle.
+type PipelineState struct {
+	Phase PipelinePhase
+	Stage Stage
+	Err   string // failure message when Phase is PipelineFailed
+}
+
+// State returns the pipeline's current overall state.
+func (p *Pipeline) State() PipelineState {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.state
+}
+
+// setState records the pipeline's overall state.
+func (p *Pipeline) setState(phase PipelinePhase, stage Stage, err error) {
+	st := PipelineState{Phase: phase, Stage: stage}
+	if err != nil {
+		st.Err = err.Error()
+	}
+	p.stateMu.Lock()
+	p.state = st
+	p.stateMu.Unlock()
+}
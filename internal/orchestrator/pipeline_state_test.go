@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateProbeExecutor records the pipeline's state while it executes.
+type stateProbeExecutor struct {
+	p      *Pipeline
+	err    error
+	during PipelineState
+}
+
+func (e *stateProbeExecutor) Execute(context.Context, Config, []StageResult) (*StageResult, error) {
+	e.during = e.p.State()
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &StageResult{Stage: StageDevelopmentStandards}, nil
+}
+
+func TestPipelineState_RunStage(t *testing.T) {
+	p := NewPipeline(Config{OutputDir: t.TempDir(), Capability: CapBasic}, nil)
+	defer p.Close()
+	probe := &stateProbeExecutor{p: p}
+	p.router.RegisterExecutor(StageDevelopmentStandards, probe)
+
+	assert.Equal(t, PipelineState{Phase: PipelineIdle}, p.State())
+
+	_, err := p.RunStage(context.Background(), StageDevelopmentStandards)
+	require.NoError(t, err)
+	assert.Equal(t, PipelineState{Phase: PipelineRunning, Stage: StageDevelopmentStandards}, probe.during)
+	assert.Equal(t, PipelineState{Phase: PipelineCompleted, Stage: StageDevelopmentStandards}, p.State())
+
+	probe.err = errors.New("agent unreachable")
+	_, err = p.RunStage(context.Background(), StageDevelopmentStandards)
+	require.Error(t, err)
+	st := p.State()
+	assert.Equal(t, PipelineFailed, st.Phase)
+	assert.Equal(t, StageDevelopmentStandards, st.Stage)
+	assert.Contains(t, st.Err, "agent unreachable")
+}
+
+func TestPipelineState_RunPipeline(t *testing.T) {
+	cfg := Config{OutputDir: t.TempDir(), Capability: CapBasic, SkipVerification: true}
+	p := NewPipeline(cfg, nil)
+	defer p.Close()
+
+	_, err := p.RunPipeline(context.Background(), StageDevelopmentStandards, StageImplementationSkeletons)
+	require.NoError(t, err)
+	assert.Equal(t, PipelineState{Phase: PipelineCompleted, Stage: StageImplementationSkeletons}, p.State())
+
+	// Stage 3 fails; the state names it rather than the last stage that ran.
+	p.router.RegisterExecutor(StageTaskIndex, &mockExecutor{err: errors.New("boom")})
+	_, err = p.RunPipeline(context.Background(), StageDevelopmentStandards, StageTaskSpecifications)
+	require.Error(t, err)
+	assert.Equal(t, PipelineFailed, p.State().Phase)
+	assert.Equal(t, StageTaskIndex, p.State().Stage)
+}
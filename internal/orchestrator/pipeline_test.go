@@ -2,8 +2,12 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -122,6 +126,65 @@ func TestPipeline_BasicMode_RunPipeline(t *testing.T) {
 	assert.NotEmpty(t, data, "stage 1 output should contain content")
 }
 
+func TestPipeline_KeepGoing(t *testing.T) {
+	cfg := Config{OutputDir: t.TempDir(), Capability: CapBasic, SkipVerification: true, KeepGoing: true}
+	pipeline := NewPipeline(cfg, stubClient(t))
+	defer pipeline.Close()
+	boom := errors.New("design agent unreachable")
+	pipeline.router.RegisterExecutor(StageDesignPack, &mockExecutor{err: boom})
+
+	results, err := pipeline.RunPipeline(context.Background(), StageDevelopmentStandards, StageTaskSpecifications)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, boom)
+
+	// Stage 0 does not depend on stage 1 and still produced its output.
+	require.Len(t, results, 1)
+	assert.Equal(t, StageDevelopmentStandards, results[0].Stage)
+
+	// Stage 1 failed, and everything downstream of it was skipped.
+	var pipeErr *PipelineError
+	require.ErrorAs(t, err, &pipeErr)
+	require.Len(t, pipeErr.Failures, 4)
+	assert.Equal(t, StageDesignPack, pipeErr.Failures[0].Stage)
+	assert.Equal(t, boom, pipeErr.Failures[0].Err)
+	for _, f := range pipeErr.Failures[1:] {
+		assert.Contains(t, f.Err.Error(), "skipped: prerequisite stage")
+	}
+	assert.Contains(t, err.Error(), "stage 1 (design-pack): design agent unreachable")
+
+	assert.Equal(t, PipelineFailed, pipeline.State().Phase)
+	assert.Equal(t, StageDesignPack, pipeline.State().Stage)
+}
+
+func TestPipeline_KeepGoingRunsIndependentStages(t *testing.T) {
+	cfg := Config{
+		OutputDir:        t.TempDir(),
+		Capability:       CapBasic,
+		SkipVerification: true,
+		KeepGoing:        true,
+		Prerequisites:    map[Stage][]Stage{StageTaskSpecifications: {StageDesignPack}},
+	}
+	pipeline := NewPipeline(cfg, stubClient(t))
+	defer pipeline.Close()
+	pipeline.router.RegisterExecutor(StageTaskIndex, &mockExecutor{err: errors.New("boom")})
+	specs := &mockExecutor{result: &StageResult{Stage: StageTaskSpecifications}}
+	pipeline.router.RegisterExecutor(StageTaskSpecifications, specs)
+
+	results, err := pipeline.RunPipeline(context.Background(), StageDevelopmentStandards, StageTaskSpecifications)
+	var pipeErr *PipelineError
+	require.ErrorAs(t, err, &pipeErr)
+	require.Len(t, pipeErr.Failures, 1)
+	assert.Equal(t, StageTaskIndex, pipeErr.Failures[0].Stage)
+
+	// Stage 4 only requires stage 1 here, so stage 3's failure does not skip it.
+	assert.Equal(t, 1, specs.called)
+	var stages []Stage
+	for _, r := range results {
+		stages = append(stages, r.Stage)
+	}
+	assert.Equal(t, []Stage{StageDevelopmentStandards, StageDesignPack, StageImplementationSkeletons, StageTaskSpecifications}, stages)
+}
+
 // TestPipeline_ProgressEvents subscribes to the progress channel, runs a
 // stage, and verifies that relevant progress events are received.
 func TestPipeline_ProgressEvents(t *testing.T) {
@@ -199,3 +262,194 @@ func TestPipeline_Close(t *testing.T) {
 		t.Fatal("timed out waiting for progress channel to close")
 	}
 }
+
+// TestAssignSectionsToAgents_MaxSectionsPerStage verifies that a 10-section
+// plan capped at 4 tasks produces at most 4 AgentTasks whose prompts cover
+// every section.
+func TestAssignSectionsToAgents_MaxSectionsPerStage(t *testing.T) {
+	plan := MergePlan{Strategy: MergeConcatenate}
+	for i := 0; i < 10; i++ {
+		plan.SectionOrder = append(plan.SectionOrder, fmt.Sprintf("sec-%d", i))
+	}
+
+	cfg := Config{AgentEndpoints: []string{"http://a", "http://b"}, MaxSectionsPerStage: 4}
+	tasks := assignSectionsToAgents(cfg, plan, StageDesignPack, "ctx")
+	require.LessOrEqual(t, len(tasks), 4)
+
+	var covered []string
+	for _, task := range tasks {
+		names := task.Sections
+		if names == nil {
+			names = []string{task.Section}
+		}
+		for _, name := range names {
+			assert.Contains(t, task.Message.Parts[0].Text, sectionMarker(name))
+		}
+		covered = append(covered, names...)
+	}
+	assert.Equal(t, plan.SectionOrder, covered, "every section covered exactly once, in order")
+
+	// Without a cap every section gets its own task.
+	assert.Len(t, assignSectionsToAgents(Config{AgentEndpoints: []string{"http://a"}}, plan, StageDesignPack, "ctx"), 10)
+}
+
+// TestAssignSectionsToAgents_OutputModes verifies that each stage's tasks
+// carry the stage's accepted output modes, and that a custom definition
+// overrides them.
+func TestAssignSectionsToAgents_OutputModes(t *testing.T) {
+	cfg := Config{AgentEndpoints: []string{"http://a"}}
+
+	stage3 := assignSectionsToAgents(cfg, MergePlanForStage(StageTaskIndex), StageTaskIndex, "ctx")
+	require.NotEmpty(t, stage3)
+	for _, task := range stage3 {
+		assert.Equal(t, []string{"text/markdown", "application/json"}, task.AcceptedOutputModes)
+	}
+
+	stage2 := assignSectionsToAgents(cfg, MergePlanForStage(StageImplementationSkeletons), StageImplementationSkeletons, "ctx")
+	require.NotEmpty(t, stage2)
+	for _, task := range stage2 {
+		assert.Equal(t, "text/markdown", task.AcceptedOutputModes[0])
+	}
+
+	cfg.Stages = []StageDefinition{{Name: "only", OutputModes: []string{"application/yaml"}}}
+	custom := assignSectionsToAgents(cfg, MergePlan{SectionOrder: []string{"only"}}, 0, "ctx")
+	require.Len(t, custom, 1)
+	assert.Equal(t, []string{"application/yaml"}, custom[0].AcceptedOutputModes)
+}
+
+func TestFanOut_SendsAcceptedOutputModes(t *testing.T) {
+	var got []string
+	client := &mockClient{
+		sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			got = req.Configuration.AcceptedOutputModes
+			return &a2a.Task{ID: "a", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}}, nil
+		},
+	}
+	tasks := []AgentTask{{AgentEndpoint: "http://a", Section: "s", AcceptedOutputModes: []string{"application/json"}}}
+	_, err := NewFanOut(client, nil).Run(context.Background(), StageTaskIndex, tasks)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"application/json"}, got)
+}
+
+func TestExtractTextFromArtifacts_DataParts(t *testing.T) {
+	got := extractTextFromArtifacts([]a2a.Artifact{{Parts: []a2a.Part{
+		a2a.TextPart("## Tasks"),
+		{Data: []byte(`{"id":"T-01.01"}`), MediaType: "application/json"},
+	}}})
+	assert.Equal(t, "## Tasks\n\n```json\n{\n  \"id\": \"T-01.01\"\n}\n```", got)
+}
+
+func TestExtractTextFromArtifacts_URLParts(t *testing.T) {
+	got := extractTextFromArtifacts([]a2a.Artifact{
+		{Name: "Design Pack", Parts: []a2a.Part{
+			a2a.TextPart("## Summary"),
+			{URL: "http://localhost:9100/artifacts/abc", MediaType: "text/markdown"},
+		}},
+		{Parts: []a2a.Part{{URL: "http://localhost:9100/artifacts/def", Filename: "tasks.md"}}},
+	})
+	assert.Equal(t, "## Summary\n\n[Design Pack](http://localhost:9100/artifacts/abc)\n\n[tasks.md](http://localhost:9100/artifacts/def)", got)
+}
+
+// TestPipeline_MaxSectionsPerStage runs Stage 1 in full mode with a cap and
+// an agent that answers combined prompts using the section markers, then
+// checks that the merged output contains every section's content.
+func TestPipeline_MaxSectionsPerStage(t *testing.T) {
+	var calls atomic.Int32
+	client := &mockClient{
+		sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			calls.Add(1)
+			var b strings.Builder
+			for _, line := range strings.Split(req.Message.Parts[0].Text, "\n") {
+				if strings.HasPrefix(line, "<!-- section: ") {
+					name := strings.TrimSuffix(strings.TrimPrefix(line, "<!-- section: "), " -->")
+					fmt.Fprintf(&b, "%s\nbody of %s\n", line, name)
+				}
+			}
+			return &a2a.Task{
+				ID:     fmt.Sprintf("t%d", calls.Load()),
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+				Artifacts: []a2a.Artifact{
+					{ArtifactID: "art", Parts: []a2a.Part{a2a.TextPart(b.String())}},
+				},
+			}, nil
+		},
+	}
+
+	// Seed Stage 0 so the pipeline infers Stage 1 from its inputs.
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, stageFileName(StageDevelopmentStandards)), []byte("# Standards\n"), 0o644))
+
+	cfg := Config{
+		Name:                "capped",
+		OutputDir:           dir,
+		Capability:          CapA2AMCP,
+		AgentEndpoints:      []string{"http://a"},
+		SkipVerification:    true,
+		MaxSectionsPerStage: 4,
+	}
+	p := NewPipeline(cfg, client)
+	defer p.Close()
+
+	result, err := p.RunStage(context.Background(), StageDesignPack)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), calls.Load())
+
+	data, err := os.ReadFile(result.FilePaths[0])
+	require.NoError(t, err)
+	for _, name := range Stage1MergePlan.SectionOrder {
+		assert.Contains(t, string(data), "body of "+name)
+	}
+	assert.Len(t, result.Sections, len(Stage1MergePlan.SectionOrder))
+}
+
+func TestPipeline_TaskSpecFanOutPerMilestone(t *testing.T) {
+	var calls atomic.Int32
+	client := &mockClient{
+		sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			calls.Add(1)
+			text := req.Message.Parts[0].Text
+			require.True(t, strings.HasPrefix(text, "write-task-specs\n"), text)
+			heading := strings.SplitN(text, "\n", 3)[1]
+			return &a2a.Task{
+				ID:     heading,
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+				Artifacts: []a2a.Artifact{
+					{ArtifactID: "art", Parts: []a2a.Part{a2a.TextPart("# Tasks for " + heading + "\n")}},
+				},
+			}, nil
+		},
+	}
+
+	dir := t.TempDir()
+	cfg := Config{
+		Name:             "fanout",
+		OutputDir:        dir,
+		Capability:       CapA2AMCP,
+		AgentEndpoints:   []string{"http://a", "http://b"},
+		SkipVerification: true,
+	}
+	p := NewPipeline(cfg, client)
+	defer p.Close()
+
+	stage3 := StageResult{
+		Stage: StageTaskIndex,
+		Sections: []Section{{
+			Name:    "progress",
+			Content: "## M1: Scaffolding\n\nSet up the module.\n\n## M2: API\n\nServe requests.\n\nM1 → M2\n",
+		}},
+	}
+	result, err := p.ExecuteStage(context.Background(), cfg, StageTaskSpecifications, []StageResult{stage3})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+
+	require.Equal(t, []string{
+		filepath.Join(dir, "tasks_m01.md"),
+		filepath.Join(dir, "tasks_m02.md"),
+	}, result.FilePaths)
+	for i, path := range result.FilePaths {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), fmt.Sprintf("# Tasks for Milestone %d:", i+1))
+	}
+	assert.NoFileExists(t, stageOutputPath(cfg, StageTaskSpecifications))
+}
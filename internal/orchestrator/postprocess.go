@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// PostProcessor rewrites the merged content of a stage before it is written.
+// Returning an error aborts the write.
+type PostProcessor func(stage Stage, content string) (string, error)
+
+// applyPostProcessors runs processors over content in order.
+func applyPostProcessors(processors []PostProcessor, stage Stage, content string) (string, error) {
+	for i, process := range processors {
+		out, err := process(stage, content)
+		if err != nil {
+			return "", fmt.Errorf("post-processor %d for stage %d (%s): %w", i+1, stage, stage, err)
+		}
+		content = out
+	}
+	return content, nil
+}
+
+// GofmtPostProcessor reformats the body of every ```go fenced code block
+// with gofmt and leaves the rest of the content untouched. Blocks that do
+// not parse as Go, such as snippets with elided code, are kept as written.
+func GofmtPostProcessor(_ Stage, content string) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	var out strings.Builder
+	for i := 0; i < len(lines); i++ {
+		out.WriteString(lines[i])
+		if strings.TrimSpace(lines[i]) != "```go" {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "```" {
+			end++
+		}
+		if end == len(lines) {
+			// Unterminated block: copy the remainder verbatim.
+			for _, l := range lines[i+1:] {
+				out.WriteString(l)
+			}
+			break
+		}
+
+		body := strings.Join(lines[i+1:end], "")
+		if formatted, err := format.Source([]byte(body)); err == nil {
+			body = string(formatted)
+			if !bytes.HasSuffix(formatted, []byte("\n")) {
+				body += "\n"
+			}
+		}
+		out.WriteString(body)
+		out.WriteString(lines[end])
+		i = end
+	}
+	return out.String(), nil
+}
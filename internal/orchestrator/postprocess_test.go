@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGofmtPostProcessor_ReformatsGoBlocks(t *testing.T) {
+	merged, err := NewMerger(Stage2MergePlan).Merge([]Section{
+		{
+			Name:  "data-model-code",
+			Agent: "schema",
+			Content: "## Data Model\n\n" +
+				"Keep   this   prose.\n\n" +
+				"```go\ntype User struct {\nID string\n    Name   string\n}\n```\n",
+		},
+		{Name: "interface-contracts", Agent: "schema", Content: "## Contracts\n\n```sh\nfoo   bar\n```\n"},
+		{Name: "documentation", Agent: "schema", Content: "## Docs\n"},
+	})
+	require.NoError(t, err)
+
+	out, err := GofmtPostProcessor(StageImplementationSkeletons, merged)
+	require.NoError(t, err)
+	assert.Contains(t, out, "```go\ntype User struct {\n\tID   string\n\tName string\n}\n```\n")
+	assert.Contains(t, out, "Keep   this   prose.")
+	assert.Contains(t, out, "```sh\nfoo   bar\n```")
+}
+
+func TestGofmtPostProcessor_KeepsUnparsableBlocks(t *testing.T) {
+	in := "```go\nfunc f() {\n  ...\n```\n"
+	out, err := GofmtPostProcessor(StageImplementationSkeletons, in)
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+}
+
+func TestWriteStageOutput_PostProcessorErrorAborts(t *testing.T) {
+	cfg := Config{
+		Name:      "demo",
+		OutputDir: t.TempDir(),
+		PostProcessors: []PostProcessor{
+			GofmtPostProcessor,
+			func(Stage, string) (string, error) { return "", errors.New("lint failed") },
+		},
+	}
+	_, err := writeStageOutput(cfg, StageDesignPack, "# Design\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "post-processor 2 for stage 1")
+	assert.Contains(t, err.Error(), "lint failed")
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "stage-1-design-pack.md"))
+}
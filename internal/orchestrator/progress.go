@@ -1,57 +1,236 @@
 package orchestrator
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
-// ProgressReporter emits progress events through a buffered channel.
+// DefaultProgressBuffer is the progress channel buffer size used when none is
+// configured.
+const DefaultProgressBuffer = 64
+
+// ProgressReporter emits progress events to one or more subscribers, each
+// with its own buffered channel; see Subscribe.
+//
+// Emits are serialized, so events reach subscribers in the order Emit was
+// called; events emitted from one goroutine (such as the events of a single
+// stage) are never reordered. By default Emit never blocks: when a
+// subscriber's buffer is full the event is dropped for that subscriber alone
+// and counted in DroppedEvents, so a slow or absent consumer cannot stall the
+// pipeline or starve the others. WithBlockingEmit trades that guarantee for
+// lossless delivery.
 type ProgressReporter struct {
-	ch chan ProgressEvent
+	buffer   int
+	blocking bool
+
+	mu      sync.Mutex // guards subs, claimed, and closed
+	sendMu  sync.Mutex // serializes Emit and Close; held while a blocking Emit sends
+	subs    []chan ProgressEvent
+	claimed bool // the first subscriber has been handed out
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// ProgressOption configures a ProgressReporter.
+type ProgressOption func(*ProgressReporter)
+
+// WithProgressBuffer sets each subscriber's channel buffer size. Sizes below
+// zero are treated as zero (unbuffered).
+func WithProgressBuffer(size int) ProgressOption {
+	return func(pr *ProgressReporter) {
+		pr.buffer = max(size, 0)
+	}
 }
 
-// NewProgressReporter creates a ProgressReporter with a buffered channel of size 64.
-func NewProgressReporter() *ProgressReporter {
-	return &ProgressReporter{
-		ch: make(chan ProgressEvent, 64),
+// WithBlockingEmit makes Emit wait for buffer space instead of dropping
+// events. Every subscriber must drain its channel, or Emit blocks forever.
+func WithBlockingEmit() ProgressOption {
+	return func(pr *ProgressReporter) {
+		pr.blocking = true
 	}
 }
 
-// Emit sends a progress event in a non-blocking fashion.
-// If the channel is full, the event is silently dropped.
+// NewProgressReporter creates a ProgressReporter whose subscribers get
+// channels of DefaultProgressBuffer events and drop events when full.
+func NewProgressReporter(opts ...ProgressOption) *ProgressReporter {
+	pr := &ProgressReporter{buffer: DefaultProgressBuffer}
+	for _, opt := range opts {
+		opt(pr)
+	}
+	// The first subscriber's channel exists from the start, so it also
+	// receives events emitted before Subscribe is called.
+	pr.subs = []chan ProgressEvent{make(chan ProgressEvent, pr.buffer)}
+	return pr
+}
+
+// Emit sends a progress event to every subscriber. In the default
+// non-blocking mode a subscriber whose channel is full misses the event, and
+// DroppedEvents is incremented once per such subscriber. Events emitted after
+// Close are dropped the same way. Emit stamps events that have no Time
+// with the current time.
 func (pr *ProgressReporter) Emit(event ProgressEvent) {
-	select {
-	case pr.ch <- event:
-	default:
-		// Drop the event if the channel is full.
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	pr.sendMu.Lock()
+	defer pr.sendMu.Unlock()
+
+	pr.mu.Lock()
+	if pr.closed {
+		pr.mu.Unlock()
+		pr.dropped.Add(1)
+		return
+	}
+	subs := slices.Clone(pr.subs)
+	pr.mu.Unlock()
+
+	// Send outside mu so that a blocked send cannot stall Subscribe; sendMu
+	// keeps Close from closing a channel mid-send.
+	for _, ch := range subs {
+		if pr.blocking {
+			ch <- event
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			pr.dropped.Add(1)
+		}
 	}
 }
 
-// Subscribe returns a read-only channel for consuming progress events.
+// DroppedEvents returns the number of events dropped because a subscriber's
+// channel was full or the reporter was closed.
+func (pr *ProgressReporter) DroppedEvents() uint64 {
+	return pr.dropped.Load()
+}
+
+// Subscribe returns a new read-only channel that receives every event
+// emitted from then on, independently of other subscribers. The first call
+// returns a channel that has buffered events since the reporter was created.
+// Channels returned after Close are already closed.
 func (pr *ProgressReporter) Subscribe() <-chan ProgressEvent {
-	return pr.ch
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if !pr.claimed {
+		pr.claimed = true
+		return pr.subs[0]
+	}
+	ch := make(chan ProgressEvent, pr.buffer)
+	if pr.closed {
+		close(ch)
+		return ch
+	}
+	pr.subs = append(pr.subs, ch)
+	return ch
 }
 
-// Close closes the progress event channel.
+// Close closes every subscriber's channel. It is safe to call more than once.
 func (pr *ProgressReporter) Close() {
-	close(pr.ch)
+	pr.sendMu.Lock()
+	defer pr.sendMu.Unlock()
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.closed {
+		return
+	}
+	pr.closed = true
+	for _, ch := range pr.subs {
+		close(ch)
+	}
 }
 
-// FormatProgress formats a ProgressEvent as a human-readable status line.
-func FormatProgress(event ProgressEvent) string {
+// ANSI escape sequences used by ProgressFormatter.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiDim    = "\x1b[2m"
+)
+
+// ProgressFormatter formats ProgressEvents as status lines, optionally
+// colorizing the status glyph and text with ANSI escapes: green for complete,
+// red for failed, yellow for working/verifying, dim for pending.
+type ProgressFormatter struct {
+	Color bool
+}
+
+// NewProgressFormatter returns a formatter for output written to f. Colors
+// are enabled only when f is a terminal, noColor is false, and the NO_COLOR
+// environment variable is unset or empty (see https://no-color.org).
+func NewProgressFormatter(f *os.File, noColor bool) *ProgressFormatter {
+	return &ProgressFormatter{Color: ShouldColor(IsTerminal(f), noColor)}
+}
+
+// ShouldColor reports whether colored output should be used for a stream,
+// given whether it is a terminal and whether --no-color was passed. A
+// non-empty NO_COLOR environment variable disables colors.
+func ShouldColor(isTTY, noColor bool) bool {
+	if noColor || !isTTY {
+		return false
+	}
+	return os.Getenv("NO_COLOR") == ""
+}
+
+// IsTerminal reports whether f refers to a character device such as a TTY.
+func IsTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Format formats a ProgressEvent as a human-readable status line.
+func (pf *ProgressFormatter) Format(event ProgressEvent) string {
 	switch event.Status {
 	case ProgressPending:
-		return fmt.Sprintf("  \u25cb %s (pending)", event.Section)
+		return pf.paint(ansiDim, fmt.Sprintf("  \u25cb %s (pending)", event.Section))
 	case ProgressWorking:
-		return fmt.Sprintf("  \u25cf %s...", event.Section)
+		return pf.paint(ansiYellow, fmt.Sprintf("  \u25cf %s...", event.Section))
+	case ProgressVerifying:
+		return pf.paint(ansiYellow, fmt.Sprintf("  \u25cf %s (verifying)", event.Section))
 	case ProgressComplete:
-		return fmt.Sprintf("  \u2713 %s complete", event.Section)
+		return pf.paint(ansiGreen, fmt.Sprintf("  \u2713 %s complete", event.Section))
 	case ProgressFailed:
-		return fmt.Sprintf("  \u2717 %s failed: %s", event.Section, event.Message)
+		return pf.paint(ansiRed, fmt.Sprintf("  \u2717 %s failed: %s", event.Section, event.Message))
 	default:
 		return fmt.Sprintf("  ? %s (unknown status)", event.Section)
 	}
 }
 
+// paint wraps s in the given color when colors are enabled.
+func (pf *ProgressFormatter) paint(color, s string) string {
+	if !pf.Color {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// FormatProgress formats a ProgressEvent as a plain (uncolored) status line.
+func FormatProgress(event ProgressEvent) string {
+	return (&ProgressFormatter{}).Format(event)
+}
+
 // FormatStageHeader formats a stage header for display.
 // Returns: "[{name}] Stage {N}: {stage.String()}"
 func FormatStageHeader(name string, stage Stage) string {
-	return fmt.Sprintf("[%s] Stage %d: %s", name, int(stage), stage.String())
+	return formatStageHeader(name, stage, stage.String())
+}
+
+// formatStageHeader is FormatStageHeader with an explicit stage name, used for
+// custom pipelines.
+func formatStageHeader(name string, stage Stage, stageName string) string {
+	return fmt.Sprintf("[%s] Stage %d: %s", name, int(stage), stageName)
 }
@@ -1,6 +1,10 @@
 package orchestrator
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +28,8 @@ func TestProgressReporter_EmitAndSubscribe(t *testing.T) {
 
 	select {
 	case got := <-ch:
+		assert.False(t, got.Time.IsZero(), "Emit stamps the event")
+		got.Time = time.Time{}
 		assert.Equal(t, want, got)
 	case <-time.After(time.Second):
 		t.Fatal("timed out waiting for progress event")
@@ -56,6 +62,158 @@ func TestProgressReporter_EmitWhenFull_DoesNotBlock(t *testing.T) {
 	}
 }
 
+func TestProgressReporter_DropsWhenFull_PreservesOrder(t *testing.T) {
+	pr := NewProgressReporter(WithProgressBuffer(2))
+
+	for i := 0; i < 5; i++ {
+		pr.Emit(ProgressEvent{Stage: StageDesignPack, Section: fmt.Sprintf("s%d", i), Status: ProgressWorking})
+	}
+	assert.Equal(t, uint64(3), pr.DroppedEvents())
+
+	pr.Close()
+	pr.Emit(ProgressEvent{Section: "after-close"})
+	assert.Equal(t, uint64(4), pr.DroppedEvents())
+
+	var sections []string
+	for ev := range pr.Subscribe() {
+		sections = append(sections, ev.Section)
+	}
+	assert.Equal(t, []string{"s0", "s1"}, sections)
+}
+
+func TestProgressReporter_BlockingEmit_DeliversAllInOrder(t *testing.T) {
+	pr := NewProgressReporter(WithProgressBuffer(0), WithBlockingEmit())
+
+	go func() {
+		for i := 0; i < 20; i++ {
+			pr.Emit(ProgressEvent{Section: fmt.Sprintf("s%d", i)})
+		}
+		pr.Close()
+	}()
+
+	var sections []string
+	for ev := range pr.Subscribe() {
+		sections = append(sections, ev.Section)
+	}
+	require.Len(t, sections, 20)
+	for i, section := range sections {
+		assert.Equal(t, fmt.Sprintf("s%d", i), section)
+	}
+	assert.Zero(t, pr.DroppedEvents())
+}
+
+// TestProgressReporter_BlockingEmit_DoesNotStallSubscribe verifies that a
+// blocking Emit waiting on a full channel does not hold up Subscribe.
+func TestProgressReporter_BlockingEmit_DoesNotStallSubscribe(t *testing.T) {
+	pr := NewProgressReporter(WithProgressBuffer(0), WithBlockingEmit())
+	first := pr.Subscribe()
+
+	emitted := make(chan struct{})
+	go func() {
+		pr.Emit(ProgressEvent{Section: "s0"})
+		close(emitted)
+	}()
+
+	subscribed := make(chan (<-chan ProgressEvent))
+	go func() { subscribed <- pr.Subscribe() }()
+	select {
+	case second := <-subscribed:
+		// Emit may or may not have included the new subscriber.
+		go func() {
+			for range second {
+			}
+		}()
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe blocked behind a blocking Emit")
+	}
+
+	assert.Equal(t, "s0", (<-first).Section)
+	<-emitted
+	pr.Close()
+}
+
+func TestPipeline_TinyProgressBufferWithoutConsumer(t *testing.T) {
+	cfg := Config{OutputDir: t.TempDir(), Capability: CapBasic, SkipVerification: true, ProgressBuffer: 1}
+	p := NewPipeline(cfg, nil)
+
+	// RunStage emits a header and a completion event per stage.
+	done := make(chan error, 1)
+	go func() {
+		for stage := StageDevelopmentStandards; stage <= StageImplementationSkeletons; stage++ {
+			if _, err := p.RunStage(context.Background(), stage); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline blocked on an undrained progress channel")
+	}
+	assert.Equal(t, uint64(5), p.DroppedEvents())
+
+	// Only the first event fit in the buffer; later ones were dropped.
+	p.Close()
+	var events []ProgressEvent
+	for ev := range p.Progress() {
+		events = append(events, ev)
+	}
+	require.Len(t, events, 1)
+	assert.Equal(t, StageDevelopmentStandards, events[0].Stage)
+}
+
+func TestPipeline_MultipleProgressSubscribers(t *testing.T) {
+	cfg := Config{OutputDir: t.TempDir(), Capability: CapBasic, SkipVerification: true}
+	p := NewPipeline(cfg, nil)
+
+	// Each subscriber drains into its own slice concurrently.
+	subs := []<-chan ProgressEvent{p.Progress(), p.Progress()}
+	received := make([][]ProgressEvent, len(subs))
+	done := make(chan struct{})
+	for i, ch := range subs {
+		go func() {
+			for ev := range ch {
+				received[i] = append(received[i], ev)
+			}
+			done <- struct{}{}
+		}()
+	}
+
+	_, err := p.RunPipeline(context.Background(), StageDevelopmentStandards, StageImplementationSkeletons)
+	require.NoError(t, err)
+	p.Close()
+	for range subs {
+		<-done
+	}
+
+	require.NotEmpty(t, received[0])
+	assert.Equal(t, received[0], received[1])
+	assert.Zero(t, p.DroppedEvents())
+}
+
+func TestProgressReporter_SlowSubscriberDoesNotStarveOthers(t *testing.T) {
+	pr := NewProgressReporter(WithProgressBuffer(1))
+	fast, slow := pr.Subscribe(), pr.Subscribe()
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		pr.Emit(ProgressEvent{Section: fmt.Sprintf("s%d", i)})
+		got = append(got, (<-fast).Section)
+	}
+	pr.Close()
+
+	assert.Equal(t, []string{"s0", "s1", "s2"}, got)
+	assert.Equal(t, "s0", (<-slow).Section)
+	assert.Equal(t, uint64(2), pr.DroppedEvents())
+
+	_, ok := <-pr.Subscribe()
+	assert.False(t, ok, "subscribing after Close returns a closed channel")
+}
+
 func TestProgressReporter_Close_ChannelClosed(t *testing.T) {
 	pr := NewProgressReporter()
 	ch := pr.Subscribe()
@@ -112,6 +270,53 @@ func TestFormatProgress_AllStatuses(t *testing.T) {
 	}
 }
 
+func TestProgressFormatter_Color(t *testing.T) {
+	complete := ProgressEvent{Section: "data-model", Status: ProgressComplete}
+	failed := ProgressEvent{Section: "data-model", Status: ProgressFailed, Message: "timeout"}
+	working := ProgressEvent{Section: "data-model", Status: ProgressWorking}
+
+	colored := &ProgressFormatter{Color: true}
+	assert.Equal(t, "\x1b[32m  \u2713 data-model complete\x1b[0m", colored.Format(complete))
+	assert.True(t, strings.HasPrefix(colored.Format(failed), "\x1b[31m"))
+	assert.True(t, strings.HasPrefix(colored.Format(working), "\x1b[33m"))
+
+	plain := &ProgressFormatter{}
+	for _, ev := range []ProgressEvent{complete, failed, working} {
+		assert.NotContains(t, plain.Format(ev), "\x1b[")
+	}
+}
+
+func TestShouldColor(t *testing.T) {
+	t.Run("tty", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "") // restored on cleanup
+		os.Unsetenv("NO_COLOR")
+		assert.True(t, ShouldColor(true, false))
+	})
+	t.Run("empty NO_COLOR env", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		assert.True(t, ShouldColor(true, false))
+	})
+	t.Run("not a tty", func(t *testing.T) {
+		assert.False(t, ShouldColor(false, false))
+	})
+	t.Run("no-color flag", func(t *testing.T) {
+		assert.False(t, ShouldColor(true, true))
+	})
+	t.Run("NO_COLOR env", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		assert.False(t, ShouldColor(true, false))
+	})
+}
+
+func TestNewProgressFormatter_NonTTY(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "progress")
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.False(t, IsTerminal(f))
+	assert.False(t, NewProgressFormatter(f, false).Color)
+}
+
 func TestFormatStageHeader(t *testing.T) {
 	got := FormatStageHeader("my-project", StageDesignPack)
 	assert.Equal(t, "[my-project] Stage 1: design-pack", got)
@@ -0,0 +1,184 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// Compile-time interface checks.
+var (
+	_ a2a.Client = (*RecordingClient)(nil)
+	_ a2a.Client = (*ReplayClient)(nil)
+)
+
+// ErrReplayMiss is returned by ReplayClient when a request has no recorded
+// response.
+var ErrReplayMiss = errors.New("no recorded response")
+
+// errReplayUnsupported is returned by ReplayClient for every method other than
+// SendMessage; a replayed run never talks to an agent.
+var errReplayUnsupported = errors.New("replay: operation not supported in replay mode")
+
+// taskMetadata is attached to every fan-out message so that recording and
+// replay clients can identify the stage and section a request belongs to.
+type taskMetadata struct {
+	Stage   int    `json:"stage"`
+	Section string `json:"section"`
+}
+
+// Recording holds agent responses captured during a live run, keyed by
+// RecordingKey. It is serialized as JSON.
+type Recording struct {
+	// Endpoints are the agent endpoints used by the recorded run. A replayed
+	// run fans out to the same endpoints so that section assignment matches.
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Responses maps RecordingKey to the task returned by the agent.
+	Responses map[string]*a2a.Task `json:"responses"`
+}
+
+// LoadRecording reads a Recording from a JSON file.
+func LoadRecording(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load recording: %w", err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("load recording %s: %w", path, err)
+	}
+	if rec.Responses == nil {
+		rec.Responses = make(map[string]*a2a.Task)
+	}
+	return &rec, nil
+}
+
+// Save writes the Recording to path as indented JSON.
+func (r *Recording) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save recording: %w", err)
+	}
+	if err := writeOutputFile(path, string(data)+"\n"); err != nil {
+		return fmt.Errorf("save recording: %w", err)
+	}
+	return nil
+}
+
+// RecordingKey identifies a recorded response by stage, section, and a hash
+// of the prompt text: "stage-{N}/{section}/{hash}".
+func RecordingKey(stage Stage, section, prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return fmt.Sprintf("stage-%d/%s/%s", int(stage), section, hex.EncodeToString(sum[:8]))
+}
+
+// recordingKeyFor derives the RecordingKey for a SendMessage request from
+// the message metadata and text parts.
+func recordingKeyFor(req a2a.SendMessageRequest) string {
+	meta := taskMetadata{Stage: -1}
+	if len(req.Message.Metadata) > 0 {
+		_ = json.Unmarshal(req.Message.Metadata, &meta)
+	}
+	var prompt strings.Builder
+	for _, p := range req.Message.Parts {
+		prompt.WriteString(p.Text)
+	}
+	return RecordingKey(Stage(meta.Stage), meta.Section, prompt.String())
+}
+
+// RecordingClient wraps an a2a.Client and captures every successful
+// SendMessage response into a Recording. All other methods delegate to the
+// wrapped client unchanged.
+type RecordingClient struct {
+	a2a.Client
+
+	mu  sync.Mutex
+	rec *Recording
+}
+
+// NewRecordingClient returns a RecordingClient that delegates to inner and
+// records responses for a run against endpoints.
+func NewRecordingClient(inner a2a.Client, endpoints []string) *RecordingClient {
+	return &RecordingClient{
+		Client: inner,
+		rec: &Recording{
+			Endpoints: endpoints,
+			Responses: make(map[string]*a2a.Task),
+		},
+	}
+}
+
+// SendMessage forwards the request and records the returned task.
+func (c *RecordingClient) SendMessage(ctx context.Context, endpoint string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+	task, err := c.Client.SendMessage(ctx, endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.rec.Responses[recordingKeyFor(req)] = task
+	c.mu.Unlock()
+	return task, nil
+}
+
+// Recording returns the responses captured so far.
+func (c *RecordingClient) Recording() *Recording {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rec
+}
+
+// ReplayClient serves SendMessage responses from a Recording instead of
+// calling agents. A request without a recorded response fails with
+// ErrReplayMiss.
+type ReplayClient struct {
+	rec *Recording
+}
+
+// NewReplayClient returns a ReplayClient backed by rec.
+func NewReplayClient(rec *Recording) *ReplayClient {
+	return &ReplayClient{rec: rec}
+}
+
+// SendMessage returns the recorded task for the request.
+func (c *ReplayClient) SendMessage(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+	key := recordingKeyFor(req)
+	task, ok := c.rec.Responses[key]
+	if !ok {
+		return nil, fmt.Errorf("replay: %s: %w", key, ErrReplayMiss)
+	}
+	return task, nil
+}
+
+// GetTask is not supported in replay mode.
+func (c *ReplayClient) GetTask(context.Context, string, a2a.GetTaskRequest) (*a2a.Task, error) {
+	return nil, errReplayUnsupported
+}
+
+// ListTasks is not supported in replay mode.
+func (c *ReplayClient) ListTasks(context.Context, string, a2a.ListTasksRequest) (*a2a.ListTasksResponse, error) {
+	return nil, errReplayUnsupported
+}
+
+// CancelTask is not supported in replay mode.
+func (c *ReplayClient) CancelTask(context.Context, string, a2a.CancelTaskRequest) (*a2a.Task, error) {
+	return nil, errReplayUnsupported
+}
+
+// SubscribeToTask is not supported in replay mode.
+func (c *ReplayClient) SubscribeToTask(context.Context, string, string) (<-chan a2a.StreamEvent, error) {
+	return nil, errReplayUnsupported
+}
+
+// DiscoverAgent is not supported in replay mode.
+func (c *ReplayClient) DiscoverAgent(context.Context, string) (*a2a.AgentCard, error) {
+	return nil, errReplayUnsupported
+}
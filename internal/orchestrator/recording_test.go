@@ -0,0 +1,90 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordReplay_IdenticalOutput records a full-mode Stage 1 run against a
+// mock agent, then replays it into a fresh output directory and verifies the
+// stage files are byte-identical with no agent calls made.
+func TestRecordReplay_IdenticalOutput(t *testing.T) {
+	endpoints := []string{"http://agent-a", "http://agent-b"}
+
+	var liveCalls atomic.Int32
+	live := &mockClient{
+		sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			n := liveCalls.Add(1)
+			return completedTask(fmt.Sprintf("task-%d", n), req.Message.Parts[0].Text), nil
+		},
+	}
+
+	// Seed Stage 0 in both output directories so the pipeline infers Stage 1.
+	seedStage0 := func(dir string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, stageFileName(StageDevelopmentStandards)), []byte("# Standards\n"), 0o644))
+	}
+
+	recorder := NewRecordingClient(live, endpoints)
+	recordDir := t.TempDir()
+	seedStage0(recordDir)
+	recordCfg := Config{
+		Name:             "record",
+		OutputDir:        recordDir,
+		Capability:       CapA2AMCP,
+		AgentEndpoints:   endpoints,
+		SkipVerification: true,
+	}
+	p := NewPipeline(recordCfg, recorder)
+	recorded, err := p.RunStage(context.Background(), StageDesignPack)
+	p.Close()
+	require.NoError(t, err)
+	require.Equal(t, StageDesignPack, recorded.Stage)
+	require.Equal(t, int32(len(Stage1MergePlan.SectionOrder)), liveCalls.Load())
+
+	recPath := filepath.Join(t.TempDir(), "recording.json")
+	require.NoError(t, recorder.Recording().Save(recPath))
+
+	rec, err := LoadRecording(recPath)
+	require.NoError(t, err)
+	assert.Equal(t, endpoints, rec.Endpoints)
+	assert.Len(t, rec.Responses, int(liveCalls.Load()))
+
+	callsBefore := liveCalls.Load()
+	replayDir := t.TempDir()
+	seedStage0(replayDir)
+	replayCfg := recordCfg
+	replayCfg.Name = "replay"
+	replayCfg.OutputDir = replayDir
+	replayCfg.AgentEndpoints = rec.Endpoints
+
+	rp := NewPipeline(replayCfg, NewReplayClient(rec))
+	replayed, err := rp.RunStage(context.Background(), StageDesignPack)
+	rp.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, callsBefore, liveCalls.Load(), "replay must not call the live client")
+
+	want, err := os.ReadFile(recorded.FilePaths[0])
+	require.NoError(t, err)
+	got, err := os.ReadFile(replayed.FilePaths[0])
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestReplayClient_MissReturnsError(t *testing.T) {
+	c := NewReplayClient(&Recording{Responses: map[string]*a2a.Task{}})
+
+	_, err := c.SendMessage(context.Background(), "http://agent", a2a.SendMessageRequest{
+		Message: a2a.Message{Role: a2a.RoleUser, Parts: []a2a.Part{a2a.TextPart("hello")}},
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrReplayMiss)
+}
@@ -0,0 +1,229 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReportFileName is the file a run report is written to, inside the
+// output directory.
+const ReportFileName = "report.md"
+
+// ReportCollector records stage timings and failures from progress events
+// for a RunReport. Feed it every event the pipeline emits, from the
+// goroutine draining Pipeline.Progress; it is safe for concurrent use.
+type ReportCollector struct {
+	mu     sync.Mutex
+	stages map[Stage]*stageTiming
+}
+
+// stageTiming is what the collector knows about one stage.
+type stageTiming struct {
+	first, last time.Time
+	failures    []string // "section: message" for each failed event
+}
+
+// NewReportCollector returns an empty collector.
+func NewReportCollector() *ReportCollector {
+	return &ReportCollector{
+		stages: make(map[Stage]*stageTiming),
+	}
+}
+
+// Observe records ev. A stage's duration runs from its first event to its
+// last, which for pipeline runs are the stage header and the stage-level
+// complete or failed event, timed by when they were emitted rather than
+// observed. Events without a Time count as emitted now.
+func (c *ReportCollector) Observe(ev ProgressEvent) {
+	at := ev.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st, ok := c.stages[ev.Stage]
+	if !ok {
+		st = &stageTiming{first: at, last: at}
+		c.stages[ev.Stage] = st
+	}
+	if at.Before(st.first) {
+		st.first = at
+	}
+	if at.After(st.last) {
+		st.last = at
+	}
+	if ev.Status == ProgressFailed {
+		st.failures = append(st.failures, ev.Section+": "+ev.Message)
+	}
+}
+
+// RunReport summarizes a pipeline run: one entry per stage that ran.
+type RunReport struct {
+	Name   string
+	Stages []StageReport
+	Err    string // the error that stopped the run, if any
+}
+
+// StageReport summarizes one stage of a run.
+type StageReport struct {
+	Stage           Stage
+	Name            string
+	Status          ProgressStatus // complete or failed
+	Duration        time.Duration
+	Agents          []string // agents that produced the stage's sections, sorted
+	Files           []ReportFile
+	CoherenceIssues []string
+	Failures        []string // failed sections, as "section: message"
+}
+
+// ReportFile is an output file and its size on disk. Size is -1 when the
+// file could not be read.
+type ReportFile struct {
+	Path string
+	Size int64
+}
+
+// Report builds the RunReport for a run that returned results and runErr.
+// Stages with a result are complete unless their verification found
+// critical issues; a stage seen in progress events without a result is
+// failed.
+func (c *ReportCollector) Report(cfg Config, results []StageResult, runErr error) *RunReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &RunReport{Name: cfg.Name}
+	if runErr != nil {
+		report.Err = runErr.Error()
+	}
+
+	byStage := make(map[Stage]*StageResult, len(results))
+	for i := range results {
+		byStage[results[i].Stage] = &results[i]
+	}
+	var stages []Stage
+	for stage := range byStage {
+		stages = append(stages, stage)
+	}
+	for stage := range c.stages {
+		if byStage[stage] == nil {
+			stages = append(stages, stage)
+		}
+	}
+	sort.Slice(stages, func(i, j int) bool { return stages[i] < stages[j] })
+
+	for _, stage := range stages {
+		sr := StageReport{
+			Stage:  stage,
+			Name:   cfg.StageName(stage),
+			Status: ProgressFailed,
+		}
+		if st := c.stages[stage]; st != nil {
+			sr.Duration = st.last.Sub(st.first)
+			sr.Failures = st.failures
+		}
+		if res := byStage[stage]; res != nil {
+			if res.VerificationReport == nil || !res.VerificationReport.HasCritical() {
+				sr.Status = ProgressComplete
+			}
+			sr.Agents = sectionAgents(res.Sections)
+			for _, path := range res.FilePaths {
+				size := int64(-1)
+				if info, err := os.Stat(path); err == nil {
+					size = info.Size()
+				}
+				sr.Files = append(sr.Files, ReportFile{Path: path, Size: size})
+			}
+			for _, issue := range res.CoherenceIssues {
+				sr.CoherenceIssues = append(sr.CoherenceIssues, issue.Description)
+			}
+		}
+		report.Stages = append(report.Stages, sr)
+	}
+	return report
+}
+
+// sectionAgents returns the distinct agents named by sections, sorted.
+func sectionAgents(sections []Section) []string {
+	seen := make(map[string]bool)
+	var agents []string
+	for _, sec := range sections {
+		if sec.Agent != "" && !seen[sec.Agent] {
+			seen[sec.Agent] = true
+			agents = append(agents, sec.Agent)
+		}
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+// Markdown renders the report as a markdown document with a stage table
+// followed by any coherence issues and failures.
+func (r *RunReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Run report: %s\n\n", r.Name)
+	b.WriteString("| Stage | Status | Duration | Agents | Output | Size |\n")
+	b.WriteString("|-------|--------|----------|--------|--------|------|\n")
+	for _, s := range r.Stages {
+		agents := strings.Join(s.Agents, ", ")
+		if agents == "" {
+			agents = "-"
+		}
+		var paths, sizes []string
+		for _, f := range s.Files {
+			paths = append(paths, f.Path)
+			sizes = append(sizes, formatSize(f.Size))
+		}
+		if len(paths) == 0 {
+			paths, sizes = []string{"-"}, []string{"-"}
+		}
+		fmt.Fprintf(&b, "| %d %s | %s | %s | %s | %s | %s |\n",
+			int(s.Stage), s.Name, s.Status, s.Duration.Round(time.Millisecond),
+			agents, strings.Join(paths, ", "), strings.Join(sizes, ", "))
+	}
+
+	writeList := func(title string, items func(StageReport) []string) {
+		var lines []string
+		for _, s := range r.Stages {
+			for _, item := range items(s) {
+				lines = append(lines, fmt.Sprintf("- Stage %d (%s): %s", int(s.Stage), s.Name, item))
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", title, strings.Join(lines, "\n"))
+		}
+	}
+	writeList("Coherence issues", func(s StageReport) []string { return s.CoherenceIssues })
+	writeList("Failures", func(s StageReport) []string { return s.Failures })
+
+	if r.Err != "" {
+		fmt.Fprintf(&b, "\nRun failed: %s\n", r.Err)
+	}
+	return b.String()
+}
+
+// WriteFile writes the markdown report to ReportFileName in dir.
+func (r *RunReport) WriteFile(dir string) (string, error) {
+	path := filepath.Join(dir, ReportFileName)
+	if err := writeOutputFile(path, r.Markdown()); err != nil {
+		return "", fmt.Errorf("write report: %w", err)
+	}
+	return path, nil
+}
+
+// formatSize formats a byte count for the report; negative means unknown.
+func formatSize(n int64) string {
+	switch {
+	case n < 0:
+		return "?"
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	default:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+}
@@ -0,0 +1,132 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at returns a time the given number of seconds into a run.
+func at(seconds int) time.Time {
+	return time.Date(2026, 1, 1, 0, 0, seconds, 0, time.UTC)
+}
+
+func TestReportCollector_TwoStageRun(t *testing.T) {
+	client := &mockClient{
+		sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			return &a2a.Task{
+				ID:     "research-agent",
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+				Artifacts: []a2a.Artifact{
+					{ArtifactID: "art", Parts: []a2a.Part{a2a.TextPart("## Output\n\nUses Go 1.22.\n")}},
+				},
+			}, nil
+		},
+	}
+	cfg := Config{
+		Name:             "report",
+		OutputDir:        t.TempDir(),
+		Capability:       CapA2AMCP,
+		AgentEndpoints:   []string{"http://a"},
+		SkipVerification: true,
+	}
+	p := NewPipeline(cfg, client)
+
+	collector := NewReportCollector()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range p.Progress() {
+			collector.Observe(ev)
+		}
+	}()
+	results, err := p.RunPipeline(context.Background(), StageDesignPack, StageImplementationSkeletons)
+	p.Close()
+	<-done
+	require.NoError(t, err)
+
+	report := collector.Report(cfg, results, err)
+	require.Len(t, report.Stages, 2)
+	for i, stage := range []Stage{StageDesignPack, StageImplementationSkeletons} {
+		sr := report.Stages[i]
+		assert.Equal(t, stage, sr.Stage)
+		assert.Equal(t, ProgressComplete, sr.Status)
+		assert.Positive(t, sr.Duration)
+		assert.Equal(t, []string{"research-agent"}, sr.Agents)
+		require.Len(t, sr.Files, 1)
+		assert.Equal(t, results[i].FilePaths[0], sr.Files[0].Path)
+		assert.Positive(t, sr.Files[0].Size)
+	}
+
+	md := report.Markdown()
+	assert.Contains(t, md, "# Run report: report\n")
+	assert.Contains(t, md, "| 1 design-pack | complete | ")
+	assert.Contains(t, md, "| 2 implementation-skeletons | complete | ")
+	for _, r := range results {
+		assert.Contains(t, md, r.FilePaths[0])
+	}
+
+	path, err := report.WriteFile(cfg.OutputDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cfg.OutputDir, ReportFileName), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, md, string(data))
+}
+
+func TestRunReport_FailuresAndCoherence(t *testing.T) {
+	collector := NewReportCollector()
+	collector.Observe(ProgressEvent{Stage: StageDesignPack, Section: "[x] Stage 1: design-pack", Status: ProgressWorking, Time: at(1)})
+	collector.Observe(ProgressEvent{Stage: StageDesignPack, Section: "design-pack", Status: ProgressComplete, Time: at(2)})
+	collector.Observe(ProgressEvent{Stage: StageImplementationSkeletons, Section: "[x] Stage 2: implementation-skeletons", Status: ProgressWorking, Time: at(3)})
+	collector.Observe(ProgressEvent{Stage: StageImplementationSkeletons, Section: "data-model", Status: ProgressFailed, Message: "agent timeout", Time: at(4)})
+
+	results := []StageResult{{
+		Stage: StageDesignPack,
+		CoherenceIssues: []CoherenceIssue{
+			{SectionA: "platform", SectionB: "data-model", Description: "go: 1.21 vs 1.22"},
+		},
+	}}
+	md := collector.Report(Config{Name: "x"}, results, errors.New("router: stage 2 failed")).Markdown()
+
+	assert.Contains(t, md, "| 1 design-pack | complete | 1s | - | - | - |")
+	assert.Contains(t, md, "| 2 implementation-skeletons | failed | 1s | - | - | - |")
+	assert.Contains(t, md, "## Coherence issues\n\n- Stage 1 (design-pack): go: 1.21 vs 1.22\n")
+	assert.Contains(t, md, "## Failures\n\n- Stage 2 (implementation-skeletons): data-model: agent timeout\n")
+	assert.True(t, strings.HasSuffix(md, "\nRun failed: router: stage 2 failed\n"))
+}
+
+func TestReportCollector_UsesEmitTime(t *testing.T) {
+	pr := NewProgressReporter()
+	pr.Emit(ProgressEvent{Stage: StageDesignPack, Section: "design-pack", Status: ProgressWorking})
+	time.Sleep(20 * time.Millisecond)
+	pr.Emit(ProgressEvent{Stage: StageDesignPack, Section: "design-pack", Status: ProgressComplete})
+	pr.Close()
+
+	// The events are drained at once, after the run; the stage is still
+	// timed by when they were emitted.
+	collector := NewReportCollector()
+	for ev := range pr.Subscribe() {
+		assert.False(t, ev.Time.IsZero(), "Emit stamps the event")
+		collector.Observe(ev)
+	}
+
+	report := collector.Report(Config{Name: "x"}, nil, nil)
+	require.Len(t, report.Stages, 1)
+	assert.GreaterOrEqual(t, report.Stages[0].Duration, 20*time.Millisecond)
+
+	// Events arriving out of order keep the earliest and latest times.
+	collector = NewReportCollector()
+	collector.Observe(ProgressEvent{Stage: StageTaskIndex, Status: ProgressComplete, Time: at(5)})
+	collector.Observe(ProgressEvent{Stage: StageTaskIndex, Status: ProgressWorking, Time: at(2)})
+	report = collector.Report(Config{Name: "x"}, nil, nil)
+	assert.Equal(t, 3*time.Second, report.Stages[0].Duration)
+}
@@ -14,8 +14,17 @@ type StageExecutor interface {
 	Execute(ctx context.Context, cfg Config, inputs []StageResult) (*StageResult, error)
 }
 
+// stageAwareExecutor is implemented by executors that can run an explicitly
+// named stage instead of inferring it from the inputs. Inference picks the
+// first stage without readable output, which is wrong when an earlier stage
+// wrote nothing the router can read back (e.g. Stage 4 in basic mode).
+type stageAwareExecutor interface {
+	ExecuteStage(ctx context.Context, cfg Config, stage Stage, inputs []StageResult) (*StageResult, error)
+}
+
 // Router maps pipeline stages to their registered executors and handles
-// prerequisite resolution.
+// prerequisite resolution. Prerequisites come from cfg.Stages when a custom
+// pipeline is configured and from the built-in rules otherwise.
 type Router struct {
 	cfg       Config
 	executors map[Stage]StageExecutor
@@ -38,16 +47,20 @@ func (r *Router) RegisterExecutor(stage Stage, exec StageExecutor) {
 // Route resolves prerequisites for the given stage, reads their output files,
 // and delegates to the registered StageExecutor.
 func (r *Router) Route(ctx context.Context, stage Stage) (*StageResult, error) {
+	name := r.cfg.StageName(stage)
 	exec, ok := r.executors[stage]
 	if !ok {
-		return nil, fmt.Errorf("router: no executor registered for stage %d (%s)", stage, stage)
+		return nil, fmt.Errorf("router: no executor registered for stage %d (%s)", stage, name)
 	}
 
 	inputs, err := r.resolvePrerequisites(stage)
 	if err != nil {
-		return nil, fmt.Errorf("router: prerequisite check failed for stage %d (%s): %w", stage, stage, err)
+		return nil, fmt.Errorf("router: prerequisite check failed for stage %d (%s): %w", stage, name, err)
 	}
 
+	if se, ok := exec.(stageAwareExecutor); ok {
+		return se.ExecuteStage(ctx, r.cfg, stage, inputs)
+	}
 	return exec.Execute(ctx, r.cfg, inputs)
 }
 
@@ -64,13 +77,13 @@ func (r *Router) RouteRange(ctx context.Context, from, to Stage) ([]StageResult,
 	for stage := from; stage <= to; stage++ {
 		result, err := r.Route(ctx, stage)
 		if err != nil {
-			return results, fmt.Errorf("router: stage %d (%s) failed: %w", stage, stage, err)
+			return results, fmt.Errorf("router: stage %d (%s) failed: %w", stage, r.cfg.StageName(stage), err)
 		}
 		results = append(results, *result)
 
 		// Block pipeline progression if verification found critical issues.
 		if result.VerificationReport != nil && result.VerificationReport.HasCritical() {
-			return results, fmt.Errorf("router: stage %d (%s) failed verification with critical findings", stage, stage)
+			return results, fmt.Errorf("router: stage %d (%s) %w", stage, r.cfg.StageName(stage), ErrVerificationFailed)
 		}
 	}
 
@@ -116,6 +129,43 @@ func prerequisites(stage Stage) []prerequisiteRule {
 	}
 }
 
+// DefaultPrerequisites returns the stages the built-in pipeline reads before
+// stage. All are required except Stage 0 before Stage 1, which is optional.
+// Config.Prerequisites overrides them per stage.
+func DefaultPrerequisites(stage Stage) []Stage {
+	rules := prerequisites(stage)
+	out := make([]Stage, len(rules))
+	for i, rule := range rules {
+		out[i] = rule.stage
+	}
+	return out
+}
+
+// prerequisiteRules returns the prerequisite rules for stage in the
+// configured pipeline: c.Prerequisites when it lists the stage, else the
+// custom or built-in rules. Configured and custom prerequisites are always
+// required.
+func (c Config) prerequisiteRules(stage Stage) []prerequisiteRule {
+	if pres, ok := c.Prerequisites[stage]; ok {
+		rules := make([]prerequisiteRule, len(pres))
+		for i, pre := range pres {
+			rules[i] = prerequisiteRule{stage: pre, required: true}
+		}
+		return rules
+	}
+	def, ok := c.stageDefinition(stage)
+	if !ok {
+		return prerequisites(stage)
+	}
+	rules := make([]prerequisiteRule, 0, len(def.Prerequisites))
+	for _, name := range def.Prerequisites {
+		if pre, ok := c.stageIndex(name); ok {
+			rules = append(rules, prerequisiteRule{stage: pre, required: true})
+		}
+	}
+	return rules
+}
+
 // stageFileName returns the expected output filename for a stage.
 func stageFileName(stage Stage) string {
 	return fmt.Sprintf("stage-%d-%s.md", int(stage), stage.String())
@@ -132,9 +182,12 @@ func (r *Router) resolvePrerequisites(stage Stage) ([]StageResult, error) {
 	}
 
 	// Build a set of required stages for fast lookup.
-	rules := prerequisites(stage)
+	rules := r.cfg.prerequisiteRules(stage)
 	required := make(map[Stage]bool, len(rules))
 	for _, rule := range rules {
+		if rule.stage >= stage {
+			return nil, fmt.Errorf("prerequisite stage %d (%s) is not an earlier stage", rule.stage, r.cfg.StageName(rule.stage))
+		}
 		if rule.required {
 			required[rule.stage] = true
 		}
@@ -147,7 +200,7 @@ func (r *Router) resolvePrerequisites(stage Stage) ([]StageResult, error) {
 		if err != nil {
 			if required[s] {
 				return nil, fmt.Errorf("required prerequisite stage %d (%s) not satisfied: %w",
-					s, s, err)
+					s, r.cfg.StageName(s), err)
 			}
 			// Non-required prior stage: skip silently.
 			continue
@@ -166,8 +219,7 @@ func (r *Router) readStageOutput(stage Stage) (*StageResult, error) {
 		return r.readTaskSpecFiles()
 	}
 
-	filename := stageFileName(stage)
-	p := filepath.Join(r.cfg.OutputDir, filename)
+	p := stageOutputPath(r.cfg, stage)
 
 	data, err := os.ReadFile(p)
 	if err != nil {
@@ -179,7 +231,7 @@ func (r *Router) readStageOutput(stage Stage) (*StageResult, error) {
 		FilePaths: []string{p},
 		Sections: []Section{
 			{
-				Name:    stage.String(),
+				Name:    r.cfg.StageName(stage),
 				Content: string(data),
 			},
 		},
@@ -187,9 +239,11 @@ func (r *Router) readStageOutput(stage Stage) (*StageResult, error) {
 }
 
 // readTaskSpecFiles reads all task specification files matching
-// "tasks_m*.md" in the output directory.
+// "tasks_m*.md" in the Stage 4 output directory (OutputDir for the flat
+// layout, OutputDir/stage-4 for the nested layout).
 func (r *Router) readTaskSpecFiles() (*StageResult, error) {
-	pattern := filepath.Join(r.cfg.OutputDir, "tasks_m*.md")
+	dir := filepath.Dir(stageOutputPath(r.cfg, StageTaskSpecifications))
+	pattern := filepath.Join(dir, "tasks_m*.md")
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("globbing task spec files %s: %w", pattern, err)
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/onedusk/pd/internal/a2a"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -231,3 +234,57 @@ func TestRoute_NoExecutorRegistered_ReturnsError(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "no executor registered")
 }
+
+func TestRoute_PrerequisitesOverride(t *testing.T) {
+	run := func(t *testing.T, writeStage0 bool, prereqs map[Stage][]Stage) (string, error) {
+		t.Helper()
+		var (
+			mu      sync.Mutex
+			prompts []string
+		)
+		client := &mockClient{
+			sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+				mu.Lock()
+				prompts = append(prompts, req.Message.Parts[0].Text)
+				mu.Unlock()
+				return completedTask("t", "section"), nil
+			},
+		}
+		dir := t.TempDir()
+		if writeStage0 {
+			writeStageFile(t, dir, StageDevelopmentStandards, "# Standards\n\nUse table-driven tests.\n")
+		}
+		writeStageFile(t, dir, StageDesignPack, "# Design Pack\n")
+		cfg := Config{
+			OutputDir:        dir,
+			Capability:       CapA2AMCP,
+			AgentEndpoints:   []string{"http://a"},
+			SkipVerification: true,
+			Prerequisites:    prereqs,
+		}
+		p := NewPipeline(cfg, client)
+		defer p.Close()
+		_, err := p.RunStage(context.Background(), StageImplementationSkeletons)
+		return strings.Join(prompts, "\n"), err
+	}
+
+	t.Run("configured predecessors feed the context", func(t *testing.T) {
+		prompts, err := run(t, true, map[Stage][]Stage{StageImplementationSkeletons: {StageDevelopmentStandards, StageDesignPack}})
+		require.NoError(t, err)
+		assert.Contains(t, prompts, "Use table-driven tests.")
+		assert.Contains(t, prompts, "# Design Pack")
+	})
+
+	t.Run("unlisted predecessors are left out", func(t *testing.T) {
+		prompts, err := run(t, true, map[Stage][]Stage{StageImplementationSkeletons: {StageDesignPack}})
+		require.NoError(t, err)
+		assert.NotContains(t, prompts, "Use table-driven tests.")
+		assert.Contains(t, prompts, "# Design Pack")
+	})
+
+	t.Run("configured predecessors are required", func(t *testing.T) {
+		_, err := run(t, false, map[Stage][]Stage{StageImplementationSkeletons: {StageDevelopmentStandards, StageDesignPack}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "required prerequisite stage 0")
+	})
+}
@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// sectionCacheSuffix names the sidecar file, next to a stage's output file,
+// that holds the sections merged into it; see Config.MergeOnly.
+const sectionCacheSuffix = ".sections.json"
+
+// sectionCache is the sidecar file format: the stage's sections as the
+// agents returned them, before merging and post-processing.
+type sectionCache struct {
+	Stage    int       `json:"stage"`
+	Sections []Section `json:"sections"`
+}
+
+// SectionCachePath returns the path of the sidecar file caching the sections
+// of stage under cfg.OutputDir.
+func SectionCachePath(cfg Config, stage Stage) string {
+	return stageOutputPath(cfg, stage) + sectionCacheSuffix
+}
+
+// saveSections writes sections to the sidecar file for stage.
+func saveSections(cfg Config, stage Stage, sections []Section) error {
+	data, err := json.MarshalIndent(sectionCache{Stage: int(stage), Sections: sections}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cache sections: %w", err)
+	}
+	if err := writeOutputFile(SectionCachePath(cfg, stage), string(data)+"\n"); err != nil {
+		return fmt.Errorf("cache sections: %w", err)
+	}
+	return nil
+}
+
+// loadSections reads the sections cached for stage by an earlier run.
+func loadSections(cfg Config, stage Stage) ([]Section, error) {
+	path := SectionCachePath(cfg, stage)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no cached sections at %s; run the stage with agents first", path)
+		}
+		return nil, fmt.Errorf("load cached sections: %w", err)
+	}
+	var cache sectionCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("load cached sections %s: %w", path, err)
+	}
+	if cache.Stage != int(stage) {
+		return nil, fmt.Errorf("load cached sections %s: cached for stage %d, not %d", path, cache.Stage, stage)
+	}
+	return cache.Sections, nil
+}
@@ -0,0 +1,162 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sectionEchoClient answers each section prompt with a body naming the
+// section, counting calls.
+func sectionEchoClient(calls *atomic.Int32) *mockClient {
+	return &mockClient{
+		sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			n := calls.Add(1)
+			text := req.Message.Parts[0].Text
+			name := strings.SplitN(strings.TrimPrefix(text, `Generate the "`), `"`, 2)[0]
+			return &a2a.Task{
+				ID:     fmt.Sprintf("t%d", n),
+				Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+				Artifacts: []a2a.Artifact{
+					{ArtifactID: "art", Parts: []a2a.Part{a2a.TextPart("## " + name + "\n\nbody of " + name)}},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestPipeline_MergeOnlyReproducesStageFromCache(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, stageFileName(StageDevelopmentStandards)), []byte("# Standards\n"), 0o644))
+	cfg := Config{
+		Name:             "cached",
+		OutputDir:        dir,
+		Capability:       CapA2AMCP,
+		AgentEndpoints:   []string{"http://a", "http://b"},
+		SkipVerification: true,
+	}
+
+	var calls atomic.Int32
+	p := NewPipeline(cfg, sectionEchoClient(&calls))
+	result, err := p.RunStage(context.Background(), StageDesignPack)
+	p.Close()
+	require.NoError(t, err)
+	require.Equal(t, int32(len(Stage1MergePlan.SectionOrder)), calls.Load())
+	assert.FileExists(t, SectionCachePath(cfg, StageDesignPack))
+
+	stagePath := result.FilePaths[0]
+	want, err := os.ReadFile(stagePath)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(stagePath))
+
+	cfg.MergeOnly = true
+	p = NewPipeline(cfg, stubClient(t))
+	defer p.Close()
+	rerun, err := p.RunStage(context.Background(), StageDesignPack)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{stagePath}, rerun.FilePaths)
+	got, err := os.ReadFile(stagePath)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+	assert.Equal(t, result.Sections, rerun.Sections)
+}
+
+func TestPipeline_MergeOnlyAppliesNewPostProcessors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, stageFileName(StageDevelopmentStandards)), []byte("# Standards\n"), 0o644))
+	cfg := Config{
+		Name:             "reprocess",
+		OutputDir:        dir,
+		Capability:       CapA2AMCP,
+		AgentEndpoints:   []string{"http://a"},
+		SkipVerification: true,
+	}
+
+	var calls atomic.Int32
+	p := NewPipeline(cfg, sectionEchoClient(&calls))
+	_, err := p.RunStage(context.Background(), StageDesignPack)
+	p.Close()
+	require.NoError(t, err)
+
+	cfg.MergeOnly = true
+	cfg.PostProcessors = []PostProcessor{func(_ Stage, content string) (string, error) {
+		return strings.ToUpper(content), nil
+	}}
+	p = NewPipeline(cfg, stubClient(t))
+	defer p.Close()
+	result, err := p.RunStage(context.Background(), StageDesignPack)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(result.FilePaths[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "BODY OF "+strings.ToUpper(Stage1MergePlan.SectionOrder[0]))
+}
+
+func TestPipeline_MergeOnlyTaskSpecs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{
+		Name:             "specs",
+		OutputDir:        dir,
+		Capability:       CapA2AMCP,
+		AgentEndpoints:   []string{"http://a"},
+		SkipVerification: true,
+	}
+	stage3 := StageResult{
+		Stage:    StageTaskIndex,
+		Sections: []Section{{Name: "progress", Content: "## M1: Scaffolding\n\nSet up.\n\n## M2: API\n\nServe.\n"}},
+	}
+	client := &mockClient{
+		sendMessage: func(_ context.Context, _ string, req a2a.SendMessageRequest) (*a2a.Task, error) {
+			heading := strings.SplitN(req.Message.Parts[0].Text, "\n", 3)[1]
+			return &a2a.Task{
+				ID:        heading,
+				Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted},
+				Artifacts: []a2a.Artifact{{ArtifactID: "art", Parts: []a2a.Part{a2a.TextPart("# Tasks for " + heading + "\n")}}},
+			}, nil
+		},
+	}
+	p := NewPipeline(cfg, client)
+	first, err := p.ExecuteStage(context.Background(), cfg, StageTaskSpecifications, []StageResult{stage3})
+	p.Close()
+	require.NoError(t, err)
+	for _, path := range first.FilePaths {
+		require.NoError(t, os.Remove(path))
+	}
+
+	cfg.MergeOnly = true
+	p = NewPipeline(cfg, stubClient(t))
+	defer p.Close()
+	rerun, err := p.ExecuteStage(context.Background(), cfg, StageTaskSpecifications, []StageResult{stage3})
+	require.NoError(t, err)
+	assert.Equal(t, first.FilePaths, rerun.FilePaths)
+	for _, path := range rerun.FilePaths {
+		assert.FileExists(t, path)
+	}
+	assert.Equal(t, first.Sections, rerun.Sections)
+}
+
+func TestPipeline_MergeOnlyWithoutCacheFails(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, stageFileName(StageDevelopmentStandards)), []byte("# Standards\n"), 0o644))
+	cfg := Config{
+		Name:       "uncached",
+		OutputDir:  dir,
+		Capability: CapA2AMCP,
+		MergeOnly:  true,
+	}
+	p := NewPipeline(cfg, stubClient(t))
+	defer p.Close()
+
+	_, err := p.RunStage(context.Background(), StageDesignPack)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no cached sections")
+}
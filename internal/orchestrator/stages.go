@@ -0,0 +1,261 @@
+package orchestrator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StageDefinition describes one stage of a custom pipeline. Definitions are
+// positional: the Nth entry in Config.Stages is Stage N. Entries at positions
+// 0–4 take over the built-in stage at that position under a new name and keep
+// its merge plan, verification rules, and output handling; later entries are
+// generic single-agent stages.
+type StageDefinition struct {
+	// Name identifies the stage in file names, headers, and prerequisite
+	// lists. It must be unique and must not contain path separators.
+	Name string
+
+	// Template is an optional markdown template path, relative to the
+	// project root. Its "## " headings become the stage's section order.
+	Template string
+
+	// Prerequisites names the stages whose output must exist before this
+	// stage runs. Each must refer to an earlier entry.
+	Prerequisites []string
+
+	// Merge optionally overrides the merge strategy of the stage's plan,
+	// e.g. MergeDedup to drop boilerplate repeated across agent sections.
+	Merge MergeStrategy
+
+	// Conflicts optionally sets how the stage's merge handles sections that
+	// share a name; the zero value rejects them.
+	Conflicts ConflictPolicy
+
+	// OutputModes optionally replaces the media types the stage asks its
+	// agents for; see DefaultOutputModes.
+	OutputModes []string
+}
+
+// ValidateStageDefinitions checks that stage names are present and unique and
+// that every prerequisite names an earlier stage, which makes list order a
+// valid execution order for the prerequisite DAG.
+func ValidateStageDefinitions(defs []StageDefinition) error {
+	seen := make(map[string]bool, len(defs))
+	for i, def := range defs {
+		if def.Name == "" {
+			return fmt.Errorf("stage %d: name is required", i)
+		}
+		if strings.ContainsAny(def.Name, `/\`) {
+			return fmt.Errorf("stage %d: name %q must not contain path separators", i, def.Name)
+		}
+		if seen[def.Name] {
+			return fmt.Errorf("stage %d: duplicate stage name %q", i, def.Name)
+		}
+		switch def.Merge {
+		case "", MergeConcatenate, MergeDedup:
+		default:
+			return fmt.Errorf("stage %d (%s): unknown merge strategy %q", i, def.Name, def.Merge)
+		}
+		switch def.Conflicts {
+		case ConflictReject, ConflictFirstWins, ConflictLastWins, ConflictLongestWins, ConflictConcat:
+		default:
+			return fmt.Errorf("stage %d (%s): unknown conflict policy %q", i, def.Name, def.Conflicts)
+		}
+		for _, pre := range def.Prerequisites {
+			if !seen[pre] {
+				return fmt.Errorf("stage %d (%s): prerequisite %q is not an earlier stage", i, def.Name, pre)
+			}
+		}
+		seen[def.Name] = true
+	}
+	return nil
+}
+
+// StageName returns the name of a stage, taken from Config.Stages when a
+// custom pipeline is configured and from the built-in names otherwise.
+func (c Config) StageName(stage Stage) string {
+	if def, ok := c.stageDefinition(stage); ok {
+		return def.Name
+	}
+	return stage.String()
+}
+
+// OutputModes returns the media types a stage's agent tasks accept, in
+// order of preference: the custom definition's OutputModes when set and
+// DefaultOutputModes otherwise.
+func (c Config) OutputModes(stage Stage) []string {
+	if def, ok := c.stageDefinition(stage); ok && len(def.OutputModes) > 0 {
+		return def.OutputModes
+	}
+	return DefaultOutputModes(stage)
+}
+
+// DefaultOutputModes returns the media types the built-in pipeline accepts
+// from agents for stage, in order of preference. Every stage prefers
+// markdown; Stage 2 also takes Go code and Stage 3 also takes structured
+// task data.
+func DefaultOutputModes(stage Stage) []string {
+	switch stage {
+	case StageImplementationSkeletons:
+		return []string{"text/markdown", "text/x-go"}
+	case StageTaskIndex:
+		return []string{"text/markdown", "application/json"}
+	default:
+		return []string{"text/markdown"}
+	}
+}
+
+// LastStage returns the final stage of the configured pipeline.
+func (c Config) LastStage() Stage {
+	if len(c.Stages) > 0 {
+		return Stage(len(c.Stages) - 1)
+	}
+	return StageTaskSpecifications
+}
+
+// StageInfo describes one stage of the configured pipeline as the Router
+// resolves it.
+type StageInfo struct {
+	Stage Stage
+	Name  string
+	// Template is the custom definition's template path; empty for stages
+	// that use a built-in section plan.
+	Template string
+	// Sections is the stage's section order; see mergePlanFor.
+	Sections      []string
+	Prerequisites []StagePrerequisite
+}
+
+// StagePrerequisite is an earlier stage whose output a stage reads. A
+// missing required prerequisite stops the stage; a missing optional one is
+// skipped.
+type StagePrerequisite struct {
+	Stage    Stage
+	Required bool
+}
+
+// ResolveStages returns every stage of the configured pipeline in execution
+// order, with the names, section plans, and prerequisites the Router uses.
+func (c Config) ResolveStages() ([]StageInfo, error) {
+	var infos []StageInfo
+	for stage := StageDevelopmentStandards; stage <= c.LastStage(); stage++ {
+		plan, err := mergePlanFor(c, stage)
+		if err != nil {
+			return nil, err
+		}
+		info := StageInfo{
+			Stage:    stage,
+			Name:     c.StageName(stage),
+			Sections: plan.SectionOrder,
+		}
+		if def, ok := c.stageDefinition(stage); ok {
+			info.Template = def.Template
+		}
+		for _, rule := range c.prerequisiteRules(stage) {
+			info.Prerequisites = append(info.Prerequisites, StagePrerequisite{Stage: rule.stage, Required: rule.required})
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// stageDefinition returns the custom definition for stage, if any.
+func (c Config) stageDefinition(stage Stage) (StageDefinition, bool) {
+	if int(stage) < 0 || int(stage) >= len(c.Stages) {
+		return StageDefinition{}, false
+	}
+	return c.Stages[stage], true
+}
+
+// stageIndex returns the stage with the given custom name.
+func (c Config) stageIndex(name string) (Stage, bool) {
+	for i, def := range c.Stages {
+		if def.Name == name {
+			return Stage(i), true
+		}
+	}
+	return 0, false
+}
+
+// mergePlanFor returns the MergePlan for a stage of the configured pipeline.
+// A custom stage with a template uses the template's headings as its section
+// order; otherwise built-in multi-section stages keep their plans and every
+// other stage is a single section named after the stage. A definition's
+// Merge strategy and Conflicts policy, if set, replace the plan's.
+func mergePlanFor(cfg Config, stage Stage) (MergePlan, error) {
+	plan, err := basePlanFor(cfg, stage)
+	if err != nil {
+		return MergePlan{}, err
+	}
+	if def, ok := cfg.stageDefinition(stage); ok {
+		if def.Merge != "" {
+			plan.Strategy = def.Merge
+		}
+		if def.Conflicts != "" {
+			plan.Conflicts = def.Conflicts
+		}
+	}
+	return plan, nil
+}
+
+// basePlanFor returns the stage's MergePlan before any strategy override.
+func basePlanFor(cfg Config, stage Stage) (MergePlan, error) {
+	if def, ok := cfg.stageDefinition(stage); ok && def.Template != "" {
+		path := def.Template
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cfg.ProjectRoot, path)
+		}
+		sections, err := templateSections(path)
+		if err != nil {
+			return MergePlan{}, fmt.Errorf("stage %d (%s): %w", stage, def.Name, err)
+		}
+		if len(sections) > 0 {
+			return MergePlan{Strategy: MergeConcatenate, SectionOrder: sections}, nil
+		}
+	}
+	if stage > StageDevelopmentStandards && stage < StageTaskSpecifications {
+		return MergePlanForStage(stage), nil
+	}
+	return MergePlan{
+		Strategy:     MergeConcatenate,
+		SectionOrder: []string{cfg.StageName(stage)},
+	}, nil
+}
+
+// templateSections reads a markdown template and returns the kebab-case
+// names of its level-2 headings in order.
+func templateSections(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	defer f.Close()
+
+	var sections []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		heading, ok := strings.CutPrefix(scanner.Text(), "## ")
+		if !ok {
+			continue
+		}
+		if name := kebabCase(heading); name != "" {
+			sections = append(sections, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read template %s: %w", path, err)
+	}
+	return sections, nil
+}
+
+// kebabCase lowercases s and joins its alphanumeric runs with hyphens:
+// "Threat Model (STRIDE)" → "threat-model-stride".
+func kebabCase(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+	return strings.Join(words, "-")
+}
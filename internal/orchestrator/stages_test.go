@@ -0,0 +1,128 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// customStages returns the built-in five stages followed by a sixth,
+// template-driven "security-review" stage that requires the design pack.
+func customStages() []StageDefinition {
+	return []StageDefinition{
+		{Name: "development-standards"},
+		{Name: "design-pack", Prerequisites: []string{"development-standards"}},
+		{Name: "implementation-skeletons", Prerequisites: []string{"design-pack"}},
+		{Name: "task-index", Prerequisites: []string{"design-pack", "implementation-skeletons"}},
+		{Name: "task-specifications", Prerequisites: []string{"task-index"}},
+		{Name: "security-review", Template: "security.md", Prerequisites: []string{"design-pack"}},
+	}
+}
+
+// TestPipeline_CustomSixthStage runs a six-stage pipeline in basic mode and
+// verifies the added stage runs after its prerequisite, writes its own file,
+// and takes its sections from the template headings.
+func TestPipeline_CustomSixthStage(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "security.md"),
+		[]byte("# Security Review\n\n## Threat Model\n\n## Secrets Handling\n"), 0o644))
+
+	cfg := Config{
+		Name:             "custom",
+		ProjectRoot:      root,
+		OutputDir:        filepath.Join(root, "out"),
+		Capability:       CapBasic,
+		SkipVerification: true,
+		Stages:           customStages(),
+	}
+	require.NoError(t, ValidateStageDefinitions(cfg.Stages))
+
+	p := NewPipeline(cfg, nil)
+	defer p.Close()
+
+	results, err := p.RunPipeline(context.Background(), StageDevelopmentStandards, cfg.LastStage())
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+
+	order := make([]Stage, len(results))
+	for i, r := range results {
+		order[i] = r.Stage
+	}
+	assert.Equal(t, []Stage{0, 1, 2, 3, 4, 5}, order)
+
+	review := results[5]
+	assert.Equal(t, filepath.Join(cfg.OutputDir, "stage-5-security-review.md"), review.FilePaths[0])
+	data, err := os.ReadFile(review.FilePaths[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Stage 5: security-review")
+	assert.Contains(t, string(data), "## threat-model")
+	assert.Contains(t, string(data), "## secrets-handling")
+}
+
+// TestRouter_CustomStageRequiresPrerequisite verifies that a custom stage
+// does not run until its prerequisite's output exists.
+func TestRouter_CustomStageRequiresPrerequisite(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{OutputDir: dir, Stages: customStages()}
+	router := NewRouter(cfg)
+	exec := &mockExecutor{result: &StageResult{Stage: 5}}
+	router.RegisterExecutor(5, exec)
+
+	_, err := router.Route(context.Background(), 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "design-pack")
+	assert.Equal(t, 0, exec.called)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stage-1-design-pack.md"), []byte("# Design"), 0o644))
+	_, err = router.Route(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, exec.called)
+}
+
+func TestValidateStageDefinitions(t *testing.T) {
+	require.NoError(t, ValidateStageDefinitions(nil))
+	require.NoError(t, ValidateStageDefinitions(customStages()))
+
+	for name, defs := range map[string][]StageDefinition{
+		"missing name":         {{Name: ""}},
+		"duplicate name":       {{Name: "a"}, {Name: "a"}},
+		"path separator":       {{Name: "a/b"}},
+		"forward prerequisite": {{Name: "a", Prerequisites: []string{"b"}}, {Name: "b"}},
+		"unknown prerequisite": {{Name: "a", Prerequisites: []string{"nope"}}},
+		"unknown merge":        {{Name: "a", Merge: "zip"}},
+	} {
+		assert.Error(t, ValidateStageDefinitions(defs), name)
+	}
+}
+
+// TestConfig_DefaultStages verifies that without custom stages the built-in
+// names, file paths, and last stage are unchanged.
+func TestConfig_DefaultStages(t *testing.T) {
+	cfg := Config{OutputDir: "out"}
+	assert.Equal(t, StageTaskSpecifications, cfg.LastStage())
+	for s := StageDevelopmentStandards; s <= StageTaskSpecifications; s++ {
+		assert.Equal(t, s.String(), cfg.StageName(s))
+		assert.Equal(t, StageFilePath("out", s, LayoutFlat), stageOutputPath(cfg, s))
+	}
+}
+
+// TestMergePlanFor_StrategyOverride verifies that a stage definition's Merge
+// strategy replaces the built-in plan's while keeping its section order.
+func TestMergePlanFor_StrategyOverride(t *testing.T) {
+	stages := customStages()
+	stages[1].Merge = MergeDedup
+	cfg := Config{Stages: stages}
+
+	plan, err := mergePlanFor(cfg, StageDesignPack)
+	require.NoError(t, err)
+	assert.Equal(t, MergeDedup, plan.Strategy)
+	assert.Equal(t, Stage1MergePlan.SectionOrder, plan.SectionOrder)
+
+	plan, err = mergePlanFor(cfg, StageImplementationSkeletons)
+	require.NoError(t, err)
+	assert.Equal(t, MergeConcatenate, plan.Strategy)
+}
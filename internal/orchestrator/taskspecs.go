@@ -0,0 +1,134 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// taskSpecFileName returns the Stage 4 file name for a milestone ID such as
+// "M3": "tasks_m03.md".
+func taskSpecFileName(milestoneID string) string {
+	n, err := strconv.Atoi(strings.TrimPrefix(milestoneID, "M"))
+	if err != nil {
+		return fmt.Sprintf("tasks_%s.md", strings.ToLower(milestoneID))
+	}
+	return fmt.Sprintf("tasks_m%02d.md", n)
+}
+
+// stageContent joins the section contents of the input for stage, or
+// returns "" when inputs do not include it.
+func stageContent(inputs []StageResult, stage Stage) string {
+	for _, in := range inputs {
+		if in.Stage != stage {
+			continue
+		}
+		parts := make([]string, len(in.Sections))
+		for i, sec := range in.Sections {
+			parts[i] = sec.Content
+		}
+		return strings.Join(parts, "\n\n")
+	}
+	return ""
+}
+
+// assignMilestonesToAgents creates one write-task-specs AgentTask per
+// milestone, assigned round-robin to cfg.AgentEndpoints. Each task's Section
+// is the milestone ID.
+func assignMilestonesToAgents(cfg Config, milestones []MilestoneNode, contextText string) []AgentTask {
+	endpoints := cfg.AgentEndpoints
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	tasks := make([]AgentTask, 0, len(milestones))
+	for i, m := range milestones {
+		prompt := fmt.Sprintf("write-task-specs\nMilestone %s: %s\n\n%s",
+			strings.TrimPrefix(m.ID, "M"), m.Name, contextText)
+		meta, _ := json.Marshal(taskMetadata{Stage: int(StageTaskSpecifications), Section: m.ID})
+		tasks = append(tasks, AgentTask{
+			AgentEndpoint:       endpoints[i%len(endpoints)],
+			Section:             m.ID,
+			AcceptedOutputModes: cfg.OutputModes(StageTaskSpecifications),
+			Message: a2a.Message{
+				Role:     a2a.RoleUser,
+				Parts:    []a2a.Part{a2a.TextPart(prompt)},
+				Metadata: meta,
+			},
+		})
+	}
+	return tasks
+}
+
+// executeTaskSpecFanOut runs Stage 4 as one agent task per Stage 3
+// milestone and writes each milestone's output to its own tasks_mNN.md file
+// in the Stage 4 output directory. FilePaths lists the files in milestone
+// order.
+func (p *Pipeline) executeTaskSpecFanOut(ctx context.Context, cfg Config, milestones []MilestoneNode, inputs []StageResult) (*StageResult, error) {
+	stage := StageTaskSpecifications
+	name := cfg.StageName(stage)
+
+	tasks := assignMilestonesToAgents(cfg, milestones, buildContextMessage(cfg, stage, inputs))
+	p.narrowOutputModes(ctx, tasks)
+	agentResults, err := p.fanout.Run(ctx, stage, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: fan-out for stage %d (%s) failed: %w", stage, name, err)
+	}
+
+	// Cache the raw milestone outputs for merge-only reruns.
+	sections := make([]Section, 0, len(agentResults))
+	for _, r := range agentResults {
+		sections = append(sections, Section{
+			Name:    r.Section,
+			Content: extractTextFromArtifacts(r.Artifacts),
+			Agent:   agentFromTask(r.Task),
+		})
+	}
+	if err := saveSections(cfg, stage, sections); err != nil {
+		log.Printf("WARNING: stage %d (%s): %v", stage, name, err)
+	}
+
+	return p.writeTaskSpecs(cfg, sections, inputs)
+}
+
+// writeTaskSpecs post-processes each milestone section and writes it to its
+// tasks_mNN.md file in the Stage 4 output directory, then verifies the set.
+func (p *Pipeline) writeTaskSpecs(cfg Config, sections []Section, inputs []StageResult) (*StageResult, error) {
+	stage := StageTaskSpecifications
+	dir := filepath.Dir(stageOutputPath(cfg, stage))
+	result := &StageResult{Stage: stage}
+	var all []string
+	for _, sec := range sections {
+		content, err := applyPostProcessors(cfg.PostProcessors, stage, sec.Content)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline: %s: %w", sec.Name, err)
+		}
+		path := filepath.Join(dir, taskSpecFileName(sec.Name))
+		if err := writeOutputFile(path, content); err != nil {
+			return nil, fmt.Errorf("pipeline: write task specs for %s: %w", sec.Name, err)
+		}
+		result.FilePaths = append(result.FilePaths, path)
+		result.Sections = append(result.Sections, Section{
+			Name:    sec.Name,
+			Content: content,
+			Agent:   sec.Agent,
+		})
+		all = append(all, content)
+	}
+
+	if cfg.LayoutMode == LayoutNested {
+		if err := writeStageIndex(cfg); err != nil {
+			return nil, fmt.Errorf("pipeline: update index: %w", err)
+		}
+	}
+	if !cfg.SkipVerification {
+		p.verifyStageResult(result, strings.Join(all, "\n\n"), inputs, filepath.Join(dir, "tasks.verification.md"))
+	}
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -16,6 +17,10 @@ const (
 	SeverityInfo     Severity = "info"     // Informational observation
 )
 
+// ErrVerificationFailed is wrapped by the error RunPipeline returns when a
+// stage's verification finds critical issues.
+var ErrVerificationFailed = errors.New("failed verification with critical findings")
+
 // VerificationFinding is a single issue found during verification.
 type VerificationFinding struct {
 	ID          string   `json:"id"`
@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/onedusk/pd/internal/a2a"
+)
+
+// Warmup discovers every configured agent endpoint concurrently and caches
+// the cards in the pipeline's card cache, so an unreachable agent is
+// reported before the first stage runs and routing needs no further round
+// trips. It returns the discovery errors joined; cards that were fetched are
+// cached either way.
+func (p *Pipeline) Warmup(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(p.cfg.AgentEndpoints))
+	for i, endpoint := range p.cfg.AgentEndpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.client.DiscoverAgent(ctx, endpoint); err != nil {
+				errs[i] = fmt.Errorf("warmup %s: %w", endpoint, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// agentCards returns the cards of the agents tasks are routed to, fetched
+// concurrently through the card cache. Agents whose card cannot be fetched
+// are left out.
+func (p *Pipeline) agentCards(ctx context.Context, tasks []AgentTask) map[string]*a2a.AgentCard {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		cards = make(map[string]*a2a.AgentCard)
+		seen  = make(map[string]bool)
+	)
+	for _, task := range tasks {
+		if seen[task.AgentEndpoint] {
+			continue
+		}
+		seen[task.AgentEndpoint] = true
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			card, err := p.client.DiscoverAgent(ctx, endpoint)
+			if err != nil || card == nil {
+				return
+			}
+			mu.Lock()
+			cards[endpoint] = card
+			mu.Unlock()
+		}(task.AgentEndpoint)
+	}
+	wg.Wait()
+	return cards
+}
+
+// narrowOutputModes restricts each task's accepted output modes to those its
+// agent's card offers, keeping the stage's order of preference. A task keeps
+// its modes when its agent has no known card, the card lists no modes, or
+// they share none.
+func (p *Pipeline) narrowOutputModes(ctx context.Context, tasks []AgentTask) {
+	cards := p.agentCards(ctx, tasks)
+	for i, task := range tasks {
+		card := cards[task.AgentEndpoint]
+		if card == nil {
+			continue
+		}
+		var modes []string
+		for _, mode := range task.AcceptedOutputModes {
+			if slices.Contains(card.DefaultOutputModes, mode) {
+				modes = append(modes, mode)
+			}
+		}
+		if len(modes) > 0 {
+			tasks[i].AcceptedOutputModes = modes
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package orchestrator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onedusk/pd/internal/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoAgent is an a2a.Handler that completes every message with a fixed
+// text artifact.
+type echoAgent struct{}
+
+func (echoAgent) HandleSendMessage(context.Context, a2a.SendMessageRequest) (*a2a.Task, error) {
+	return completedTask(string(a2a.NewTaskID()), "section"), nil
+}
+
+func (echoAgent) HandleGetTask(context.Context, a2a.GetTaskRequest) (*a2a.Task, error) {
+	return nil, a2a.ErrNotImplemented
+}
+
+func (echoAgent) HandleListTasks(context.Context, a2a.ListTasksRequest) (*a2a.ListTasksResponse, error) {
+	return nil, a2a.ErrNotImplemented
+}
+
+func (echoAgent) HandleCancelTask(context.Context, a2a.CancelTaskRequest) (*a2a.Task, error) {
+	return nil, a2a.ErrNotImplemented
+}
+
+// countingAgentServer serves echoAgent and counts agent card requests.
+func countingAgentServer(t *testing.T, discoveries *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := a2a.NewServer(a2a.AgentCard{Name: "echo", Version: "dev"}, echoAgent{})
+	h := srv.Handler()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/agent-card.json") {
+			discoveries.Add(1)
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestPipeline_WarmupDiscoversEachAgentOnce(t *testing.T) {
+	var hits [2]atomic.Int32
+	endpoints := []string{countingAgentServer(t, &hits[0]).URL, countingAgentServer(t, &hits[1]).URL}
+
+	cfg := Config{
+		Name:             "warmup",
+		OutputDir:        t.TempDir(),
+		Capability:       CapA2AMCP,
+		AgentEndpoints:   endpoints,
+		SkipVerification: true,
+	}
+	p := NewPipeline(cfg, a2a.NewHTTPClient(a2a.WithTimeout(5*time.Second)))
+	defer p.Close()
+
+	ctx := context.Background()
+	require.NoError(t, p.Warmup(ctx))
+	for i := range hits {
+		assert.Equal(t, int32(1), hits[i].Load(), "endpoint %d discovered once", i)
+	}
+
+	// Routing the first stage reads the cached cards.
+	_, err := p.RunStage(ctx, StageDesignPack)
+	require.NoError(t, err)
+	for i := range hits {
+		assert.Equal(t, int32(1), hits[i].Load(), "no rediscovery of endpoint %d after warmup", i)
+	}
+
+	// Without Warmup, routing discovers each agent itself.
+	cold := NewPipeline(cfg, a2a.NewHTTPClient(a2a.WithTimeout(5*time.Second)))
+	defer cold.Close()
+	_, err = cold.RunStage(ctx, StageDesignPack)
+	require.NoError(t, err)
+	for i := range hits {
+		assert.Equal(t, int32(2), hits[i].Load(), "endpoint %d discovered by routing", i)
+	}
+}
+
+// cardClient is a mockClient whose agents all serve card.
+type cardClient struct {
+	mockClient
+	card        a2a.AgentCard
+	discoveries atomic.Int32
+}
+
+func (c *cardClient) DiscoverAgent(context.Context, string) (*a2a.AgentCard, error) {
+	c.discoveries.Add(1)
+	return &c.card, nil
+}
+
+func TestPipeline_NarrowsOutputModesToAgentCard(t *testing.T) {
+	client := &cardClient{card: a2a.AgentCard{Name: "md-only", DefaultOutputModes: []string{"text/plain", "text/markdown"}}}
+	p := NewPipeline(Config{Capability: CapA2AMCP}, client)
+	defer p.Close()
+
+	tasks := []AgentTask{
+		{AgentEndpoint: "http://a", AcceptedOutputModes: []string{"text/markdown", "application/json"}},
+		{AgentEndpoint: "http://a", AcceptedOutputModes: []string{"application/json"}},
+	}
+	p.narrowOutputModes(context.Background(), tasks)
+	assert.Equal(t, []string{"text/markdown"}, tasks[0].AcceptedOutputModes)
+	assert.Equal(t, []string{"application/json"}, tasks[1].AcceptedOutputModes, "no shared mode keeps the stage's modes")
+
+	p.narrowOutputModes(context.Background(), tasks)
+	assert.Equal(t, int32(1), client.discoveries.Load(), "cards are cached")
+}
+
+func TestPipeline_WarmupReportsUnreachableAgents(t *testing.T) {
+	var hits atomic.Int32
+	up := countingAgentServer(t, &hits).URL
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	cfg := Config{OutputDir: t.TempDir(), Capability: CapA2AMCP, AgentEndpoints: []string{up, down.URL}}
+	p := NewPipeline(cfg, a2a.NewHTTPClient(a2a.WithTimeout(time.Second)))
+	defer p.Close()
+
+	err := p.Warmup(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "warmup "+down.URL)
+	assert.NotContains(t, err.Error(), "warmup "+up+":")
+	assert.Equal(t, int32(1), hits.Load())
+
+	// The reachable agent's card was cached despite the failure.
+	_, err = p.client.DiscoverAgent(context.Background(), up)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/onedusk/pd/internal/orchestrator"
 )
@@ -34,20 +35,32 @@ var stageLabels = [5]string{
 }
 
 // ScanCompletedStages checks which stage output files exist in a directory.
-// Returns the stage numbers (0-4) that have output files.
+// Both flat (stage-N-name.md) and nested (stage-N/name.md) layouts are
+// recognized. Returns the stage numbers (0-4) that have output files.
 func ScanCompletedStages(dir string) []int {
 	var completed []int
 	for stage := 0; stage <= 4; stage++ {
 		s := orchestrator.Stage(stage)
-		filename := fmt.Sprintf("stage-%d-%s.md", stage, s.String())
-		path := filepath.Join(dir, filename)
-		if _, err := os.Stat(path); err == nil {
-			completed = append(completed, stage)
+		for _, layout := range []orchestrator.LayoutMode{orchestrator.LayoutFlat, orchestrator.LayoutNested} {
+			if _, err := os.Stat(orchestrator.StageFilePath(dir, s, layout)); err == nil {
+				completed = append(completed, stage)
+				break
+			}
 		}
 	}
 	return completed
 }
 
+// existingStagePath returns the path of the stage file in dir, preferring the
+// nested layout when it exists and falling back to the flat layout.
+func existingStagePath(dir string, s orchestrator.Stage) string {
+	nested := orchestrator.StageFilePath(dir, s, orchestrator.LayoutNested)
+	if _, err := os.Stat(nested); err == nil {
+		return nested
+	}
+	return orchestrator.StageFilePath(dir, s, orchestrator.LayoutFlat)
+}
+
 // NextStage returns the next stage to run based on completed stages.
 // Returns -1 if all stages are complete.
 func NextStage(completed []int) int {
@@ -90,7 +103,7 @@ func GetDecompositionStatus(projectRoot, name string) DecompositionStatus {
 			if i == 0 {
 				filePath = stage0Path
 			} else {
-				filePath = filepath.Join(outputDir, fmt.Sprintf("stage-%d-%s.md", i, slug))
+				filePath = existingStagePath(outputDir, orchestrator.Stage(i))
 			}
 		}
 		stages[i] = StageInfo{
@@ -114,8 +127,38 @@ func GetDecompositionStatus(projectRoot, name string) DecompositionStatus {
 	}
 }
 
+// ListOption configures ListDecompositions.
+type ListOption func(*listConfig)
+
+type listConfig struct {
+	concurrency int
+	progress    func(done, total int)
+}
+
+// WithConcurrency scans up to n decompositions at once. Values below 2 scan
+// serially, which is the default.
+func WithConcurrency(n int) ListOption {
+	return func(c *listConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithProgress calls fn after each decomposition is scanned with the number
+// scanned so far and the total. Calls are serialized.
+func WithProgress(fn func(done, total int)) ListOption {
+	return func(c *listConfig) {
+		c.progress = fn
+	}
+}
+
 // ListDecompositions scans the docs/decompose directory for all decompositions.
-func ListDecompositions(projectRoot string) ([]DecompositionStatus, bool) {
+// Results are in directory order regardless of concurrency.
+func ListDecompositions(projectRoot string, opts ...ListOption) ([]DecompositionStatus, bool) {
+	var cfg listConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	decomposeDir := filepath.Join(projectRoot, "docs", "decompose")
 	entries, err := os.ReadDir(decomposeDir)
 	if err != nil {
@@ -123,8 +166,7 @@ func ListDecompositions(projectRoot string) ([]DecompositionStatus, bool) {
 	}
 
 	hasStage0 := false
-	var results []DecompositionStatus
-
+	var names []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			if strings.HasPrefix(entry.Name(), "stage-0-") {
@@ -132,8 +174,36 @@ func ListDecompositions(projectRoot string) ([]DecompositionStatus, bool) {
 			}
 			continue
 		}
-		results = append(results, GetDecompositionStatus(projectRoot, entry.Name()))
+		names = append(names, entry.Name())
+	}
+	if len(names) == 0 {
+		return nil, hasStage0
+	}
+
+	results := make([]DecompositionStatus, len(names))
+	var (
+		mu   sync.Mutex
+		done int
+		wg   sync.WaitGroup
+	)
+	workers := max(cfg.concurrency, 1)
+	sem := make(chan struct{}, workers)
+	for i, name := range names {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = GetDecompositionStatus(projectRoot, name)
+			if cfg.progress != nil {
+				mu.Lock()
+				done++
+				cfg.progress(done, len(names))
+				mu.Unlock()
+			}
+		}()
 	}
+	wg.Wait()
 
 	return results, hasStage0
 }
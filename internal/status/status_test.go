@@ -0,0 +1,52 @@
+package status
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/onedusk/pd/internal/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDecompositions_ConcurrentMatchesSerial(t *testing.T) {
+	root := t.TempDir()
+	decomposeDir := filepath.Join(root, "docs", "decompose")
+	for i := 0; i < 10; i++ {
+		dir := filepath.Join(decomposeDir, fmt.Sprintf("feature-%02d", i))
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		// Decomposition i has stages 1..i%5 complete.
+		for stage := 1; stage <= i%5; stage++ {
+			path := orchestrator.StageFilePath(dir, orchestrator.Stage(stage), orchestrator.LayoutFlat)
+			require.NoError(t, os.WriteFile(path, []byte("# x\n"), 0o644))
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(decomposeDir, "stage-0-development-standards.md"), []byte("# s\n"), 0o644))
+
+	serial, serialStage0 := ListDecompositions(root)
+	require.Len(t, serial, 10)
+	assert.Equal(t, 3, serial[2].NextStage)
+	assert.Equal(t, -1, serial[4].NextStage)
+
+	var (
+		mu    sync.Mutex
+		calls []int
+	)
+	concurrent, concurrentStage0 := ListDecompositions(root,
+		WithConcurrency(4),
+		WithProgress(func(done, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, 10, total)
+			calls = append(calls, done)
+		}),
+	)
+
+	assert.Equal(t, serial, concurrent)
+	assert.Equal(t, serialStage0, concurrentStage0)
+	assert.True(t, concurrentStage0)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, calls)
+}
@@ -0,0 +1,30 @@
+import functools
+
+
+def cached(fn):
+    @functools.wraps(fn)
+    def wrapper(*args):
+        return fn(*args)
+
+    return wrapper
+
+
+class Account:
+    def __init__(self, balance):
+        self._balance = balance
+
+    @property
+    def balance(self):
+        return self._balance
+
+    @staticmethod
+    @cached
+    def currency():
+        return "USD"
+
+    async def refresh(self):
+        return self._balance
+
+
+async def fetch_account(account_id):
+    return Account(0)